@@ -3,11 +3,15 @@ package api
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 
 	"github.com/ONSdigital/dp-areas-api/api/geodata"
+	"github.com/ONSdigital/dp-areas-api/auth"
 	"github.com/ONSdigital/dp-areas-api/config"
+	"github.com/ONSdigital/dp-areas-api/fixtures"
 	"github.com/ONSdigital/dp-areas-api/models"
+	"github.com/ONSdigital/log.go/v2/log"
 
 	"github.com/gorilla/mux"
 )
@@ -23,9 +27,15 @@ var (
 
 //API provides a struct to wrap the api around
 type API struct {
-	Router       *mux.Router
-	GeoData      map[string]models.AreasDataResults
-	rdsAreaStore RDSAreaStore
+	Router              *mux.Router
+	GeoData             map[string]models.AreasDataResults
+	Boundaries          map[string]models.BoundaryDataResults
+	rdsAreaStore        RDSAreaStore
+	bulkUpsertBatchSize int
+	// largest bulk upsert request body accepted
+	bulkUpsertMaxBodyBytes int64
+	// StaleResponses serves public reads while the database is unavailable, nil when degraded mode is disabled
+	StaleResponses *StaleResponses
 }
 
 type baseHandler func(ctx context.Context, w http.ResponseWriter, r *http.Request) (*models.SuccessResponse, *models.ErrorResponse)
@@ -42,28 +52,55 @@ func contextAndErrors(h baseHandler) http.HandlerFunc {
 	}
 }
 
-//Setup function sets up the api and returns an api
-func Setup(ctx context.Context, cfg *config.Config, r *mux.Router, rdsStore RDSAreaStore) (*API, error) {
+//Setup function sets up the api and returns an api. The verifier identifies the callers of the private endpoints,
+//and may be nil when they are disabled.
+func Setup(ctx context.Context, cfg *config.Config, r *mux.Router, rdsStore RDSAreaStore, verifier auth.Verifier) (*API, error) {
+	if cfg.EnablePrivateEndpoints && verifier == nil {
+		return nil, errors.New("private endpoints require an identity verifier")
+	}
+
 	// initialised stubbed geo data
 	geoData, err := initialiseStubbedAreaData(ctx)
 	if err != nil {
 		return nil, err
 	}
 
+	// stubbed boundaries are served from the configured fixtures
+	boundaries, err := initialiseStubbedBoundaries(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	// a snapshot that cannot be loaded only leaves fewer responses to serve during an outage
+	staleResponses := NewStaleResponses(cfg)
+	if err := staleResponses.LoadSnapshot(); err != nil {
+		log.Error(ctx, "failed to load stale responses", err, log.Data{"file": cfg.StaleSnapshotFile})
+	}
+
 	api := &API{
-		Router:       r,
-		GeoData:      geoData,
-		rdsAreaStore: rdsStore,
+		Router:                 r,
+		GeoData:                geoData,
+		Boundaries:             boundaries,
+		rdsAreaStore:           rdsStore,
+		bulkUpsertBatchSize:    cfg.BulkUpsertBatchSize,
+		bulkUpsertMaxBodyBytes: cfg.BulkUpsertMaxBodyBytes,
+		StaleResponses:         staleResponses,
 	}
 
-	r.HandleFunc("/v1/areas/{id}", contextAndErrors(api.getAreaData)).Methods(http.MethodGet)
-	r.HandleFunc("/v1/areas/{id}/relations", contextAndErrors(api.getAreaRelationships)).Methods(http.MethodGet)
+	// public reads are revalidated against their ETag and Last-Modified headers, and cached as each route's policy allows
+	r.HandleFunc("/v1/areas/{id}", contextAndErrors(cacheable(cfg.AreaCacheControl, staleResponses.serveStale(api.getAreaData)))).Methods(http.MethodGet)
+	r.HandleFunc("/v1/areas/{id}/relations", contextAndErrors(cacheable(cfg.RelationsCacheControl, staleResponses.serveStale(api.getAreaRelationships)))).Methods(http.MethodGet)
 
 	if cfg.EnablePrivateEndpoints {
-		r.HandleFunc("/v1/areas/{id}", contextAndErrors(api.updateArea)).Methods(http.MethodPut)
+		r.HandleFunc("/v1/areas/{id}", contextAndErrors(authorised(verifier, auth.PermissionUpdate, api.updateArea))).Methods(http.MethodPut)
+		r.HandleFunc("/v1/areas/{id}", contextAndErrors(authorised(verifier, auth.PermissionUpdate, api.patchArea))).Methods(http.MethodPatch)
+		r.HandleFunc("/v1/areas/{id}", contextAndErrors(authorised(verifier, auth.PermissionUpdate, api.retireArea))).Methods(http.MethodDelete)
+		r.HandleFunc("/v1/areas:bulk", contextAndErrors(authorised(verifier, auth.PermissionUpdate, api.bulkUpsertAreas))).Methods(http.MethodPost)
+		r.HandleFunc("/v1/areas/{id}/audit", contextAndErrors(authorised(verifier, auth.PermissionReadAudit, api.getAreaAudit))).Methods(http.MethodGet)
+		r.HandleFunc("/v1/schema/drift", contextAndErrors(authorised(verifier, auth.PermissionReadSchema, api.getSchemaDrift))).Methods(http.MethodGet)
 	}
 
-	r.HandleFunc("/v1/boundaries/{id}", contextAndErrors(api.getBoundary)).Methods(http.MethodGet)
+	r.HandleFunc("/v1/boundaries/{id}", contextAndErrors(cacheable(cfg.BoundaryCacheControl, api.getBoundary))).Methods(http.MethodGet)
 
 	return api, nil
 }
@@ -78,6 +115,23 @@ func initialiseStubbedAreaData(_ context.Context) (map[string]models.AreasDataRe
 	return geoData, nil
 }
 
+func initialiseStubbedBoundaries(cfg *config.Config) (map[string]models.BoundaryDataResults, error) {
+	set, err := fixtures.Open(cfg.FixturesVersion, cfg.FixturesDir)
+	if err != nil {
+		return nil, err
+	}
+	boundaries := make(map[string]models.BoundaryDataResults, len(set.Boundaries))
+	for _, boundary := range set.Boundaries {
+		data := models.BoundaryDataResults{Columns: boundaryColumns}
+		data.Values.AreaID = boundary.AreaID
+		data.Values.Boundary = boundary.Boundary
+		data.Values.Centroid = boundary.Centroid
+		data.Values.CentroidBng = boundary.CentroidBng
+		boundaries[boundary.AreaID] = data
+	}
+	return boundaries, nil
+}
+
 func writeErrorResponse(ctx context.Context, w http.ResponseWriter, errorResponse *models.ErrorResponse) {
 	w.Header().Set("Content-Type", "application/json")
 	// process custom headers
@@ -112,6 +166,10 @@ func writeSuccessResponse(ctx context.Context, w http.ResponseWriter, successRes
 		}
 	}
 	w.WriteHeader(successResponse.Status)
+	// a 304 must not have a body
+	if successResponse.Status == http.StatusNotModified {
+		return
+	}
 
 	_, err := w.Write(successResponse.Body)
 	if err != nil {
@@ -8,6 +8,7 @@ import (
 
 	"github.com/ONSdigital/dp-areas-api/api"
 	"github.com/ONSdigital/dp-areas-api/api/mock"
+	"github.com/ONSdigital/dp-areas-api/auth"
 	"github.com/ONSdigital/dp-areas-api/config"
 	"github.com/gorilla/mux"
 	. "github.com/smartystreets/goconvey/convey"
@@ -21,11 +22,16 @@ func TestSetup(t *testing.T) {
 		ctx := context.Background()
 		cfg, err := config.Get()
 		So(err, ShouldBeNil)
-		api, _ := api.Setup(ctx, cfg, r, &mock.RDSAreaStoreMock{})
+		api, _ := api.Setup(ctx, cfg, r, &mock.RDSAreaStoreMock{}, auth.NewStubVerifier())
 		Convey("When created the following routes should have been added", func() {
 			So(hasRoute(api.Router, "/v1/areas/{id}", "GET"), ShouldBeTrue)
 			So(hasRoute(api.Router, "/v1/areas/{id}/relations", "GET"), ShouldBeTrue)
 			So(hasRoute(api.Router, "/v1/areas/{id}", "PUT"), ShouldBeTrue)
+			So(hasRoute(api.Router, "/v1/areas/{id}", "PATCH"), ShouldBeTrue)
+			So(hasRoute(api.Router, "/v1/areas/{id}", "DELETE"), ShouldBeTrue)
+			So(hasRoute(api.Router, "/v1/areas:bulk", "POST"), ShouldBeTrue)
+			So(hasRoute(api.Router, "/v1/areas/{id}/audit", "GET"), ShouldBeTrue)
+			So(hasRoute(api.Router, "/v1/schema/drift", "GET"), ShouldBeTrue)
 		})
 	})
 }
@@ -3,27 +3,33 @@ package api
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"github.com/ONSdigital/dp-areas-api/models/DBRelationalData"
 	"io/ioutil"
 	"net/http"
 	"regexp"
+	"sort"
+	"strconv"
 
 	"github.com/ONSdigital/log.go/v2/log"
 
+	"github.com/ONSdigital/dp-areas-api/apierrors"
 	"github.com/ONSdigital/dp-areas-api/models"
 	"github.com/gorilla/mux"
 )
 
 const (
 	acceptLanguageHeaderMatchString = "en|cy"
+	includeInactiveQueryParameter   = "include_inactive"
+	cascadeQueryParameter           = "cascade"
+	boundaryColumns                 = "area_id, centroid_bng, centroid, boundary"
 )
 
 var (
 	queryStr = "select id, code, active from areas_basic where id=$1"
 )
 
-// getBoundary is a handler that gets boundary for an ID - currently from stubbed data
+// getBoundary is a handler that gets boundary for an ID - currently from the stubbed fixture boundaries
 func (api *API) getBoundary(ctx context.Context, _ http.ResponseWriter, req *http.Request) (*models.SuccessResponse, *models.ErrorResponse) {
 	// identifier from request
 	vars := mux.Vars(req)
@@ -32,7 +38,7 @@ func (api *API) getBoundary(ctx context.Context, _ http.ResponseWriter, req *htt
 	log.Info(ctx, "received request to get boundary", logData)
 
 	// get boundary data
-	data, exist := DBRelationalData.BoundariesData[boundaryID]
+	data, exist := api.Boundaries[boundaryID]
 	if !exist {
 		// boundary id does not exist = 404
 		responseErr := models.NewError(ctx, nil, models.MarshallingAreaBoundaryError, fmt.Sprintf("boundary identifier %s does not exist", boundaryID))
@@ -67,21 +73,17 @@ func (api *API) getAreaData(ctx context.Context, _ http.ResponseWriter, req *htt
 		return nil, models.NewErrorResponse(http.StatusNotFound, nil, validationErrs...)
 	}
 
-	// get ancestry data
-	ancestryData, err := api.rdsAreaStore.GetAncestors(areaID)
-	if err != nil {
-		responseErr := models.NewError(ctx, err, models.AncestryDataGetError, err.Error())
-		return nil, models.NewErrorResponse(http.StatusInternalServerError, nil, responseErr)
+	includeInactive, errResponse := getIncludeInactiveParameter(ctx, req)
+	if errResponse != nil {
+		return nil, errResponse
 	}
 
-	area, err := api.rdsAreaStore.GetArea(ctx, areaID)
-
+	// the area and its ancestry are fetched together in one round trip
+	details, err := api.rdsAreaStore.GetAreaDetails(ctx, areaID, models.AreaDetailsOptions{IncludeInactive: includeInactive, Boundary: true})
 	if err != nil {
 		return nil, models.NewDBReadError(ctx, err)
 	}
-
-	// update area data with ancestry data
-	area.Ancestors = ancestryData
+	area := details.Area
 
 	areaData, err := json.Marshal(area)
 	if err != nil {
@@ -89,7 +91,18 @@ func (api *API) getAreaData(ctx context.Context, _ http.ResponseWriter, req *htt
 		return nil, models.NewErrorResponse(http.StatusInternalServerError, nil, responseErr)
 	}
 
-	return models.NewSuccessResponse(areaData, http.StatusOK, nil), nil
+	// the ancestors in the body change without the area's version, so both validators cover them. Writes are
+	// conditional on the version at the start of the ETag with If-Match.
+	ancestry, err := json.Marshal(area.Ancestors)
+	if err != nil {
+		responseErr := models.NewError(ctx, err, models.MarshallingAreaDataError, err.Error())
+		return nil, models.NewErrorResponse(http.StatusInternalServerError, nil, responseErr)
+	}
+	headers := map[string]string{models.ETagHeaderName: models.AreaDetailsETag(area.Version, ancestry)}
+	if !area.LastModified.IsZero() {
+		headers[models.LastModifiedHeaderName] = area.LastModified.UTC().Format(http.TimeFormat)
+	}
+	return models.NewSuccessResponse(areaData, http.StatusOK, headers), nil
 }
 
 //getAreaRelationships is a handler that gets area relationship by ID - currently from stubbed data
@@ -98,14 +111,22 @@ func (api *API) getAreaRelationships(ctx context.Context, w http.ResponseWriter,
 	areaID := vars["id"]
 	relationshipParameter := req.URL.Query().Get("relationship")
 
-	err := api.rdsAreaStore.ValidateArea(areaID)
+	includeInactive, errResponse := getIncludeInactiveParameter(ctx, req)
+	if errResponse != nil {
+		return nil, errResponse
+	}
+
+	err := api.rdsAreaStore.ValidateArea(ctx, areaID, includeInactive)
 
 	if err != nil {
 		return nil, models.NewDBReadError(ctx, err)
 	}
 
-	relatedAreaDetails, err := api.rdsAreaStore.GetRelationships(areaID, relationshipParameter)
+	relatedAreaDetails, err := api.rdsAreaStore.GetRelationships(ctx, areaID, relationshipParameter, includeInactive)
 	if err != nil {
+		if errorResponse := models.NewStoreErrorResponse(ctx, err); errorResponse != nil {
+			return nil, errorResponse
+		}
 		return nil, models.NewErrorResponse(http.StatusInternalServerError, nil, err)
 	}
 
@@ -159,8 +180,14 @@ func (api *API) updateArea(ctx context.Context, w http.ResponseWriter, req *http
 		return nil, models.NewErrorResponse(http.StatusNotFound, nil, validationErrors...)
 	}
 
-	isInserted, err := api.rdsAreaStore.UpsertArea(ctx, area)
+	isInserted, err := api.rdsAreaStore.UpsertArea(ctx, area, req.Header.Get(models.IfMatchHeaderName))
 	if err != nil {
+		if errorResponse := models.NewStoreErrorResponse(ctx, err); errorResponse != nil {
+			return nil, errorResponse
+		}
+		if err == apierrors.ErrPreconditionFailed {
+			return nil, newPreconditionFailedError(ctx, err)
+		}
 		responseErr := models.NewError(ctx, err, models.AreaDataIdUpsertError, err.Error())
 		return nil, models.NewErrorResponse(http.StatusInternalServerError, nil, responseErr)
 	}
@@ -172,3 +199,171 @@ func (api *API) updateArea(ctx context.Context, w http.ResponseWriter, req *http
 	}
 
 }
+
+// bulkUpsertAreas is a handler that upserts many areas at once. Every area is validated before anything is written, then
+// areas are applied parents first in one transaction or in batches of the configured size. The response reports the
+// outcome of each area by its index in the request.
+func (api *API) bulkUpsertAreas(ctx context.Context, w http.ResponseWriter, req *http.Request) (*models.SuccessResponse, *models.ErrorResponse) {
+	defer func() {
+		if err := req.Body.Close(); err != nil {
+			_ = models.NewError(ctx, err, models.BodyCloseError, models.BodyClosedFailedDescription)
+		}
+	}()
+
+	body, err := ioutil.ReadAll(http.MaxBytesReader(w, req.Body, api.bulkUpsertMaxBodyBytes))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			responseErr := models.NewError(ctx, err, models.RequestBodyTooLargeError, models.RequestBodyTooLargeErrorDescription)
+			return nil, models.NewErrorResponse(http.StatusRequestEntityTooLarge, nil, responseErr)
+		}
+		return nil, models.NewBodyReadError(ctx, err)
+	}
+
+	areas, err := models.ParseBulkAreas(req.Header.Get("Content-Type"), body)
+	if err != nil {
+		return nil, models.NewBodyUnmarshalError(ctx, err)
+	}
+	if len(areas) == 0 {
+		responseErr := models.NewValidationError(ctx, models.BulkUpsertError, models.EmptyBulkRequestErrorDescription)
+		return nil, models.NewErrorResponse(http.StatusBadRequest, nil, responseErr)
+	}
+	log.Info(ctx, "received request to bulk upsert areas", log.Data{"areas": len(areas), "batch size": api.bulkUpsertBatchSize})
+
+	order, invalid := models.ValidateBulkAreas(ctx, areas)
+	if len(invalid) != 0 {
+		return newBulkAreaResponse(ctx, invalid, http.StatusBadRequest)
+	}
+
+	sorted := make([]models.AreaParams, len(order))
+	for i, index := range order {
+		sorted[i] = areas[index]
+	}
+
+	results, err := api.rdsAreaStore.BulkUpsertAreas(ctx, sorted, api.bulkUpsertBatchSize)
+	// results are reported against the position of each area in the request rather than the order they were applied
+	for i := range results {
+		results[i].Index = order[i]
+	}
+	if err != nil {
+		log.Error(ctx, "bulk upsert failed", err)
+		if errorResponse := models.NewStoreErrorResponse(ctx, err); errorResponse != nil {
+			return newBulkAreaResponse(ctx, results, errorResponse.Status)
+		}
+		return newBulkAreaResponse(ctx, results, http.StatusInternalServerError)
+	}
+
+	return newBulkAreaResponse(ctx, results, http.StatusOK)
+}
+
+func newBulkAreaResponse(ctx context.Context, results []models.BulkAreaResult, status int) (*models.SuccessResponse, *models.ErrorResponse) {
+	sort.Slice(results, func(i, j int) bool { return results[i].Index < results[j].Index })
+	jsonResponse, err := json.Marshal(models.BulkAreaResponse{Results: results})
+	if err != nil {
+		responseErr := models.NewError(ctx, err, models.BulkUpsertError, err.Error())
+		return nil, models.NewErrorResponse(http.StatusInternalServerError, nil, responseErr)
+	}
+	return models.NewSuccessResponse(jsonResponse, status, nil), nil
+}
+
+// patchArea is a handler that applies a JSON Patch or merge patch to an existing area
+func (api *API) patchArea(ctx context.Context, w http.ResponseWriter, req *http.Request) (*models.SuccessResponse, *models.ErrorResponse) {
+	defer func() {
+		if err := req.Body.Close(); err != nil {
+			_ = models.NewError(ctx, err, models.BodyCloseError, models.BodyClosedFailedDescription)
+		}
+	}()
+
+	vars := mux.Vars(req)
+	areaCode := vars["id"]
+	logData := log.Data{"area code": areaCode}
+	log.Info(ctx, "received request to patch area", logData)
+
+	body, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		return nil, models.NewBodyReadError(ctx, err)
+	}
+
+	patch, err := models.ParseAreaPatch(req.Header.Get("Content-Type"), body)
+	if err != nil {
+		responseErr := models.NewError(ctx, err, models.InvalidPatchError, err.Error())
+		return nil, models.NewErrorResponse(http.StatusBadRequest, nil, responseErr)
+	}
+
+	validationErrors := patch.Validate(ctx)
+	if len(validationErrors) != 0 {
+		return nil, models.NewErrorResponse(http.StatusBadRequest, nil, validationErrors...)
+	}
+
+	err = api.rdsAreaStore.PatchArea(ctx, areaCode, patch, req.Header.Get(models.IfMatchHeaderName))
+	if err != nil {
+		if errorResponse := models.NewStoreErrorResponse(ctx, err); errorResponse != nil {
+			return nil, errorResponse
+		}
+		if err == apierrors.ErrPreconditionFailed {
+			return nil, newPreconditionFailedError(ctx, err)
+		}
+		if errors.Is(err, apierrors.ErrInvalidAreaPatch) {
+			responseErr := models.NewError(ctx, err, models.InvalidPatchError, err.Error())
+			return nil, models.NewErrorResponse(http.StatusBadRequest, nil, responseErr)
+		}
+		responseErr := models.NewError(ctx, err, models.AreaPatchError, err.Error())
+		return nil, models.NewErrorResponse(http.StatusInternalServerError, nil, responseErr)
+	}
+
+	return models.NewSuccessResponse(nil, http.StatusOK, nil), nil
+}
+
+// retireArea is a handler that soft-deletes an area, optionally retiring all of its descendants
+func (api *API) retireArea(ctx context.Context, _ http.ResponseWriter, req *http.Request) (*models.SuccessResponse, *models.ErrorResponse) {
+	vars := mux.Vars(req)
+	areaCode := vars["id"]
+	logData := log.Data{"area code": areaCode}
+	log.Info(ctx, "received request to retire area", logData)
+
+	cascade, errResponse := getBoolQueryParameter(ctx, req, cascadeQueryParameter)
+	if errResponse != nil {
+		return nil, errResponse
+	}
+
+	err := api.rdsAreaStore.RetireArea(ctx, areaCode, cascade, req.Header.Get(models.IfMatchHeaderName))
+	if err != nil {
+		if errorResponse := models.NewStoreErrorResponse(ctx, err); errorResponse != nil {
+			return nil, errorResponse
+		}
+		if err == apierrors.ErrPreconditionFailed {
+			return nil, newPreconditionFailedError(ctx, err)
+		}
+		if err == apierrors.ErrAreaHasLiveChildren {
+			responseErr := models.NewError(ctx, err, models.AreaHasLiveChildrenError, models.AreaHasLiveChildrenErrorDescription)
+			return nil, models.NewErrorResponse(http.StatusConflict, nil, responseErr)
+		}
+		responseErr := models.NewError(ctx, err, models.AreaRetireError, err.Error())
+		return nil, models.NewErrorResponse(http.StatusInternalServerError, nil, responseErr)
+	}
+
+	return models.NewSuccessResponse(nil, http.StatusNoContent, nil), nil
+}
+
+func newPreconditionFailedError(ctx context.Context, err error) *models.ErrorResponse {
+	responseErr := models.NewError(ctx, err, models.PreconditionFailedError, models.PreconditionFailedErrorDescription)
+	return models.NewErrorResponse(http.StatusPreconditionFailed, nil, responseErr)
+}
+
+// getIncludeInactiveParameter reads the flag that allows retired areas to be returned by public endpoints
+func getIncludeInactiveParameter(ctx context.Context, req *http.Request) (bool, *models.ErrorResponse) {
+	return getBoolQueryParameter(ctx, req, includeInactiveQueryParameter)
+}
+
+func getBoolQueryParameter(ctx context.Context, req *http.Request, name string) (bool, *models.ErrorResponse) {
+	value := req.URL.Query().Get(name)
+	if value == "" {
+		return false, nil
+	}
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		responseErr := models.NewValidationError(ctx, models.InvalidQueryParameterError, fmt.Sprintf("%s: %s", models.InvalidQueryParameterErrorDescription, name))
+		return false, models.NewErrorResponse(http.StatusBadRequest, nil, responseErr)
+	}
+	return parsed, nil
+}
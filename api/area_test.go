@@ -3,6 +3,7 @@ package api_test
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
@@ -14,8 +15,11 @@ import (
 	"github.com/ONSdigital/dp-areas-api/api"
 	"github.com/ONSdigital/dp-areas-api/api/mock"
 	"github.com/ONSdigital/dp-areas-api/apierrors"
+	"github.com/ONSdigital/dp-areas-api/auth"
 	"github.com/ONSdigital/dp-areas-api/config"
+	"github.com/ONSdigital/dp-areas-api/memory"
 	"github.com/ONSdigital/dp-areas-api/models"
+	dprequest "github.com/ONSdigital/dp-net/request"
 	"github.com/gorilla/mux"
 	. "github.com/smartystreets/goconvey/convey"
 )
@@ -44,17 +48,24 @@ var ancestors = map[string][]models.AreasAncestors{
 	EnglandAreaData: {},
 	WalesAreaData:   {},
 	SheffieldAreaData: {
-		{YorkshireAreaData, "Yorkshire and the Humber"},
-		{EnglandAreaData, "England"},
+		{Id: YorkshireAreaData, Name: "Yorkshire and the Humber"},
+		{Id: EnglandAreaData, Name: "England"},
 	},
 }
 
+// detailsETag returns the ETag served with the details of an area of the given version and ancestry
+func detailsETag(version int, ancestry []models.AreasAncestors) string {
+	b, err := json.Marshal(ancestry)
+	So(err, ShouldBeNil)
+	return models.AreaDetailsETag(version, b)
+}
+
 func GetAPIWithRDSMocks(mockedRDSStore api.RDSAreaStore) (*api.API, error) {
 	mu.Lock()
 	defer mu.Unlock()
 	cfg, err := config.Get()
 	So(err, ShouldBeNil)
-	return api.Setup(context.Background(), cfg, mux.NewRouter(), mockedRDSStore)
+	return api.Setup(context.Background(), cfg, mux.NewRouter(), mockedRDSStore, auth.NewStubVerifier())
 }
 
 func TestGetBoundaryDataReturnsOk(t *testing.T) {
@@ -64,7 +75,7 @@ func TestGetBoundaryDataReturnsOk(t *testing.T) {
 		w := httptest.NewRecorder()
 
 		areaApi, _ := GetAPIWithRDSMocks(&mock.RDSAreaStoreMock{
-			GetAreaFunc: func(ctx context.Context, areaId string) (*models.AreasDataResults, error) {
+			GetAreaFunc: func(ctx context.Context, areaId string, includeInactive bool) (*models.AreasDataResults, error) {
 				return &models.AreasDataResults{Code: "E92000001", Name: &EnglandName, GeometricData: testGeometricData(), Visible: &isVisible, AreaType: &countryAreaType}, nil
 			},
 		})
@@ -95,7 +106,7 @@ func TestGetBoundaryDataWithUnknownIDReturnsNotFound(t *testing.T) {
 		w := httptest.NewRecorder()
 
 		areaApi, _ := GetAPIWithRDSMocks(&mock.RDSAreaStoreMock{
-			GetAreaFunc: func(ctx context.Context, areaId string) (*models.AreasDataResults, error) {
+			GetAreaFunc: func(ctx context.Context, areaId string, includeInactive bool) (*models.AreasDataResults, error) {
 				return &models.AreasDataResults{Code: "E92000002", Name: &EnglandName, GeometricData: testGeometricData(), Visible: &isVisible, AreaType: &countryAreaType}, nil
 			},
 		})
@@ -120,11 +131,8 @@ func TestGetAreaDataReturnsOkForEngland(t *testing.T) {
 		w := httptest.NewRecorder()
 
 		areaApi, _ := GetAPIWithRDSMocks(&mock.RDSAreaStoreMock{
-			GetAreaFunc: func(ctx context.Context, areaId string) (*models.AreasDataResults, error) {
-				return &models.AreasDataResults{Code: "E92000001", Name: &EnglandName, GeometricData: testGeometricData(), Visible: &isVisible, AreaType: &countryAreaType}, nil
-			},
-			GetAncestorsFunc: func(areaCode string) ([]models.AreasAncestors, error) {
-				return ancestors[WalesAreaData], nil
+			GetAreaDetailsFunc: func(ctx context.Context, areaCode string, opts models.AreaDetailsOptions) (*models.AreaDetails, error) {
+				return &models.AreaDetails{Area: &models.AreasDataResults{Code: "E92000001", Name: &EnglandName, GeometricData: testGeometricData(), Visible: &isVisible, AreaType: &countryAreaType, Ancestors: ancestors[WalesAreaData]}}, nil
 			},
 		})
 		areaApi.Router.ServeHTTP(w, r)
@@ -156,11 +164,8 @@ func TestGetAreaDataReturnsOkForWales(t *testing.T) {
 		w := httptest.NewRecorder()
 
 		areaApi, _ := GetAPIWithRDSMocks(&mock.RDSAreaStoreMock{
-			GetAreaFunc: func(ctx context.Context, areaId string) (*models.AreasDataResults, error) {
-				return &models.AreasDataResults{Code: "W92000004", Name: &WalesName, Visible: &isVisible, AreaType: &countryAreaType}, nil
-			},
-			GetAncestorsFunc: func(areaCode string) ([]models.AreasAncestors, error) {
-				return ancestors[WalesAreaData], nil
+			GetAreaDetailsFunc: func(ctx context.Context, areaCode string, opts models.AreaDetailsOptions) (*models.AreaDetails, error) {
+				return &models.AreaDetails{Area: &models.AreasDataResults{Code: "W92000004", Name: &WalesName, Visible: &isVisible, AreaType: &countryAreaType, Ancestors: ancestors[WalesAreaData]}}, nil
 			},
 		})
 		areaApi.Router.ServeHTTP(w, r)
@@ -190,7 +195,7 @@ func TestGetAreaDataReturnsValidationErrors(t *testing.T) {
 		w := httptest.NewRecorder()
 
 		areaApi, _ := GetAPIWithRDSMocks(&mock.RDSAreaStoreMock{
-			GetAreaFunc: func(ctx context.Context, areaId string) (*models.AreasDataResults, error) {
+			GetAreaDetailsFunc: func(ctx context.Context, areaCode string, opts models.AreaDetailsOptions) (*models.AreaDetails, error) {
 				return nil, apierrors.ErrNoRows
 			},
 		})
@@ -238,22 +243,22 @@ func TestGetAreaRelationshipsReturnsOk(t *testing.T) {
 		r.Header.Set(models.AcceptLanguageHeaderName, "en")
 		w := httptest.NewRecorder()
 		relatedAreas := []*models.AreaBasicData{
-			{"E12000001", "North East"},
-			{"E12000002", "North West"},
-			{"E12000003", "Yorkshire and The Humber"},
+			{Code: "E12000001", Name: "North East"},
+			{Code: "E12000002", Name: "North West"},
+			{Code: "E12000003", Name: "Yorkshire and The Humber"},
 		}
 
 		expectedRelationShips := []*models.AreaRelationShips{
-			{"E12000001", "North East", "/v1/area/E12000001"},
-			{"E12000002", "North West", "/v1/area/E12000002"},
-			{"E12000003", "Yorkshire and The Humber", "/v1/area/E12000003"},
+			{AreaCode: "E12000001", AreaName: "North East", Href: "/v1/area/E12000001"},
+			{AreaCode: "E12000002", AreaName: "North West", Href: "/v1/area/E12000002"},
+			{AreaCode: "E12000003", AreaName: "Yorkshire and The Humber", Href: "/v1/area/E12000003"},
 		}
 
 		areaApi, _ := GetAPIWithRDSMocks(&mock.RDSAreaStoreMock{
-			ValidateAreaFunc: func(areaCode string) error {
+			ValidateAreaFunc: func(ctx context.Context, areaCode string, includeInactive bool) error {
 				return nil
 			},
-			GetRelationshipsFunc: func(areaCode, relationshipParameter string) ([]*models.AreaBasicData, error) {
+			GetRelationshipsFunc: func(ctx context.Context, areaCode, relationshipParameter string, includeInactive bool) ([]*models.AreaBasicData, error) {
 				return relatedAreas, nil
 			},
 		})
@@ -274,6 +279,88 @@ func TestGetAreaRelationshipsReturnsOk(t *testing.T) {
 	})
 }
 
+func TestGetAreaDataWithMemoryStore(t *testing.T) {
+	Convey("Given an api backed by the in-memory store", t, func() {
+		store := memory.New()
+		cfg, err := config.Get()
+		So(err, ShouldBeNil)
+		So(store.Init(context.Background(), cfg), ShouldBeNil)
+		areaApi, err := GetAPIWithRDSMocks(store)
+		So(err, ShouldBeNil)
+
+		Convey("When an area is renamed and then requested", func() {
+			patch := strings.NewReader(`[{"op": "replace", "path": "/area_name/name", "value": "City of Sheffield"}]`)
+			r := httptest.NewRequest(http.MethodPatch, fmt.Sprintf("http://localhost:2200/v1/areas/%s", SheffieldAreaData), patch)
+			r.Header.Set(dprequest.FlorenceHeaderKey, auth.StubFlorenceToken)
+			r.Header.Set("Content-Type", models.JSONPatchContentType)
+			w := httptest.NewRecorder()
+			areaApi.Router.ServeHTTP(w, r)
+			So(w.Code, ShouldEqual, http.StatusOK)
+
+			r = httptest.NewRequest(http.MethodGet, fmt.Sprintf("http://localhost:2200/v1/areas/%s", SheffieldAreaData), nil)
+			r.Header.Set(models.AcceptLanguageHeaderName, "en")
+			w = httptest.NewRecorder()
+			areaApi.Router.ServeHTTP(w, r)
+
+			Convey("Then the new name and the seeded ancestry are returned", func() {
+				So(w.Code, ShouldEqual, http.StatusOK)
+				So(w.Header().Get(models.ETagHeaderName), ShouldEqual, detailsETag(2, ancestors[SheffieldAreaData]))
+				var area models.AreasDataResults
+				So(json.Unmarshal(w.Body.Bytes(), &area), ShouldBeNil)
+				So(*area.Name, ShouldEqual, "City of Sheffield")
+				So(area.Ancestors, ShouldResemble, ancestors[SheffieldAreaData])
+			})
+		})
+
+		Convey("When an area's parent is renamed after the area was requested", func() {
+			get := func(headers map[string]string) *httptest.ResponseRecorder {
+				r := httptest.NewRequest(http.MethodGet, fmt.Sprintf("http://localhost:2200/v1/areas/%s", SheffieldAreaData), nil)
+				r.Header.Set(models.AcceptLanguageHeaderName, "en")
+				for name, value := range headers {
+					r.Header.Set(name, value)
+				}
+				w := httptest.NewRecorder()
+				areaApi.Router.ServeHTTP(w, r)
+				return w
+			}
+			before := get(nil)
+			So(before.Code, ShouldEqual, http.StatusOK)
+
+			patch := strings.NewReader(`[{"op": "replace", "path": "/area_name/name", "value": "Yorkshire"}]`)
+			r := httptest.NewRequest(http.MethodPatch, fmt.Sprintf("http://localhost:2200/v1/areas/%s", YorkshireAreaData), patch)
+			r.Header.Set(dprequest.FlorenceHeaderKey, auth.StubFlorenceToken)
+			r.Header.Set("Content-Type", models.JSONPatchContentType)
+			w := httptest.NewRecorder()
+			areaApi.Router.ServeHTTP(w, r)
+			So(w.Code, ShouldEqual, http.StatusOK)
+
+			w = get(map[string]string{
+				models.IfNoneMatchHeaderName:     before.Header().Get(models.ETagHeaderName),
+				models.IfModifiedSinceHeaderName: before.Header().Get(models.LastModifiedHeaderName),
+			})
+
+			Convey("Then a conditional request for the area returns it with the new ancestor name", func() {
+				So(w.Code, ShouldEqual, http.StatusOK)
+				So(w.Header().Get(models.ETagHeaderName), ShouldNotEqual, before.Header().Get(models.ETagHeaderName))
+				var area models.AreasDataResults
+				So(json.Unmarshal(w.Body.Bytes(), &area), ShouldBeNil)
+				So(area.Ancestors[0], ShouldResemble, models.AreasAncestors{Id: YorkshireAreaData, Name: "Yorkshire"})
+			})
+
+			Convey("Then an update conditional on the tag served before the rename still succeeds", func() {
+				body := strings.NewReader(`[{"op": "replace", "path": "/area_name/name", "value": "City of Sheffield"}]`)
+				r := httptest.NewRequest(http.MethodPatch, fmt.Sprintf("http://localhost:2200/v1/areas/%s", SheffieldAreaData), body)
+				r.Header.Set(dprequest.FlorenceHeaderKey, auth.StubFlorenceToken)
+				r.Header.Set("Content-Type", models.JSONPatchContentType)
+				r.Header.Set(models.IfMatchHeaderName, before.Header().Get(models.ETagHeaderName))
+				w := httptest.NewRecorder()
+				areaApi.Router.ServeHTTP(w, r)
+				So(w.Code, ShouldEqual, http.StatusOK)
+			})
+		})
+	})
+}
+
 func TestGetAreaRelationshipsFailsForInvalidIds(t *testing.T) {
 	Convey("Given a successful request to area relationship data - invalid", t, func() {
 		r := httptest.NewRequest(http.MethodGet, fmt.Sprintf("http://localhost:2200/v1/areas/%s/relations", "InvalidAreaCode"), nil)
@@ -281,7 +368,7 @@ func TestGetAreaRelationshipsFailsForInvalidIds(t *testing.T) {
 		w := httptest.NewRecorder()
 
 		areaApi, _ := GetAPIWithRDSMocks(&mock.RDSAreaStoreMock{
-			ValidateAreaFunc: func(areaCode string) error {
+			ValidateAreaFunc: func(ctx context.Context, areaCode string, includeInactive bool) error {
 				return apierrors.ErrNoRows
 			},
 		})
@@ -302,23 +389,23 @@ func TestGetAreaRelationshipsWithParameterReturnsOk(t *testing.T) {
 		r.Header.Set(models.AcceptLanguageHeaderName, "en")
 		w := httptest.NewRecorder()
 		childRelatedAreas := []*models.AreaBasicData{
-			{SheffieldAreaData, SheffieldName},
+			{Code: SheffieldAreaData, Name: SheffieldName},
 		}
 		relatedAreas := []*models.AreaBasicData{
-			{"E92000001", "North East"},
-			{"E12000002", "North West"},
-			{SheffieldAreaData, SheffieldName},
+			{Code: "E92000001", Name: "North East"},
+			{Code: "E12000002", Name: "North West"},
+			{Code: SheffieldAreaData, Name: SheffieldName},
 		}
 
 		expectedChildRelationShips := []*models.AreaRelationShips{
-			{SheffieldAreaData, SheffieldName, "/v1/area/E08000019"},
+			{AreaCode: SheffieldAreaData, AreaName: SheffieldName, Href: "/v1/area/E08000019"},
 		}
 
 		areaApi, _ := GetAPIWithRDSMocks(&mock.RDSAreaStoreMock{
-			ValidateAreaFunc: func(areaCode string) error {
+			ValidateAreaFunc: func(ctx context.Context, areaCode string, includeInactive bool) error {
 				return nil
 			},
-			GetRelationshipsFunc: func(areaCode, relationshipParameter string) ([]*models.AreaBasicData, error) {
+			GetRelationshipsFunc: func(ctx context.Context, areaCode, relationshipParameter string, includeInactive bool) ([]*models.AreaBasicData, error) {
 				if relationshipParameter == "child" {
 					return childRelatedAreas, nil
 				}
@@ -349,7 +436,7 @@ func TestGetAreaRelationshipsWithParameterFailsForInvalidIds(t *testing.T) {
 		w := httptest.NewRecorder()
 
 		areaApi, _ := GetAPIWithRDSMocks(&mock.RDSAreaStoreMock{
-			ValidateAreaFunc: func(areaCode string) error {
+			ValidateAreaFunc: func(ctx context.Context, areaCode string, includeInactive bool) error {
 				return apierrors.ErrNoRows
 			},
 		})
@@ -377,11 +464,8 @@ func TestGetAreaDataReturnsOKWithOrderedAncestryList(t *testing.T) {
 		w := httptest.NewRecorder()
 
 		areaApi, _ := GetAPIWithRDSMocks(&mock.RDSAreaStoreMock{
-			GetAreaFunc: func(ctx context.Context, areaId string) (*models.AreasDataResults, error) {
-				return &models.AreasDataResults{Code: SheffieldAreaData, Name: &SheffieldName, AreaType: &countryAreaType}, nil
-			},
-			GetAncestorsFunc: func(areaCode string) ([]models.AreasAncestors, error) {
-				return ancestors[SheffieldAreaData], nil
+			GetAreaDetailsFunc: func(ctx context.Context, areaCode string, opts models.AreaDetailsOptions) (*models.AreaDetails, error) {
+				return &models.AreaDetails{Area: &models.AreasDataResults{Code: SheffieldAreaData, Name: &SheffieldName, AreaType: &countryAreaType, Ancestors: ancestors[SheffieldAreaData]}}, nil
 			},
 		})
 		areaApi.Router.ServeHTTP(w, r)
@@ -413,11 +497,8 @@ func TestGetAreaDataReturnsOkForEmptyAncestorsList(t *testing.T) {
 		w := httptest.NewRecorder()
 
 		areaApi, _ := GetAPIWithRDSMocks(&mock.RDSAreaStoreMock{
-			GetAreaFunc: func(ctx context.Context, areaId string) (*models.AreasDataResults, error) {
-				return &models.AreasDataResults{Code: WalesAreaData, Name: &WalesName, AreaType: &countryAreaType}, nil
-			},
-			GetAncestorsFunc: func(areaCode string) ([]models.AreasAncestors, error) {
-				return ancestors[WalesAreaData], nil
+			GetAreaDetailsFunc: func(ctx context.Context, areaCode string, opts models.AreaDetailsOptions) (*models.AreaDetails, error) {
+				return &models.AreaDetails{Area: &models.AreasDataResults{Code: WalesAreaData, Name: &WalesName, AreaType: &countryAreaType, Ancestors: ancestors[WalesAreaData]}}, nil
 			},
 		})
 		areaApi.Router.ServeHTTP(w, r)
@@ -440,18 +521,18 @@ func TestGetAreaDataReturnsOkForEmptyAncestorsList(t *testing.T) {
 	})
 }
 
-func TestGetAreaDataFailsForAncestorsDataError(t *testing.T) {
-	Convey("Given a successful request to stubbed area data - Ancestors Data Error", t, func() {
+func TestGetAreaDataFailsForStoreError(t *testing.T) {
+	Convey("Given a successful request to stubbed area data - Store Error", t, func() {
 		r := httptest.NewRequest(http.MethodGet, fmt.Sprintf("http://localhost:2200/v1/areas/%s", SwanseaAirportBuaData), nil)
 		r.Header.Set(models.AcceptLanguageHeaderName, "cy")
 		w := httptest.NewRecorder()
 
 		areaApi, _ := GetAPIWithRDSMocks(&mock.RDSAreaStoreMock{
-			ValidateAreaFunc: func(areaCode string) error {
+			ValidateAreaFunc: func(ctx context.Context, areaCode string, includeInactive bool) error {
 				return nil
 			},
-			GetAncestorsFunc: func(areaCode string) ([]models.AreasAncestors, error) {
-				return ancestors[SwanseaAirportBuaData], apierrors.ErrInternalServer
+			GetAreaDetailsFunc: func(ctx context.Context, areaCode string, opts models.AreaDetailsOptions) (*models.AreaDetails, error) {
+				return nil, apierrors.ErrInternalServer
 			},
 		})
 		areaApi.Router.ServeHTTP(w, r)
@@ -464,7 +545,7 @@ func TestGetAreaDataFailsForAncestorsDataError(t *testing.T) {
 				_ = json.Unmarshal(payload, &responseBody)
 				So(len(responseBody["errors"].([]interface{})), ShouldEqual, 1)
 				error := responseBody["errors"].([]interface{})[0].(map[string]interface{})
-				So(error["code"], ShouldEqual, models.AncestryDataGetError)
+				So(error["code"], ShouldEqual, models.AreaDataIdGetError)
 				So(w.Code, ShouldEqual, http.StatusInternalServerError)
 			})
 		})
@@ -475,10 +556,11 @@ func TestUpdateAreaData(t *testing.T) {
 	Convey("Given a request to update a new area data - W92000004", t, func() {
 		reader := strings.NewReader(`{"area_name": {"name": "Wales", "active_from": "2022-01-01T00:00:00Z", "active_to": "2022-02-01T00:00:00Z"}}`)
 		r := httptest.NewRequest(http.MethodPut, fmt.Sprintf("http://localhost:2200/v1/areas/%s", "W92000004"), reader)
+		r.Header.Set(dprequest.FlorenceHeaderKey, auth.StubFlorenceToken)
 		w := httptest.NewRecorder()
 
 		areaApi, _ := GetAPIWithRDSMocks(&mock.RDSAreaStoreMock{
-			UpsertAreaFunc: func(ctx context.Context, area models.AreaParams) (bool, error) { return true, nil },
+			UpsertAreaFunc: func(ctx context.Context, area models.AreaParams, ifMatch string) (bool, error) { return true, nil },
 		})
 		areaApi.Router.ServeHTTP(w, r)
 
@@ -495,6 +577,7 @@ func TestUpdateAreaDataReturnsValidationError(t *testing.T) {
 	Convey("Given a request without area details area name details", t, func() {
 		reader := strings.NewReader(`{}`)
 		r := httptest.NewRequest(http.MethodPut, fmt.Sprintf("http://localhost:2200/v1/areas/%s", WalesAreaData), reader)
+		r.Header.Set(dprequest.FlorenceHeaderKey, auth.StubFlorenceToken)
 		w := httptest.NewRecorder()
 
 		areaApi, _ := GetAPIWithRDSMocks(&mock.RDSAreaStoreMock{})
@@ -519,6 +602,7 @@ func TestUpdateAreaDataReturnsValidationError(t *testing.T) {
 	Convey("Given invalid area name details", t, func() {
 		reader := strings.NewReader(`{"area_name":{}}`)
 		r := httptest.NewRequest(http.MethodPut, fmt.Sprintf("http://localhost:2200/v1/areas/%s", WalesAreaData), reader)
+		r.Header.Set(dprequest.FlorenceHeaderKey, auth.StubFlorenceToken)
 		w := httptest.NewRecorder()
 
 		areaApi, _ := GetAPIWithRDSMocks(&mock.RDSAreaStoreMock{})
@@ -549,6 +633,143 @@ func TestUpdateAreaDataReturnsValidationError(t *testing.T) {
 	})
 }
 
+func TestGetAreaDataIncludeInactive(t *testing.T) {
+	Convey("Given a request for a retired area with include_inactive set", t, func() {
+		r := httptest.NewRequest(http.MethodGet, fmt.Sprintf("http://localhost:2200/v1/areas/%s?include_inactive=true", SheffieldAreaData), nil)
+		r.Header.Set(models.AcceptLanguageHeaderName, "en")
+		w := httptest.NewRecorder()
+
+		rdsMock := &mock.RDSAreaStoreMock{
+			GetAreaDetailsFunc: func(ctx context.Context, areaCode string, opts models.AreaDetailsOptions) (*models.AreaDetails, error) {
+				return &models.AreaDetails{Area: &models.AreasDataResults{Code: SheffieldAreaData, Name: &SheffieldName, AreaType: &countryAreaType, Ancestors: ancestors[SheffieldAreaData]}}, nil
+			},
+		}
+		areaApi, _ := GetAPIWithRDSMocks(rdsMock)
+		areaApi.Router.ServeHTTP(w, r)
+
+		Convey("When request area data is served", func() {
+
+			Convey("Then retired areas are requested from the store", func() {
+				So(w.Code, ShouldEqual, http.StatusOK)
+				So(rdsMock.GetAreaDetailsCalls(), ShouldHaveLength, 1)
+				So(rdsMock.GetAreaDetailsCalls()[0].Opts.IncludeInactive, ShouldBeTrue)
+				So(rdsMock.GetAreaDetailsCalls()[0].Opts.Boundary, ShouldBeTrue)
+			})
+		})
+	})
+
+	Convey("Given a request for area relationships with an invalid include_inactive value", t, func() {
+		r := httptest.NewRequest(http.MethodGet, fmt.Sprintf("http://localhost:2200/v1/areas/%s/relations?include_inactive=maybe", EnglandAreaData), nil)
+		w := httptest.NewRecorder()
+
+		rdsMock := &mock.RDSAreaStoreMock{}
+		areaApi, _ := GetAPIWithRDSMocks(rdsMock)
+		areaApi.Router.ServeHTTP(w, r)
+
+		Convey("When request area relationship data is served", func() {
+
+			Convey("Then a 400 response is returned without querying the store", func() {
+				So(w.Code, ShouldEqual, http.StatusBadRequest)
+				So(rdsMock.ValidateAreaCalls(), ShouldHaveLength, 0)
+				payload, _ := ioutil.ReadAll(w.Body)
+				var responseBody map[string]interface{}
+				_ = json.Unmarshal(payload, &responseBody)
+				error := responseBody["errors"].([]interface{})[0].(map[string]interface{})
+				So(error["code"], ShouldEqual, models.InvalidQueryParameterError)
+			})
+		})
+	})
+}
+
+func TestRetireArea(t *testing.T) {
+	Convey("Given a request to retire an area without live children", t, func() {
+		r := httptest.NewRequest(http.MethodDelete, fmt.Sprintf("http://localhost:2200/v1/areas/%s", SheffieldAreaData), nil)
+		r.Header.Set(dprequest.FlorenceHeaderKey, auth.StubFlorenceToken)
+		w := httptest.NewRecorder()
+
+		rdsMock := &mock.RDSAreaStoreMock{
+			RetireAreaFunc: func(ctx context.Context, areaCode string, cascade bool, ifMatch string) error { return nil },
+		}
+		areaApi, _ := GetAPIWithRDSMocks(rdsMock)
+		areaApi.Router.ServeHTTP(w, r)
+
+		Convey("When retire area is served", func() {
+
+			Convey("Then a 204 response is returned", func() {
+				So(w.Code, ShouldEqual, http.StatusNoContent)
+				So(rdsMock.RetireAreaCalls(), ShouldHaveLength, 1)
+				So(rdsMock.RetireAreaCalls()[0].AreaCode, ShouldEqual, SheffieldAreaData)
+				So(rdsMock.RetireAreaCalls()[0].Cascade, ShouldBeFalse)
+			})
+		})
+	})
+
+	Convey("Given a request to retire an area and its descendants", t, func() {
+		r := httptest.NewRequest(http.MethodDelete, fmt.Sprintf("http://localhost:2200/v1/areas/%s?cascade=true", YorkshireAreaData), nil)
+		r.Header.Set(dprequest.FlorenceHeaderKey, auth.StubFlorenceToken)
+		w := httptest.NewRecorder()
+
+		rdsMock := &mock.RDSAreaStoreMock{
+			RetireAreaFunc: func(ctx context.Context, areaCode string, cascade bool, ifMatch string) error { return nil },
+		}
+		areaApi, _ := GetAPIWithRDSMocks(rdsMock)
+		areaApi.Router.ServeHTTP(w, r)
+
+		Convey("When retire area is served", func() {
+
+			Convey("Then the cascade flag is passed to the store", func() {
+				So(w.Code, ShouldEqual, http.StatusNoContent)
+				So(rdsMock.RetireAreaCalls()[0].Cascade, ShouldBeTrue)
+			})
+		})
+	})
+
+	Convey("Given a request to retire an area with live children", t, func() {
+		r := httptest.NewRequest(http.MethodDelete, fmt.Sprintf("http://localhost:2200/v1/areas/%s", YorkshireAreaData), nil)
+		r.Header.Set(dprequest.FlorenceHeaderKey, auth.StubFlorenceToken)
+		w := httptest.NewRecorder()
+
+		areaApi, _ := GetAPIWithRDSMocks(&mock.RDSAreaStoreMock{
+			RetireAreaFunc: func(ctx context.Context, areaCode string, cascade bool, ifMatch string) error {
+				return apierrors.ErrAreaHasLiveChildren
+			},
+		})
+		areaApi.Router.ServeHTTP(w, r)
+
+		Convey("When retire area is served", func() {
+
+			Convey("Then a 409 response is returned", func() {
+				So(w.Code, ShouldEqual, http.StatusConflict)
+				payload, _ := ioutil.ReadAll(w.Body)
+				var responseBody map[string]interface{}
+				_ = json.Unmarshal(payload, &responseBody)
+				error := responseBody["errors"].([]interface{})[0].(map[string]interface{})
+				So(error["code"], ShouldEqual, models.AreaHasLiveChildrenError)
+			})
+		})
+	})
+
+	Convey("Given a request to retire an unknown area", t, func() {
+		r := httptest.NewRequest(http.MethodDelete, fmt.Sprintf("http://localhost:2200/v1/areas/%s", "InvalidAreaCode"), nil)
+		r.Header.Set(dprequest.FlorenceHeaderKey, auth.StubFlorenceToken)
+		w := httptest.NewRecorder()
+
+		areaApi, _ := GetAPIWithRDSMocks(&mock.RDSAreaStoreMock{
+			RetireAreaFunc: func(ctx context.Context, areaCode string, cascade bool, ifMatch string) error {
+				return apierrors.ErrNoRows
+			},
+		})
+		areaApi.Router.ServeHTTP(w, r)
+
+		Convey("When retire area is served", func() {
+
+			Convey("Then a 404 response is returned", func() {
+				So(w.Code, ShouldEqual, http.StatusNotFound)
+			})
+		})
+	})
+}
+
 func testGeometricData() [][][2]float64 {
 	var gd [][][2]float64
 	gd = make([][][2]float64, 1)
@@ -561,3 +782,454 @@ func testGeometricData() [][][2]float64 {
 	}
 	return gd
 }
+
+func TestPatchArea(t *testing.T) {
+	Convey("Given a merge patch request for an existing area", t, func() {
+		r := httptest.NewRequest(http.MethodPatch, fmt.Sprintf("http://localhost:2200/v1/areas/%s", SheffieldAreaData), strings.NewReader(`{"visible": false}`))
+		r.Header.Set(dprequest.FlorenceHeaderKey, auth.StubFlorenceToken)
+		r.Header.Set("Content-Type", models.MergePatchContentType)
+		w := httptest.NewRecorder()
+
+		rdsMock := &mock.RDSAreaStoreMock{
+			PatchAreaFunc: func(ctx context.Context, areaCode string, patch models.AreaPatch, ifMatch string) error { return nil },
+		}
+		areaApi, _ := GetAPIWithRDSMocks(rdsMock)
+		areaApi.Router.ServeHTTP(w, r)
+
+		Convey("When patch area is served", func() {
+
+			Convey("Then the patch is passed to the store and a 200 response is returned", func() {
+				So(w.Code, ShouldEqual, http.StatusOK)
+				So(rdsMock.PatchAreaCalls(), ShouldHaveLength, 1)
+				So(rdsMock.PatchAreaCalls()[0].AreaCode, ShouldEqual, SheffieldAreaData)
+				So(rdsMock.PatchAreaCalls()[0].Patch, ShouldResemble, models.AreaPatch{
+					{Op: models.PatchOpReplace, Path: "/visible", Value: []byte(`false`)},
+				})
+			})
+		})
+	})
+
+	Convey("Given a JSON Patch request for a path that cannot be patched", t, func() {
+		r := httptest.NewRequest(http.MethodPatch, fmt.Sprintf("http://localhost:2200/v1/areas/%s", SheffieldAreaData), strings.NewReader(`[{"op": "replace", "path": "/code", "value": "E08000020"}]`))
+		r.Header.Set(dprequest.FlorenceHeaderKey, auth.StubFlorenceToken)
+		r.Header.Set("Content-Type", models.JSONPatchContentType)
+		w := httptest.NewRecorder()
+
+		rdsMock := &mock.RDSAreaStoreMock{}
+		areaApi, _ := GetAPIWithRDSMocks(rdsMock)
+		areaApi.Router.ServeHTTP(w, r)
+
+		Convey("When patch area is served", func() {
+
+			Convey("Then a 400 response is returned without touching the store", func() {
+				So(w.Code, ShouldEqual, http.StatusBadRequest)
+				So(rdsMock.PatchAreaCalls(), ShouldHaveLength, 0)
+				payload, _ := ioutil.ReadAll(w.Body)
+				var responseBody map[string]interface{}
+				_ = json.Unmarshal(payload, &responseBody)
+				error := responseBody["errors"].([]interface{})[0].(map[string]interface{})
+				So(error["code"], ShouldEqual, models.InvalidPatchError)
+			})
+		})
+	})
+
+	Convey("Given a patch that leaves the area invalid", t, func() {
+		r := httptest.NewRequest(http.MethodPatch, fmt.Sprintf("http://localhost:2200/v1/areas/%s", SheffieldAreaData), strings.NewReader(`{"area_name": {"active_to": null}}`))
+		r.Header.Set(dprequest.FlorenceHeaderKey, auth.StubFlorenceToken)
+		w := httptest.NewRecorder()
+
+		areaApi, _ := GetAPIWithRDSMocks(&mock.RDSAreaStoreMock{
+			PatchAreaFunc: func(ctx context.Context, areaCode string, patch models.AreaPatch, ifMatch string) error {
+				return fmt.Errorf("%w: area has no name", apierrors.ErrInvalidAreaPatch)
+			},
+		})
+		areaApi.Router.ServeHTTP(w, r)
+
+		Convey("When patch area is served", func() {
+
+			Convey("Then a 400 response is returned", func() {
+				So(w.Code, ShouldEqual, http.StatusBadRequest)
+			})
+		})
+	})
+
+	Convey("Given a patch request for an unknown area", t, func() {
+		r := httptest.NewRequest(http.MethodPatch, fmt.Sprintf("http://localhost:2200/v1/areas/%s", "InvalidAreaCode"), strings.NewReader(`{"visible": true}`))
+		r.Header.Set(dprequest.FlorenceHeaderKey, auth.StubFlorenceToken)
+		w := httptest.NewRecorder()
+
+		areaApi, _ := GetAPIWithRDSMocks(&mock.RDSAreaStoreMock{
+			PatchAreaFunc: func(ctx context.Context, areaCode string, patch models.AreaPatch, ifMatch string) error {
+				return apierrors.ErrNoRows
+			},
+		})
+		areaApi.Router.ServeHTTP(w, r)
+
+		Convey("When patch area is served", func() {
+
+			Convey("Then a 404 response is returned", func() {
+				So(w.Code, ShouldEqual, http.StatusNotFound)
+			})
+		})
+	})
+}
+
+func TestBulkUpsertAreas(t *testing.T) {
+	bulkBody := `{"code": "E08000019", "parent_code": "E12000003", "area_name": {"name": "Sheffield", "active_from": "2022-01-01T00:00:00Z", "active_to": "2022-12-31T00:00:00Z"}}
+{"code": "E12000003", "area_name": {"name": "Yorkshire and the Humber", "active_from": "2022-01-01T00:00:00Z", "active_to": "2022-12-31T00:00:00Z"}}
+`
+
+	Convey("Given a valid NDJSON bulk request with a child before its parent", t, func() {
+		r := httptest.NewRequest(http.MethodPost, "http://localhost:2200/v1/areas:bulk", strings.NewReader(bulkBody))
+		r.Header.Set(dprequest.FlorenceHeaderKey, auth.StubFlorenceToken)
+		r.Header.Set("Content-Type", models.NDJSONContentType)
+		w := httptest.NewRecorder()
+
+		rdsMock := &mock.RDSAreaStoreMock{
+			BulkUpsertAreasFunc: func(ctx context.Context, areas []models.AreaParams, batchSize int) ([]models.BulkAreaResult, error) {
+				results := make([]models.BulkAreaResult, len(areas))
+				for i, area := range areas {
+					results[i] = models.BulkAreaResult{Index: i, Code: area.Code, Status: models.BulkAreaCreated}
+				}
+				return results, nil
+			},
+		}
+		areaApi, _ := GetAPIWithRDSMocks(rdsMock)
+		areaApi.Router.ServeHTTP(w, r)
+
+		Convey("When bulk upsert is served", func() {
+
+			Convey("Then the parent is applied first and results are reported by request index", func() {
+				So(w.Code, ShouldEqual, http.StatusOK)
+				So(rdsMock.BulkUpsertAreasCalls(), ShouldHaveLength, 1)
+				applied := rdsMock.BulkUpsertAreasCalls()[0].Areas
+				So(applied[0].Code, ShouldEqual, YorkshireAreaData)
+				So(applied[1].Code, ShouldEqual, SheffieldAreaData)
+
+				var response models.BulkAreaResponse
+				payload, _ := ioutil.ReadAll(w.Body)
+				So(json.Unmarshal(payload, &response), ShouldBeNil)
+				So(response.Results, ShouldResemble, []models.BulkAreaResult{
+					{Index: 0, Code: SheffieldAreaData, Status: models.BulkAreaCreated},
+					{Index: 1, Code: YorkshireAreaData, Status: models.BulkAreaCreated},
+				})
+			})
+		})
+	})
+
+	Convey("Given a bulk request containing an invalid area", t, func() {
+		r := httptest.NewRequest(http.MethodPost, "http://localhost:2200/v1/areas:bulk", strings.NewReader(bulkBody+`{"code": "E08000018"}`))
+		r.Header.Set(dprequest.FlorenceHeaderKey, auth.StubFlorenceToken)
+		w := httptest.NewRecorder()
+
+		rdsMock := &mock.RDSAreaStoreMock{}
+		areaApi, _ := GetAPIWithRDSMocks(rdsMock)
+		areaApi.Router.ServeHTTP(w, r)
+
+		Convey("When bulk upsert is served", func() {
+
+			Convey("Then a 400 response is returned and nothing is written", func() {
+				So(w.Code, ShouldEqual, http.StatusBadRequest)
+				So(rdsMock.BulkUpsertAreasCalls(), ShouldHaveLength, 0)
+				payload, _ := ioutil.ReadAll(w.Body)
+				var responseBody map[string]interface{}
+				_ = json.Unmarshal(payload, &responseBody)
+				result := responseBody["results"].([]interface{})[0].(map[string]interface{})
+				So(result["index"], ShouldEqual, 2)
+				So(result["status"], ShouldEqual, models.BulkAreaInvalid)
+			})
+		})
+	})
+
+	Convey("Given a bulk request that fails part way through", t, func() {
+		r := httptest.NewRequest(http.MethodPost, "http://localhost:2200/v1/areas:bulk", strings.NewReader(bulkBody))
+		r.Header.Set(dprequest.FlorenceHeaderKey, auth.StubFlorenceToken)
+		w := httptest.NewRecorder()
+
+		areaApi, _ := GetAPIWithRDSMocks(&mock.RDSAreaStoreMock{
+			BulkUpsertAreasFunc: func(ctx context.Context, areas []models.AreaParams, batchSize int) ([]models.BulkAreaResult, error) {
+				return []models.BulkAreaResult{
+					{Index: 0, Code: areas[0].Code, Status: models.BulkAreaRolledBack},
+					{Index: 1, Code: areas[1].Code, Status: models.BulkAreaFailed},
+				}, errors.New("failed to upsert into area")
+			},
+		})
+		areaApi.Router.ServeHTTP(w, r)
+
+		Convey("When bulk upsert is served", func() {
+
+			Convey("Then a 500 response reports the outcome of each area", func() {
+				So(w.Code, ShouldEqual, http.StatusInternalServerError)
+				var response models.BulkAreaResponse
+				payload, _ := ioutil.ReadAll(w.Body)
+				So(json.Unmarshal(payload, &response), ShouldBeNil)
+				So(response.Results[0].Status, ShouldEqual, models.BulkAreaFailed)
+				So(response.Results[1].Status, ShouldEqual, models.BulkAreaRolledBack)
+			})
+		})
+	})
+
+	Convey("Given a bulk request larger than the service accepts", t, func() {
+		r := httptest.NewRequest(http.MethodPost, "http://localhost:2200/v1/areas:bulk", strings.NewReader(bulkBody))
+		r.Header.Set(dprequest.FlorenceHeaderKey, auth.StubFlorenceToken)
+		w := httptest.NewRecorder()
+
+		rdsMock := &mock.RDSAreaStoreMock{}
+		cfg := &config.Config{FixturesVersion: "v1", EnablePrivateEndpoints: true, BulkUpsertMaxBodyBytes: int64(len(bulkBody) - 1)}
+		areaApi, err := api.Setup(context.Background(), cfg, mux.NewRouter(), rdsMock, auth.NewStubVerifier())
+		So(err, ShouldBeNil)
+		areaApi.Router.ServeHTTP(w, r)
+
+		Convey("When bulk upsert is served", func() {
+
+			Convey("Then a 413 response is returned and nothing is written", func() {
+				So(w.Code, ShouldEqual, http.StatusRequestEntityTooLarge)
+				So(rdsMock.BulkUpsertAreasCalls(), ShouldHaveLength, 0)
+				payload, _ := ioutil.ReadAll(w.Body)
+				var responseBody map[string]interface{}
+				_ = json.Unmarshal(payload, &responseBody)
+				error := responseBody["errors"].([]interface{})[0].(map[string]interface{})
+				So(error["code"], ShouldEqual, models.RequestBodyTooLargeError)
+			})
+		})
+	})
+}
+
+func TestAreaETags(t *testing.T) {
+	Convey("Given a request for an area", t, func() {
+		r := httptest.NewRequest(http.MethodGet, fmt.Sprintf("http://localhost:2200/v1/areas/%s", SheffieldAreaData), nil)
+		r.Header.Set(models.AcceptLanguageHeaderName, "en")
+		w := httptest.NewRecorder()
+
+		areaApi, _ := GetAPIWithRDSMocks(&mock.RDSAreaStoreMock{
+			GetAreaDetailsFunc: func(ctx context.Context, areaCode string, opts models.AreaDetailsOptions) (*models.AreaDetails, error) {
+				return &models.AreaDetails{Area: &models.AreasDataResults{Code: SheffieldAreaData, Name: &SheffieldName, AreaType: &countryAreaType, Version: 4, Ancestors: ancestors[SheffieldAreaData]}}, nil
+			},
+		})
+		areaApi.Router.ServeHTTP(w, r)
+
+		Convey("When request area data is served", func() {
+
+			Convey("Then the ETag of the area's version and ancestry is returned", func() {
+				So(w.Code, ShouldEqual, http.StatusOK)
+				So(w.Header().Get(models.ETagHeaderName), ShouldEqual, detailsETag(4, ancestors[SheffieldAreaData]))
+			})
+		})
+	})
+
+	Convey("Given an update made with a stale If-Match", t, func() {
+		r := httptest.NewRequest(http.MethodPut, fmt.Sprintf("http://localhost:2200/v1/areas/%s", SheffieldAreaData), strings.NewReader(`{"area_name": {"name": "Sheffield", "active_from": "2022-01-01T00:00:00Z", "active_to": "2022-12-31T00:00:00Z"}}`))
+		r.Header.Set(dprequest.FlorenceHeaderKey, auth.StubFlorenceToken)
+		r.Header.Set(models.IfMatchHeaderName, `"3"`)
+		w := httptest.NewRecorder()
+
+		rdsMock := &mock.RDSAreaStoreMock{
+			UpsertAreaFunc: func(ctx context.Context, area models.AreaParams, ifMatch string) (bool, error) {
+				return false, apierrors.ErrPreconditionFailed
+			},
+		}
+		areaApi, _ := GetAPIWithRDSMocks(rdsMock)
+		areaApi.Router.ServeHTTP(w, r)
+
+		Convey("When update area is served", func() {
+
+			Convey("Then the If-Match value is checked by the store and a 412 response is returned", func() {
+				So(rdsMock.UpsertAreaCalls()[0].IfMatch, ShouldEqual, `"3"`)
+				So(w.Code, ShouldEqual, http.StatusPreconditionFailed)
+				payload, _ := ioutil.ReadAll(w.Body)
+				var responseBody map[string]interface{}
+				_ = json.Unmarshal(payload, &responseBody)
+				error := responseBody["errors"].([]interface{})[0].(map[string]interface{})
+				So(error["code"], ShouldEqual, models.PreconditionFailedError)
+			})
+		})
+	})
+
+	Convey("Given a patch made with a stale If-Match", t, func() {
+		r := httptest.NewRequest(http.MethodPatch, fmt.Sprintf("http://localhost:2200/v1/areas/%s", SheffieldAreaData), strings.NewReader(`{"visible": false}`))
+		r.Header.Set(dprequest.FlorenceHeaderKey, auth.StubFlorenceToken)
+		r.Header.Set(models.IfMatchHeaderName, `"3"`)
+		w := httptest.NewRecorder()
+
+		areaApi, _ := GetAPIWithRDSMocks(&mock.RDSAreaStoreMock{
+			PatchAreaFunc: func(ctx context.Context, areaCode string, patch models.AreaPatch, ifMatch string) error {
+				return apierrors.ErrPreconditionFailed
+			},
+		})
+		areaApi.Router.ServeHTTP(w, r)
+
+		Convey("When patch area is served", func() {
+
+			Convey("Then a 412 response is returned", func() {
+				So(w.Code, ShouldEqual, http.StatusPreconditionFailed)
+			})
+		})
+	})
+
+	Convey("Given a retire request made with a stale If-Match", t, func() {
+		r := httptest.NewRequest(http.MethodDelete, fmt.Sprintf("http://localhost:2200/v1/areas/%s", SheffieldAreaData), nil)
+		r.Header.Set(dprequest.FlorenceHeaderKey, auth.StubFlorenceToken)
+		r.Header.Set(models.IfMatchHeaderName, `"3"`)
+		w := httptest.NewRecorder()
+
+		areaApi, _ := GetAPIWithRDSMocks(&mock.RDSAreaStoreMock{
+			RetireAreaFunc: func(ctx context.Context, areaCode string, cascade bool, ifMatch string) error {
+				return apierrors.ErrPreconditionFailed
+			},
+		})
+		areaApi.Router.ServeHTTP(w, r)
+
+		Convey("When retire area is served", func() {
+
+			Convey("Then a 412 response is returned", func() {
+				So(w.Code, ShouldEqual, http.StatusPreconditionFailed)
+			})
+		})
+	})
+}
+
+func TestStoreContextErrors(t *testing.T) {
+	Convey("Given a store call that exceeds the query timeout", t, func() {
+		r := httptest.NewRequest(http.MethodGet, fmt.Sprintf("http://localhost:2200/v1/areas/%s/relations", EnglandAreaData), nil)
+		w := httptest.NewRecorder()
+
+		areaApi, _ := GetAPIWithRDSMocks(&mock.RDSAreaStoreMock{
+			ValidateAreaFunc: func(ctx context.Context, areaCode string, includeInactive bool) error {
+				return nil
+			},
+			GetRelationshipsFunc: func(ctx context.Context, areaCode, relationshipParameter string, includeInactive bool) ([]*models.AreaBasicData, error) {
+				return nil, fmt.Errorf("%w: timeout: conn closed", context.DeadlineExceeded)
+			},
+		})
+		areaApi.Router.ServeHTTP(w, r)
+
+		Convey("When the request is served", func() {
+
+			Convey("Then a 504 response with the query timeout code is returned", func() {
+				So(w.Code, ShouldEqual, http.StatusGatewayTimeout)
+				var responseBody map[string]interface{}
+				So(json.Unmarshal(w.Body.Bytes(), &responseBody), ShouldBeNil)
+				error := responseBody["errors"].([]interface{})[0].(map[string]interface{})
+				So(error["code"], ShouldEqual, models.QueryTimeoutError)
+			})
+		})
+	})
+
+	Convey("Given a request that is cancelled before the store responds", t, func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		r := httptest.NewRequest(http.MethodGet, fmt.Sprintf("http://localhost:2200/v1/areas/%s", EnglandAreaData), nil).WithContext(ctx)
+		r.Header.Set(models.AcceptLanguageHeaderName, "en")
+		w := httptest.NewRecorder()
+
+		rdsMock := &mock.RDSAreaStoreMock{
+			GetAreaDetailsFunc: func(ctx context.Context, areaCode string, opts models.AreaDetailsOptions) (*models.AreaDetails, error) {
+				cancel()
+				return nil, ctx.Err()
+			},
+		}
+		areaApi, _ := GetAPIWithRDSMocks(rdsMock)
+		areaApi.Router.ServeHTTP(w, r)
+
+		Convey("When the request is served", func() {
+
+			Convey("Then the store is given the request context and a 503 response with the cancelled code is returned", func() {
+				So(rdsMock.GetAreaDetailsCalls()[0].Ctx.Err(), ShouldEqual, context.Canceled)
+				So(w.Code, ShouldEqual, http.StatusServiceUnavailable)
+				var responseBody map[string]interface{}
+				So(json.Unmarshal(w.Body.Bytes(), &responseBody), ShouldBeNil)
+				error := responseBody["errors"].([]interface{})[0].(map[string]interface{})
+				So(error["code"], ShouldEqual, models.QueryCancelledError)
+			})
+		})
+	})
+
+	Convey("Given an update that exceeds the query timeout", t, func() {
+		r := httptest.NewRequest(http.MethodPut, fmt.Sprintf("http://localhost:2200/v1/areas/%s", SheffieldAreaData), strings.NewReader(`{"area_name": {"name": "Sheffield", "active_from": "2022-01-01T00:00:00Z", "active_to": "2022-12-31T00:00:00Z"}}`))
+		r.Header.Set(dprequest.FlorenceHeaderKey, auth.StubFlorenceToken)
+		w := httptest.NewRecorder()
+
+		areaApi, _ := GetAPIWithRDSMocks(&mock.RDSAreaStoreMock{
+			UpsertAreaFunc: func(ctx context.Context, area models.AreaParams, ifMatch string) (bool, error) {
+				return false, context.DeadlineExceeded
+			},
+		})
+		areaApi.Router.ServeHTTP(w, r)
+
+		Convey("When the request is served", func() {
+
+			Convey("Then a 504 response is returned", func() {
+				So(w.Code, ShouldEqual, http.StatusGatewayTimeout)
+			})
+		})
+	})
+}
+
+func TestStoreClassifiedErrors(t *testing.T) {
+	updateBody := `{"area_name": {"name": "Sheffield", "active_from": "2022-01-01T00:00:00Z", "active_to": "2022-12-31T00:00:00Z"}, "parent_code": "E12000099"}`
+
+	tests := []struct {
+		name   string
+		err    error
+		status int
+		code   string
+	}{
+		{"an unknown parent", &apierrors.StoreError{Kind: apierrors.ErrInvalidReference, Constraint: "area_relationship_area_code_fkey", Err: errors.New("violates foreign key constraint")}, http.StatusUnprocessableEntity, models.InvalidReferenceError},
+		{"a duplicate name", apierrors.NewStoreError(apierrors.ErrConflict, errors.New("violates unique constraint")), http.StatusConflict, models.DataConflictError},
+		{"invalid data", apierrors.NewStoreError(apierrors.ErrInvalidData, errors.New("value too long")), http.StatusUnprocessableEntity, models.InvalidDataError},
+		{"an unavailable database", apierrors.NewStoreError(apierrors.ErrUnavailable, errors.New("connection refused")), http.StatusServiceUnavailable, models.DatabaseUnavailableError},
+		{"a statement timeout", apierrors.NewStoreError(apierrors.ErrTimeout, errors.New("canceling statement due to statement timeout")), http.StatusGatewayTimeout, models.QueryTimeoutError},
+		{"an unclassified error", errors.New("failed to commit"), http.StatusInternalServerError, models.AreaDataIdUpsertError},
+	}
+
+	for _, tc := range tests {
+		Convey(fmt.Sprintf("Given an update that fails with %s", tc.name), t, func() {
+			r := httptest.NewRequest(http.MethodPut, fmt.Sprintf("http://localhost:2200/v1/areas/%s", SheffieldAreaData), strings.NewReader(updateBody))
+			r.Header.Set(dprequest.FlorenceHeaderKey, auth.StubFlorenceToken)
+			w := httptest.NewRecorder()
+
+			areaApi, _ := GetAPIWithRDSMocks(&mock.RDSAreaStoreMock{
+				UpsertAreaFunc: func(ctx context.Context, area models.AreaParams, ifMatch string) (bool, error) {
+					return false, tc.err
+				},
+			})
+			areaApi.Router.ServeHTTP(w, r)
+
+			Convey("When the request is served", func() {
+
+				Convey(fmt.Sprintf("Then a %d response with the %s code is returned", tc.status, tc.code), func() {
+					So(w.Code, ShouldEqual, tc.status)
+					var responseBody map[string]interface{}
+					So(json.Unmarshal(w.Body.Bytes(), &responseBody), ShouldBeNil)
+					error := responseBody["errors"].([]interface{})[0].(map[string]interface{})
+					So(error["code"], ShouldEqual, tc.code)
+				})
+			})
+		})
+	}
+
+	Convey("Given a patch that renames an area to a name it already has for the same period", t, func() {
+		r := httptest.NewRequest(http.MethodPatch, fmt.Sprintf("http://localhost:2200/v1/areas/%s", SheffieldAreaData), strings.NewReader(`[{"op": "replace", "path": "/area_name/name", "value": "Sheffield City"}]`))
+		r.Header.Set(dprequest.FlorenceHeaderKey, auth.StubFlorenceToken)
+		r.Header.Set("Content-Type", "application/json-patch+json")
+		w := httptest.NewRecorder()
+
+		areaApi, _ := GetAPIWithRDSMocks(&mock.RDSAreaStoreMock{
+			PatchAreaFunc: func(ctx context.Context, areaCode string, patch models.AreaPatch, ifMatch string) error {
+				return &apierrors.StoreError{Kind: apierrors.ErrConflict, Constraint: "area_name_area_code_name_active_from_idx", Err: errors.New("violates unique constraint")}
+			},
+		})
+		areaApi.Router.ServeHTTP(w, r)
+
+		Convey("When the request is served", func() {
+
+			Convey("Then a 409 response naming the constraint is returned", func() {
+				So(w.Code, ShouldEqual, http.StatusConflict)
+				var responseBody map[string]interface{}
+				So(json.Unmarshal(w.Body.Bytes(), &responseBody), ShouldBeNil)
+				error := responseBody["errors"].([]interface{})[0].(map[string]interface{})
+				So(error["code"], ShouldEqual, models.DataConflictError)
+				So(error["description"], ShouldEqual, models.DataConflictErrorDescription+" (area_name_area_code_name_active_from_idx)")
+			})
+		})
+	})
+}
@@ -0,0 +1,111 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ONSdigital/dp-areas-api/apierrors"
+	"github.com/ONSdigital/dp-areas-api/models"
+	"github.com/ONSdigital/log.go/v2/log"
+	"github.com/gorilla/mux"
+)
+
+const (
+	offsetQueryParameter = "offset"
+	limitQueryParameter  = "limit"
+	fromQueryParameter   = "from"
+	toQueryParameter     = "to"
+)
+
+// getAreaAudit is a handler that returns a page of the changes made to an area, most recent first
+func (api *API) getAreaAudit(ctx context.Context, _ http.ResponseWriter, req *http.Request) (*models.SuccessResponse, *models.ErrorResponse) {
+	areaID := mux.Vars(req)["id"]
+
+	query, errResponse := getAreaAuditQuery(ctx, req)
+	if errResponse != nil {
+		return nil, errResponse
+	}
+
+	// the log of a retired area is still served, but not of an area that never existed
+	err := api.rdsAreaStore.ValidateArea(ctx, areaID, true)
+	if err != nil {
+		return nil, models.NewDBReadError(ctx, err)
+	}
+
+	page, err := api.rdsAreaStore.GetAreaAudit(ctx, areaID, query)
+	if err != nil {
+		if errorResponse := models.NewStoreErrorResponse(ctx, err); errorResponse != nil {
+			return nil, errorResponse
+		}
+		responseErr := models.NewError(ctx, err, models.AreaAuditGetError, err.Error())
+		return nil, models.NewErrorResponse(http.StatusInternalServerError, nil, responseErr)
+	}
+	log.Info(ctx, "area audit retrieved", log.Data{"area-id": areaID, "count": page.Count, "total_count": page.TotalCount})
+
+	jsonResponse, err := json.Marshal(page)
+	if err != nil {
+		responseErr := models.NewError(ctx, err, models.MarshallingAreaAuditError, err.Error())
+		return nil, models.NewErrorResponse(http.StatusInternalServerError, nil, responseErr)
+	}
+
+	return models.NewSuccessResponse(jsonResponse, http.StatusOK, nil), nil
+}
+
+// getAreaAuditQuery reads the page and the dates of an audit log request, defaulting to the first page of
+// models.DefaultAreaAuditLimit changes of any date
+func getAreaAuditQuery(ctx context.Context, req *http.Request) (models.AreaAuditQuery, *models.ErrorResponse) {
+	query := models.AreaAuditQuery{Limit: models.DefaultAreaAuditLimit}
+
+	var errResponse *models.ErrorResponse
+	if query.Offset, errResponse = getIntQueryParameter(ctx, req, offsetQueryParameter, query.Offset); errResponse != nil {
+		return query, errResponse
+	}
+	if query.Limit, errResponse = getIntQueryParameter(ctx, req, limitQueryParameter, query.Limit); errResponse != nil {
+		return query, errResponse
+	}
+	if query.Limit > models.MaxAreaAuditLimit {
+		description := fmt.Sprintf("%s: %s must not exceed %d", models.InvalidQueryParameterErrorDescription, limitQueryParameter, models.MaxAreaAuditLimit)
+		responseErr := models.NewError(ctx, apierrors.ErrQueryParamLimitExceedMax, models.InvalidQueryParameterError, description)
+		return query, models.NewErrorResponse(http.StatusBadRequest, nil, responseErr)
+	}
+
+	if query.From, errResponse = getTimeQueryParameter(ctx, req, fromQueryParameter); errResponse != nil {
+		return query, errResponse
+	}
+	if query.To, errResponse = getTimeQueryParameter(ctx, req, toQueryParameter); errResponse != nil {
+		return query, errResponse
+	}
+	return query, nil
+}
+
+// getIntQueryParameter reads a query parameter that must be a non-negative integer, returning def when it is not set
+func getIntQueryParameter(ctx context.Context, req *http.Request, name string, def int) (int, *models.ErrorResponse) {
+	value := req.URL.Query().Get(name)
+	if value == "" {
+		return def, nil
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil || parsed < 0 {
+		responseErr := models.NewValidationError(ctx, models.InvalidQueryParameterError, fmt.Sprintf("%s: %s", models.InvalidQueryParameterErrorDescription, name))
+		return def, models.NewErrorResponse(http.StatusBadRequest, nil, responseErr)
+	}
+	return parsed, nil
+}
+
+// getTimeQueryParameter reads a query parameter that must be an RFC 3339 timestamp, returning nil when it is not set
+func getTimeQueryParameter(ctx context.Context, req *http.Request, name string) (*time.Time, *models.ErrorResponse) {
+	value := req.URL.Query().Get(name)
+	if value == "" {
+		return nil, nil
+	}
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		responseErr := models.NewValidationError(ctx, models.InvalidQueryParameterError, fmt.Sprintf("%s: %s", models.InvalidQueryParameterErrorDescription, name))
+		return nil, models.NewErrorResponse(http.StatusBadRequest, nil, responseErr)
+	}
+	return &parsed, nil
+}
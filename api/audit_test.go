@@ -0,0 +1,136 @@
+package api_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ONSdigital/dp-areas-api/api"
+	"github.com/ONSdigital/dp-areas-api/api/mock"
+	"github.com/ONSdigital/dp-areas-api/apierrors"
+	"github.com/ONSdigital/dp-areas-api/auth"
+	"github.com/ONSdigital/dp-areas-api/config"
+	"github.com/ONSdigital/dp-areas-api/models"
+	dprequest "github.com/ONSdigital/dp-net/request"
+	"github.com/gorilla/mux"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestGetAreaAudit(t *testing.T) {
+	changedAt := time.Date(2022, 3, 1, 12, 0, 0, 0, time.UTC)
+	entry := models.AreaAuditEntry{
+		ID:        1,
+		AreaCode:  "E05000001",
+		Actor:     "publisher@ons.gov.uk",
+		Operation: models.AreaAuditCreate,
+		ChangedAt: changedAt,
+		After:     &models.AreaAuditState{Code: "E05000001", Version: 1, Names: []models.AreaName{{Name: "Ward One"}}},
+	}
+
+	newStore := func() *mock.RDSAreaStoreMock {
+		return &mock.RDSAreaStoreMock{
+			ValidateAreaFunc: func(ctx context.Context, code string, includeInactive bool) error {
+				if code != "E05000001" {
+					return apierrors.ErrNoRows
+				}
+				return nil
+			},
+			GetAreaAuditFunc: func(ctx context.Context, areaCode string, query models.AreaAuditQuery) (*models.AreaAuditPage, error) {
+				return &models.AreaAuditPage{Count: 1, Offset: query.Offset, Limit: query.Limit, TotalCount: 1, Items: []models.AreaAuditEntry{entry}}, nil
+			},
+		}
+	}
+
+	get := func(store *mock.RDSAreaStoreMock, url string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, url, nil)
+		r.Header.Set(dprequest.FlorenceHeaderKey, auth.StubFlorenceToken)
+		w := httptest.NewRecorder()
+		areaApi, _ := GetAPIWithRDSMocks(store)
+		areaApi.Router.ServeHTTP(w, r)
+		return w
+	}
+
+	Convey("Given an area with an audit log", t, func() {
+		store := newStore()
+
+		Convey("When its audit log is requested without a page or dates", func() {
+			w := get(store, "http://localhost:2200/v1/areas/E05000001/audit")
+
+			Convey("Then the first page of any date is read, including when the area is retired", func() {
+				So(w.Code, ShouldEqual, http.StatusOK)
+				So(store.ValidateAreaCalls()[0].IncludeInactive, ShouldBeTrue)
+				So(store.GetAreaAuditCalls()[0].Query, ShouldResemble, models.AreaAuditQuery{Limit: models.DefaultAreaAuditLimit})
+
+				var page models.AreaAuditPage
+				So(json.Unmarshal(w.Body.Bytes(), &page), ShouldBeNil)
+				So(page.TotalCount, ShouldEqual, 1)
+				So(page.Items[0].Actor, ShouldEqual, "publisher@ons.gov.uk")
+				So(page.Items[0].Before, ShouldBeNil)
+				So(page.Items[0].After.Names[0].Name, ShouldEqual, "Ward One")
+			})
+		})
+
+		Convey("When a page between two dates is requested", func() {
+			w := get(store, "http://localhost:2200/v1/areas/E05000001/audit?offset=20&limit=10&from=2022-01-01T00:00:00Z&to=2022-04-01T00:00:00%2B01:00")
+
+			Convey("Then the page and dates are passed to the store", func() {
+				So(w.Code, ShouldEqual, http.StatusOK)
+				query := store.GetAreaAuditCalls()[0].Query
+				So(query.Offset, ShouldEqual, 20)
+				So(query.Limit, ShouldEqual, 10)
+				So(query.From.Equal(time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)), ShouldBeTrue)
+				So(query.To.Equal(time.Date(2022, 3, 31, 23, 0, 0, 0, time.UTC)), ShouldBeTrue)
+			})
+		})
+
+		Convey("When the query is invalid", func() {
+			for _, query := range []string{"limit=1001", "limit=ten", "offset=-1", "from=2022-01-01", "to=yesterday"} {
+				w := get(store, "http://localhost:2200/v1/areas/E05000001/audit?"+query)
+
+				Convey("Then 400 is returned for "+query, func() {
+					So(w.Code, ShouldEqual, http.StatusBadRequest)
+					So(w.Body.String(), ShouldContainSubstring, models.InvalidQueryParameterError)
+					So(store.GetAreaAuditCalls(), ShouldBeEmpty)
+				})
+			}
+		})
+	})
+
+	Convey("Given an area that does not exist", t, func() {
+		store := newStore()
+
+		Convey("When its audit log is requested", func() {
+			w := get(store, "http://localhost:2200/v1/areas/E05000099/audit")
+
+			Convey("Then 404 is returned", func() {
+				So(w.Code, ShouldEqual, http.StatusNotFound)
+				So(store.GetAreaAuditCalls(), ShouldBeEmpty)
+			})
+		})
+	})
+
+	Convey("Given a user who may only read the schema", t, func() {
+		store := newStore()
+		verifier := auth.NewStubVerifier()
+		verifier.Users[auth.StubFlorenceToken] = auth.Identity{ID: "reader@ons.gov.uk", Permissions: []string{auth.PermissionReadSchema}}
+
+		Convey("When an audit log is requested", func() {
+			r := httptest.NewRequest(http.MethodGet, "http://localhost:2200/v1/areas/E05000001/audit", nil)
+			r.Header.Set(dprequest.FlorenceHeaderKey, auth.StubFlorenceToken)
+			w := httptest.NewRecorder()
+			cfg, err := config.Get()
+			So(err, ShouldBeNil)
+			areaApi, err := api.Setup(context.Background(), cfg, mux.NewRouter(), store, verifier)
+			So(err, ShouldBeNil)
+			areaApi.Router.ServeHTTP(w, r)
+
+			Convey("Then 403 is returned", func() {
+				So(w.Code, ShouldEqual, http.StatusForbidden)
+				So(store.GetAreaAuditCalls(), ShouldBeEmpty)
+			})
+		})
+	})
+}
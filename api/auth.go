@@ -0,0 +1,45 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/ONSdigital/dp-areas-api/auth"
+	"github.com/ONSdigital/dp-areas-api/models"
+	dprequest "github.com/ONSdigital/dp-net/request"
+	"github.com/ONSdigital/log.go/v2/log"
+)
+
+// authorised wraps a private endpoint so that it is only called for a user or service that the verifier identifies
+// and that has the permission. The caller is added to the context of the request, as dp-net's UserIdentityKey and
+// CallerIdentityKey, so that it can be logged.
+func authorised(verifier auth.Verifier, permission string, h baseHandler) baseHandler {
+	return func(ctx context.Context, w http.ResponseWriter, req *http.Request) (*models.SuccessResponse, *models.ErrorResponse) {
+		identity, err := verifier.Verify(req)
+		if errors.Is(err, auth.ErrUnauthenticated) {
+			responseErr := models.NewError(ctx, err, models.UnauthenticatedError, models.UnauthenticatedErrorDescription)
+			return nil, models.NewErrorResponse(http.StatusUnauthorized, nil, responseErr)
+		}
+		if err != nil {
+			responseErr := models.NewError(ctx, err, models.AuthenticationError, models.AuthenticationErrorDescription)
+			return nil, models.NewErrorResponse(http.StatusInternalServerError, nil, responseErr)
+		}
+
+		logData := log.Data{"caller": identity.ID, "service": identity.Service, "permission": permission}
+		if !identity.HasPermission(permission) {
+			responseErr := models.NewError(ctx, errors.New("permission denied"), models.ForbiddenError, models.ForbiddenErrorDescription)
+			log.Warn(ctx, "caller does not have permission", logData)
+			return nil, models.NewErrorResponse(http.StatusForbidden, nil, responseErr)
+		}
+
+		user := identity.ID
+		if identity.Service {
+			user = identity.User
+		}
+		ctx = context.WithValue(ctx, dprequest.UserIdentityKey, user)
+		ctx = context.WithValue(ctx, dprequest.CallerIdentityKey, identity.ID)
+		log.Info(ctx, "caller authorised", logData)
+		return h(ctx, w, req.WithContext(ctx))
+	}
+}
@@ -0,0 +1,157 @@
+package api_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ONSdigital/dp-areas-api/api"
+	"github.com/ONSdigital/dp-areas-api/api/mock"
+	"github.com/ONSdigital/dp-areas-api/auth"
+	"github.com/ONSdigital/dp-areas-api/config"
+	"github.com/ONSdigital/dp-areas-api/models"
+	dprequest "github.com/ONSdigital/dp-net/request"
+	"github.com/gorilla/mux"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// verifierFunc adapts a function to auth.Verifier
+type verifierFunc func(req *http.Request) (*auth.Identity, error)
+
+func (f verifierFunc) Verify(req *http.Request) (*auth.Identity, error) {
+	return f(req)
+}
+
+func TestPrivateEndpointsAuthorisation(t *testing.T) {
+	ctx := context.Background()
+	cfg := &config.Config{FixturesVersion: "v1", EnablePrivateEndpoints: true}
+
+	var upsertedBy string
+	store := &mock.RDSAreaStoreMock{
+		UpsertAreaFunc: func(ctx context.Context, area models.AreaParams, ifMatch string) (bool, error) {
+			upsertedBy = dprequest.User(ctx)
+			return true, nil
+		},
+		CheckSchemaDriftFunc: func(ctx context.Context) (*models.SchemaDriftReport, error) {
+			return &models.SchemaDriftReport{InSync: true}, nil
+		},
+	}
+
+	verifier := auth.NewStubVerifier()
+	verifier.Users["reader-token"] = auth.Identity{ID: "reader@ons.gov.uk", Permissions: []string{auth.PermissionReadSchema}}
+	areaApi, err := api.Setup(ctx, cfg, mux.NewRouter(), store, verifier)
+	if err != nil {
+		t.Fatalf("failed to set up api: %v", err)
+	}
+
+	put := func(setTokens func(r *http.Request)) *httptest.ResponseRecorder {
+		body := strings.NewReader(`{"area_name": {"name": "Wales", "active_from": "2022-01-01T00:00:00Z", "active_to": "2022-02-01T00:00:00Z"}}`)
+		r := httptest.NewRequest(http.MethodPut, fmt.Sprintf("http://localhost:2200/v1/areas/%s", WalesAreaData), body)
+		setTokens(r)
+		w := httptest.NewRecorder()
+		areaApi.Router.ServeHTTP(w, r)
+		return w
+	}
+
+	Convey("Given an update to an area", t, func() {
+		upsertedBy = ""
+
+		Convey("When it is sent without a token", func() {
+			w := put(func(r *http.Request) {})
+
+			Convey("Then 401 is returned and the area is not written", func() {
+				So(w.Code, ShouldEqual, http.StatusUnauthorized)
+				So(w.Body.String(), ShouldContainSubstring, models.UnauthenticatedError)
+				So(upsertedBy, ShouldBeEmpty)
+			})
+		})
+
+		Convey("When it is sent with an unknown Florence token", func() {
+			w := put(func(r *http.Request) { dprequest.AddFlorenceHeader(r, "unknown") })
+
+			Convey("Then 401 is returned", func() {
+				So(w.Code, ShouldEqual, http.StatusUnauthorized)
+			})
+		})
+
+		Convey("When it is sent by a user without permission to update areas", func() {
+			w := put(func(r *http.Request) { dprequest.AddFlorenceHeader(r, "reader-token") })
+
+			Convey("Then 403 is returned and the area is not written", func() {
+				So(w.Code, ShouldEqual, http.StatusForbidden)
+				So(w.Body.String(), ShouldContainSubstring, models.ForbiddenError)
+				So(upsertedBy, ShouldBeEmpty)
+			})
+		})
+
+		Convey("When it is sent by a user with permission", func() {
+			w := put(func(r *http.Request) { dprequest.AddFlorenceHeader(r, auth.StubFlorenceToken) })
+
+			Convey("Then the area is written by that user", func() {
+				So(w.Code, ShouldEqual, http.StatusCreated)
+				So(upsertedBy, ShouldEqual, "publisher@ons.gov.uk")
+			})
+		})
+
+		Convey("When it is sent by a service on behalf of a user", func() {
+			w := put(func(r *http.Request) {
+				dprequest.AddServiceTokenHeader(r, auth.StubServiceToken)
+				dprequest.AddUserHeader(r, "importer@ons.gov.uk")
+			})
+
+			Convey("Then the area is written for that user", func() {
+				So(w.Code, ShouldEqual, http.StatusCreated)
+				So(upsertedBy, ShouldEqual, "importer@ons.gov.uk")
+			})
+		})
+	})
+
+	Convey("Given a user who may only read the schema", t, func() {
+		r := httptest.NewRequest(http.MethodGet, "http://localhost:2200/v1/schema/drift", nil)
+		dprequest.AddFlorenceHeader(r, "reader-token")
+
+		Convey("When schema drift is requested", func() {
+			w := httptest.NewRecorder()
+			areaApi.Router.ServeHTTP(w, r)
+
+			Convey("Then it is returned", func() {
+				So(w.Code, ShouldEqual, http.StatusOK)
+			})
+		})
+	})
+
+	Convey("Given a verifier that cannot check tokens", t, func() {
+		failing := verifierFunc(func(req *http.Request) (*auth.Identity, error) {
+			return nil, errors.New("connection refused")
+		})
+		failingApi, err := api.Setup(ctx, cfg, mux.NewRouter(), store, failing)
+		So(err, ShouldBeNil)
+
+		Convey("When schema drift is requested", func() {
+			r := httptest.NewRequest(http.MethodGet, "http://localhost:2200/v1/schema/drift", nil)
+			dprequest.AddFlorenceHeader(r, auth.StubFlorenceToken)
+			w := httptest.NewRecorder()
+			failingApi.Router.ServeHTTP(w, r)
+
+			Convey("Then 500 is returned rather than 401", func() {
+				So(w.Code, ShouldEqual, http.StatusInternalServerError)
+				So(w.Body.String(), ShouldContainSubstring, models.AuthenticationError)
+			})
+		})
+	})
+
+	Convey("Given private endpoints are enabled without a verifier", t, func() {
+
+		Convey("When the API is set up", func() {
+			_, err := api.Setup(ctx, cfg, mux.NewRouter(), store, nil)
+
+			Convey("Then it fails", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+	})
+}
@@ -0,0 +1,75 @@
+package api
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+
+	"github.com/ONSdigital/dp-areas-api/models"
+)
+
+// cacheable wraps a public read so that its responses carry the route's Cache-Control policy and an ETag, computed
+// over the body unless the handler sets one, and a request whose validators still match is answered with 304 Not
+// Modified. A Cache-Control header set by the handler is kept.
+func cacheable(cacheControl string, h baseHandler) baseHandler {
+	return func(ctx context.Context, w http.ResponseWriter, req *http.Request) (*models.SuccessResponse, *models.ErrorResponse) {
+		response, errResponse := h(ctx, w, req)
+		if errResponse != nil || response.Status != http.StatusOK {
+			return response, errResponse
+		}
+
+		headers := make(map[string]string, len(response.Headers)+2)
+		for name, value := range response.Headers {
+			headers[name] = value
+		}
+		if headers[models.ETagHeaderName] == "" {
+			headers[models.ETagHeaderName] = bodyETag(response.Body)
+		}
+		if headers[models.CacheControlHeaderName] == "" && cacheControl != "" {
+			headers[models.CacheControlHeaderName] = cacheControl
+		}
+
+		if notModified(req, headers[models.ETagHeaderName], headers[models.LastModifiedHeaderName]) {
+			return models.NewSuccessResponse(nil, http.StatusNotModified, headers), nil
+		}
+		return models.NewSuccessResponse(response.Body, response.Status, headers), nil
+	}
+}
+
+// bodyETag returns a strong entity tag for a response body
+func bodyETag(body []byte) string {
+	sum := sha256.Sum256(body)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// notModified evaluates the If-None-Match and If-Modified-Since preconditions of a read, see RFC 7232 section 6.
+// If-Modified-Since is ignored when If-None-Match is sent, or when the response has no Last-Modified date.
+func notModified(req *http.Request, eTag, lastModified string) bool {
+	if ifNoneMatch := req.Header.Get(models.IfNoneMatchHeaderName); ifNoneMatch != "" {
+		return eTagListMatches(ifNoneMatch, eTag)
+	}
+
+	ifModifiedSince, err := http.ParseTime(req.Header.Get(models.IfModifiedSinceHeaderName))
+	if err != nil || lastModified == "" {
+		return false
+	}
+	modified, err := http.ParseTime(lastModified)
+	return err == nil && !modified.After(ifModifiedSince)
+}
+
+// eTagListMatches reports whether an If-None-Match header value matches eTag. It uses the weak comparison that
+// If-None-Match calls for, so a tag weakened by a proxy still matches.
+func eTagListMatches(list, eTag string) bool {
+	if strings.TrimSpace(list) == "*" {
+		return true
+	}
+	eTag = strings.TrimPrefix(eTag, "W/")
+	for _, candidate := range strings.Split(list, ",") {
+		if strings.TrimPrefix(strings.TrimSpace(candidate), "W/") == eTag {
+			return true
+		}
+	}
+	return false
+}
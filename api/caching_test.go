@@ -0,0 +1,130 @@
+package api_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ONSdigital/dp-areas-api/api"
+	"github.com/ONSdigital/dp-areas-api/api/mock"
+	"github.com/ONSdigital/dp-areas-api/config"
+	"github.com/ONSdigital/dp-areas-api/models"
+	"github.com/gorilla/mux"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestConditionalRequests(t *testing.T) {
+	ctx := context.Background()
+	updatedAt := time.Date(2022, 6, 1, 9, 30, 15, 0, time.UTC)
+	cfg := &config.Config{
+		FixturesVersion:      "v1",
+		AreaCacheControl:     "public, max-age=300",
+		BoundaryCacheControl: "public, max-age=86400",
+	}
+
+	areaApi, err := api.Setup(ctx, cfg, mux.NewRouter(), &mock.RDSAreaStoreMock{
+		GetAreaDetailsFunc: func(ctx context.Context, areaCode string, opts models.AreaDetailsOptions) (*models.AreaDetails, error) {
+			return &models.AreaDetails{Area: &models.AreasDataResults{Code: areaCode, Name: &EnglandName, Version: 4, UpdatedAt: updatedAt, LastModified: updatedAt}}, nil
+		},
+	}, nil)
+	if err != nil {
+		t.Fatalf("failed to set up api: %v", err)
+	}
+
+	get := func(url string, headers map[string]string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, url, nil)
+		r.Header.Set(models.AcceptLanguageHeaderName, "en")
+		for name, value := range headers {
+			r.Header.Set(name, value)
+		}
+		w := httptest.NewRecorder()
+		areaApi.Router.ServeHTTP(w, r)
+		return w
+	}
+	areaURL := "http://localhost:25500/v1/areas/" + EnglandAreaData
+	eTag := models.AreaDetailsETag(4, []byte("null"))
+
+	Convey("Given an area", t, func() {
+
+		Convey("When it is requested without validators", func() {
+			w := get(areaURL, nil)
+
+			Convey("Then its tag, update time and the route's cache policy are returned", func() {
+				So(w.Code, ShouldEqual, http.StatusOK)
+				So(w.Header().Get(models.ETagHeaderName), ShouldEqual, eTag)
+				So(w.Header().Get(models.LastModifiedHeaderName), ShouldEqual, "Wed, 01 Jun 2022 09:30:15 GMT")
+				So(w.Header().Get(models.CacheControlHeaderName), ShouldEqual, "public, max-age=300")
+			})
+		})
+
+		Convey("When it is requested with a matching If-None-Match", func() {
+			w := get(areaURL, map[string]string{models.IfNoneMatchHeaderName: `"3", W/` + eTag})
+
+			Convey("Then 304 is returned with its validators and no body", func() {
+				So(w.Code, ShouldEqual, http.StatusNotModified)
+				So(w.Body.Len(), ShouldEqual, 0)
+				So(w.Header().Get(models.ETagHeaderName), ShouldEqual, eTag)
+				So(w.Header().Get(models.CacheControlHeaderName), ShouldEqual, "public, max-age=300")
+			})
+		})
+
+		Convey("When it is requested with an If-None-Match of an older version", func() {
+			w := get(areaURL, map[string]string{models.IfNoneMatchHeaderName: `"3"`})
+
+			Convey("Then it is returned", func() {
+				So(w.Code, ShouldEqual, http.StatusOK)
+				So(w.Body.Len(), ShouldBeGreaterThan, 0)
+			})
+		})
+
+		Convey("When it is requested with an If-Modified-Since at or after its update", func() {
+			w := get(areaURL, map[string]string{models.IfModifiedSinceHeaderName: "Wed, 01 Jun 2022 09:30:15 GMT"})
+
+			Convey("Then 304 is returned", func() {
+				So(w.Code, ShouldEqual, http.StatusNotModified)
+			})
+		})
+
+		Convey("When it is requested with an If-Modified-Since before its update", func() {
+			w := get(areaURL, map[string]string{models.IfModifiedSinceHeaderName: "Wed, 01 Jun 2022 09:30:14 GMT"})
+
+			Convey("Then it is returned", func() {
+				So(w.Code, ShouldEqual, http.StatusOK)
+			})
+		})
+
+		Convey("When it is requested with a stale If-None-Match and a current If-Modified-Since", func() {
+			w := get(areaURL, map[string]string{
+				models.IfNoneMatchHeaderName:     `"3"`,
+				models.IfModifiedSinceHeaderName: "Wed, 01 Jun 2022 09:30:15 GMT",
+			})
+
+			Convey("Then If-None-Match takes precedence and it is returned", func() {
+				So(w.Code, ShouldEqual, http.StatusOK)
+			})
+		})
+	})
+
+	Convey("Given a boundary", t, func() {
+		boundaryURL := "http://localhost:25500/v1/boundaries/" + EnglandAreaData
+		w := get(boundaryURL, nil)
+
+		Convey("Then its ETag is computed over the body and its route's cache policy is returned", func() {
+			So(w.Code, ShouldEqual, http.StatusOK)
+			So(w.Header().Get(models.ETagHeaderName), ShouldHaveLength, 34)
+			So(w.Header().Get(models.CacheControlHeaderName), ShouldEqual, "public, max-age=86400")
+			So(get(boundaryURL, nil).Header().Get(models.ETagHeaderName), ShouldEqual, w.Header().Get(models.ETagHeaderName))
+		})
+
+		Convey("When it is requested again with its ETag", func() {
+			notModified := get(boundaryURL, map[string]string{models.IfNoneMatchHeaderName: w.Header().Get(models.ETagHeaderName)})
+
+			Convey("Then 304 is returned", func() {
+				So(notModified.Code, ShouldEqual, http.StatusNotModified)
+				So(notModified.Body.Len(), ShouldEqual, 0)
+			})
+		})
+	})
+}
@@ -14,11 +14,18 @@ import (
 type RDSAreaStore interface {
 	Init(ctx context.Context, cfg *config.Config) error
 	Close()
-	GetRelationships(areaCode, relationshipParameter string) ([]*models.AreaBasicData, error)
-	ValidateArea(code string) error
-	GetArea(ctx context.Context, areaId string) (*models.AreasDataResults, error)
-	BuildTables(ctx context.Context, executionList []string) error
+	GetRelationships(ctx context.Context, areaCode, relationshipParameter string, includeInactive bool) ([]*models.AreaBasicData, error)
+	ValidateArea(ctx context.Context, code string, includeInactive bool) error
+	GetArea(ctx context.Context, areaId string, includeInactive bool) (*models.AreasDataResults, error)
+	BuildTables(ctx context.Context) error
 	Ping(ctx context.Context) error
-	UpsertArea(ctx context.Context, area models.AreaParams) (bool, error)
-	GetAncestors(areaID string) ([]models.AreasAncestors, error)
+	PingReader(ctx context.Context) error
+	UpsertArea(ctx context.Context, area models.AreaParams, ifMatch string) (bool, error)
+	BulkUpsertAreas(ctx context.Context, areas []models.AreaParams, batchSize int) ([]models.BulkAreaResult, error)
+	PatchArea(ctx context.Context, areaCode string, patch models.AreaPatch, ifMatch string) error
+	RetireArea(ctx context.Context, areaCode string, cascade bool, ifMatch string) error
+	GetAncestors(ctx context.Context, areaID string) ([]models.AreasAncestors, error)
+	GetAreaDetails(ctx context.Context, areaCode string, opts models.AreaDetailsOptions) (*models.AreaDetails, error)
+	CheckSchemaDrift(ctx context.Context) (*models.SchemaDriftReport, error)
+	GetAreaAudit(ctx context.Context, areaCode string, query models.AreaAuditQuery) (*models.AreaAuditPage, error)
 }
@@ -17,70 +17,112 @@ var _ api.RDSAreaStore = &RDSAreaStoreMock{}
 
 // RDSAreaStoreMock is a mock implementation of api.RDSAreaStore.
 //
-// 	func TestSomethingThatUsesRDSAreaStore(t *testing.T) {
+//	func TestSomethingThatUsesRDSAreaStore(t *testing.T) {
 //
-// 		// make and configure a mocked api.RDSAreaStore
-// 		mockedRDSAreaStore := &RDSAreaStoreMock{
-// 			BuildTablesFunc: func(ctx context.Context, executionList []string) error {
-// 				panic("mock out the BuildTables method")
-// 			},
-// 			CloseFunc: func()  {
-// 				panic("mock out the Close method")
-// 			},
-// 			GetAncestorsFunc: func(areaID string) ([]models.AreasAncestors, error) {
-// 				panic("mock out the GetAncestors method")
-// 			},
-// 			GetAreaFunc: func(ctx context.Context, areaId string) (*models.AreasDataResults, error) {
-// 				panic("mock out the GetArea method")
-// 			},
-// 			GetRelationshipsFunc: func(areaCode string, relationshipParameter string) ([]*models.AreaBasicData, error) {
-// 				panic("mock out the GetRelationships method")
-// 			},
-// 			InitFunc: func(ctx context.Context, cfg *config.Config) error {
-// 				panic("mock out the Init method")
-// 			},
-// 			PingFunc: func(ctx context.Context) error {
-// 				panic("mock out the Ping method")
-// 			},
-// 			UpsertAreaFunc: func(ctx context.Context, area models.AreaParams) (bool, error) {
-// 				panic("mock out the UpsertArea method")
-// 			},
-// 			ValidateAreaFunc: func(code string) error {
-// 				panic("mock out the ValidateArea method")
-// 			},
-// 		}
+//		// make and configure a mocked api.RDSAreaStore
+//		mockedRDSAreaStore := &RDSAreaStoreMock{
+//			BuildTablesFunc: func(ctx context.Context) error {
+//				panic("mock out the BuildTables method")
+//			},
+//			BulkUpsertAreasFunc: func(ctx context.Context, areas []models.AreaParams, batchSize int) ([]models.BulkAreaResult, error) {
+//				panic("mock out the BulkUpsertAreas method")
+//			},
+//			CheckSchemaDriftFunc: func(ctx context.Context) (*models.SchemaDriftReport, error) {
+//				panic("mock out the CheckSchemaDrift method")
+//			},
+//			CloseFunc: func()  {
+//				panic("mock out the Close method")
+//			},
+//			GetAncestorsFunc: func(ctx context.Context, areaID string) ([]models.AreasAncestors, error) {
+//				panic("mock out the GetAncestors method")
+//			},
+//			GetAreaFunc: func(ctx context.Context, areaId string, includeInactive bool) (*models.AreasDataResults, error) {
+//				panic("mock out the GetArea method")
+//			},
+//			GetAreaAuditFunc: func(ctx context.Context, areaCode string, query models.AreaAuditQuery) (*models.AreaAuditPage, error) {
+//				panic("mock out the GetAreaAudit method")
+//			},
+//			GetAreaDetailsFunc: func(ctx context.Context, areaCode string, opts models.AreaDetailsOptions) (*models.AreaDetails, error) {
+//				panic("mock out the GetAreaDetails method")
+//			},
+//			GetRelationshipsFunc: func(ctx context.Context, areaCode string, relationshipParameter string, includeInactive bool) ([]*models.AreaBasicData, error) {
+//				panic("mock out the GetRelationships method")
+//			},
+//			InitFunc: func(ctx context.Context, cfg *config.Config) error {
+//				panic("mock out the Init method")
+//			},
+//			PatchAreaFunc: func(ctx context.Context, areaCode string, patch models.AreaPatch, ifMatch string) error {
+//				panic("mock out the PatchArea method")
+//			},
+//			PingFunc: func(ctx context.Context) error {
+//				panic("mock out the Ping method")
+//			},
+//			PingReaderFunc: func(ctx context.Context) error {
+//				panic("mock out the PingReader method")
+//			},
+//			RetireAreaFunc: func(ctx context.Context, areaCode string, cascade bool, ifMatch string) error {
+//				panic("mock out the RetireArea method")
+//			},
+//			UpsertAreaFunc: func(ctx context.Context, area models.AreaParams, ifMatch string) (bool, error) {
+//				panic("mock out the UpsertArea method")
+//			},
+//			ValidateAreaFunc: func(ctx context.Context, code string, includeInactive bool) error {
+//				panic("mock out the ValidateArea method")
+//			},
+//		}
 //
-// 		// use mockedRDSAreaStore in code that requires api.RDSAreaStore
-// 		// and then make assertions.
+//		// use mockedRDSAreaStore in code that requires api.RDSAreaStore
+//		// and then make assertions.
 //
-// 	}
+//	}
 type RDSAreaStoreMock struct {
 	// BuildTablesFunc mocks the BuildTables method.
-	BuildTablesFunc func(ctx context.Context, executionList []string) error
+	BuildTablesFunc func(ctx context.Context) error
+
+	// BulkUpsertAreasFunc mocks the BulkUpsertAreas method.
+	BulkUpsertAreasFunc func(ctx context.Context, areas []models.AreaParams, batchSize int) ([]models.BulkAreaResult, error)
+
+	// CheckSchemaDriftFunc mocks the CheckSchemaDrift method.
+	CheckSchemaDriftFunc func(ctx context.Context) (*models.SchemaDriftReport, error)
 
 	// CloseFunc mocks the Close method.
 	CloseFunc func()
 
 	// GetAncestorsFunc mocks the GetAncestors method.
-	GetAncestorsFunc func(areaID string) ([]models.AreasAncestors, error)
+	GetAncestorsFunc func(ctx context.Context, areaID string) ([]models.AreasAncestors, error)
 
 	// GetAreaFunc mocks the GetArea method.
-	GetAreaFunc func(ctx context.Context, areaId string) (*models.AreasDataResults, error)
+	GetAreaFunc func(ctx context.Context, areaId string, includeInactive bool) (*models.AreasDataResults, error)
+
+	// GetAreaAuditFunc mocks the GetAreaAudit method.
+	GetAreaAuditFunc func(ctx context.Context, areaCode string, query models.AreaAuditQuery) (*models.AreaAuditPage, error)
+
+	// GetAreaDetailsFunc mocks the GetAreaDetails method.
+	GetAreaDetailsFunc func(ctx context.Context, areaCode string, opts models.AreaDetailsOptions) (*models.AreaDetails, error)
 
 	// GetRelationshipsFunc mocks the GetRelationships method.
-	GetRelationshipsFunc func(areaCode string, relationshipParameter string) ([]*models.AreaBasicData, error)
+	GetRelationshipsFunc func(ctx context.Context, areaCode string, relationshipParameter string, includeInactive bool) ([]*models.AreaBasicData, error)
 
 	// InitFunc mocks the Init method.
 	InitFunc func(ctx context.Context, cfg *config.Config) error
 
+	// PatchAreaFunc mocks the PatchArea method.
+	PatchAreaFunc func(ctx context.Context, areaCode string, patch models.AreaPatch, ifMatch string) error
+
 	// PingFunc mocks the Ping method.
 	PingFunc func(ctx context.Context) error
 
+	// PingReaderFunc mocks the PingReader method.
+	PingReaderFunc func(ctx context.Context) error
+
+	// RetireAreaFunc mocks the RetireArea method.
+	RetireAreaFunc func(ctx context.Context, areaCode string, cascade bool, ifMatch string) error
+
 	// UpsertAreaFunc mocks the UpsertArea method.
-	UpsertAreaFunc func(ctx context.Context, area models.AreaParams) (bool, error)
+	UpsertAreaFunc func(ctx context.Context, area models.AreaParams, ifMatch string) (bool, error)
 
 	// ValidateAreaFunc mocks the ValidateArea method.
-	ValidateAreaFunc func(code string) error
+	ValidateAreaFunc func(ctx context.Context, code string, includeInactive bool) error
 
 	// calls tracks calls to the methods.
 	calls struct {
@@ -88,14 +130,28 @@ type RDSAreaStoreMock struct {
 		BuildTables []struct {
 			// Ctx is the ctx argument value.
 			Ctx context.Context
-			// ExecutionList is the executionList argument value.
-			ExecutionList []string
+		}
+		// BulkUpsertAreas holds details about calls to the BulkUpsertAreas method.
+		BulkUpsertAreas []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Areas is the areas argument value.
+			Areas []models.AreaParams
+			// BatchSize is the batchSize argument value.
+			BatchSize int
+		}
+		// CheckSchemaDrift holds details about calls to the CheckSchemaDrift method.
+		CheckSchemaDrift []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
 		}
 		// Close holds details about calls to the Close method.
 		Close []struct {
 		}
 		// GetAncestors holds details about calls to the GetAncestors method.
 		GetAncestors []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
 			// AreaID is the areaID argument value.
 			AreaID string
 		}
@@ -105,13 +161,37 @@ type RDSAreaStoreMock struct {
 			Ctx context.Context
 			// AreaId is the areaId argument value.
 			AreaId string
+			// IncludeInactive is the includeInactive argument value.
+			IncludeInactive bool
+		}
+		// GetAreaAudit holds details about calls to the GetAreaAudit method.
+		GetAreaAudit []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// AreaCode is the areaCode argument value.
+			AreaCode string
+			// Query is the query argument value.
+			Query models.AreaAuditQuery
+		}
+		// GetAreaDetails holds details about calls to the GetAreaDetails method.
+		GetAreaDetails []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// AreaCode is the areaCode argument value.
+			AreaCode string
+			// Opts is the opts argument value.
+			Opts models.AreaDetailsOptions
 		}
 		// GetRelationships holds details about calls to the GetRelationships method.
 		GetRelationships []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
 			// AreaCode is the areaCode argument value.
 			AreaCode string
 			// RelationshipParameter is the relationshipParameter argument value.
 			RelationshipParameter string
+			// IncludeInactive is the includeInactive argument value.
+			IncludeInactive bool
 		}
 		// Init holds details about calls to the Init method.
 		Init []struct {
@@ -120,63 +200,100 @@ type RDSAreaStoreMock struct {
 			// Cfg is the cfg argument value.
 			Cfg *config.Config
 		}
+		// PatchArea holds details about calls to the PatchArea method.
+		PatchArea []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// AreaCode is the areaCode argument value.
+			AreaCode string
+			// Patch is the patch argument value.
+			Patch models.AreaPatch
+			// IfMatch is the ifMatch argument value.
+			IfMatch string
+		}
 		// Ping holds details about calls to the Ping method.
 		Ping []struct {
 			// Ctx is the ctx argument value.
 			Ctx context.Context
 		}
+		// PingReader holds details about calls to the PingReader method.
+		PingReader []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+		}
+		// RetireArea holds details about calls to the RetireArea method.
+		RetireArea []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// AreaCode is the areaCode argument value.
+			AreaCode string
+			// Cascade is the cascade argument value.
+			Cascade bool
+			// IfMatch is the ifMatch argument value.
+			IfMatch string
+		}
 		// UpsertArea holds details about calls to the UpsertArea method.
 		UpsertArea []struct {
 			// Ctx is the ctx argument value.
 			Ctx context.Context
 			// Area is the area argument value.
 			Area models.AreaParams
+			// IfMatch is the ifMatch argument value.
+			IfMatch string
 		}
 		// ValidateArea holds details about calls to the ValidateArea method.
 		ValidateArea []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
 			// Code is the code argument value.
 			Code string
+			// IncludeInactive is the includeInactive argument value.
+			IncludeInactive bool
 		}
 	}
 	lockBuildTables      sync.RWMutex
+	lockBulkUpsertAreas  sync.RWMutex
+	lockCheckSchemaDrift sync.RWMutex
 	lockClose            sync.RWMutex
 	lockGetAncestors     sync.RWMutex
 	lockGetArea          sync.RWMutex
+	lockGetAreaAudit     sync.RWMutex
+	lockGetAreaDetails   sync.RWMutex
 	lockGetRelationships sync.RWMutex
 	lockInit             sync.RWMutex
+	lockPatchArea        sync.RWMutex
 	lockPing             sync.RWMutex
+	lockPingReader       sync.RWMutex
+	lockRetireArea       sync.RWMutex
 	lockUpsertArea       sync.RWMutex
 	lockValidateArea     sync.RWMutex
 }
 
 // BuildTables calls BuildTablesFunc.
-func (mock *RDSAreaStoreMock) BuildTables(ctx context.Context, executionList []string) error {
+func (mock *RDSAreaStoreMock) BuildTables(ctx context.Context) error {
 	if mock.BuildTablesFunc == nil {
 		panic("RDSAreaStoreMock.BuildTablesFunc: method is nil but RDSAreaStore.BuildTables was just called")
 	}
 	callInfo := struct {
-		Ctx           context.Context
-		ExecutionList []string
+		Ctx context.Context
 	}{
-		Ctx:           ctx,
-		ExecutionList: executionList,
+		Ctx: ctx,
 	}
 	mock.lockBuildTables.Lock()
 	mock.calls.BuildTables = append(mock.calls.BuildTables, callInfo)
 	mock.lockBuildTables.Unlock()
-	return mock.BuildTablesFunc(ctx, executionList)
+	return mock.BuildTablesFunc(ctx)
 }
 
 // BuildTablesCalls gets all the calls that were made to BuildTables.
 // Check the length with:
-//     len(mockedRDSAreaStore.BuildTablesCalls())
+//
+//	len(mockedRDSAreaStore.BuildTablesCalls())
 func (mock *RDSAreaStoreMock) BuildTablesCalls() []struct {
-	Ctx           context.Context
-	ExecutionList []string
+	Ctx context.Context
 } {
 	var calls []struct {
-		Ctx           context.Context
-		ExecutionList []string
+		Ctx context.Context
 	}
 	mock.lockBuildTables.RLock()
 	calls = mock.calls.BuildTables
@@ -184,6 +301,78 @@ func (mock *RDSAreaStoreMock) BuildTablesCalls() []struct {
 	return calls
 }
 
+// BulkUpsertAreas calls BulkUpsertAreasFunc.
+func (mock *RDSAreaStoreMock) BulkUpsertAreas(ctx context.Context, areas []models.AreaParams, batchSize int) ([]models.BulkAreaResult, error) {
+	if mock.BulkUpsertAreasFunc == nil {
+		panic("RDSAreaStoreMock.BulkUpsertAreasFunc: method is nil but RDSAreaStore.BulkUpsertAreas was just called")
+	}
+	callInfo := struct {
+		Ctx       context.Context
+		Areas     []models.AreaParams
+		BatchSize int
+	}{
+		Ctx:       ctx,
+		Areas:     areas,
+		BatchSize: batchSize,
+	}
+	mock.lockBulkUpsertAreas.Lock()
+	mock.calls.BulkUpsertAreas = append(mock.calls.BulkUpsertAreas, callInfo)
+	mock.lockBulkUpsertAreas.Unlock()
+	return mock.BulkUpsertAreasFunc(ctx, areas, batchSize)
+}
+
+// BulkUpsertAreasCalls gets all the calls that were made to BulkUpsertAreas.
+// Check the length with:
+//
+//	len(mockedRDSAreaStore.BulkUpsertAreasCalls())
+func (mock *RDSAreaStoreMock) BulkUpsertAreasCalls() []struct {
+	Ctx       context.Context
+	Areas     []models.AreaParams
+	BatchSize int
+} {
+	var calls []struct {
+		Ctx       context.Context
+		Areas     []models.AreaParams
+		BatchSize int
+	}
+	mock.lockBulkUpsertAreas.RLock()
+	calls = mock.calls.BulkUpsertAreas
+	mock.lockBulkUpsertAreas.RUnlock()
+	return calls
+}
+
+// CheckSchemaDrift calls CheckSchemaDriftFunc.
+func (mock *RDSAreaStoreMock) CheckSchemaDrift(ctx context.Context) (*models.SchemaDriftReport, error) {
+	if mock.CheckSchemaDriftFunc == nil {
+		panic("RDSAreaStoreMock.CheckSchemaDriftFunc: method is nil but RDSAreaStore.CheckSchemaDrift was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+	}{
+		Ctx: ctx,
+	}
+	mock.lockCheckSchemaDrift.Lock()
+	mock.calls.CheckSchemaDrift = append(mock.calls.CheckSchemaDrift, callInfo)
+	mock.lockCheckSchemaDrift.Unlock()
+	return mock.CheckSchemaDriftFunc(ctx)
+}
+
+// CheckSchemaDriftCalls gets all the calls that were made to CheckSchemaDrift.
+// Check the length with:
+//
+//	len(mockedRDSAreaStore.CheckSchemaDriftCalls())
+func (mock *RDSAreaStoreMock) CheckSchemaDriftCalls() []struct {
+	Ctx context.Context
+} {
+	var calls []struct {
+		Ctx context.Context
+	}
+	mock.lockCheckSchemaDrift.RLock()
+	calls = mock.calls.CheckSchemaDrift
+	mock.lockCheckSchemaDrift.RUnlock()
+	return calls
+}
+
 // Close calls CloseFunc.
 func (mock *RDSAreaStoreMock) Close() {
 	if mock.CloseFunc == nil {
@@ -199,7 +388,8 @@ func (mock *RDSAreaStoreMock) Close() {
 
 // CloseCalls gets all the calls that were made to Close.
 // Check the length with:
-//     len(mockedRDSAreaStore.CloseCalls())
+//
+//	len(mockedRDSAreaStore.CloseCalls())
 func (mock *RDSAreaStoreMock) CloseCalls() []struct {
 } {
 	var calls []struct {
@@ -211,28 +401,33 @@ func (mock *RDSAreaStoreMock) CloseCalls() []struct {
 }
 
 // GetAncestors calls GetAncestorsFunc.
-func (mock *RDSAreaStoreMock) GetAncestors(areaID string) ([]models.AreasAncestors, error) {
+func (mock *RDSAreaStoreMock) GetAncestors(ctx context.Context, areaID string) ([]models.AreasAncestors, error) {
 	if mock.GetAncestorsFunc == nil {
 		panic("RDSAreaStoreMock.GetAncestorsFunc: method is nil but RDSAreaStore.GetAncestors was just called")
 	}
 	callInfo := struct {
+		Ctx    context.Context
 		AreaID string
 	}{
+		Ctx:    ctx,
 		AreaID: areaID,
 	}
 	mock.lockGetAncestors.Lock()
 	mock.calls.GetAncestors = append(mock.calls.GetAncestors, callInfo)
 	mock.lockGetAncestors.Unlock()
-	return mock.GetAncestorsFunc(areaID)
+	return mock.GetAncestorsFunc(ctx, areaID)
 }
 
 // GetAncestorsCalls gets all the calls that were made to GetAncestors.
 // Check the length with:
-//     len(mockedRDSAreaStore.GetAncestorsCalls())
+//
+//	len(mockedRDSAreaStore.GetAncestorsCalls())
 func (mock *RDSAreaStoreMock) GetAncestorsCalls() []struct {
+	Ctx    context.Context
 	AreaID string
 } {
 	var calls []struct {
+		Ctx    context.Context
 		AreaID string
 	}
 	mock.lockGetAncestors.RLock()
@@ -242,33 +437,38 @@ func (mock *RDSAreaStoreMock) GetAncestorsCalls() []struct {
 }
 
 // GetArea calls GetAreaFunc.
-func (mock *RDSAreaStoreMock) GetArea(ctx context.Context, areaId string) (*models.AreasDataResults, error) {
+func (mock *RDSAreaStoreMock) GetArea(ctx context.Context, areaId string, includeInactive bool) (*models.AreasDataResults, error) {
 	if mock.GetAreaFunc == nil {
 		panic("RDSAreaStoreMock.GetAreaFunc: method is nil but RDSAreaStore.GetArea was just called")
 	}
 	callInfo := struct {
-		Ctx    context.Context
-		AreaId string
+		Ctx             context.Context
+		AreaId          string
+		IncludeInactive bool
 	}{
-		Ctx:    ctx,
-		AreaId: areaId,
+		Ctx:             ctx,
+		AreaId:          areaId,
+		IncludeInactive: includeInactive,
 	}
 	mock.lockGetArea.Lock()
 	mock.calls.GetArea = append(mock.calls.GetArea, callInfo)
 	mock.lockGetArea.Unlock()
-	return mock.GetAreaFunc(ctx, areaId)
+	return mock.GetAreaFunc(ctx, areaId, includeInactive)
 }
 
 // GetAreaCalls gets all the calls that were made to GetArea.
 // Check the length with:
-//     len(mockedRDSAreaStore.GetAreaCalls())
+//
+//	len(mockedRDSAreaStore.GetAreaCalls())
 func (mock *RDSAreaStoreMock) GetAreaCalls() []struct {
-	Ctx    context.Context
-	AreaId string
+	Ctx             context.Context
+	AreaId          string
+	IncludeInactive bool
 } {
 	var calls []struct {
-		Ctx    context.Context
-		AreaId string
+		Ctx             context.Context
+		AreaId          string
+		IncludeInactive bool
 	}
 	mock.lockGetArea.RLock()
 	calls = mock.calls.GetArea
@@ -276,34 +476,123 @@ func (mock *RDSAreaStoreMock) GetAreaCalls() []struct {
 	return calls
 }
 
+// GetAreaAudit calls GetAreaAuditFunc.
+func (mock *RDSAreaStoreMock) GetAreaAudit(ctx context.Context, areaCode string, query models.AreaAuditQuery) (*models.AreaAuditPage, error) {
+	if mock.GetAreaAuditFunc == nil {
+		panic("RDSAreaStoreMock.GetAreaAuditFunc: method is nil but RDSAreaStore.GetAreaAudit was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		AreaCode string
+		Query    models.AreaAuditQuery
+	}{
+		Ctx:      ctx,
+		AreaCode: areaCode,
+		Query:    query,
+	}
+	mock.lockGetAreaAudit.Lock()
+	mock.calls.GetAreaAudit = append(mock.calls.GetAreaAudit, callInfo)
+	mock.lockGetAreaAudit.Unlock()
+	return mock.GetAreaAuditFunc(ctx, areaCode, query)
+}
+
+// GetAreaAuditCalls gets all the calls that were made to GetAreaAudit.
+// Check the length with:
+//
+//	len(mockedRDSAreaStore.GetAreaAuditCalls())
+func (mock *RDSAreaStoreMock) GetAreaAuditCalls() []struct {
+	Ctx      context.Context
+	AreaCode string
+	Query    models.AreaAuditQuery
+} {
+	var calls []struct {
+		Ctx      context.Context
+		AreaCode string
+		Query    models.AreaAuditQuery
+	}
+	mock.lockGetAreaAudit.RLock()
+	calls = mock.calls.GetAreaAudit
+	mock.lockGetAreaAudit.RUnlock()
+	return calls
+}
+
+// GetAreaDetails calls GetAreaDetailsFunc.
+func (mock *RDSAreaStoreMock) GetAreaDetails(ctx context.Context, areaCode string, opts models.AreaDetailsOptions) (*models.AreaDetails, error) {
+	if mock.GetAreaDetailsFunc == nil {
+		panic("RDSAreaStoreMock.GetAreaDetailsFunc: method is nil but RDSAreaStore.GetAreaDetails was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		AreaCode string
+		Opts     models.AreaDetailsOptions
+	}{
+		Ctx:      ctx,
+		AreaCode: areaCode,
+		Opts:     opts,
+	}
+	mock.lockGetAreaDetails.Lock()
+	mock.calls.GetAreaDetails = append(mock.calls.GetAreaDetails, callInfo)
+	mock.lockGetAreaDetails.Unlock()
+	return mock.GetAreaDetailsFunc(ctx, areaCode, opts)
+}
+
+// GetAreaDetailsCalls gets all the calls that were made to GetAreaDetails.
+// Check the length with:
+//
+//	len(mockedRDSAreaStore.GetAreaDetailsCalls())
+func (mock *RDSAreaStoreMock) GetAreaDetailsCalls() []struct {
+	Ctx      context.Context
+	AreaCode string
+	Opts     models.AreaDetailsOptions
+} {
+	var calls []struct {
+		Ctx      context.Context
+		AreaCode string
+		Opts     models.AreaDetailsOptions
+	}
+	mock.lockGetAreaDetails.RLock()
+	calls = mock.calls.GetAreaDetails
+	mock.lockGetAreaDetails.RUnlock()
+	return calls
+}
+
 // GetRelationships calls GetRelationshipsFunc.
-func (mock *RDSAreaStoreMock) GetRelationships(areaCode string, relationshipParameter string) ([]*models.AreaBasicData, error) {
+func (mock *RDSAreaStoreMock) GetRelationships(ctx context.Context, areaCode string, relationshipParameter string, includeInactive bool) ([]*models.AreaBasicData, error) {
 	if mock.GetRelationshipsFunc == nil {
 		panic("RDSAreaStoreMock.GetRelationshipsFunc: method is nil but RDSAreaStore.GetRelationships was just called")
 	}
 	callInfo := struct {
+		Ctx                   context.Context
 		AreaCode              string
 		RelationshipParameter string
+		IncludeInactive       bool
 	}{
+		Ctx:                   ctx,
 		AreaCode:              areaCode,
 		RelationshipParameter: relationshipParameter,
+		IncludeInactive:       includeInactive,
 	}
 	mock.lockGetRelationships.Lock()
 	mock.calls.GetRelationships = append(mock.calls.GetRelationships, callInfo)
 	mock.lockGetRelationships.Unlock()
-	return mock.GetRelationshipsFunc(areaCode, relationshipParameter)
+	return mock.GetRelationshipsFunc(ctx, areaCode, relationshipParameter, includeInactive)
 }
 
 // GetRelationshipsCalls gets all the calls that were made to GetRelationships.
 // Check the length with:
-//     len(mockedRDSAreaStore.GetRelationshipsCalls())
+//
+//	len(mockedRDSAreaStore.GetRelationshipsCalls())
 func (mock *RDSAreaStoreMock) GetRelationshipsCalls() []struct {
+	Ctx                   context.Context
 	AreaCode              string
 	RelationshipParameter string
+	IncludeInactive       bool
 } {
 	var calls []struct {
+		Ctx                   context.Context
 		AreaCode              string
 		RelationshipParameter string
+		IncludeInactive       bool
 	}
 	mock.lockGetRelationships.RLock()
 	calls = mock.calls.GetRelationships
@@ -331,7 +620,8 @@ func (mock *RDSAreaStoreMock) Init(ctx context.Context, cfg *config.Config) erro
 
 // InitCalls gets all the calls that were made to Init.
 // Check the length with:
-//     len(mockedRDSAreaStore.InitCalls())
+//
+//	len(mockedRDSAreaStore.InitCalls())
 func (mock *RDSAreaStoreMock) InitCalls() []struct {
 	Ctx context.Context
 	Cfg *config.Config
@@ -346,6 +636,50 @@ func (mock *RDSAreaStoreMock) InitCalls() []struct {
 	return calls
 }
 
+// PatchArea calls PatchAreaFunc.
+func (mock *RDSAreaStoreMock) PatchArea(ctx context.Context, areaCode string, patch models.AreaPatch, ifMatch string) error {
+	if mock.PatchAreaFunc == nil {
+		panic("RDSAreaStoreMock.PatchAreaFunc: method is nil but RDSAreaStore.PatchArea was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		AreaCode string
+		Patch    models.AreaPatch
+		IfMatch  string
+	}{
+		Ctx:      ctx,
+		AreaCode: areaCode,
+		Patch:    patch,
+		IfMatch:  ifMatch,
+	}
+	mock.lockPatchArea.Lock()
+	mock.calls.PatchArea = append(mock.calls.PatchArea, callInfo)
+	mock.lockPatchArea.Unlock()
+	return mock.PatchAreaFunc(ctx, areaCode, patch, ifMatch)
+}
+
+// PatchAreaCalls gets all the calls that were made to PatchArea.
+// Check the length with:
+//
+//	len(mockedRDSAreaStore.PatchAreaCalls())
+func (mock *RDSAreaStoreMock) PatchAreaCalls() []struct {
+	Ctx      context.Context
+	AreaCode string
+	Patch    models.AreaPatch
+	IfMatch  string
+} {
+	var calls []struct {
+		Ctx      context.Context
+		AreaCode string
+		Patch    models.AreaPatch
+		IfMatch  string
+	}
+	mock.lockPatchArea.RLock()
+	calls = mock.calls.PatchArea
+	mock.lockPatchArea.RUnlock()
+	return calls
+}
+
 // Ping calls PingFunc.
 func (mock *RDSAreaStoreMock) Ping(ctx context.Context) error {
 	if mock.PingFunc == nil {
@@ -364,7 +698,8 @@ func (mock *RDSAreaStoreMock) Ping(ctx context.Context) error {
 
 // PingCalls gets all the calls that were made to Ping.
 // Check the length with:
-//     len(mockedRDSAreaStore.PingCalls())
+//
+//	len(mockedRDSAreaStore.PingCalls())
 func (mock *RDSAreaStoreMock) PingCalls() []struct {
 	Ctx context.Context
 } {
@@ -377,34 +712,115 @@ func (mock *RDSAreaStoreMock) PingCalls() []struct {
 	return calls
 }
 
+// PingReader calls PingReaderFunc.
+func (mock *RDSAreaStoreMock) PingReader(ctx context.Context) error {
+	if mock.PingReaderFunc == nil {
+		panic("RDSAreaStoreMock.PingReaderFunc: method is nil but RDSAreaStore.PingReader was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+	}{
+		Ctx: ctx,
+	}
+	mock.lockPingReader.Lock()
+	mock.calls.PingReader = append(mock.calls.PingReader, callInfo)
+	mock.lockPingReader.Unlock()
+	return mock.PingReaderFunc(ctx)
+}
+
+// PingReaderCalls gets all the calls that were made to PingReader.
+// Check the length with:
+//
+//	len(mockedRDSAreaStore.PingReaderCalls())
+func (mock *RDSAreaStoreMock) PingReaderCalls() []struct {
+	Ctx context.Context
+} {
+	var calls []struct {
+		Ctx context.Context
+	}
+	mock.lockPingReader.RLock()
+	calls = mock.calls.PingReader
+	mock.lockPingReader.RUnlock()
+	return calls
+}
+
+// RetireArea calls RetireAreaFunc.
+func (mock *RDSAreaStoreMock) RetireArea(ctx context.Context, areaCode string, cascade bool, ifMatch string) error {
+	if mock.RetireAreaFunc == nil {
+		panic("RDSAreaStoreMock.RetireAreaFunc: method is nil but RDSAreaStore.RetireArea was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		AreaCode string
+		Cascade  bool
+		IfMatch  string
+	}{
+		Ctx:      ctx,
+		AreaCode: areaCode,
+		Cascade:  cascade,
+		IfMatch:  ifMatch,
+	}
+	mock.lockRetireArea.Lock()
+	mock.calls.RetireArea = append(mock.calls.RetireArea, callInfo)
+	mock.lockRetireArea.Unlock()
+	return mock.RetireAreaFunc(ctx, areaCode, cascade, ifMatch)
+}
+
+// RetireAreaCalls gets all the calls that were made to RetireArea.
+// Check the length with:
+//
+//	len(mockedRDSAreaStore.RetireAreaCalls())
+func (mock *RDSAreaStoreMock) RetireAreaCalls() []struct {
+	Ctx      context.Context
+	AreaCode string
+	Cascade  bool
+	IfMatch  string
+} {
+	var calls []struct {
+		Ctx      context.Context
+		AreaCode string
+		Cascade  bool
+		IfMatch  string
+	}
+	mock.lockRetireArea.RLock()
+	calls = mock.calls.RetireArea
+	mock.lockRetireArea.RUnlock()
+	return calls
+}
+
 // UpsertArea calls UpsertAreaFunc.
-func (mock *RDSAreaStoreMock) UpsertArea(ctx context.Context, area models.AreaParams) (bool, error) {
+func (mock *RDSAreaStoreMock) UpsertArea(ctx context.Context, area models.AreaParams, ifMatch string) (bool, error) {
 	if mock.UpsertAreaFunc == nil {
 		panic("RDSAreaStoreMock.UpsertAreaFunc: method is nil but RDSAreaStore.UpsertArea was just called")
 	}
 	callInfo := struct {
-		Ctx  context.Context
-		Area models.AreaParams
+		Ctx     context.Context
+		Area    models.AreaParams
+		IfMatch string
 	}{
-		Ctx:  ctx,
-		Area: area,
+		Ctx:     ctx,
+		Area:    area,
+		IfMatch: ifMatch,
 	}
 	mock.lockUpsertArea.Lock()
 	mock.calls.UpsertArea = append(mock.calls.UpsertArea, callInfo)
 	mock.lockUpsertArea.Unlock()
-	return mock.UpsertAreaFunc(ctx, area)
+	return mock.UpsertAreaFunc(ctx, area, ifMatch)
 }
 
 // UpsertAreaCalls gets all the calls that were made to UpsertArea.
 // Check the length with:
-//     len(mockedRDSAreaStore.UpsertAreaCalls())
+//
+//	len(mockedRDSAreaStore.UpsertAreaCalls())
 func (mock *RDSAreaStoreMock) UpsertAreaCalls() []struct {
-	Ctx  context.Context
-	Area models.AreaParams
+	Ctx     context.Context
+	Area    models.AreaParams
+	IfMatch string
 } {
 	var calls []struct {
-		Ctx  context.Context
-		Area models.AreaParams
+		Ctx     context.Context
+		Area    models.AreaParams
+		IfMatch string
 	}
 	mock.lockUpsertArea.RLock()
 	calls = mock.calls.UpsertArea
@@ -413,29 +829,38 @@ func (mock *RDSAreaStoreMock) UpsertAreaCalls() []struct {
 }
 
 // ValidateArea calls ValidateAreaFunc.
-func (mock *RDSAreaStoreMock) ValidateArea(code string) error {
+func (mock *RDSAreaStoreMock) ValidateArea(ctx context.Context, code string, includeInactive bool) error {
 	if mock.ValidateAreaFunc == nil {
 		panic("RDSAreaStoreMock.ValidateAreaFunc: method is nil but RDSAreaStore.ValidateArea was just called")
 	}
 	callInfo := struct {
-		Code string
+		Ctx             context.Context
+		Code            string
+		IncludeInactive bool
 	}{
-		Code: code,
+		Ctx:             ctx,
+		Code:            code,
+		IncludeInactive: includeInactive,
 	}
 	mock.lockValidateArea.Lock()
 	mock.calls.ValidateArea = append(mock.calls.ValidateArea, callInfo)
 	mock.lockValidateArea.Unlock()
-	return mock.ValidateAreaFunc(code)
+	return mock.ValidateAreaFunc(ctx, code, includeInactive)
 }
 
 // ValidateAreaCalls gets all the calls that were made to ValidateArea.
 // Check the length with:
-//     len(mockedRDSAreaStore.ValidateAreaCalls())
+//
+//	len(mockedRDSAreaStore.ValidateAreaCalls())
 func (mock *RDSAreaStoreMock) ValidateAreaCalls() []struct {
-	Code string
+	Ctx             context.Context
+	Code            string
+	IncludeInactive bool
 } {
 	var calls []struct {
-		Code string
+		Ctx             context.Context
+		Code            string
+		IncludeInactive bool
 	}
 	mock.lockValidateArea.RLock()
 	calls = mock.calls.ValidateArea
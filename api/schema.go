@@ -0,0 +1,32 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/ONSdigital/dp-areas-api/models"
+	"github.com/ONSdigital/log.go/v2/log"
+)
+
+// getSchemaDrift is a handler that reports differences between the database schema model and the live database
+func (api *API) getSchemaDrift(ctx context.Context, _ http.ResponseWriter, _ *http.Request) (*models.SuccessResponse, *models.ErrorResponse) {
+	log.Info(ctx, "received request to check schema drift")
+
+	report, err := api.rdsAreaStore.CheckSchemaDrift(ctx)
+	if err != nil {
+		if errorResponse := models.NewStoreErrorResponse(ctx, err); errorResponse != nil {
+			return nil, errorResponse
+		}
+		responseErr := models.NewError(ctx, err, models.SchemaDriftError, err.Error())
+		return nil, models.NewErrorResponse(http.StatusInternalServerError, nil, responseErr)
+	}
+
+	jsonResponse, err := json.Marshal(report)
+	if err != nil {
+		responseErr := models.NewError(ctx, err, models.SchemaDriftError, err.Error())
+		return nil, models.NewErrorResponse(http.StatusInternalServerError, nil, responseErr)
+	}
+
+	return models.NewSuccessResponse(jsonResponse, http.StatusOK, nil), nil
+}
@@ -0,0 +1,63 @@
+package api_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ONSdigital/dp-areas-api/api/mock"
+	"github.com/ONSdigital/dp-areas-api/auth"
+	"github.com/ONSdigital/dp-areas-api/models"
+	dprequest "github.com/ONSdigital/dp-net/request"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestGetSchemaDrift(t *testing.T) {
+	Convey("Given a database that has drifted from the schema model", t, func() {
+		r := httptest.NewRequest(http.MethodGet, "http://localhost:2200/v1/schema/drift", nil)
+		r.Header.Set(dprequest.FlorenceHeaderKey, auth.StubFlorenceToken)
+		w := httptest.NewRecorder()
+
+		drift := models.SchemaDrift{Kind: models.DriftMissingColumn, Table: "area", Column: "land_hectares", Expected: "real"}
+		areaApi, _ := GetAPIWithRDSMocks(&mock.RDSAreaStoreMock{
+			CheckSchemaDriftFunc: func(ctx context.Context) (*models.SchemaDriftReport, error) {
+				return &models.SchemaDriftReport{InSync: false, Drift: []models.SchemaDrift{drift}}, nil
+			},
+		})
+		areaApi.Router.ServeHTTP(w, r)
+
+		Convey("When schema drift is served", func() {
+
+			Convey("Then the drift report is returned", func() {
+				So(w.Code, ShouldEqual, http.StatusOK)
+				var report models.SchemaDriftReport
+				So(json.Unmarshal(w.Body.Bytes(), &report), ShouldBeNil)
+				So(report.InSync, ShouldBeFalse)
+				So(report.Drift, ShouldResemble, []models.SchemaDrift{drift})
+			})
+		})
+	})
+
+	Convey("Given the live schema cannot be read", t, func() {
+		r := httptest.NewRequest(http.MethodGet, "http://localhost:2200/v1/schema/drift", nil)
+		r.Header.Set(dprequest.FlorenceHeaderKey, auth.StubFlorenceToken)
+		w := httptest.NewRecorder()
+
+		areaApi, _ := GetAPIWithRDSMocks(&mock.RDSAreaStoreMock{
+			CheckSchemaDriftFunc: func(ctx context.Context) (*models.SchemaDriftReport, error) {
+				return nil, errors.New("connection refused")
+			},
+		})
+		areaApi.Router.ServeHTTP(w, r)
+
+		Convey("When schema drift is served", func() {
+
+			Convey("Then a 500 response is returned", func() {
+				So(w.Code, ShouldEqual, http.StatusInternalServerError)
+			})
+		})
+	})
+}
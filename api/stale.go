@@ -0,0 +1,230 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/ONSdigital/dp-areas-api/apierrors"
+	"github.com/ONSdigital/dp-areas-api/config"
+	"github.com/ONSdigital/dp-areas-api/models"
+	"github.com/ONSdigital/log.go/v2/log"
+)
+
+const (
+	warningHeaderName = "Warning"
+	// staleWarning marks a response served from StaleResponses, see RFC 7234 section 5.5.1
+	staleWarning = `110 dp-areas-api "Response is Stale"`
+)
+
+// StaleResponses keeps the last good response to each public read, so that the read can still be answered while the
+// database is unavailable or timing out. A response is kept until a newer one to the same request replaces it, and
+// once the configured number are kept responses to new requests are not. The responses can be saved to a snapshot
+// file periodically and loaded from it at startup, so that an instance started during an outage can serve them too.
+type StaleResponses struct {
+	mu               sync.RWMutex
+	size             int
+	responses        map[string]staleResponse
+	snapshotFile     string
+	snapshotInterval time.Duration
+	now              func() time.Time
+	cancel           context.CancelFunc
+	done             chan struct{}
+}
+
+type staleResponse struct {
+	Body    []byte            `json:"body"`
+	Status  int               `json:"status"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Stored  time.Time         `json:"stored"`
+}
+
+// NewStaleResponses creates the store of stale responses configured by cfg, or returns nil when degraded mode is
+// disabled. A nil StaleResponses serves no stale responses.
+func NewStaleResponses(cfg *config.Config) *StaleResponses {
+	if cfg.StaleResponsesSize <= 0 {
+		return nil
+	}
+	return &StaleResponses{
+		size:             cfg.StaleResponsesSize,
+		responses:        make(map[string]staleResponse),
+		snapshotFile:     cfg.StaleSnapshotFile,
+		snapshotInterval: cfg.StaleSnapshotInterval,
+		now:              time.Now,
+	}
+}
+
+// serveStale wraps a read so that its good responses are kept, and the last of them is served with a Warning header
+// when the read fails because the database is unavailable or timing out
+func (s *StaleResponses) serveStale(h baseHandler) baseHandler {
+	if s == nil {
+		return h
+	}
+	return func(ctx context.Context, w http.ResponseWriter, req *http.Request) (*models.SuccessResponse, *models.ErrorResponse) {
+		key := req.URL.RequestURI()
+		response, errResponse := h(ctx, w, req)
+		if errResponse == nil {
+			if response.Status == http.StatusOK {
+				s.put(key, response)
+			}
+			return response, nil
+		}
+		if !isUnavailable(errResponse) {
+			return nil, errResponse
+		}
+
+		stale, ok := s.get(key)
+		if !ok {
+			return nil, errResponse
+		}
+		log.Warn(ctx, "serving stale response while the database is unavailable", log.Data{"request": key, "stored": stale.Stored})
+		// a stale response must be revalidated rather than cached by the route's policy
+		headers := map[string]string{warningHeaderName: staleWarning, models.CacheControlHeaderName: "no-cache"}
+		for name, value := range stale.Headers {
+			headers[name] = value
+		}
+		return models.NewSuccessResponse(stale.Body, stale.Status, headers), nil
+	}
+}
+
+// isUnavailable reports whether a read failed because the database could not be reached or timed out, rather than
+// because of the request
+func isUnavailable(errResponse *models.ErrorResponse) bool {
+	for _, err := range errResponse.Errors {
+		if errors.Is(err, apierrors.ErrUnavailable) || errors.Is(err, apierrors.ErrTimeout) || errors.Is(err, context.DeadlineExceeded) {
+			return true
+		}
+	}
+	return false
+}
+
+// Available reports whether any response is kept to be served while the database is unavailable. It is false when
+// degraded mode is disabled.
+func (s *StaleResponses) Available() bool {
+	if s == nil {
+		return false
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.responses) > 0
+}
+
+func (s *StaleResponses) get(key string) (staleResponse, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	response, ok := s.responses[key]
+	return response, ok
+}
+
+func (s *StaleResponses) put(key string, response *models.SuccessResponse) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.responses[key]; !ok && len(s.responses) >= s.size {
+		return
+	}
+	s.responses[key] = staleResponse{Body: response.Body, Status: response.Status, Headers: response.Headers, Stored: s.now()}
+}
+
+// LoadSnapshot adds the responses saved in the snapshot file, if there is one, to those kept
+func (s *StaleResponses) LoadSnapshot() error {
+	if s == nil || s.snapshotFile == "" {
+		return nil
+	}
+	data, err := os.ReadFile(s.snapshotFile)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read stale response snapshot: %w", err)
+	}
+
+	var responses map[string]staleResponse
+	if err := json.Unmarshal(data, &responses); err != nil {
+		return fmt.Errorf("failed to parse stale response snapshot: %w", err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for key, response := range responses {
+		if _, ok := s.responses[key]; ok || len(s.responses) >= s.size {
+			continue
+		}
+		s.responses[key] = response
+	}
+	return nil
+}
+
+// SaveSnapshot writes the responses kept to the snapshot file, replacing it only once it is completely written
+func (s *StaleResponses) SaveSnapshot() error {
+	if s == nil || s.snapshotFile == "" {
+		return nil
+	}
+	s.mu.RLock()
+	data, err := json.Marshal(s.responses)
+	s.mu.RUnlock()
+	if err != nil {
+		return fmt.Errorf("failed to encode stale response snapshot: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.snapshotFile), filepath.Base(s.snapshotFile)+".*")
+	if err != nil {
+		return fmt.Errorf("failed to create stale response snapshot: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write stale response snapshot: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write stale response snapshot: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.snapshotFile); err != nil {
+		return fmt.Errorf("failed to replace stale response snapshot: %w", err)
+	}
+	return nil
+}
+
+// Start saves a snapshot every snapshot interval in a new goroutine, until Close
+func (s *StaleResponses) Start(ctx context.Context) {
+	if s == nil || s.snapshotFile == "" || s.snapshotInterval <= 0 {
+		return
+	}
+	ctx, s.cancel = context.WithCancel(ctx)
+	s.done = make(chan struct{})
+	go func() {
+		defer close(s.done)
+		ticker := time.NewTicker(s.snapshotInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := s.SaveSnapshot(); err != nil {
+					log.Error(ctx, "failed to save stale response snapshot", err, log.Data{"file": s.snapshotFile})
+				}
+			}
+		}
+	}()
+}
+
+// Close stops saving snapshots periodically and saves a final one
+func (s *StaleResponses) Close(ctx context.Context) error {
+	if s == nil {
+		return nil
+	}
+	if s.cancel != nil {
+		s.cancel()
+		select {
+		case <-s.done:
+		case <-ctx.Done():
+			return fmt.Errorf("failed to stop saving stale response snapshots: %w", ctx.Err())
+		}
+	}
+	return s.SaveSnapshot()
+}
@@ -0,0 +1,147 @@
+package api_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ONSdigital/dp-areas-api/api"
+	"github.com/ONSdigital/dp-areas-api/api/mock"
+	"github.com/ONSdigital/dp-areas-api/apierrors"
+	"github.com/ONSdigital/dp-areas-api/config"
+	"github.com/ONSdigital/dp-areas-api/models"
+	"github.com/gorilla/mux"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestStaleResponses(t *testing.T) {
+	ctx := context.Background()
+	errUnavailable := apierrors.NewStoreError(apierrors.ErrUnavailable, errors.New("connection refused"))
+
+	getArea := func(a *api.API, areaCode string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, "http://localhost:25500/v1/areas/"+areaCode, nil)
+		r.Header.Set(models.AcceptLanguageHeaderName, "en")
+		w := httptest.NewRecorder()
+		a.Router.ServeHTTP(w, r)
+		return w
+	}
+
+	newStore := func(err *error) *mock.RDSAreaStoreMock {
+		return &mock.RDSAreaStoreMock{
+			GetAreaDetailsFunc: func(ctx context.Context, areaCode string, opts models.AreaDetailsOptions) (*models.AreaDetails, error) {
+				if *err != nil {
+					return nil, *err
+				}
+				return &models.AreaDetails{Area: &models.AreasDataResults{Code: areaCode, Name: &EnglandName, Version: 2}}, nil
+			},
+		}
+	}
+
+	Convey("Given an area read while the database was available", t, func() {
+		var storeErr error
+		cfg := &config.Config{FixturesVersion: "v1", StaleResponsesSize: 1}
+		areaApi, err := api.Setup(ctx, cfg, mux.NewRouter(), newStore(&storeErr), nil)
+		So(err, ShouldBeNil)
+		So(areaApi.StaleResponses.Available(), ShouldBeFalse)
+		good := getArea(areaApi, EnglandAreaData)
+		So(good.Code, ShouldEqual, http.StatusOK)
+		So(areaApi.StaleResponses.Available(), ShouldBeTrue)
+
+		Convey("When the database becomes unavailable", func() {
+			storeErr = errUnavailable
+
+			Convey("Then the last good response is served with a Warning header", func() {
+				w := getArea(areaApi, EnglandAreaData)
+				So(w.Code, ShouldEqual, http.StatusOK)
+				So(w.Body.String(), ShouldEqual, good.Body.String())
+				So(w.Header().Get("Warning"), ShouldEqual, `110 dp-areas-api "Response is Stale"`)
+				So(w.Header().Get(models.ETagHeaderName), ShouldEqual, good.Header().Get(models.ETagHeaderName))
+				So(w.Header().Get(models.CacheControlHeaderName), ShouldEqual, "no-cache")
+			})
+
+			Convey("Then an area that was never read still fails", func() {
+				w := getArea(areaApi, WalesAreaData)
+				So(w.Code, ShouldEqual, http.StatusServiceUnavailable)
+			})
+		})
+
+		Convey("When the database times out", func() {
+			storeErr = context.DeadlineExceeded
+
+			Convey("Then the last good response is served", func() {
+				w := getArea(areaApi, EnglandAreaData)
+				So(w.Code, ShouldEqual, http.StatusOK)
+				So(w.Header().Get("Warning"), ShouldNotBeEmpty)
+			})
+		})
+
+		Convey("When the area is no longer found", func() {
+			storeErr = apierrors.ErrNoRows
+
+			Convey("Then the error is returned rather than the stale response", func() {
+				w := getArea(areaApi, EnglandAreaData)
+				So(w.Code, ShouldEqual, http.StatusNotFound)
+			})
+		})
+
+		Convey("When another area is read once the responses kept are full", func() {
+			So(getArea(areaApi, WalesAreaData).Code, ShouldEqual, http.StatusOK)
+			storeErr = errUnavailable
+
+			Convey("Then only the response kept first is served", func() {
+				So(getArea(areaApi, EnglandAreaData).Code, ShouldEqual, http.StatusOK)
+				So(getArea(areaApi, WalesAreaData).Code, ShouldEqual, http.StatusServiceUnavailable)
+			})
+		})
+	})
+
+	Convey("Given degraded mode is disabled", t, func() {
+		storeErr := error(nil)
+		cfg := &config.Config{FixturesVersion: "v1"}
+		areaApi, err := api.Setup(ctx, cfg, mux.NewRouter(), newStore(&storeErr), nil)
+		So(err, ShouldBeNil)
+		So(areaApi.StaleResponses, ShouldBeNil)
+		So(areaApi.StaleResponses.Available(), ShouldBeFalse)
+		So(getArea(areaApi, EnglandAreaData).Code, ShouldEqual, http.StatusOK)
+
+		Convey("When the database becomes unavailable", func() {
+			storeErr = errUnavailable
+
+			Convey("Then the read fails", func() {
+				So(getArea(areaApi, EnglandAreaData).Code, ShouldEqual, http.StatusServiceUnavailable)
+			})
+		})
+	})
+
+	Convey("Given the responses of an instance saved to a snapshot", t, func() {
+		var storeErr error
+		cfg := &config.Config{
+			FixturesVersion:       "v1",
+			StaleResponsesSize:    10,
+			StaleSnapshotFile:     filepath.Join(t.TempDir(), "stale.json"),
+			StaleSnapshotInterval: time.Hour,
+		}
+		areaApi, err := api.Setup(ctx, cfg, mux.NewRouter(), newStore(&storeErr), nil)
+		So(err, ShouldBeNil)
+		areaApi.StaleResponses.Start(ctx)
+		good := getArea(areaApi, EnglandAreaData)
+		So(areaApi.StaleResponses.Close(ctx), ShouldBeNil)
+
+		Convey("When another instance starts while the database is unavailable", func() {
+			storeErr = errUnavailable
+			restarted, err := api.Setup(ctx, cfg, mux.NewRouter(), newStore(&storeErr), nil)
+			So(err, ShouldBeNil)
+
+			Convey("Then it serves the responses loaded from the snapshot", func() {
+				w := getArea(restarted, EnglandAreaData)
+				So(w.Code, ShouldEqual, http.StatusOK)
+				So(w.Body.String(), ShouldEqual, good.Body.String())
+				So(w.Header().Get("Warning"), ShouldNotBeEmpty)
+			})
+		})
+	})
+}
@@ -11,5 +11,46 @@ var (
 	ErrInternalServer           = errors.New("internal error")
 	ErrInvalidQueryParameter    = errors.New("invalid query parameter")
 	ErrQueryParamLimitExceedMax = errors.New("limit exceeds max value")
-	ErrNoRows                   = errors.New("no rows in result set")
+	ErrNoRows                   = NewStoreError(ErrNotFound, errors.New("no rows in result set"))
+	ErrAreaHasLiveChildren      = errors.New("area has live child areas")
+	ErrInvalidAreaPatch         = errors.New("patched area is invalid")
+	ErrPreconditionFailed       = errors.New("area has been modified")
+	ErrSchemaLockTimeout        = errors.New("timed out waiting for another instance to finish building the schema")
+	ErrAreaParentCycle          = errors.New("parent area is the area itself or one of its descendants")
 )
+
+// Kinds of store error, matched with errors.Is against the errors returned by an area store
+var (
+	ErrNotFound         = errors.New("not found")
+	ErrConflict         = errors.New("conflicts with existing data")
+	ErrInvalidReference = errors.New("refers to data that does not exist")
+	ErrInvalidData      = errors.New("breaks a data constraint")
+	ErrUnavailable      = errors.New("database unavailable")
+	ErrTimeout          = errors.New("database timed out")
+)
+
+// StoreError is a store failure classified as one of the store error kinds, so that handlers can choose a response
+// without knowing which store or database produced it
+type StoreError struct {
+	Kind       error
+	Constraint string
+	Err        error
+}
+
+// NewStoreError classifies err as the given kind
+func NewStoreError(kind, err error) *StoreError {
+	return &StoreError{Kind: kind, Err: err}
+}
+
+func (e *StoreError) Error() string {
+	return e.Err.Error()
+}
+
+// Is reports whether target is the kind of the error
+func (e *StoreError) Is(target error) bool {
+	return target == e.Kind
+}
+
+func (e *StoreError) Unwrap() error {
+	return e.Err
+}
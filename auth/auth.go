@@ -0,0 +1,76 @@
+// Package auth identifies the users and services calling the private endpoints from the Florence and service tokens
+// they send, and holds the permissions each identity is granted. Tokens are checked by a Verifier, which is Zebedee in
+// an environment and a stub with fixed tokens for local development and tests.
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	dprequest "github.com/ONSdigital/dp-net/request"
+)
+
+// Permissions checked by the private endpoints
+const (
+	// PermissionUpdate allows areas to be created, updated and retired
+	PermissionUpdate = "areas:update"
+	// PermissionReadSchema allows the live database schema to be compared with the schema model
+	PermissionReadSchema = "areas:schema:read"
+	// PermissionReadAudit allows the audit log of changes to an area to be read
+	PermissionReadAudit = "areas:audit:read"
+)
+
+// SystemActor is recorded as the actor of changes made without a caller, such as by a job run against the store
+const SystemActor = "dp-areas-api"
+
+// ErrUnauthenticated is returned by a Verifier when a request has no token, or none that it accepts
+var ErrUnauthenticated = errors.New("request has no valid user or service token")
+
+// Identity is the user or service a request was verified as, and the permissions it is granted
+type Identity struct {
+	// ID is the user's email or the service's name
+	ID string
+	// Service is set when the request was verified by a service token
+	Service bool
+	// User is the user a service is acting for, forwarded in the User-Identity header, if any
+	User        string
+	Permissions []string
+}
+
+// HasPermission reports whether the identity is granted the permission
+func (i *Identity) HasPermission(permission string) bool {
+	for _, p := range i.Permissions {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// Verifier identifies the user or service that sent a request from its tokens. It returns an error wrapping
+// ErrUnauthenticated when the request has no token it accepts, and any other error when it could not check them.
+type Verifier interface {
+	Verify(req *http.Request) (*Identity, error)
+}
+
+// Tokens returns the Florence token and the service token sent with a request, either of which may be empty. The
+// service token is sent in the Authorization header, with or without its Bearer prefix.
+func Tokens(req *http.Request) (florenceToken, serviceToken string) {
+	florenceToken = req.Header.Get(dprequest.FlorenceHeaderKey)
+	serviceToken = strings.TrimPrefix(req.Header.Get(dprequest.AuthHeaderKey), dprequest.BearerPrefix)
+	return florenceToken, serviceToken
+}
+
+// Actor returns who a change made with ctx is recorded as: the user, the calling service when it acts for no user, or
+// SystemActor when ctx has neither
+func Actor(ctx context.Context) string {
+	if user := dprequest.User(ctx); user != "" {
+		return user
+	}
+	if caller := dprequest.Caller(ctx); caller != "" {
+		return caller
+	}
+	return SystemActor
+}
@@ -0,0 +1,241 @@
+package auth_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ONSdigital/dp-api-clients-go/v2/identity"
+	"github.com/ONSdigital/dp-areas-api/auth"
+	"github.com/ONSdigital/dp-areas-api/auth/mock"
+	dprequest "github.com/ONSdigital/dp-net/request"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func newRequest(florenceToken, serviceToken string) *http.Request {
+	r := httptest.NewRequest(http.MethodPut, "http://localhost:25500/v1/areas/E92000001", nil)
+	dprequest.AddFlorenceHeader(r, florenceToken)
+	dprequest.AddServiceTokenHeader(r, serviceToken)
+	return r
+}
+
+func TestStubVerifier(t *testing.T) {
+	Convey("Given the stub verifier", t, func() {
+		verifier := auth.NewStubVerifier()
+
+		Convey("When a request with its Florence token is verified", func() {
+			identity, err := verifier.Verify(newRequest(auth.StubFlorenceToken, ""))
+
+			Convey("Then a user with every permission is returned", func() {
+				So(err, ShouldBeNil)
+				So(identity.Service, ShouldBeFalse)
+				So(identity.HasPermission(auth.PermissionUpdate), ShouldBeTrue)
+				So(identity.HasPermission(auth.PermissionReadSchema), ShouldBeTrue)
+				So(identity.HasPermission(auth.PermissionReadAudit), ShouldBeTrue)
+			})
+		})
+
+		Convey("When a request with its service token is verified on behalf of a user", func() {
+			r := newRequest("", auth.StubServiceToken)
+			dprequest.AddUserHeader(r, "publisher@ons.gov.uk")
+			identity, err := verifier.Verify(r)
+
+			Convey("Then the service and the user it acts for are returned", func() {
+				So(err, ShouldBeNil)
+				So(identity.Service, ShouldBeTrue)
+				So(identity.User, ShouldEqual, "publisher@ons.gov.uk")
+			})
+		})
+
+		Convey("When a request with an unknown token is verified", func() {
+			_, err := verifier.Verify(newRequest("", "unknown"))
+
+			Convey("Then it is unauthenticated", func() {
+				So(errors.Is(err, auth.ErrUnauthenticated), ShouldBeTrue)
+			})
+		})
+
+		Convey("When a request without tokens is verified", func() {
+			_, err := verifier.Verify(newRequest("", ""))
+
+			Convey("Then it is unauthenticated", func() {
+				So(errors.Is(err, auth.ErrUnauthenticated), ShouldBeTrue)
+			})
+		})
+	})
+}
+
+func TestZebedeeVerifier(t *testing.T) {
+	permissions := &auth.Permissions{
+		Users: map[string][]string{
+			"publisher@ons.gov.uk": {auth.PermissionUpdate},
+			"auditor@ons.gov.uk":   {auth.PermissionReadAudit},
+		},
+		Services: map[string][]string{"dp-import-api": {auth.PermissionUpdate, auth.PermissionReadSchema}},
+	}
+
+	Convey("Given Zebedee identifies callers", t, func() {
+		// each Florence token is identified as the user of that name, and any service token as dp-import-api
+		client := &mock.IdentityClientMock{
+			CheckRequestFunc: func(req *http.Request, florenceToken, serviceAuthToken string) (context.Context, int, identity.AuthFailure, error) {
+				ctx := req.Context()
+				if florenceToken != "" {
+					ctx = context.WithValue(ctx, dprequest.UserIdentityKey, florenceToken)
+					return context.WithValue(ctx, dprequest.CallerIdentityKey, florenceToken), http.StatusOK, nil, nil
+				}
+				return context.WithValue(ctx, dprequest.CallerIdentityKey, "dp-import-api"), http.StatusOK, nil, nil
+			},
+		}
+		verifier := auth.NewZebedeeVerifierWithClient(client, permissions)
+
+		Convey("When a request with a Florence token is verified", func() {
+			identity, err := verifier.Verify(newRequest("publisher@ons.gov.uk", ""))
+
+			Convey("Then the user is granted the permissions listed for them", func() {
+				So(err, ShouldBeNil)
+				So(identity.ID, ShouldEqual, "publisher@ons.gov.uk")
+				So(identity.Service, ShouldBeFalse)
+				So(identity.Permissions, ShouldResemble, []string{auth.PermissionUpdate})
+				So(client.CheckRequestCalls()[0].FlorenceToken, ShouldEqual, "publisher@ons.gov.uk")
+			})
+		})
+
+		Convey("When requests from two users are verified", func() {
+			publisher, err := verifier.Verify(newRequest("publisher@ons.gov.uk", ""))
+			So(err, ShouldBeNil)
+			auditor, err := verifier.Verify(newRequest("auditor@ons.gov.uk", ""))
+			So(err, ShouldBeNil)
+
+			Convey("Then each is granted only their own permissions", func() {
+				So(publisher.HasPermission(auth.PermissionUpdate), ShouldBeTrue)
+				So(publisher.HasPermission(auth.PermissionReadAudit), ShouldBeFalse)
+				So(auditor.HasPermission(auth.PermissionReadAudit), ShouldBeTrue)
+				So(auditor.HasPermission(auth.PermissionUpdate), ShouldBeFalse)
+			})
+		})
+
+		Convey("When a request from a user who is not listed is verified", func() {
+			identity, err := verifier.Verify(newRequest("visitor@ons.gov.uk", ""))
+
+			Convey("Then they are identified but granted nothing", func() {
+				So(err, ShouldBeNil)
+				So(identity.ID, ShouldEqual, "visitor@ons.gov.uk")
+				So(identity.Permissions, ShouldBeEmpty)
+			})
+		})
+
+		Convey("When a request with a service token is verified", func() {
+			identity, err := verifier.Verify(newRequest("", "service"))
+
+			Convey("Then the service is granted the permissions listed for it", func() {
+				So(err, ShouldBeNil)
+				So(identity.ID, ShouldEqual, "dp-import-api")
+				So(identity.Service, ShouldBeTrue)
+				So(identity.Permissions, ShouldResemble, []string{auth.PermissionUpdate, auth.PermissionReadSchema})
+				So(client.CheckRequestCalls()[0].ServiceAuthToken, ShouldEqual, "service")
+			})
+		})
+
+		Convey("When a request without tokens is verified", func() {
+			_, err := verifier.Verify(newRequest("", ""))
+
+			Convey("Then it is unauthenticated without calling Zebedee", func() {
+				So(errors.Is(err, auth.ErrUnauthenticated), ShouldBeTrue)
+				So(client.CheckRequestCalls(), ShouldBeEmpty)
+			})
+		})
+	})
+
+	Convey("Given Zebedee rejects a token", t, func() {
+		verifier := auth.NewZebedeeVerifierWithClient(&mock.IdentityClientMock{
+			CheckRequestFunc: func(req *http.Request, florenceToken, serviceAuthToken string) (context.Context, int, identity.AuthFailure, error) {
+				return req.Context(), http.StatusUnauthorized, errors.New("unable to identify request"), nil
+			},
+		}, permissions)
+
+		Convey("When it is verified", func() {
+			_, err := verifier.Verify(newRequest("expired", ""))
+
+			Convey("Then it is unauthenticated", func() {
+				So(errors.Is(err, auth.ErrUnauthenticated), ShouldBeTrue)
+			})
+		})
+	})
+
+	Convey("Given Zebedee cannot be reached", t, func() {
+		verifier := auth.NewZebedeeVerifierWithClient(&mock.IdentityClientMock{
+			CheckRequestFunc: func(req *http.Request, florenceToken, serviceAuthToken string) (context.Context, int, identity.AuthFailure, error) {
+				return req.Context(), http.StatusInternalServerError, nil, errors.New("connection refused")
+			},
+		}, permissions)
+
+		Convey("When a token is verified", func() {
+			_, err := verifier.Verify(newRequest("florence", ""))
+
+			Convey("Then an error other than unauthenticated is returned", func() {
+				So(err, ShouldNotBeNil)
+				So(errors.Is(err, auth.ErrUnauthenticated), ShouldBeFalse)
+			})
+		})
+	})
+}
+
+func TestLoadPermissions(t *testing.T) {
+	Convey("Given a permissions file", t, func() {
+		path := filepath.Join(t.TempDir(), "permissions.json")
+		err := os.WriteFile(path, []byte(`{"users": {"publisher@ons.gov.uk": ["areas:update"]}, "services": {"dp-import-api": ["areas:update", "areas:schema:read"]}}`), 0600)
+		So(err, ShouldBeNil)
+
+		Convey("When it is loaded", func() {
+			permissions, err := auth.LoadPermissions(path)
+
+			Convey("Then each caller is granted the permissions listed for it", func() {
+				So(err, ShouldBeNil)
+				So(permissions.ForUser("publisher@ons.gov.uk"), ShouldResemble, []string{auth.PermissionUpdate})
+				So(permissions.ForService("dp-import-api"), ShouldResemble, []string{auth.PermissionUpdate, auth.PermissionReadSchema})
+				So(permissions.ForService("publisher@ons.gov.uk"), ShouldBeEmpty)
+			})
+		})
+	})
+
+	Convey("Given no permissions file", t, func() {
+		permissions, err := auth.LoadPermissions("")
+
+		Convey("Then no caller is granted anything", func() {
+			So(err, ShouldBeNil)
+			So(permissions.ForUser("publisher@ons.gov.uk"), ShouldBeEmpty)
+		})
+	})
+
+	Convey("Given a permissions file that does not exist", t, func() {
+		_, err := auth.LoadPermissions(filepath.Join(t.TempDir(), "missing.json"))
+
+		Convey("Then an error is returned", func() {
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestActor(t *testing.T) {
+	Convey("Given the identities a change can be made with", t, func() {
+		ctx := context.Background()
+		service := context.WithValue(ctx, dprequest.CallerIdentityKey, "dp-import-api")
+		user := context.WithValue(service, dprequest.UserIdentityKey, "publisher@ons.gov.uk")
+
+		Convey("Then a user is recorded in preference to the service acting for them", func() {
+			So(auth.Actor(user), ShouldEqual, "publisher@ons.gov.uk")
+		})
+
+		Convey("Then a service acting for no user is recorded itself", func() {
+			So(auth.Actor(service), ShouldEqual, "dp-import-api")
+		})
+
+		Convey("Then a change without a caller is recorded as the system", func() {
+			So(auth.Actor(ctx), ShouldEqual, auth.SystemActor)
+		})
+	})
+}
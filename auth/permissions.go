@@ -0,0 +1,45 @@
+package auth
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// Permissions grants each user and service a verifier identifies its own permissions, keyed by the user's email or the
+// service's name. Callers that are not listed are granted none.
+type Permissions struct {
+	Users    map[string][]string `json:"users"`
+	Services map[string][]string `json:"services"`
+}
+
+// LoadPermissions reads the permissions granted to each caller from a JSON file such as
+//
+//	{"users": {"publisher@ons.gov.uk": ["areas:update"]}, "services": {"dp-import-api": ["areas:update"]}}
+//
+// No caller is granted anything when path is empty.
+func LoadPermissions(path string) (*Permissions, error) {
+	if path == "" {
+		return &Permissions{}, nil
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read permissions: %w", err)
+	}
+	permissions := &Permissions{}
+	if err = json.Unmarshal(b, permissions); err != nil {
+		return nil, fmt.Errorf("failed to parse permissions %s: %w", path, err)
+	}
+	return permissions, nil
+}
+
+// ForUser returns the permissions granted to a user
+func (p *Permissions) ForUser(id string) []string {
+	return p.Users[id]
+}
+
+// ForService returns the permissions granted to a service
+func (p *Permissions) ForService(id string) []string {
+	return p.Services[id]
+}
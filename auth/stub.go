@@ -0,0 +1,50 @@
+package auth
+
+import (
+	"fmt"
+	"net/http"
+
+	dprequest "github.com/ONSdigital/dp-net/request"
+)
+
+// Tokens accepted by the stub returned by NewStubVerifier, each granted every permission
+const (
+	StubFlorenceToken = "stub-florence-token"
+	StubServiceToken  = "stub-service-token"
+)
+
+// StubVerifier accepts a fixed set of tokens without calling out, for local development and tests. It must not be used
+// in an environment.
+type StubVerifier struct {
+	// Users and Services are the identities of the accepted Florence and service tokens
+	Users    map[string]Identity
+	Services map[string]Identity
+}
+
+// NewStubVerifier creates a stub that accepts StubFlorenceToken and StubServiceToken with every permission
+func NewStubVerifier() *StubVerifier {
+	all := []string{PermissionUpdate, PermissionReadSchema, PermissionReadAudit}
+	return &StubVerifier{
+		Users:    map[string]Identity{StubFlorenceToken: {ID: "publisher@ons.gov.uk", Permissions: all}},
+		Services: map[string]Identity{StubServiceToken: {ID: "dp-areas-api-stub", Service: true, Permissions: all}},
+	}
+}
+
+// Verify returns the identity of the request's Florence token, or else of its service token
+func (s *StubVerifier) Verify(req *http.Request) (*Identity, error) {
+	florenceToken, serviceToken := Tokens(req)
+	if florenceToken != "" {
+		if identity, ok := s.Users[florenceToken]; ok {
+			return &identity, nil
+		}
+		return nil, fmt.Errorf("%w: unknown florence token", ErrUnauthenticated)
+	}
+	if serviceToken != "" {
+		if identity, ok := s.Services[serviceToken]; ok {
+			identity.User = req.Header.Get(dprequest.UserHeaderKey)
+			return &identity, nil
+		}
+		return nil, fmt.Errorf("%w: unknown service token", ErrUnauthenticated)
+	}
+	return nil, ErrUnauthenticated
+}
@@ -0,0 +1,73 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/ONSdigital/dp-api-clients-go/v2/identity"
+	"github.com/ONSdigital/dp-areas-api/config"
+	"github.com/ONSdigital/dp-healthcheck/healthcheck"
+	dprequest "github.com/ONSdigital/dp-net/request"
+)
+
+//go:generate moq -out mock/identityClient.go -pkg mock . IdentityClient
+
+// IdentityClient defines the required methods from the Zebedee identity client
+type IdentityClient interface {
+	CheckRequest(req *http.Request, florenceToken, serviceAuthToken string) (context.Context, int, identity.AuthFailure, error)
+	Checker(ctx context.Context, check *healthcheck.CheckState) error
+}
+
+// ZebedeeVerifier checks tokens with Zebedee's identity endpoint. Zebedee only identifies the caller, so the caller it
+// identifies is granted the permissions listed for that user or service.
+type ZebedeeVerifier struct {
+	client      IdentityClient
+	permissions *Permissions
+}
+
+// NewZebedeeVerifier creates a verifier that calls the Zebedee at cfg.ZebedeeURL, granting the permissions in
+// cfg.AuthPermissionsFile
+func NewZebedeeVerifier(cfg *config.Config) (*ZebedeeVerifier, error) {
+	permissions, err := LoadPermissions(cfg.AuthPermissionsFile)
+	if err != nil {
+		return nil, err
+	}
+	return NewZebedeeVerifierWithClient(identity.New(cfg.ZebedeeURL), permissions), nil
+}
+
+// NewZebedeeVerifierWithClient creates a verifier that calls Zebedee through client
+func NewZebedeeVerifierWithClient(client IdentityClient, permissions *Permissions) *ZebedeeVerifier {
+	return &ZebedeeVerifier{
+		client:      client,
+		permissions: permissions,
+	}
+}
+
+// Verify returns the identity Zebedee gives the request's Florence token, or else its service token
+func (z *ZebedeeVerifier) Verify(req *http.Request) (*Identity, error) {
+	florenceToken, serviceToken := Tokens(req)
+	if florenceToken == "" && serviceToken == "" {
+		return nil, ErrUnauthenticated
+	}
+
+	ctx, status, authFailure, err := z.client.CheckRequest(req, florenceToken, serviceToken)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check token with zebedee: %w", err)
+	}
+	if authFailure != nil {
+		return nil, fmt.Errorf("%w: zebedee returned %d: %v", ErrUnauthenticated, status, authFailure)
+	}
+
+	if florenceToken != "" {
+		user := dprequest.User(ctx)
+		return &Identity{ID: user, Permissions: z.permissions.ForUser(user)}, nil
+	}
+	service := dprequest.Caller(ctx)
+	return &Identity{ID: service, Service: true, User: dprequest.User(ctx), Permissions: z.permissions.ForService(service)}, nil
+}
+
+// Checker reports the health of Zebedee
+func (z *ZebedeeVerifier) Checker(ctx context.Context, check *healthcheck.CheckState) error {
+	return z.client.Checker(ctx, check)
+}
@@ -0,0 +1,204 @@
+// Package cache is a decorator for api.RDSAreaStore that caches area reads in memory. Each cached value is indexed by
+// the areas it was built from, so that a write through the store evicts exactly the values it may have changed: those
+// of the written area, which hold it as an ancestor or a related area, and those of its parent, whose children change.
+// Writes made by other instances are evicted when they are notified to an rds.Listener, or otherwise once the cached
+// values expire.
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/ONSdigital/dp-areas-api/api"
+	"github.com/ONSdigital/dp-areas-api/config"
+	"github.com/ONSdigital/dp-areas-api/models"
+)
+
+// parentCodePatchPath is the patch path that moves an area to another parent
+const parentCodePatchPath = "/parent_code"
+
+// Store caches the reads of the store it wraps. Cached values are shared between callers, which must not modify them.
+type Store struct {
+	api.RDSAreaStore
+	areas         *lru
+	ancestors     *lru
+	relationships *lru
+	areaDetails   *lru
+	validAreas    *lru
+}
+
+// New wraps store with the caches configured for each read
+func New(store api.RDSAreaStore, cfg *config.Config) *Store {
+	return &Store{
+		RDSAreaStore:  store,
+		areas:         newLRU(cfg.AreaCache.Size, cfg.AreaCache.TTL),
+		ancestors:     newLRU(cfg.AncestorsCache.Size, cfg.AncestorsCache.TTL),
+		relationships: newLRU(cfg.RelationshipsCache.Size, cfg.RelationshipsCache.TTL),
+		areaDetails:   newLRU(cfg.AreaDetailsCache.Size, cfg.AreaDetailsCache.TTL),
+		validAreas:    newLRU(cfg.ValidateAreaCache.Size, cfg.ValidateAreaCache.TTL),
+	}
+}
+
+// Enabled reports whether any read is configured to be cached
+func Enabled(cfg *config.Config) bool {
+	return cfg.AreaCache.Size > 0 || cfg.AncestorsCache.Size > 0 || cfg.RelationshipsCache.Size > 0 || cfg.AreaDetailsCache.Size > 0 ||
+		cfg.ValidateAreaCache.Size > 0
+}
+
+// ValidateArea caches the areas found to exist. Areas that are not found are checked with the wrapped store every time.
+func (s *Store) ValidateArea(ctx context.Context, areaCode string, includeInactive bool) error {
+	_, err := s.validAreas.fetch(fmt.Sprintf("%s|%t", areaCode, includeInactive), func() (interface{}, []string, error) {
+		return true, []string{areaCode}, s.RDSAreaStore.ValidateArea(ctx, areaCode, includeInactive)
+	})
+	return err
+}
+
+func (s *Store) GetArea(ctx context.Context, areaCode string, includeInactive bool) (*models.AreasDataResults, error) {
+	value, err := s.areas.fetch(fmt.Sprintf("%s|%t", areaCode, includeInactive), func() (interface{}, []string, error) {
+		area, err := s.RDSAreaStore.GetArea(ctx, areaCode, includeInactive)
+		return area, []string{areaCode}, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value.(*models.AreasDataResults), nil
+}
+
+func (s *Store) GetAncestors(ctx context.Context, areaCode string) ([]models.AreasAncestors, error) {
+	value, err := s.ancestors.fetch(areaCode, func() (interface{}, []string, error) {
+		ancestors, err := s.RDSAreaStore.GetAncestors(ctx, areaCode)
+		return ancestors, append([]string{areaCode}, ancestorCodes(ancestors)...), err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value.([]models.AreasAncestors), nil
+}
+
+func (s *Store) GetRelationships(ctx context.Context, areaCode, relationshipParameter string, includeInactive bool) ([]*models.AreaBasicData, error) {
+	key := fmt.Sprintf("%s|%s|%t", areaCode, relationshipParameter, includeInactive)
+	value, err := s.relationships.fetch(key, func() (interface{}, []string, error) {
+		relationships, err := s.RDSAreaStore.GetRelationships(ctx, areaCode, relationshipParameter, includeInactive)
+		return relationships, append([]string{areaCode}, basicDataCodes(relationships)...), err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value.([]*models.AreaBasicData), nil
+}
+
+func (s *Store) GetAreaDetails(ctx context.Context, areaCode string, opts models.AreaDetailsOptions) (*models.AreaDetails, error) {
+	key := fmt.Sprintf("%s|%t|%t|%t", areaCode, opts.IncludeInactive, opts.Children, opts.Boundary)
+	value, err := s.areaDetails.fetch(key, func() (interface{}, []string, error) {
+		details, err := s.RDSAreaStore.GetAreaDetails(ctx, areaCode, opts)
+		if err != nil {
+			return nil, nil, err
+		}
+		areas := append([]string{areaCode}, ancestorCodes(details.Area.Ancestors)...)
+		return details, append(areas, basicDataCodes(details.Children)...), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value.(*models.AreaDetails), nil
+}
+
+// UpsertArea writes an area and evicts the values built from it or its new parent. They are evicted even when the
+// write fails, as a failed commit may still have been applied.
+func (s *Store) UpsertArea(ctx context.Context, area models.AreaParams, ifMatch string) (bool, error) {
+	defer s.Invalidate(area.Code, area.ParentCode)
+	return s.RDSAreaStore.UpsertArea(ctx, area, ifMatch)
+}
+
+// BulkUpsertAreas writes areas and evicts the values built from any of them or their parents
+func (s *Store) BulkUpsertAreas(ctx context.Context, areas []models.AreaParams, batchSize int) ([]models.BulkAreaResult, error) {
+	codes := make([]string, 0, 2*len(areas))
+	for _, area := range areas {
+		codes = append(codes, area.Code, area.ParentCode)
+	}
+	defer s.Invalidate(codes...)
+	return s.RDSAreaStore.BulkUpsertAreas(ctx, areas, batchSize)
+}
+
+// PatchArea patches an area and evicts the values built from it or the parent it is moved to
+func (s *Store) PatchArea(ctx context.Context, areaCode string, patch models.AreaPatch, ifMatch string) error {
+	codes := []string{areaCode}
+	for _, op := range patch {
+		var parentCode string
+		if op.Path == parentCodePatchPath && json.Unmarshal(op.Value, &parentCode) == nil {
+			codes = append(codes, parentCode)
+		}
+	}
+	defer s.Invalidate(codes...)
+	return s.RDSAreaStore.PatchArea(ctx, areaCode, patch, ifMatch)
+}
+
+// RetireArea retires an area and evicts the values built from it. A cascade retires descendants whose own values do
+// not name the area, so it empties the caches.
+func (s *Store) RetireArea(ctx context.Context, areaCode string, cascade bool, ifMatch string) error {
+	if cascade {
+		defer s.Purge()
+	} else {
+		defer s.Invalidate(areaCode)
+	}
+	return s.RDSAreaStore.RetireArea(ctx, areaCode, cascade, ifMatch)
+}
+
+// BuildTables migrates and seeds the wrapped store, which may change any area
+func (s *Store) BuildTables(ctx context.Context) error {
+	defer s.Purge()
+	return s.RDSAreaStore.BuildTables(ctx)
+}
+
+// Invalidate evicts every cached value built from any of the given area codes
+func (s *Store) Invalidate(areaCodes ...string) {
+	for _, c := range s.caches() {
+		c.invalidate(areaCodes...)
+	}
+}
+
+// Purge evicts every cached value
+func (s *Store) Purge() {
+	for _, c := range s.caches() {
+		c.purge()
+	}
+}
+
+// Stats returns the hit, miss and eviction counts of each cached read, keyed by store method. Reads that are not
+// cached are left out.
+func (s *Store) Stats() map[string]Stats {
+	stats := make(map[string]Stats)
+	for method, c := range s.caches() {
+		if snapshot := c.snapshot(); snapshot != nil {
+			stats[method] = *snapshot
+		}
+	}
+	return stats
+}
+
+func (s *Store) caches() map[string]*lru {
+	return map[string]*lru{
+		"GetArea":          s.areas,
+		"GetAncestors":     s.ancestors,
+		"GetRelationships": s.relationships,
+		"GetAreaDetails":   s.areaDetails,
+		"ValidateArea":     s.validAreas,
+	}
+}
+
+func ancestorCodes(ancestors []models.AreasAncestors) []string {
+	codes := make([]string, 0, len(ancestors))
+	for _, ancestor := range ancestors {
+		codes = append(codes, ancestor.Id)
+	}
+	return codes
+}
+
+func basicDataCodes(areas []*models.AreaBasicData) []string {
+	codes := make([]string, 0, len(areas))
+	for _, area := range areas {
+		codes = append(codes, area.Code)
+	}
+	return codes
+}
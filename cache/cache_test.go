@@ -0,0 +1,190 @@
+package cache_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ONSdigital/dp-areas-api/api/mock"
+	"github.com/ONSdigital/dp-areas-api/apierrors"
+	"github.com/ONSdigital/dp-areas-api/cache"
+	"github.com/ONSdigital/dp-areas-api/config"
+	"github.com/ONSdigital/dp-areas-api/fixtures"
+	"github.com/ONSdigital/dp-areas-api/memory"
+	"github.com/ONSdigital/dp-areas-api/models"
+	"github.com/ONSdigital/dp-areas-api/storetest"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+var cacheConfig = &config.Config{
+	AreaCache:          config.CacheConfig{Size: 10, TTL: time.Minute},
+	AncestorsCache:     config.CacheConfig{Size: 10, TTL: time.Minute},
+	RelationshipsCache: config.CacheConfig{Size: 10, TTL: time.Minute},
+	AreaDetailsCache:   config.CacheConfig{Size: 10, TTL: time.Minute},
+	ValidateAreaCache:  config.CacheConfig{Size: 10, TTL: time.Minute},
+}
+
+// cachedStore is a memory store behind a cache, which empties the cache when fixtures are loaded underneath it
+type cachedStore struct {
+	*cache.Store
+	memory *memory.Store
+}
+
+func newCachedStore() cachedStore {
+	store := memory.New()
+	return cachedStore{Store: cache.New(store, cacheConfig), memory: store}
+}
+
+func (s cachedStore) LoadFixtures(ctx context.Context, set *fixtures.Set) error {
+	defer s.Purge()
+	return s.memory.LoadFixtures(ctx, set)
+}
+
+func TestStore_Conformance(t *testing.T) {
+	storetest.Run(t, func(t *testing.T) storetest.Store {
+		return newCachedStore()
+	})
+}
+
+func TestStore_Invalidation(t *testing.T) {
+	ctx := context.Background()
+	set, err := fixtures.Open(fixtures.DefaultVersion, "")
+	if err != nil {
+		t.Fatalf("failed to open fixtures: %v", err)
+	}
+
+	Convey("Given a cache holding an area, its descendant's ancestry and the children of two areas", t, func() {
+		store := newCachedStore()
+		So(store.LoadFixtures(ctx, set), ShouldBeNil)
+		_, err := store.GetArea(ctx, "E12000003", false)
+		So(err, ShouldBeNil)
+		_, err = store.GetAncestors(ctx, "E08000019")
+		So(err, ShouldBeNil)
+		_, err = store.GetRelationships(ctx, "E12000003", "child", false)
+		So(err, ShouldBeNil)
+		_, err = store.GetRelationships(ctx, "W92000004", "child", false)
+		So(err, ShouldBeNil)
+		_, err = store.GetArea(ctx, "W92000004", false)
+		So(err, ShouldBeNil)
+		So(store.ValidateArea(ctx, "E08000019", false), ShouldBeNil)
+
+		Convey("When the descendant is validated again", func() {
+			err := store.ValidateArea(ctx, "E08000019", false)
+
+			Convey("Then it is served from the cache", func() {
+				So(err, ShouldBeNil)
+				So(store.Stats()["ValidateArea"], ShouldResemble, cache.Stats{Hits: 1, Misses: 1, Entries: 1})
+			})
+		})
+
+		Convey("When the descendant is retired", func() {
+			So(store.RetireArea(ctx, "E08000019", false, ""), ShouldBeNil)
+
+			Convey("Then it is validated again and no longer found", func() {
+				err := store.ValidateArea(ctx, "E08000019", false)
+				So(errors.Is(err, apierrors.ErrNotFound), ShouldBeTrue)
+			})
+		})
+
+		Convey("When the area is renamed", func() {
+			err := store.PatchArea(ctx, "E12000003", models.AreaPatch{{Op: models.PatchOpReplace, Path: "/area_name/name", Value: json.RawMessage(`"Yorkshire"`)}}, "")
+			So(err, ShouldBeNil)
+
+			Convey("Then the area and its descendant's ancestry are read again with the new name", func() {
+				area, err := store.GetArea(ctx, "E12000003", false)
+				So(err, ShouldBeNil)
+				So(*area.Name, ShouldEqual, "Yorkshire")
+				ancestors, err := store.GetAncestors(ctx, "E08000019")
+				So(err, ShouldBeNil)
+				So(ancestors[0], ShouldResemble, models.AreasAncestors{Id: "E12000003", Name: "Yorkshire"})
+			})
+
+			Convey("Then unrelated areas stay cached", func() {
+				_, err := store.GetArea(ctx, "W92000004", false)
+				So(err, ShouldBeNil)
+				So(store.Stats()["GetArea"].Hits, ShouldEqual, 1)
+			})
+		})
+
+		Convey("When a new child is upserted under the area", func() {
+			_, err := store.UpsertArea(ctx, models.AreaParams{
+				Code:       "E08000018",
+				AreaType:   "Country",
+				AreaName:   &models.AreaName{Name: "Rotherham"},
+				ParentCode: "E12000003",
+			}, "")
+			So(err, ShouldBeNil)
+
+			Convey("Then the area's children are read again", func() {
+				children, err := store.GetRelationships(ctx, "E12000003", "child", false)
+				So(err, ShouldBeNil)
+				So(children, ShouldHaveLength, 2)
+			})
+		})
+
+		Convey("When the descendant is upserted under another parent", func() {
+			_, err := store.UpsertArea(ctx, models.AreaParams{
+				Code:       "E08000019",
+				AreaType:   "Country",
+				AreaName:   &models.AreaName{Name: "Sheffield"},
+				ParentCode: "W92000004",
+			}, "")
+			So(err, ShouldBeNil)
+
+			Convey("Then its ancestry and the new parent's children are read again", func() {
+				ancestors, err := store.GetAncestors(ctx, "E08000019")
+				So(err, ShouldBeNil)
+				So(ancestors, ShouldContain, models.AreasAncestors{Id: "W92000004", Name: "Wales"})
+				children, err := store.GetRelationships(ctx, "W92000004", "child", false)
+				So(err, ShouldBeNil)
+				So(children, ShouldContain, &models.AreaBasicData{Code: "E08000019", Name: "Sheffield"})
+			})
+		})
+	})
+}
+
+func TestStore_Stats(t *testing.T) {
+	ctx := context.Background()
+	cfg := &config.Config{AreaCache: config.CacheConfig{Size: 10, TTL: time.Minute}}
+
+	Convey("Given a store with only GetArea cached", t, func() {
+		calls := 0
+		rdsMock := &mock.RDSAreaStoreMock{
+			GetAreaFunc: func(ctx context.Context, areaId string, includeInactive bool) (*models.AreasDataResults, error) {
+				if calls++; areaId == "E00000000" {
+					return nil, apierrors.ErrNoRows
+				}
+				return &models.AreasDataResults{Code: areaId}, nil
+			},
+			GetAncestorsFunc: func(ctx context.Context, areaCode string) ([]models.AreasAncestors, error) {
+				return nil, nil
+			},
+		}
+		store := cache.New(rdsMock, cfg)
+
+		Convey("When areas and their ancestors are read twice", func() {
+			for i := 0; i < 2; i++ {
+				_, err := store.GetArea(ctx, "E92000001", false)
+				So(err, ShouldBeNil)
+				_, err = store.GetArea(ctx, "E00000000", false)
+				So(errors.Is(err, apierrors.ErrNotFound), ShouldBeTrue)
+				_, err = store.GetAncestors(ctx, "E92000001")
+				So(err, ShouldBeNil)
+			}
+
+			Convey("Then only a found area is served from the cache", func() {
+				So(calls, ShouldEqual, 3)
+				So(rdsMock.GetAncestorsCalls(), ShouldHaveLength, 2)
+			})
+
+			Convey("Then hits and misses are reported for GetArea only", func() {
+				So(store.Stats(), ShouldResemble, map[string]cache.Stats{
+					"GetArea": {Hits: 1, Misses: 3, Entries: 1},
+				})
+			})
+		})
+	})
+}
@@ -0,0 +1,179 @@
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// Stats counts the lookups and evictions of one cached store method
+type Stats struct {
+	Hits          uint64 `json:"hits"`
+	Misses        uint64 `json:"misses"`
+	Evictions     uint64 `json:"evictions"`
+	Invalidations uint64 `json:"invalidations"`
+	Entries       int    `json:"entries"`
+}
+
+// entry is a cached value, the area codes it was built from and when it expires
+type entry struct {
+	key     string
+	value   interface{}
+	areas   []string
+	expires time.Time
+}
+
+// lru is a size-bounded cache that evicts the least recently used entry when full and ignores entries older than its
+// TTL. Entries are indexed by the area codes they were built from, so that a write can evict exactly the entries it
+// affects. A nil lru caches nothing.
+type lru struct {
+	mu      sync.Mutex
+	size    int
+	ttl     time.Duration
+	now     func() time.Time
+	order   *list.List
+	entries map[string]*list.Element
+	byArea  map[string]map[string]bool
+	// generation is advanced by every invalidation, so that a value loaded before one is not cached after it
+	generation uint64
+	stats      Stats
+}
+
+// newLRU returns a cache holding up to size entries for ttl, or nil when size is 0
+func newLRU(size int, ttl time.Duration) *lru {
+	if size <= 0 {
+		return nil
+	}
+	return &lru{
+		size:    size,
+		ttl:     ttl,
+		now:     time.Now,
+		order:   list.New(),
+		entries: make(map[string]*list.Element),
+		byArea:  make(map[string]map[string]bool),
+	}
+}
+
+// fetch returns the value cached for key, or loads it and caches it against the area codes load returns. Errors are
+// not cached.
+func (c *lru) fetch(key string, load func() (interface{}, []string, error)) (interface{}, error) {
+	if c == nil {
+		value, _, err := load()
+		return value, err
+	}
+
+	value, generation, ok := c.get(key)
+	if ok {
+		return value, nil
+	}
+
+	value, areas, err := load()
+	if err != nil {
+		return nil, err
+	}
+	c.add(key, value, areas, generation)
+	return value, nil
+}
+
+// get returns the unexpired value cached for key, and the generation to add a value loaded on a miss with
+func (c *lru) get(key string) (interface{}, uint64, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[key]
+	if ok && c.ttl > 0 && !c.now().Before(element.Value.(*entry).expires) {
+		c.remove(element)
+		ok = false
+	}
+	if !ok {
+		c.stats.Misses++
+		return nil, c.generation, false
+	}
+
+	c.stats.Hits++
+	c.order.MoveToFront(element)
+	return element.Value.(*entry).value, c.generation, true
+}
+
+// add caches a value loaded at the given generation, unless an invalidation has happened since
+func (c *lru) add(key string, value interface{}, areas []string, generation uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if generation != c.generation {
+		return
+	}
+	if element, ok := c.entries[key]; ok {
+		c.remove(element)
+	}
+
+	c.entries[key] = c.order.PushFront(&entry{key: key, value: value, areas: areas, expires: c.now().Add(c.ttl)})
+	for _, area := range areas {
+		if c.byArea[area] == nil {
+			c.byArea[area] = make(map[string]bool)
+		}
+		c.byArea[area][key] = true
+	}
+
+	for c.order.Len() > c.size {
+		c.remove(c.order.Back())
+		c.stats.Evictions++
+	}
+}
+
+// invalidate removes every entry built from any of the given area codes
+func (c *lru) invalidate(areas ...string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.generation++
+	for _, area := range areas {
+		for key := range c.byArea[area] {
+			c.remove(c.entries[key])
+			c.stats.Invalidations++
+		}
+	}
+}
+
+// purge removes every entry
+func (c *lru) purge() {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.generation++
+	c.stats.Invalidations += uint64(c.order.Len())
+	c.order.Init()
+	c.entries = make(map[string]*list.Element)
+	c.byArea = make(map[string]map[string]bool)
+}
+
+// remove drops an entry and its area index, with the lock held
+func (c *lru) remove(element *list.Element) {
+	e := c.order.Remove(element).(*entry)
+	delete(c.entries, e.key)
+	for _, area := range e.areas {
+		delete(c.byArea[area], e.key)
+		if len(c.byArea[area]) == 0 {
+			delete(c.byArea, area)
+		}
+	}
+}
+
+// snapshot returns the cache's stats, or nil when it caches nothing
+func (c *lru) snapshot() *Stats {
+	if c == nil {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stats := c.stats
+	stats.Entries = c.order.Len()
+	return &stats
+}
@@ -0,0 +1,97 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestLRU(t *testing.T) {
+	loadValue := func(value string, areas ...string) func() (interface{}, []string, error) {
+		return func() (interface{}, []string, error) {
+			return value, areas, nil
+		}
+	}
+
+	Convey("Given a cache holding two entries", t, func() {
+		now := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+		c := newLRU(2, time.Minute)
+		c.now = func() time.Time { return now }
+		c.fetch("a", loadValue("A", "E92000001"))
+		c.fetch("b", loadValue("B", "W92000004"))
+
+		Convey("When a third entry is added", func() {
+			c.fetch("a", loadValue("unused"))
+			c.fetch("c", loadValue("C"))
+
+			Convey("Then the least recently used entry is evicted", func() {
+				_, _, ok := c.get("b")
+				So(ok, ShouldBeFalse)
+				value, _, ok := c.get("a")
+				So(ok, ShouldBeTrue)
+				So(value, ShouldEqual, "A")
+				So(c.snapshot().Evictions, ShouldEqual, 1)
+				So(c.byArea, ShouldNotContainKey, "W92000004")
+			})
+		})
+
+		Convey("When its TTL has passed", func() {
+			now = now.Add(time.Minute)
+			value, err := c.fetch("a", loadValue("A2"))
+
+			Convey("Then the entry is loaded again", func() {
+				So(err, ShouldBeNil)
+				So(value, ShouldEqual, "A2")
+				So(c.snapshot().Misses, ShouldEqual, 3)
+			})
+		})
+
+		Convey("When an area is invalidated", func() {
+			c.invalidate("E92000001")
+
+			Convey("Then only the entries built from it are removed", func() {
+				_, _, ok := c.get("a")
+				So(ok, ShouldBeFalse)
+				_, _, ok = c.get("b")
+				So(ok, ShouldBeTrue)
+				So(c.snapshot().Invalidations, ShouldEqual, 1)
+			})
+		})
+
+		Convey("When an area is invalidated while an entry is being loaded", func() {
+			value, err := c.fetch("c", func() (interface{}, []string, error) {
+				c.invalidate("W92000004")
+				return "C", nil, nil
+			})
+
+			Convey("Then the loaded value is returned but not cached", func() {
+				So(err, ShouldBeNil)
+				So(value, ShouldEqual, "C")
+				_, _, ok := c.get("c")
+				So(ok, ShouldBeFalse)
+			})
+		})
+
+		Convey("When it is purged", func() {
+			c.purge()
+
+			Convey("Then it is empty", func() {
+				So(c.snapshot().Entries, ShouldEqual, 0)
+				So(c.byArea, ShouldBeEmpty)
+			})
+		})
+	})
+
+	Convey("Given a cache of size 0", t, func() {
+		c := newLRU(0, time.Minute)
+
+		Convey("Then every fetch is loaded and nothing is counted", func() {
+			value, err := c.fetch("a", loadValue("A"))
+			So(err, ShouldBeNil)
+			So(value, ShouldEqual, "A")
+			So(c, ShouldBeNil)
+			So(c.snapshot(), ShouldBeNil)
+		})
+	})
+}
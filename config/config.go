@@ -17,11 +17,18 @@ type Config struct {
 	RDSDBName                  string        `envconfig:"DBNAME"`
 	RDSDBUser                  string        `envconfig:"DBUSER"`
 	RDSDBHost                  string        `envconfig:"DBHOST"`
+	RDSDBReaderHost            string        `envconfig:"DBREADERHOST"`
 	RDSDBPort                  string        `envconfig:"DBPORT"`
 	AWSRegion                  string        `envconfig:"AWSREGION"`
 	RDSDBConnectionTTL         time.Duration `envconfig:"RDSCONNECTIONTTL"`
 	RDSDBMaxConnections        int           `envconfig:"RDSMAXCONNECTIONS"`
 	RDSDBMinConnections        int           `envconfig:"RDSMINCONNECTIONS"`
+	RDSDBCABundle              string        `envconfig:"DBCABUNDLE"`
+	RDSDBMaxConnIdleTime       time.Duration `envconfig:"RDSMAXCONNIDLETIME"`
+	RDSDBHealthCheckPeriod     time.Duration `envconfig:"RDSHEALTHCHECKPERIOD"`
+	RDSDBStatementCacheMode    string        `envconfig:"RDSSTATEMENTCACHEMODE"`
+	// plain connection string used instead of the local or IAM connection settings when set
+	DBDSN string `envconfig:"DBDSN" json:"-"` // Sensitive field which may hold a password.
 	// flag to use local postres instace provided by dp-compose
 	DPPostgresLocal        bool   `envconfig:"USEPOSTGRESLOCAL"`
 	DPPostgresUserName     string `envconfig:"DPPOSTGRESUSERNAME"`
@@ -34,6 +41,55 @@ type Config struct {
 	AWSAccessKey           string `envconfig:"AWS_ACCESS_KEY_ID" json:"-"`     // Sensitive field which should not be output in JSON.
 	AWSSecretKey           string `envconfig:"AWS_SECRET_ACCESS_KEY" json:"-"` // Sensitive field which should not be output in JSON.
 	LoadSampleData         bool   `envconfig:"LOAD_SAMPLE_DATA"`
+	// number of areas written per transaction by the bulk upsert endpoint, 0 applies a request in a single transaction
+	BulkUpsertBatchSize int `envconfig:"BULK_UPSERT_BATCH_SIZE"`
+	// largest bulk upsert request body accepted, larger bodies are rejected with a 413
+	BulkUpsertMaxBodyBytes int64 `envconfig:"BULK_UPSERT_MAX_BODY_BYTES"`
+	// how long an instance waits for another to finish migrating and seeding the database before giving up
+	SchemaLockTimeout time.Duration `envconfig:"SCHEMA_LOCK_TIMEOUT"`
+	// fixture version seeded when LOAD_SAMPLE_DATA is set, and an optional directory whose files override it
+	FixturesVersion string `envconfig:"FIXTURES_VERSION"`
+	FixturesDir     string `envconfig:"FIXTURES_DIR"`
+	// area store used by the service: "postgres", or "memory" for an in-memory store seeded from the fixtures
+	StoreBackend string `envconfig:"STORE_BACKEND"`
+	// longest a store call may run before it is abandoned with a 504, 0 for no limit beyond the request's own
+	QueryTimeout time.Duration `envconfig:"QUERY_TIMEOUT"`
+	// caches in front of each area store read, see CacheConfig
+	AreaCache          CacheConfig `envconfig:"CACHE_AREA"`
+	AncestorsCache     CacheConfig `envconfig:"CACHE_ANCESTORS"`
+	RelationshipsCache CacheConfig `envconfig:"CACHE_RELATIONSHIPS"`
+	AreaDetailsCache   CacheConfig `envconfig:"CACHE_AREA_DETAILS"`
+	ValidateAreaCache  CacheConfig `envconfig:"CACHE_VALIDATE_AREA"`
+	// how long the listener for areas changed by other instances waits before reconnecting
+	CacheListenerRetryInterval time.Duration `envconfig:"CACHE_LISTENER_RETRY_INTERVAL"`
+	// last good responses kept to serve public reads while the database is unavailable, 0 disables degraded mode
+	StaleResponsesSize int `envconfig:"STALE_RESPONSES_SIZE"`
+	// file the stale responses are saved to every interval and loaded from at startup, not saved when empty
+	StaleSnapshotFile     string        `envconfig:"STALE_SNAPSHOT_FILE"`
+	StaleSnapshotInterval time.Duration `envconfig:"STALE_SNAPSHOT_INTERVAL"`
+	// Cache-Control header of each public read, not sent when empty
+	AreaCacheControl      string `envconfig:"CACHE_CONTROL_AREA"`
+	RelationsCacheControl string `envconfig:"CACHE_CONTROL_RELATIONS"`
+	BoundaryCacheControl  string `envconfig:"CACHE_CONTROL_BOUNDARY"`
+	// verifier of the user and service tokens sent to private endpoints: "zebedee", or "stub" for local development
+	AuthVerifier string `envconfig:"AUTH_VERIFIER"`
+	ZebedeeURL   string `envconfig:"ZEBEDEE_URL"`
+	// JSON file of the permissions granted to each user and service Zebedee identifies, none are granted when empty
+	AuthPermissionsFile string `envconfig:"AUTH_PERMISSIONS_FILE"`
+	// OTLP/HTTP collector spans are exported to as host:port, spans are dropped when empty
+	OTExporterOTLPEndpoint string `envconfig:"OTEL_EXPORTER_OTLP_ENDPOINT"`
+	// send spans to the collector over plain HTTP rather than HTTPS
+	OTExporterOTLPInsecure bool `envconfig:"OTEL_EXPORTER_OTLP_INSECURE"`
+	// service name spans are reported under, and the longest spans wait to be exported in a batch
+	OTServiceName  string        `envconfig:"OTEL_SERVICE_NAME"`
+	OTBatchTimeout time.Duration `envconfig:"OTEL_BATCH_TIMEOUT"`
+}
+
+// CacheConfig bounds the cache of one area store read, set by <PREFIX>_SIZE and <PREFIX>_TTL. A size of 0 disables
+// the cache and a TTL of 0 keeps values until they are evicted.
+type CacheConfig struct {
+	Size int           `envconfig:"SIZE"`
+	TTL  time.Duration `envconfig:"TTL"`
 }
 
 func (c Config) GetRDSEndpoint() string {
@@ -46,6 +102,26 @@ const (
 	AreasCollection = "AreasCollection"
 )
 
+// Statement cache modes, see stmtcache in github.com/jackc/pgconn. Describe suits connection poolers that do not
+// support named prepared statements.
+const (
+	StatementCachePrepare  = "prepare"
+	StatementCacheDescribe = "describe"
+	StatementCacheNone     = "none"
+)
+
+// Area store backends
+const (
+	StorePostgres = "postgres"
+	StoreMemory   = "memory"
+)
+
+// Verifiers of the tokens sent to private endpoints
+const (
+	AuthVerifierZebedee = "zebedee"
+	AuthVerifierStub    = "stub"
+)
+
 // Get returns the default config with any modifications through environment
 // variables
 func Get() (*Config, error) {
@@ -66,9 +142,38 @@ func Get() (*Config, error) {
 		RDSDBConnectionTTL:         24 * time.Hour,
 		RDSDBMaxConnections:        4,
 		RDSDBMinConnections:        1,
+		RDSDBMaxConnIdleTime:       30 * time.Minute,
+		RDSDBHealthCheckPeriod:     time.Minute,
+		RDSDBStatementCacheMode:    StatementCachePrepare,
 		EnablePrivateEndpoints:     true,
 		LoadSampleData:             false,
 		S3Bucket:                   "ons-dp-area-boundaries",
+		BulkUpsertBatchSize:        0,
+		BulkUpsertMaxBodyBytes:     10 << 20,
+		SchemaLockTimeout:          2 * time.Minute,
+		FixturesVersion:            "v1",
+		FixturesDir:                "",
+		StoreBackend:               StorePostgres,
+		QueryTimeout:               10 * time.Second,
+		AreaCache:                  CacheConfig{Size: 100, TTL: 15 * time.Minute},
+		AncestorsCache:             CacheConfig{Size: 10000, TTL: 15 * time.Minute},
+		RelationshipsCache:         CacheConfig{Size: 10000, TTL: 15 * time.Minute},
+		AreaDetailsCache:           CacheConfig{Size: 100, TTL: 15 * time.Minute},
+		ValidateAreaCache:          CacheConfig{Size: 10000, TTL: 15 * time.Minute},
+		CacheListenerRetryInterval: 5 * time.Second,
+		StaleResponsesSize:         1000,
+		StaleSnapshotFile:          "",
+		StaleSnapshotInterval:      5 * time.Minute,
+		AreaCacheControl:           "public, max-age=300",
+		RelationsCacheControl:      "public, max-age=300",
+		BoundaryCacheControl:       "public, max-age=86400",
+		AuthVerifier:               AuthVerifierZebedee,
+		ZebedeeURL:                 "http://localhost:8082",
+		AuthPermissionsFile:        "",
+		OTExporterOTLPEndpoint:     "",
+		OTExporterOTLPInsecure:     false,
+		OTServiceName:              "dp-areas-api",
+		OTBatchTimeout:             5 * time.Second,
 	}
 
 	return cfg, envconfig.Process("", cfg)
@@ -79,12 +184,40 @@ func (c Config) GetDBEndpoint() string {
 	return fmt.Sprintf("%s:%s", c.RDSDBHost, c.RDSDBPort)
 }
 
+// GetDBReaderEndpoint gets the sql reader endpoint, or the writer endpoint when no reader is configured
+func (c Config) GetDBReaderEndpoint() string {
+	if !c.HasDBReader() {
+		return c.GetDBEndpoint()
+	}
+	return fmt.Sprintf("%s:%s", c.RDSDBReaderHost, c.RDSDBPort)
+}
+
+// HasDBReader reports whether reads are served by a separate reader endpoint. Local instances and plain connection
+// strings have only one.
+func (c Config) HasDBReader() bool {
+	return c.RDSDBReaderHost != "" && !c.DPPostgresLocal && c.DBDSN == ""
+}
+
 // GetLocalDBConnectionString returns local connection string
 func (c Config) GetLocalDBConnectionString() string {
 	return fmt.Sprintf("postgres://%s:%s@localhost:%s/%s", c.DPPostgresUserName, c.DPPostgresUserPassword, c.DPPostgresLocalPort, c.DPPostgresLocalDB)
 }
 
-// GetRemoteDBConnectionString returns remote connection string
-func (c Config) GetRemoteDBConnectionString(authToken string) string {
-	return fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s pool_max_conns=%d pool_min_conns=%d pool_max_conn_lifetime=%s", c.RDSDBHost, c.RDSDBPort, c.RDSDBUser, authToken, c.RDSDBName, c.RDSDBMaxConnections, c.RDSDBMinConnections, c.RDSDBConnectionTTL)
+// GetRemoteDBConnectionString returns remote connection string. It has no password, as every connection signs its own
+// IAM auth token, and requires TLS verified against the CA bundle, or the system roots when none is configured.
+func (c Config) GetRemoteDBConnectionString() string {
+	return c.remoteDBConnectionString(c.RDSDBHost)
+}
+
+// GetRemoteDBReaderConnectionString returns the remote connection string for the reader endpoint
+func (c Config) GetRemoteDBReaderConnectionString() string {
+	return c.remoteDBConnectionString(c.RDSDBReaderHost)
+}
+
+func (c Config) remoteDBConnectionString(host string) string {
+	connectionString := fmt.Sprintf("host=%s port=%s user=%s dbname=%s sslmode=verify-full", host, c.RDSDBPort, c.RDSDBUser, c.RDSDBName)
+	if c.RDSDBCABundle != "" {
+		connectionString += fmt.Sprintf(" sslrootcert=%s", c.RDSDBCABundle)
+	}
+	return connectionString
 }
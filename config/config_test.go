@@ -36,9 +36,38 @@ func TestConfig(t *testing.T) {
 					RDSDBConnectionTTL:         24 * time.Hour,
 					RDSDBMaxConnections:        4,
 					RDSDBMinConnections:        1,
+					RDSDBMaxConnIdleTime:       30 * time.Minute,
+					RDSDBHealthCheckPeriod:     time.Minute,
+					RDSDBStatementCacheMode:    StatementCachePrepare,
 					EnablePrivateEndpoints:     true,
 					S3Bucket:                   "ons-dp-area-boundaries",
 					LoadSampleData:             false,
+					BulkUpsertBatchSize:        0,
+					BulkUpsertMaxBodyBytes:     10 << 20,
+					SchemaLockTimeout:          2 * time.Minute,
+					FixturesVersion:            "v1",
+					FixturesDir:                "",
+					StoreBackend:               StorePostgres,
+					QueryTimeout:               10 * time.Second,
+					AreaCache:                  CacheConfig{Size: 100, TTL: 15 * time.Minute},
+					AncestorsCache:             CacheConfig{Size: 10000, TTL: 15 * time.Minute},
+					RelationshipsCache:         CacheConfig{Size: 10000, TTL: 15 * time.Minute},
+					AreaDetailsCache:           CacheConfig{Size: 100, TTL: 15 * time.Minute},
+					ValidateAreaCache:          CacheConfig{Size: 10000, TTL: 15 * time.Minute},
+					CacheListenerRetryInterval: 5 * time.Second,
+					StaleResponsesSize:         1000,
+					StaleSnapshotFile:          "",
+					StaleSnapshotInterval:      5 * time.Minute,
+					AreaCacheControl:           "public, max-age=300",
+					RelationsCacheControl:      "public, max-age=300",
+					BoundaryCacheControl:       "public, max-age=86400",
+					AuthVerifier:               AuthVerifierZebedee,
+					ZebedeeURL:                 "http://localhost:8082",
+					AuthPermissionsFile:        "",
+					OTExporterOTLPEndpoint:     "",
+					OTExporterOTLPInsecure:     false,
+					OTServiceName:              "dp-areas-api",
+					OTBatchTimeout:             5 * time.Second,
 				})
 			})
 
@@ -69,6 +98,7 @@ func TestConfig(t *testing.T) {
 			LoadSampleData:             false,
 			AWSAccessKey:               "awsAccessKeyID",     // Sensitive field.
 			AWSSecretKey:               "awsSecretAccessKey", // Sensitive field.
+			DBDSN:                      "postgres://user:dbPassword@db:5432/dp-areas-api",
 		}
 
 		Convey("When the config struct is marshalled to JSON", func() {
@@ -86,6 +116,82 @@ func TestConfig(t *testing.T) {
 				So(out, ShouldNotContainSubstring, "AWS_SECRET_ACCESS_KEY")
 				So(out, ShouldNotContainSubstring, "awsSecretAccessKey")
 			})
+
+			Convey("And the output does not contain the database connection string", func() {
+				So(out, ShouldNotContainSubstring, "dbPassword")
+			})
+		})
+	})
+}
+
+func TestDBReader(t *testing.T) {
+	Convey("Given a remote database with a reader endpoint", t, func() {
+		cfg := Config{RDSDBHost: "writer.cluster", RDSDBReaderHost: "reader.cluster-ro", RDSDBPort: "5432"}
+
+		Convey("Then reads are served by the reader", func() {
+			So(cfg.HasDBReader(), ShouldBeTrue)
+			So(cfg.GetDBEndpoint(), ShouldEqual, "writer.cluster:5432")
+			So(cfg.GetDBReaderEndpoint(), ShouldEqual, "reader.cluster-ro:5432")
+			So(cfg.GetRemoteDBReaderConnectionString(), ShouldStartWith, "host=reader.cluster-ro port=5432")
+		})
+	})
+
+	Convey("Given a remote database without a reader endpoint", t, func() {
+		cfg := Config{RDSDBHost: "writer.cluster", RDSDBPort: "5432"}
+
+		Convey("Then reads fall back to the writer", func() {
+			So(cfg.HasDBReader(), ShouldBeFalse)
+			So(cfg.GetDBReaderEndpoint(), ShouldEqual, "writer.cluster:5432")
+		})
+	})
+
+	Convey("Given a local database with a reader endpoint set", t, func() {
+		cfg := Config{RDSDBReaderHost: "reader.cluster-ro", DPPostgresLocal: true}
+
+		Convey("Then the reader is ignored", func() {
+			So(cfg.HasDBReader(), ShouldBeFalse)
+		})
+	})
+}
+
+func TestGetRemoteDBConnectionString(t *testing.T) {
+	Convey("Given a remote database", t, func() {
+		cfg := Config{RDSDBHost: "writer.cluster", RDSDBPort: "5432", RDSDBUser: "dp-areas-api-publishing", RDSDBName: "dp-areas-api"}
+
+		Convey("Then its connection string requires verified TLS and has no password", func() {
+			connectionString := cfg.GetRemoteDBConnectionString()
+			So(connectionString, ShouldContainSubstring, "sslmode=verify-full")
+			So(connectionString, ShouldNotContainSubstring, "password")
+			So(connectionString, ShouldNotContainSubstring, "sslrootcert")
+		})
+
+		Convey("Then a CA bundle is used as the root certificates when configured", func() {
+			cfg.RDSDBCABundle = "/etc/ssl/rds-global-bundle.pem"
+			So(cfg.GetRemoteDBConnectionString(), ShouldEndWith, "sslrootcert=/etc/ssl/rds-global-bundle.pem")
+		})
+	})
+}
+
+func TestCacheConfig(t *testing.T) {
+	Convey("Given cache settings in the environment", t, func() {
+		os.Clearenv()
+		cfg = nil
+		os.Setenv("CACHE_AREA_SIZE", "0")
+		os.Setenv("CACHE_ANCESTORS_TTL", "1m")
+		defer func() {
+			os.Clearenv()
+			cfg = nil
+		}()
+
+		Convey("When the config values are retrieved", func() {
+			configuration, err := Get()
+
+			Convey("Then each read's cache is configured by its own prefix", func() {
+				So(err, ShouldBeNil)
+				So(configuration.AreaCache, ShouldResemble, CacheConfig{Size: 0, TTL: 15 * time.Minute})
+				So(configuration.AncestorsCache, ShouldResemble, CacheConfig{Size: 10000, TTL: time.Minute})
+				So(configuration.RelationshipsCache, ShouldResemble, CacheConfig{Size: 10000, TTL: 15 * time.Minute})
+			})
 		})
 	})
 }
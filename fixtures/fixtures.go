@@ -0,0 +1,216 @@
+// Package fixtures loads the sample data used to seed local and test databases. Fixtures are versioned: each version
+// is a directory under data holding one JSON or CSV file per table.
+package fixtures
+
+import (
+	"bytes"
+	"embed"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+)
+
+//go:embed data
+var embedded embed.FS
+
+// DefaultVersion is the fixture version loaded when none is configured
+const DefaultVersion = "v1"
+
+// Fixture file names, without extension, in the order their tables must be loaded to satisfy foreign keys
+const (
+	AreaTypesFile         = "area_types"
+	RelationshipTypesFile = "relationship_types"
+	AreasFile             = "areas"
+	AreaNamesFile         = "area_names"
+	AreaRelationshipsFile = "area_relationships"
+	BoundariesFile        = "boundaries"
+)
+
+// ErrMissingFile is returned when a fixture version has neither a JSON nor a CSV file for a table
+var ErrMissingFile = errors.New("fixture file not found")
+
+// AreaType is a row of area_type
+type AreaType struct {
+	Name string `json:"name"`
+}
+
+// RelationshipType is a row of relationship_type
+type RelationshipType struct {
+	Name string `json:"name"`
+}
+
+// Area is a row of area, referring to its area type by name
+type Area struct {
+	Code          string  `json:"code"`
+	ActiveFrom    *string `json:"active_from"`
+	ActiveTo      *string `json:"active_to"`
+	AreaType      string  `json:"area_type"`
+	GeometricArea string  `json:"geometric_area"`
+	Visible       Bool    `json:"visible"`
+}
+
+// AreaName is a row of area_name
+type AreaName struct {
+	AreaCode   string  `json:"area_code"`
+	Name       string  `json:"name"`
+	ActiveFrom *string `json:"active_from"`
+	ActiveTo   *string `json:"active_to"`
+}
+
+// AreaRelationship is a row of area_relationship, referring to its relationship type by name
+type AreaRelationship struct {
+	AreaCode    string `json:"area_code"`
+	RelAreaCode string `json:"rel_area_code"`
+	RelType     string `json:"rel_type"`
+}
+
+// Boundary is a row of boundaries
+type Boundary struct {
+	AreaID      string `json:"area_id"`
+	CentroidBng string `json:"centroid_bng"`
+	Centroid    string `json:"centroid"`
+	Boundary    string `json:"boundary"`
+}
+
+// Set is every fixture of one version
+type Set struct {
+	Version           string
+	AreaTypes         []AreaType
+	RelationshipTypes []RelationshipType
+	Areas             []Area
+	AreaNames         []AreaName
+	AreaRelationships []AreaRelationship
+	Boundaries        []Boundary
+}
+
+// Bool is a boolean that can also be read from the strings "true" and "false", as CSV fixtures hold them
+type Bool bool
+
+// UnmarshalJSON accepts a JSON boolean or a quoted one
+func (b *Bool) UnmarshalJSON(data []byte) error {
+	var value bool
+	if err := json.Unmarshal(bytes.Trim(data, `"`), &value); err != nil {
+		return fmt.Errorf("invalid boolean %s", data)
+	}
+	*b = Bool(value)
+	return nil
+}
+
+// Open loads and validates the given fixture version from the embedded fixtures. When dir is set, any fixture file in
+// dir/<version> replaces the embedded file of the same table.
+func Open(version, dir string) (*Set, error) {
+	if version == "" {
+		version = DefaultVersion
+	}
+	sources := []fs.FS{mustSub(embedded, "data")}
+	if dir != "" {
+		sources = append([]fs.FS{os.DirFS(dir)}, sources...)
+	}
+
+	set, err := load(version, sources)
+	if err != nil {
+		return nil, err
+	}
+	return set, set.Validate()
+}
+
+// Load reads a fixture version from fsys, which holds one directory per version. Each table is read from
+// <version>/<table>.json or, if there is none, <version>/<table>.csv. Load does not validate the set.
+func Load(fsys fs.FS, version string) (*Set, error) {
+	return load(version, []fs.FS{fsys})
+}
+
+// load reads each table from the first source that has a file for it
+func load(version string, sources []fs.FS) (*Set, error) {
+	set := &Set{Version: version}
+	files := []struct {
+		name string
+		dest interface{}
+	}{
+		{AreaTypesFile, &set.AreaTypes},
+		{RelationshipTypesFile, &set.RelationshipTypes},
+		{AreasFile, &set.Areas},
+		{AreaNamesFile, &set.AreaNames},
+		{AreaRelationshipsFile, &set.AreaRelationships},
+		{BoundariesFile, &set.Boundaries},
+	}
+	for _, file := range files {
+		if err := readFile(sources, path.Join(version, file.name), file.dest); err != nil {
+			return nil, err
+		}
+	}
+	return set, nil
+}
+
+// readFile decodes name.json, or name.csv converted to JSON objects keyed by the CSV header, into dest
+func readFile(sources []fs.FS, name string, dest interface{}) error {
+	for _, fsys := range sources {
+		data, err := fs.ReadFile(fsys, name+".json")
+		if errors.Is(err, fs.ErrNotExist) {
+			data, err = fs.ReadFile(fsys, name+".csv")
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			if err == nil {
+				data, err = csvToJSON(data)
+			}
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(dest); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		return nil
+	}
+	return fmt.Errorf("%w: %s", ErrMissingFile, name)
+}
+
+// csvToJSON converts CSV with a header row to a JSON array of objects. Empty values are left out so that they decode
+// as null.
+func csvToJSON(data []byte) ([]byte, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	header, err := reader.Read()
+	if err != nil {
+		return nil, err
+	}
+	for i := range header {
+		header[i] = strings.TrimSpace(header[i])
+	}
+
+	rows := make([]map[string]string, 0)
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		row := make(map[string]string, len(header))
+		for i, value := range record {
+			if value != "" {
+				row[header[i]] = value
+			}
+		}
+		rows = append(rows, row)
+	}
+	return json.Marshal(rows)
+}
+
+func mustSub(fsys fs.FS, dir string) fs.FS {
+	sub, err := fs.Sub(fsys, dir)
+	if err != nil {
+		panic(err)
+	}
+	return sub
+}
@@ -0,0 +1,198 @@
+package fixtures_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/ONSdigital/dp-areas-api/fixtures"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// minimalFiles is a valid fixture version with one area of each kind of row
+var minimalFiles = map[string]string{
+	"v2/area_types.json":         `[{"name": "Country"}]`,
+	"v2/relationship_types.json": `[{"name": "child"}]`,
+	"v2/areas.json":              `[{"code": "E92000001", "area_type": "Country", "visible": true}]`,
+	"v2/area_names.json":         `[{"area_code": "E92000001", "name": "England"}]`,
+	"v2/area_relationships.json": `[]`,
+	"v2/boundaries.json":         `[]`,
+}
+
+func mapFS(files map[string]string) fstest.MapFS {
+	fsys := fstest.MapFS{}
+	for name, data := range files {
+		fsys[name] = &fstest.MapFile{Data: []byte(data)}
+	}
+	return fsys
+}
+
+func TestOpen(t *testing.T) {
+	Convey("Given the embedded fixtures", t, func() {
+		Convey("When the default version is opened", func() {
+			set, err := fixtures.Open("", "")
+
+			Convey("Then every table is loaded and the set is valid", func() {
+				So(err, ShouldBeNil)
+				So(set.Version, ShouldEqual, fixtures.DefaultVersion)
+				So(set.AreaTypes, ShouldNotBeEmpty)
+				So(set.RelationshipTypes, ShouldNotBeEmpty)
+				So(set.Areas, ShouldHaveLength, 7)
+				So(set.AreaNames, ShouldHaveLength, 7)
+				So(set.AreaRelationships, ShouldHaveLength, 4)
+				boundary, ok := set.Boundary("E92000001")
+				So(ok, ShouldBeTrue)
+				So(boundary.Centroid, ShouldEqual, "[-4.333344310304969,51.7249345567844]")
+			})
+		})
+
+		Convey("When a version that does not exist is opened", func() {
+			_, err := fixtures.Open("v99", "")
+
+			Convey("Then a missing file error is returned", func() {
+				So(errors.Is(err, fixtures.ErrMissingFile), ShouldBeTrue)
+			})
+		})
+	})
+
+	Convey("Given a fixtures directory that overrides one table", t, func() {
+		dir := t.TempDir()
+		So(os.Mkdir(filepath.Join(dir, "v1"), 0755), ShouldBeNil)
+		err := os.WriteFile(filepath.Join(dir, "v1", "boundaries.csv"), []byte("area_id,centroid_bng,centroid,boundary\nW92000004,\"[1,2]\",\"[3,4]\",[]\n"), 0644)
+		So(err, ShouldBeNil)
+
+		Convey("When the default version is opened", func() {
+			set, err := fixtures.Open("v1", dir)
+
+			Convey("Then the table is read from disk and the rest are embedded", func() {
+				So(err, ShouldBeNil)
+				So(set.Boundaries, ShouldResemble, []fixtures.Boundary{{AreaID: "W92000004", CentroidBng: "[1,2]", Centroid: "[3,4]", Boundary: "[]"}})
+				So(set.Areas, ShouldHaveLength, 7)
+			})
+		})
+	})
+}
+
+func TestLoad(t *testing.T) {
+	Convey("Given a fixture version with a CSV table", t, func() {
+		files := map[string]string{}
+		for name, data := range minimalFiles {
+			files[name] = data
+		}
+		delete(files, "v2/areas.json")
+		files["v2/areas.csv"] = "code,active_from,active_to,area_type,geometric_area,visible\nE92000001,2004-10-19,,Country,[],true\n"
+
+		Convey("When it is loaded", func() {
+			set, err := fixtures.Load(mapFS(files), "v2")
+
+			Convey("Then the CSV rows are decoded with empty values as null", func() {
+				So(err, ShouldBeNil)
+				So(set.Areas, ShouldHaveLength, 1)
+				So(set.Areas[0].Code, ShouldEqual, "E92000001")
+				So(*set.Areas[0].ActiveFrom, ShouldEqual, "2004-10-19")
+				So(set.Areas[0].ActiveTo, ShouldBeNil)
+				So(bool(set.Areas[0].Visible), ShouldBeTrue)
+				So(set.Validate(), ShouldBeNil)
+			})
+		})
+	})
+
+	Convey("Given a fixture file with an unknown column", t, func() {
+		files := map[string]string{}
+		for name, data := range minimalFiles {
+			files[name] = data
+		}
+		files["v2/area_types.json"] = `[{"name": "Country", "creation_order": 0}]`
+
+		Convey("When it is loaded", func() {
+			_, err := fixtures.Load(mapFS(files), "v2")
+
+			Convey("Then the file is rejected", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldContainSubstring, "v2/area_types")
+			})
+		})
+	})
+}
+
+func TestSet_Validate(t *testing.T) {
+	Convey("Given a fixture set with broken references", t, func() {
+		set := &fixtures.Set{
+			Version:           "v2",
+			AreaTypes:         []fixtures.AreaType{{Name: "Country"}},
+			RelationshipTypes: []fixtures.RelationshipType{{Name: "child"}},
+			Areas: []fixtures.Area{
+				{Code: "E92000001", AreaType: "Country"},
+				{Code: "E92000001", AreaType: "Region"},
+			},
+			AreaNames:         []fixtures.AreaName{{AreaCode: "W92000004", Name: "Wales"}},
+			AreaRelationships: []fixtures.AreaRelationship{{AreaCode: "E92000001", RelAreaCode: "E12000003", RelType: "parent"}},
+			Boundaries:        []fixtures.Boundary{{AreaID: ""}},
+		}
+
+		Convey("When it is validated", func() {
+			err := set.Validate()
+
+			Convey("Then every problem is reported", func() {
+				var validationErr *fixtures.ValidationError
+				So(errors.As(err, &validationErr), ShouldBeTrue)
+				So(validationErr.Problems, ShouldResemble, []string{
+					`areas[1]: duplicate code "E92000001"`,
+					`areas[1]: unknown area_type "Region"`,
+					`area_names[0]: unknown area_code "W92000004"`,
+					`area_relationships[0]: unknown rel_area_code "E12000003"`,
+					`area_relationships[0]: unknown rel_type "parent"`,
+					`boundaries[0]: area_id is required`,
+				})
+			})
+		})
+	})
+}
+
+func TestSet_Validate_Keys(t *testing.T) {
+	from2004, from2011 := "2004-10-19 10:23:54 UTC", "2011-03-12 10:23:54 UTC"
+
+	Convey("Given a fixture set in which areas share names and are related by several types", t, func() {
+		set := &fixtures.Set{
+			Version:           "v2",
+			AreaTypes:         []fixtures.AreaType{{Name: "Country"}},
+			RelationshipTypes: []fixtures.RelationshipType{{Name: "child"}, {Name: "related"}},
+			Areas:             []fixtures.Area{{Code: "E92000001", AreaType: "Country"}, {Code: "W92000004", AreaType: "Country"}},
+			AreaNames: []fixtures.AreaName{
+				{AreaCode: "E92000001", Name: "England", ActiveFrom: &from2004},
+				{AreaCode: "E92000001", Name: "England", ActiveFrom: &from2011},
+				{AreaCode: "W92000004", Name: "England", ActiveFrom: &from2004},
+			},
+			AreaRelationships: []fixtures.AreaRelationship{
+				{AreaCode: "E92000001", RelAreaCode: "W92000004", RelType: "child"},
+				{AreaCode: "E92000001", RelAreaCode: "W92000004", RelType: "related"},
+			},
+		}
+
+		Convey("When it is validated", func() {
+			err := set.Validate()
+
+			Convey("Then it is valid", func() {
+				So(err, ShouldBeNil)
+			})
+		})
+
+		Convey("When a name and a relationship are repeated", func() {
+			set.AreaNames = append(set.AreaNames, fixtures.AreaName{AreaCode: "E92000001", Name: "England", ActiveFrom: &from2011})
+			set.AreaRelationships = append(set.AreaRelationships, fixtures.AreaRelationship{AreaCode: "E92000001", RelAreaCode: "W92000004", RelType: "related"})
+			err := set.Validate()
+
+			Convey("Then both duplicates are reported", func() {
+				var validationErr *fixtures.ValidationError
+				So(errors.As(err, &validationErr), ShouldBeTrue)
+				So(validationErr.Problems, ShouldResemble, []string{
+					`area_names[3]: duplicate name "England" for area "E92000001" from "2011-03-12 10:23:54 UTC"`,
+					`area_relationships[2]: duplicate relationship E92000001/W92000004/related`,
+				})
+			})
+		})
+	})
+}
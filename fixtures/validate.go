@@ -0,0 +1,120 @@
+package fixtures
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ValidationError lists every problem found in a fixture set
+type ValidationError struct {
+	Version  string
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("invalid fixtures %s: %s", e.Version, strings.Join(e.Problems, "; "))
+}
+
+// Validate checks that every row has its key, that keys are unique and that every reference between fixtures
+// resolves, so that a set can be loaded without violating a constraint
+func (s *Set) Validate() error {
+	var problems []string
+	addProblem := func(format string, args ...interface{}) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	areaTypes := make(map[string]bool)
+	for i, areaType := range s.AreaTypes {
+		if areaType.Name == "" {
+			addProblem("%s[%d]: name is required", AreaTypesFile, i)
+		} else if areaTypes[areaType.Name] {
+			addProblem("%s[%d]: duplicate name %q", AreaTypesFile, i, areaType.Name)
+		}
+		areaTypes[areaType.Name] = true
+	}
+
+	relationshipTypes := make(map[string]bool)
+	for i, relationshipType := range s.RelationshipTypes {
+		if relationshipType.Name == "" {
+			addProblem("%s[%d]: name is required", RelationshipTypesFile, i)
+		} else if relationshipTypes[relationshipType.Name] {
+			addProblem("%s[%d]: duplicate name %q", RelationshipTypesFile, i, relationshipType.Name)
+		}
+		relationshipTypes[relationshipType.Name] = true
+	}
+
+	areas := make(map[string]bool)
+	for i, area := range s.Areas {
+		if area.Code == "" {
+			addProblem("%s[%d]: code is required", AreasFile, i)
+		} else if areas[area.Code] {
+			addProblem("%s[%d]: duplicate code %q", AreasFile, i, area.Code)
+		}
+		areas[area.Code] = true
+		if !areaTypes[area.AreaType] {
+			addProblem("%s[%d]: unknown area_type %q", AreasFile, i, area.AreaType)
+		}
+	}
+
+	// areas may share a name, and an area may have the same name over several periods
+	names := make(map[string]bool)
+	for i, name := range s.AreaNames {
+		activeFrom := ""
+		if name.ActiveFrom != nil {
+			activeFrom = *name.ActiveFrom
+		}
+		key := name.AreaCode + "/" + name.Name + "/" + activeFrom
+		if name.Name == "" {
+			addProblem("%s[%d]: name is required", AreaNamesFile, i)
+		} else if names[key] {
+			addProblem("%s[%d]: duplicate name %q for area %q from %q", AreaNamesFile, i, name.Name, name.AreaCode, activeFrom)
+		}
+		names[key] = true
+		if !areas[name.AreaCode] {
+			addProblem("%s[%d]: unknown area_code %q", AreaNamesFile, i, name.AreaCode)
+		}
+	}
+
+	relationships := make(map[string]bool)
+	for i, relationship := range s.AreaRelationships {
+		if !areas[relationship.AreaCode] {
+			addProblem("%s[%d]: unknown area_code %q", AreaRelationshipsFile, i, relationship.AreaCode)
+		}
+		if !areas[relationship.RelAreaCode] {
+			addProblem("%s[%d]: unknown rel_area_code %q", AreaRelationshipsFile, i, relationship.RelAreaCode)
+		}
+		if !relationshipTypes[relationship.RelType] {
+			addProblem("%s[%d]: unknown rel_type %q", AreaRelationshipsFile, i, relationship.RelType)
+		}
+		key := relationship.AreaCode + "/" + relationship.RelAreaCode + "/" + relationship.RelType
+		if relationships[key] {
+			addProblem("%s[%d]: duplicate relationship %s", AreaRelationshipsFile, i, key)
+		}
+		relationships[key] = true
+	}
+
+	boundaries := make(map[string]bool)
+	for i, boundary := range s.Boundaries {
+		if boundary.AreaID == "" {
+			addProblem("%s[%d]: area_id is required", BoundariesFile, i)
+		} else if boundaries[boundary.AreaID] {
+			addProblem("%s[%d]: duplicate area_id %q", BoundariesFile, i, boundary.AreaID)
+		}
+		boundaries[boundary.AreaID] = true
+	}
+
+	if len(problems) != 0 {
+		return &ValidationError{Version: s.Version, Problems: problems}
+	}
+	return nil
+}
+
+// Boundary returns the boundary fixture for an area
+func (s *Set) Boundary(areaID string) (Boundary, bool) {
+	for _, boundary := range s.Boundaries {
+		if boundary.AreaID == areaID {
+			return boundary, true
+		}
+	}
+	return Boundary{}, false
+}
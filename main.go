@@ -32,6 +32,27 @@ func main() {
 	log.Namespace = serviceName
 	ctx := context.Background()
 
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "migrate":
+			if err := migrate(ctx, os.Args[2:]); err != nil {
+				log.Fatal(ctx, "migration command failed", err)
+				os.Exit(1)
+			}
+			return
+		case "schema-drift":
+			inSync, err := schemaDrift(ctx, os.Args[2:])
+			if err != nil {
+				log.Fatal(ctx, "schema drift command failed", err)
+				os.Exit(1)
+			}
+			if !inSync {
+				os.Exit(driftExitCode)
+			}
+			return
+		}
+	}
+
 	if err := run(ctx); err != nil {
 		log.Fatal(nil, "fatal runtime error", err)
 		os.Exit(1)
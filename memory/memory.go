@@ -0,0 +1,859 @@
+// Package memory is an in-memory implementation of api.RDSAreaStore for local development and tests. It follows the
+// same semantics as the Postgres store in package rds, which the storetest conformance suite checks for both.
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ONSdigital/dp-areas-api/apierrors"
+	"github.com/ONSdigital/dp-areas-api/auth"
+	"github.com/ONSdigital/dp-areas-api/config"
+	"github.com/ONSdigital/dp-areas-api/fixtures"
+	"github.com/ONSdigital/dp-areas-api/models"
+	"github.com/ONSdigital/log.go/v2/log"
+)
+
+const (
+	childRelationship = "child"
+	// maxClosureDepth matches the limit the rds store places on ancestry, guarding against cycles
+	maxClosureDepth = 32
+)
+
+// fixtureTimeLayouts are the timestamp formats accepted in fixture files
+var fixtureTimeLayouts = []string{"2006-01-02 15:04:05 MST", "2006-01-02 15:04:05", time.RFC3339, "2006-01-02"}
+
+type area struct {
+	code       string
+	activeFrom *time.Time
+	activeTo   *time.Time
+	geometry   string
+	areaType   string
+	visible    *bool
+	hectares   float64
+	version    int
+	updatedAt  time.Time
+}
+
+type areaName struct {
+	areaCode   string
+	name       string
+	activeFrom *time.Time
+	activeTo   *time.Time
+}
+
+type relationship struct {
+	areaCode    string
+	relAreaCode string
+	relType     string
+}
+
+// data holds the rows of every table. Names, relationships and the audit log are kept in insertion order, as a table
+// scan would return them. Audit entries are never changed once added.
+type data struct {
+	areaTypes         map[string]bool
+	relationshipTypes map[string]bool
+	areas             map[string]*area
+	names             []*areaName
+	relationships     []*relationship
+	audit             []*models.AreaAuditEntry
+}
+
+// Store is an in-memory area store. Each write works on a copy of the data that replaces it only on success, so a
+// failed write leaves the store unchanged as a rolled back transaction would.
+type Store struct {
+	mu   sync.RWMutex
+	data *data
+}
+
+// New returns an empty store
+func New() *Store {
+	return &Store{data: &data{
+		areaTypes:         make(map[string]bool),
+		relationshipTypes: make(map[string]bool),
+		areas:             make(map[string]*area),
+	}}
+}
+
+// Init seeds the store with the configured fixtures
+func (s *Store) Init(ctx context.Context, cfg *config.Config) error {
+	set, err := fixtures.Open(cfg.FixturesVersion, cfg.FixturesDir)
+	if err != nil {
+		return fmt.Errorf("failed to open fixtures: %w", err)
+	}
+	return s.LoadFixtures(ctx, set)
+}
+
+func (s *Store) Close() {}
+
+// BuildTables does nothing, as the store has no schema and is seeded by Init
+func (s *Store) BuildTables(ctx context.Context) error {
+	return nil
+}
+
+func (s *Store) Ping(ctx context.Context) error {
+	return nil
+}
+
+func (s *Store) PingReader(ctx context.Context) error {
+	return nil
+}
+
+// CheckSchemaDrift always reports the store in sync, as it has no schema to drift from
+func (s *Store) CheckSchemaDrift(ctx context.Context) (*models.SchemaDriftReport, error) {
+	return &models.SchemaDriftReport{InSync: true, Drift: []models.SchemaDrift{}}, nil
+}
+
+// LoadFixtures writes a fixture set with the same upsert rules as the rds store. Boundaries are not kept, as the API
+// serves them from the fixtures directly.
+func (s *Store) LoadFixtures(ctx context.Context, set *fixtures.Set) error {
+	err := s.update(ctx, func(d *data) error {
+		for _, areaType := range set.AreaTypes {
+			d.areaTypes[areaType.Name] = true
+		}
+		for _, relationshipType := range set.RelationshipTypes {
+			d.relationshipTypes[relationshipType.Name] = true
+		}
+
+		for _, fixture := range set.Areas {
+			activeFrom, err := parseFixtureTime(fixture.ActiveFrom)
+			if err != nil {
+				return fmt.Errorf("failed to insert area %s: %+v", fixture.Code, err)
+			}
+			activeTo, err := parseFixtureTime(fixture.ActiveTo)
+			if err != nil {
+				return fmt.Errorf("failed to insert area %s: %+v", fixture.Code, err)
+			}
+			areaType := fixture.AreaType
+			if !d.areaTypes[areaType] {
+				areaType = ""
+			}
+
+			// an existing area keeps its visibility and version, as in the rds fixture upsert
+			a, ok := d.areas[fixture.Code]
+			if !ok {
+				visible := bool(fixture.Visible)
+				a = &area{code: fixture.Code, visible: &visible, version: 1, updatedAt: time.Now()}
+				d.areas[fixture.Code] = a
+			}
+			a.activeFrom, a.activeTo, a.areaType, a.geometry = activeFrom, activeTo, areaType, fixture.GeometricArea
+		}
+
+		for _, fixture := range set.AreaNames {
+			if d.areas[fixture.AreaCode] == nil {
+				return fmt.Errorf("failed to insert area name %s: unknown area %s", fixture.Name, fixture.AreaCode)
+			}
+			activeFrom, err := parseFixtureTime(fixture.ActiveFrom)
+			if err != nil {
+				return fmt.Errorf("failed to insert area name %s: %+v", fixture.Name, err)
+			}
+			activeTo, err := parseFixtureTime(fixture.ActiveTo)
+			if err != nil {
+				return fmt.Errorf("failed to insert area name %s: %+v", fixture.Name, err)
+			}
+			if d.namePeriodRow(fixture.AreaCode, fixture.Name, activeFrom) != nil {
+				continue
+			}
+			d.names = append(d.names, &areaName{areaCode: fixture.AreaCode, name: fixture.Name, activeFrom: activeFrom, activeTo: activeTo})
+		}
+
+		for _, fixture := range set.AreaRelationships {
+			if d.areas[fixture.AreaCode] == nil || d.areas[fixture.RelAreaCode] == nil {
+				return fmt.Errorf("failed to insert area relationship %s/%s: unknown area", fixture.AreaCode, fixture.RelAreaCode)
+			}
+			relType := fixture.RelType
+			if !d.relationshipTypes[relType] {
+				relType = ""
+			}
+			d.upsertRelationship(fixture.AreaCode, fixture.RelAreaCode, relType)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	log.Info(ctx, "fixtures loaded successfully", log.Data{"version": set.Version, "areas": len(set.Areas)})
+	return nil
+}
+
+func (s *Store) ValidateArea(ctx context.Context, code string, includeInactive bool) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if a := s.data.areas[code]; a != nil && (includeInactive || a.isActive()) {
+		return nil
+	}
+	return apierrors.ErrNoRows
+}
+
+func (s *Store) GetArea(ctx context.Context, areaId string, includeInactive bool) (*models.AreasDataResults, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	a := s.data.areas[areaId]
+	if a == nil || !(includeInactive || a.isActive()) {
+		return nil, apierrors.ErrNoRows
+	}
+
+	result := &models.AreasDataResults{Code: a.code, Visible: copyBool(a.visible), Version: a.version, UpdatedAt: a.updatedAt}
+	if name := s.data.firstName(a.code); name != nil {
+		result.Name = copyString(&name.name)
+	}
+	if a.areaType != "" {
+		result.AreaType = copyString(&a.areaType)
+	}
+	if len(a.geometry) != 0 {
+		geometricData := make([][][2]float64, 0)
+		if err := json.Unmarshal([]byte(a.geometry), &geometricData); err != nil {
+			return nil, err
+		}
+		result.GeometricData = geometricData
+	}
+	return result, nil
+}
+
+// GetRelationships returns the named areas related to an area, optionally only those of one relationship type
+func (s *Store) GetRelationships(ctx context.Context, areaCode string, relationshipParameter string, includeInactive bool) ([]*models.AreaBasicData, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var relationships []*models.AreaBasicData
+	for _, rel := range s.data.relationships {
+		if rel.areaCode != areaCode {
+			continue
+		}
+		if relationshipParameter != "" && rel.relType != relationshipParameter {
+			continue
+		}
+		// area_closure holds an area at depth 0 of itself, so it is never its own child
+		if relationshipParameter == childRelationship && rel.relAreaCode == areaCode {
+			continue
+		}
+		child := s.data.areas[rel.relAreaCode]
+		if child == nil || !(includeInactive || child.isActive()) {
+			continue
+		}
+		for _, name := range s.data.names {
+			if name.areaCode == child.code {
+				relationships = append(relationships, &models.AreaBasicData{Code: child.code, Name: name.name})
+			}
+		}
+	}
+	return relationships, nil
+}
+
+// GetAncestors returns the named ancestors of an area, nearest first
+func (s *Store) GetAncestors(ctx context.Context, areaCode string) ([]models.AreasAncestors, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	depths := s.data.ancestorDepths(areaCode)
+	codes := make([]string, 0, len(depths))
+	for code := range depths {
+		codes = append(codes, code)
+	}
+	sort.Slice(codes, func(i, j int) bool {
+		if depths[codes[i]] != depths[codes[j]] {
+			return depths[codes[i]] < depths[codes[j]]
+		}
+		return codes[i] < codes[j]
+	})
+
+	var ancestors []models.AreasAncestors
+	for _, code := range codes {
+		for _, name := range s.data.names {
+			if name.areaCode == code {
+				ancestors = append(ancestors, models.AreasAncestors{Id: code, Name: name.name})
+			}
+		}
+	}
+	return ancestors, nil
+}
+
+// GetAreaDetails returns an area with its names and ancestors, and its children and boundary when requested
+func (s *Store) GetAreaDetails(ctx context.Context, areaCode string, opts models.AreaDetailsOptions) (*models.AreaDetails, error) {
+	area, err := s.GetArea(ctx, areaCode, opts.IncludeInactive)
+	if err != nil {
+		return nil, err
+	}
+	if !opts.Boundary {
+		area.GeometricData = nil
+	}
+	area.Ancestors, err = s.GetAncestors(ctx, areaCode)
+	if err != nil {
+		return nil, err
+	}
+	area.LastModified = s.lastModified(areaCode, area.UpdatedAt)
+	details := &models.AreaDetails{Area: area, Names: s.areaNames(areaCode)}
+
+	if opts.Children {
+		details.Children, err = s.GetRelationships(ctx, areaCode, childRelationship, opts.IncludeInactive)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return details, nil
+}
+
+// lastModified returns when an area, last written at updatedAt, or one of its ancestors was last written
+func (s *Store) lastModified(areaCode string, updatedAt time.Time) time.Time {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for code := range s.data.ancestorDepths(areaCode) {
+		if ancestor := s.data.areas[code]; ancestor.updatedAt.After(updatedAt) {
+			updatedAt = ancestor.updatedAt
+		}
+	}
+	return updatedAt
+}
+
+// areaNames returns the names of an area in the order of the rds store, earliest first
+func (s *Store) areaNames(areaCode string) []models.AreaName {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.data.areaNames(areaCode)
+}
+
+// GetAreaAudit returns a page of the audit log of an area, most recent change first, with the number of changes that
+// match the query's dates in total
+func (s *Store) GetAreaAudit(ctx context.Context, areaCode string, query models.AreaAuditQuery) (*models.AreaAuditPage, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var entries []models.AreaAuditEntry
+	for i := len(s.data.audit) - 1; i >= 0; i-- {
+		if entry := s.data.audit[i]; entry.AreaCode == areaCode && query.Includes(entry.ChangedAt) {
+			entries = append(entries, *entry)
+		}
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].ChangedAt.After(entries[j].ChangedAt) })
+
+	page := &models.AreaAuditPage{Offset: query.Offset, Limit: query.Limit, TotalCount: len(entries), Items: []models.AreaAuditEntry{}}
+	if query.Offset < len(entries) {
+		end := len(entries)
+		if query.Limit < end-query.Offset {
+			end = query.Offset + query.Limit
+		}
+		page.Items = append(page.Items, entries[query.Offset:end]...)
+	}
+	page.Count = len(page.Items)
+	return page, nil
+}
+
+// areaNames returns the names of an area in the order of the rds store, earliest first
+func (d *data) areaNames(areaCode string) []models.AreaName {
+	var names []models.AreaName
+	for _, name := range d.names {
+		if name.areaCode == areaCode {
+			names = append(names, models.AreaName{Name: name.name, ActiveFrom: copyTime(name.activeFrom), ActiveTo: copyTime(name.activeTo)})
+		}
+	}
+	sort.SliceStable(names, func(i, j int) bool {
+		if (names[i].ActiveFrom == nil) != (names[j].ActiveFrom == nil) {
+			return names[i].ActiveFrom == nil
+		}
+		if names[i].ActiveFrom != nil && !names[i].ActiveFrom.Equal(*names[j].ActiveFrom) {
+			return names[i].ActiveFrom.Before(*names[j].ActiveFrom)
+		}
+		return names[i].Name < names[j].Name
+	})
+	return names
+}
+
+// UpsertArea creates or replaces an area. When ifMatch is set the area must already exist with a matching ETag,
+// otherwise apierrors.ErrPreconditionFailed is returned.
+func (s *Store) UpsertArea(ctx context.Context, area models.AreaParams, ifMatch string) (bool, error) {
+	var isInserted bool
+	err := s.update(ctx, func(d *data) error {
+		if ifMatch != "" {
+			existing := d.areas[area.Code]
+			if existing == nil || !models.ETagMatches(ifMatch, existing.version) {
+				return apierrors.ErrPreconditionFailed
+			}
+		}
+
+		var err error
+		isInserted, err = d.upsertAuditedArea(auth.Actor(ctx), area)
+		return err
+	})
+	return isInserted, err
+}
+
+// BulkUpsertAreas upserts areas in the order given, in batches of batchSize that are each applied or discarded whole,
+// reporting a result for every area exactly as the rds store does.
+func (s *Store) BulkUpsertAreas(ctx context.Context, areas []models.AreaParams, batchSize int) ([]models.BulkAreaResult, error) {
+	results := make([]models.BulkAreaResult, len(areas))
+	for i, area := range areas {
+		results[i] = models.BulkAreaResult{Index: i, Code: area.Code, Status: models.BulkAreaSkipped}
+	}
+
+	if batchSize <= 0 {
+		batchSize = len(areas)
+	}
+
+	for start := 0; start < len(areas); start += batchSize {
+		end := start + batchSize
+		if end > len(areas) {
+			end = len(areas)
+		}
+
+		batch, batchResults := areas[start:end], results[start:end]
+		err := s.update(ctx, func(d *data) error {
+			for i, area := range batch {
+				isInserted, err := d.upsertAuditedArea(auth.Actor(ctx), area)
+				if err != nil {
+					for j := 0; j < i; j++ {
+						batchResults[j].Status = models.BulkAreaRolledBack
+					}
+					batchResults[i].Status = models.BulkAreaFailed
+					batchResults[i].Errors = []error{models.NewError(ctx, err, models.BulkUpsertError, err.Error())}
+					return err
+				}
+				if isInserted {
+					batchResults[i].Status = models.BulkAreaCreated
+				} else {
+					batchResults[i].Status = models.BulkAreaUpdated
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return results, err
+		}
+	}
+	return results, nil
+}
+
+// PatchArea applies a validated patch to an existing area, returning apierrors.ErrNoRows when the area does not exist,
+// apierrors.ErrPreconditionFailed when ifMatch does not match its ETag and apierrors.ErrInvalidAreaPatch when the
+// patched area fails validation. A patch of /parent_code moves the area, detaching it from any other parent.
+func (s *Store) PatchArea(ctx context.Context, areaCode string, patch models.AreaPatch, ifMatch string) error {
+	return s.update(ctx, func(d *data) error {
+		existing := d.areas[areaCode]
+		if existing == nil {
+			return apierrors.ErrNoRows
+		}
+		if !models.ETagMatches(ifMatch, existing.version) {
+			return apierrors.ErrPreconditionFailed
+		}
+
+		before := d.auditState(areaCode)
+		area := d.areaParams(existing)
+		var currentName string
+		if area.AreaName != nil {
+			currentName = area.AreaName.Name
+		}
+
+		if err := patch.Apply(area); err != nil {
+			return fmt.Errorf("%w: %v", apierrors.ErrInvalidAreaPatch, err)
+		}
+		if validationErrs := area.ValidatePatchedArea(ctx); len(validationErrs) != 0 {
+			return fmt.Errorf("%w: %v", apierrors.ErrInvalidAreaPatch, validationErrs)
+		}
+
+		// names are unique per area and period, so a rename has to update the existing name rather than add a second one
+		if currentName != "" && currentName != area.AreaName.Name {
+			for _, name := range d.names {
+				// as in the unique index, names without a start date never conflict
+				if name.areaCode == areaCode && name.name == currentName && name.activeFrom != nil && d.namePeriodRow(areaCode, area.AreaName.Name, name.activeFrom) != nil {
+					return apierrors.NewStoreError(apierrors.ErrConflict, fmt.Errorf("failed to rename area_name: name %q already exists", area.AreaName.Name))
+				}
+			}
+			for _, name := range d.names {
+				if name.areaCode == areaCode && name.name == currentName {
+					name.name = area.AreaName.Name
+				}
+			}
+		}
+
+		if _, err := d.upsertArea(*area); err != nil {
+			return err
+		}
+		if patch.SetsParent() {
+			d.removeOtherParents(areaCode, area.ParentCode)
+		}
+		d.recordAudit(auth.Actor(ctx), areaCode, models.AreaAuditPatch, before)
+		return nil
+	})
+}
+
+// RetireArea soft-deletes an area by ending it now and hiding it. Unless cascade is set, areas with live children are
+// refused with apierrors.ErrAreaHasLiveChildren; with cascade all live descendants are retired too. An area that is
+// already retired is left as it is.
+func (s *Store) RetireArea(ctx context.Context, areaCode string, cascade bool, ifMatch string) error {
+	return s.update(ctx, func(d *data) error {
+		existing := d.areas[areaCode]
+		if existing == nil {
+			return apierrors.ErrNoRows
+		}
+		if !models.ETagMatches(ifMatch, existing.version) {
+			return apierrors.ErrPreconditionFailed
+		}
+		if !existing.isActive() {
+			return nil
+		}
+
+		now, actor := time.Now(), auth.Actor(ctx)
+		if cascade {
+			descendants := d.descendantDepths(areaCode)
+			codes := make([]string, 0, len(descendants))
+			for code := range descendants {
+				codes = append(codes, code)
+			}
+			sort.Strings(codes)
+
+			for _, code := range codes {
+				if descendant := d.areas[code]; descendant != nil && descendant.isActive() {
+					before := d.auditState(code)
+					descendant.retire(now)
+					d.recordAudit(actor, code, models.AreaAuditRetire, before)
+				}
+			}
+		} else {
+			for code, depth := range d.descendantDepths(areaCode) {
+				if child := d.areas[code]; depth == 1 && child != nil && child.isActive() {
+					return apierrors.ErrAreaHasLiveChildren
+				}
+			}
+		}
+
+		before := d.auditState(areaCode)
+		existing.retire(now)
+		d.recordAudit(actor, areaCode, models.AreaAuditRetire, before)
+		return nil
+	})
+}
+
+// update applies fn to a copy of the data, which replaces the store's data only if fn succeeds. Nothing is written once
+// ctx has ended.
+func (s *Store) update(ctx context.Context, fn func(d *data) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	d := s.data.clone()
+	if err := fn(d); err != nil {
+		return err
+	}
+	s.data = d
+	return nil
+}
+
+// upsertArea writes an area, its name and its parent relationship
+func (d *data) upsertArea(params models.AreaParams) (bool, error) {
+	if !d.areaTypes[params.AreaType] {
+		return false, apierrors.NewStoreError(apierrors.ErrInvalidReference, fmt.Errorf("failed to get area type: %v", apierrors.ErrNoRows))
+	}
+
+	a, exists := d.areas[params.Code]
+	if exists {
+		a.version++
+	} else {
+		a = &area{code: params.Code, version: 1}
+		d.areas[params.Code] = a
+	}
+	a.updatedAt = time.Now()
+	a.activeFrom, a.activeTo = copyTime(params.ActiveFrom), copyTime(params.ActiveTo)
+	a.geometry, a.areaType, a.visible, a.hectares = params.GeometricData, params.AreaType, copyBool(params.Visible), params.AreaHectares
+
+	// an existing name of the area only has its dates updated
+	if names := d.nameRows(params.Code, params.AreaName.Name); len(names) != 0 {
+		for _, name := range names {
+			name.activeFrom, name.activeTo = copyTime(params.AreaName.ActiveFrom), copyTime(params.AreaName.ActiveTo)
+		}
+	} else {
+		d.names = append(d.names, &areaName{
+			areaCode:   params.Code,
+			name:       params.AreaName.Name,
+			activeFrom: copyTime(params.AreaName.ActiveFrom),
+			activeTo:   copyTime(params.AreaName.ActiveTo),
+		})
+	}
+
+	if params.ParentCode != "" {
+		if !d.relationshipTypes[childRelationship] {
+			return !exists, apierrors.NewStoreError(apierrors.ErrInvalidReference, fmt.Errorf("failed to get child relationshipid: %v", apierrors.ErrNoRows))
+		}
+		if d.areas[params.ParentCode] == nil {
+			return !exists, apierrors.NewStoreError(apierrors.ErrInvalidReference, fmt.Errorf("failed to upsert into area relationship: unknown parent area %s", params.ParentCode))
+		}
+		if _, isDescendant := d.descendantDepths(params.Code)[params.ParentCode]; isDescendant || params.ParentCode == params.Code {
+			return !exists, &apierrors.StoreError{Kind: apierrors.ErrInvalidData, Constraint: "parent_code", Err: apierrors.ErrAreaParentCycle}
+		}
+		d.upsertRelationship(params.ParentCode, params.Code, childRelationship)
+	}
+	return !exists, nil
+}
+
+// upsertAuditedArea writes an area with upsertArea, recording the change in the area's audit log
+func (d *data) upsertAuditedArea(actor string, params models.AreaParams) (bool, error) {
+	before := d.auditState(params.Code)
+	isInserted, err := d.upsertArea(params)
+	if err != nil {
+		return isInserted, err
+	}
+	d.recordAudit(actor, params.Code, models.AreaAuditUpdate, before)
+	return isInserted, nil
+}
+
+// recordAudit adds a change to the audit log of an area, given the state it was in before the change. The operation
+// is recorded as models.AreaAuditCreate when the area did not exist before.
+func (d *data) recordAudit(actor, areaCode, operation string, before *models.AreaAuditState) {
+	if before == nil {
+		operation = models.AreaAuditCreate
+	}
+	d.audit = append(d.audit, &models.AreaAuditEntry{
+		ID:        int64(len(d.audit) + 1),
+		AreaCode:  areaCode,
+		Actor:     actor,
+		Operation: operation,
+		ChangedAt: time.Now(),
+		Before:    before,
+		After:     d.auditState(areaCode),
+	})
+}
+
+// auditState returns an area as it is recorded in the audit log, or nil when it does not exist
+func (d *data) auditState(areaCode string) *models.AreaAuditState {
+	a := d.areas[areaCode]
+	if a == nil {
+		return nil
+	}
+
+	state := &models.AreaAuditState{
+		Code:          a.code,
+		AreaType:      a.areaType,
+		ActiveFrom:    copyTime(a.activeFrom),
+		ActiveTo:      copyTime(a.activeTo),
+		Visible:       copyBool(a.visible),
+		AreaHectares:  a.hectares,
+		GeometricData: a.geometry,
+		Version:       a.version,
+		Names:         append([]models.AreaName{}, d.areaNames(areaCode)...),
+	}
+	state.ParentCode = d.parentCode(areaCode)
+	return state
+}
+
+// parentCode returns the parent of an area, or "" when it has none. As in the rds store, an area with more than one
+// parent is given the first by code.
+func (d *data) parentCode(areaCode string) string {
+	var parentCode string
+	for _, rel := range d.relationships {
+		if rel.relType == childRelationship && rel.relAreaCode == areaCode && rel.areaCode != areaCode && d.areas[rel.areaCode] != nil {
+			if parentCode == "" || rel.areaCode < parentCode {
+				parentCode = rel.areaCode
+			}
+		}
+	}
+	return parentCode
+}
+
+// areaParams returns the current state of an area in the form it is written
+func (d *data) areaParams(a *area) *models.AreaParams {
+	params := &models.AreaParams{
+		Code:          a.code,
+		ActiveFrom:    copyTime(a.activeFrom),
+		ActiveTo:      copyTime(a.activeTo),
+		GeometricData: a.geometry,
+		Visible:       copyBool(a.visible),
+		AreaType:      a.areaType,
+		AreaHectares:  a.hectares,
+		ParentCode:    d.parentCode(a.code),
+	}
+	if name := d.firstName(a.code); name != nil {
+		params.AreaName = &models.AreaName{Name: name.name, ActiveFrom: copyTime(name.activeFrom), ActiveTo: copyTime(name.activeTo)}
+	}
+	return params
+}
+
+// removeOtherParents removes the child relationships of an area to any parent other than parentCode
+func (d *data) removeOtherParents(areaCode, parentCode string) {
+	kept := d.relationships[:0]
+	for _, rel := range d.relationships {
+		if rel.relType != childRelationship || rel.relAreaCode != areaCode || rel.areaCode == parentCode {
+			kept = append(kept, rel)
+		}
+	}
+	d.relationships = kept
+}
+
+func (d *data) upsertRelationship(areaCode, relAreaCode, relType string) {
+	for _, rel := range d.relationships {
+		if rel.areaCode == areaCode && rel.relAreaCode == relAreaCode && rel.relType == relType {
+			return
+		}
+	}
+	d.relationships = append(d.relationships, &relationship{areaCode: areaCode, relAreaCode: relAreaCode, relType: relType})
+}
+
+func (d *data) nameRows(areaCode, name string) []*areaName {
+	var rows []*areaName
+	for _, row := range d.names {
+		if row.areaCode == areaCode && row.name == name {
+			rows = append(rows, row)
+		}
+	}
+	return rows
+}
+
+func (d *data) namePeriodRow(areaCode, name string, activeFrom *time.Time) *areaName {
+	for _, row := range d.nameRows(areaCode, name) {
+		if sameTime(row.activeFrom, activeFrom) {
+			return row
+		}
+	}
+	return nil
+}
+
+func (d *data) firstName(areaCode string) *areaName {
+	for _, row := range d.names {
+		if row.areaCode == areaCode {
+			return row
+		}
+	}
+	return nil
+}
+
+// ancestorDepths returns the shortest distance to every ancestor of an area through child relationships
+func (d *data) ancestorDepths(areaCode string) map[string]int {
+	return d.walk(areaCode, func(rel *relationship) (string, string) { return rel.relAreaCode, rel.areaCode })
+}
+
+// descendantDepths returns the shortest distance to every descendant of an area through child relationships
+func (d *data) descendantDepths(areaCode string) map[string]int {
+	return d.walk(areaCode, func(rel *relationship) (string, string) { return rel.areaCode, rel.relAreaCode })
+}
+
+// walk follows child relationships breadth first from an area, using edge to orient each relationship, and returns
+// the depth at which each area is first reached
+func (d *data) walk(areaCode string, edge func(rel *relationship) (from, to string)) map[string]int {
+	depths := make(map[string]int)
+	current := []string{areaCode}
+	for depth := 1; depth <= maxClosureDepth && len(current) != 0; depth++ {
+		var next []string
+		for _, code := range current {
+			for _, rel := range d.relationships {
+				from, to := edge(rel)
+				if rel.relType != childRelationship || from != code || to == areaCode || d.areas[to] == nil {
+					continue
+				}
+				if _, seen := depths[to]; !seen {
+					depths[to] = depth
+					next = append(next, to)
+				}
+			}
+		}
+		current = next
+	}
+	return depths
+}
+
+func (d *data) clone() *data {
+	c := &data{
+		areaTypes:         make(map[string]bool, len(d.areaTypes)),
+		relationshipTypes: make(map[string]bool, len(d.relationshipTypes)),
+		areas:             make(map[string]*area, len(d.areas)),
+		names:             make([]*areaName, len(d.names)),
+		relationships:     make([]*relationship, len(d.relationships)),
+		audit:             append([]*models.AreaAuditEntry(nil), d.audit...),
+	}
+	for name := range d.areaTypes {
+		c.areaTypes[name] = true
+	}
+	for name := range d.relationshipTypes {
+		c.relationshipTypes[name] = true
+	}
+	for code, a := range d.areas {
+		copied := *a
+		c.areas[code] = &copied
+	}
+	for i, name := range d.names {
+		copied := *name
+		c.names[i] = &copied
+	}
+	for i, rel := range d.relationships {
+		copied := *rel
+		c.relationships[i] = &copied
+	}
+	return c
+}
+
+// isActive reports whether an area has not been retired, i.e. is still visible or has no end date in the past
+func (a *area) isActive() bool {
+	return a.visible == nil || *a.visible || a.activeTo == nil || a.activeTo.After(time.Now())
+}
+
+func (a *area) retire(now time.Time) {
+	visible := false
+	a.activeTo, a.visible = &now, &visible
+	a.version++
+	a.updatedAt = now
+}
+
+func parseFixtureTime(value *string) (*time.Time, error) {
+	if value == nil {
+		return nil, nil
+	}
+	for _, layout := range fixtureTimeLayouts {
+		if t, err := time.Parse(layout, *value); err == nil {
+			return &t, nil
+		}
+	}
+	return nil, fmt.Errorf("invalid timestamp %q", *value)
+}
+
+func copyTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	copied := *t
+	return &copied
+}
+
+// sameTime reports whether two optional times are both unset or equal
+func sameTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
+
+func copyBool(b *bool) *bool {
+	if b == nil {
+		return nil
+	}
+	copied := *b
+	return &copied
+}
+
+func copyString(s *string) *string {
+	if s == nil {
+		return nil
+	}
+	copied := *s
+	return &copied
+}
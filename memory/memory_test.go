@@ -0,0 +1,14 @@
+package memory_test
+
+import (
+	"testing"
+
+	"github.com/ONSdigital/dp-areas-api/memory"
+	"github.com/ONSdigital/dp-areas-api/storetest"
+)
+
+func TestStore_Conformance(t *testing.T) {
+	storetest.Run(t, func(t *testing.T) storetest.Store {
+		return memory.New()
+	})
+}
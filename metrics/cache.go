@@ -0,0 +1,81 @@
+package metrics
+
+import (
+	"sync"
+
+	"github.com/ONSdigital/dp-areas-api/cache"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// cacheStats is the part of a cache.Store the cache metrics are read from
+type cacheStats interface {
+	Stats() map[string]cache.Stats
+}
+
+// cacheCollector reads the metrics of the registered cache from its stats as they are collected, labelled by the
+// cached store method
+type cacheCollector struct {
+	mu    sync.Mutex
+	store cacheStats
+
+	hits          *prometheus.Desc
+	misses        *prometheus.Desc
+	hitRatio      *prometheus.Desc
+	evictions     *prometheus.Desc
+	invalidations *prometheus.Desc
+	entries       *prometheus.Desc
+}
+
+func newCacheCollector() *cacheCollector {
+	desc := func(name, help string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName(namespace, "cache", name), help, []string{"method"}, nil)
+	}
+	return &cacheCollector{
+		hits:          desc("hits_total", "Reads served from the cache."),
+		misses:        desc("misses_total", "Reads not found in the cache."),
+		hitRatio:      desc("hit_ratio", "Share of reads served from the cache since the service started."),
+		evictions:     desc("evictions_total", "Entries evicted to make room for others."),
+		invalidations: desc("invalidations_total", "Entries evicted because their areas changed."),
+		entries:       desc("entries", "Entries currently cached."),
+	}
+}
+
+// RegisterCache reports the stats of the area cache, replacing any cache registered before
+func RegisterCache(store cacheStats) {
+	caches.mu.Lock()
+	defer caches.mu.Unlock()
+	caches.store = store
+}
+
+// Describe implements prometheus.Collector
+func (c *cacheCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, d := range []*prometheus.Desc{c.hits, c.misses, c.hitRatio, c.evictions, c.invalidations, c.entries} {
+		ch <- d
+	}
+}
+
+// Collect implements prometheus.Collector
+func (c *cacheCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.store == nil {
+		return
+	}
+	for method, stats := range c.store.Stats() {
+		ch <- prometheus.MustNewConstMetric(c.hits, prometheus.CounterValue, float64(stats.Hits), method)
+		ch <- prometheus.MustNewConstMetric(c.misses, prometheus.CounterValue, float64(stats.Misses), method)
+		ch <- prometheus.MustNewConstMetric(c.hitRatio, prometheus.GaugeValue, hitRatio(stats), method)
+		ch <- prometheus.MustNewConstMetric(c.evictions, prometheus.CounterValue, float64(stats.Evictions), method)
+		ch <- prometheus.MustNewConstMetric(c.invalidations, prometheus.CounterValue, float64(stats.Invalidations), method)
+		ch <- prometheus.MustNewConstMetric(c.entries, prometheus.GaugeValue, float64(stats.Entries), method)
+	}
+}
+
+// hitRatio returns the share of lookups that were hits, which is 0 before the first lookup
+func hitRatio(stats cache.Stats) float64 {
+	lookups := stats.Hits + stats.Misses
+	if lookups == 0 {
+		return 0
+	}
+	return float64(stats.Hits) / float64(lookups)
+}
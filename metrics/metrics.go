@@ -0,0 +1,91 @@
+// Package metrics holds the Prometheus metrics of the service and serves them at /metrics: the requests served by each
+// route, the duration of each database query, the state of the database pools and the use of the area cache. Metrics
+// are registered once on a registry of the package, so that the stores record them without it being threaded through
+// their constructors.
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// namespace prefixes the name of every metric of the service
+const namespace = "dp_areas_api"
+
+// OtherQuery names the queries that have no name of their own, such as those of schema migrations
+const OtherQuery = "other"
+
+// sizeBuckets bound the response sizes, from 100B to 10MB
+var sizeBuckets = prometheus.ExponentialBuckets(100, 10, 6)
+
+var (
+	requests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "Requests served, by route template, method and status.",
+	}, []string{"route", "method", "status"})
+
+	requestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "Time taken to serve a request, by route template, method and status.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"route", "method", "status"})
+
+	responseSize = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_response_size_bytes",
+		Help:      "Size of response bodies, by route template, method and status.",
+		Buckets:   sizeBuckets,
+	}, []string{"route", "method", "status"})
+
+	queryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "db_query_duration_seconds",
+		Help:      "Time taken by a database query, by query name and whether it failed.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"query", "failed"})
+
+	pools  = newPoolCollector()
+	caches = newCacheCollector()
+)
+
+// registry holds every metric of the service
+var registry = newRegistry()
+
+func newRegistry() *prometheus.Registry {
+	r := prometheus.NewRegistry()
+	r.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		requests,
+		requestDuration,
+		responseSize,
+		queryDuration,
+		pools,
+		caches,
+	)
+	return r
+}
+
+// Handler serves the metrics in the Prometheus text format
+func Handler() http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+}
+
+// ObserveQuery times a database query from when it starts. It is a pgx.QueryObserver.
+func ObserveQuery(_ context.Context, name string) func(err error) {
+	start := time.Now()
+	return func(err error) {
+		failed := "false"
+		if err != nil {
+			failed = "true"
+		}
+		queryDuration.WithLabelValues(name, failed).Observe(time.Since(start).Seconds())
+	}
+}
@@ -0,0 +1,119 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ONSdigital/dp-areas-api/cache"
+	"github.com/gorilla/mux"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// cacheStatsFunc returns fixed cache stats
+type cacheStatsFunc func() map[string]cache.Stats
+
+func (f cacheStatsFunc) Stats() map[string]cache.Stats { return f() }
+
+func TestMiddleware(t *testing.T) {
+	Convey("Given a router whose routes are measured", t, func() {
+		r := mux.NewRouter()
+		r.Use(Middleware)
+		r.Path("/v1/areas/{id}").Methods(http.MethodGet).HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			if mux.Vars(req)["id"] == "missing" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_, _ = w.Write([]byte(`{"code":"E92000001"}`))
+		})
+
+		before := testutil.ToFloat64(requests.WithLabelValues("/v1/areas/{id}", http.MethodGet, "200"))
+		notFoundBefore := testutil.ToFloat64(requests.WithLabelValues("/v1/areas/{id}", http.MethodGet, "404"))
+
+		Convey("When areas are requested", func() {
+			for _, id := range []string{"E92000001", "W92000004", "missing"} {
+				r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/v1/areas/"+id, nil))
+			}
+
+			Convey("Then the requests are counted by route template and status", func() {
+				So(testutil.ToFloat64(requests.WithLabelValues("/v1/areas/{id}", http.MethodGet, "200")), ShouldEqual, before+2)
+				So(testutil.ToFloat64(requests.WithLabelValues("/v1/areas/{id}", http.MethodGet, "404")), ShouldEqual, notFoundBefore+1)
+			})
+
+			Convey("Then their duration and response size are recorded", func() {
+				body := scrape()
+				So(body, ShouldContainSubstring, `dp_areas_api_http_request_duration_seconds_count{method="GET",route="/v1/areas/{id}",status="200"}`)
+				So(body, ShouldContainSubstring, `dp_areas_api_http_response_size_bytes_sum{method="GET",route="/v1/areas/{id}",status="404"}`)
+			})
+		})
+	})
+}
+
+func TestObserveQuery(t *testing.T) {
+	Convey("When queries are observed", t, func() {
+		ObserveQuery(context.Background(), "getArea")(nil)
+		ObserveQuery(context.Background(), "getArea")(errors.New("query failed"))
+
+		Convey("Then their durations are recorded by name and whether they failed", func() {
+			body := scrape()
+			So(body, ShouldContainSubstring, `dp_areas_api_db_query_duration_seconds_count{failed="false",query="getArea"}`)
+			So(body, ShouldContainSubstring, `dp_areas_api_db_query_duration_seconds_count{failed="true",query="getArea"}`)
+		})
+	})
+}
+
+func TestCollectors(t *testing.T) {
+	Convey("Given a registered cache and pool", t, func() {
+		RegisterCache(cacheStatsFunc(func() map[string]cache.Stats {
+			return map[string]cache.Stats{"GetArea": {Hits: 3, Misses: 1, Entries: 1}}
+		}))
+		poolConfig, err := pgxpool.ParseConfig("postgres://areas@localhost:5432/areas")
+		So(err, ShouldBeNil)
+		poolConfig.LazyConnect = true
+		pool, err := pgxpool.ConnectConfig(context.Background(), poolConfig)
+		So(err, ShouldBeNil)
+		RegisterPool(WriterPool, pool)
+		Reset(func() {
+			pool.Close()
+			RegisterCache(nil)
+			pools.mu.Lock()
+			delete(pools.pools, WriterPool)
+			pools.mu.Unlock()
+		})
+
+		Convey("When the metrics are scraped", func() {
+			body := scrape()
+
+			Convey("Then the cache use is reported by method", func() {
+				So(body, ShouldContainSubstring, `dp_areas_api_cache_hits_total{method="GetArea"} 3`)
+				So(body, ShouldContainSubstring, `dp_areas_api_cache_misses_total{method="GetArea"} 1`)
+				So(body, ShouldContainSubstring, `dp_areas_api_cache_hit_ratio{method="GetArea"} 0.75`)
+			})
+
+			Convey("Then the pool is reported by name", func() {
+				So(body, ShouldContainSubstring, `dp_areas_api_db_pool_acquired_connections{pool="writer"} 0`)
+				So(body, ShouldContainSubstring, `dp_areas_api_db_pool_idle_connections{pool="writer"} 0`)
+				So(body, ShouldContainSubstring, `dp_areas_api_db_pool_waited_acquires_total{pool="writer"} 0`)
+			})
+		})
+	})
+
+	Convey("Given no lookups have been made", t, func() {
+		Convey("Then the hit ratio is 0", func() {
+			So(hitRatio(cache.Stats{}), ShouldEqual, 0)
+		})
+	})
+}
+
+func scrape() string {
+	w := httptest.NewRecorder()
+	Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body, _ := io.ReadAll(w.Body)
+	return strings.TrimSpace(string(body))
+}
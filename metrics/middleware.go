@@ -0,0 +1,26 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ONSdigital/dp-areas-api/middleware"
+)
+
+// Middleware records the count, duration and response size of the requests served by the routes of a mux.Router. It
+// is added with Router.Use, which only runs it for requests that match a route, so that routes are labelled by their
+// template rather than by path and the number of label values stays bounded.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		start := time.Now()
+		recorder := middleware.NewResponseRecorder(w)
+
+		next.ServeHTTP(recorder, req)
+
+		labels := []string{middleware.RouteTemplate(req), req.Method, strconv.Itoa(recorder.Status)}
+		requests.WithLabelValues(labels...).Inc()
+		requestDuration.WithLabelValues(labels...).Observe(time.Since(start).Seconds())
+		responseSize.WithLabelValues(labels...).Observe(float64(recorder.Size))
+	})
+}
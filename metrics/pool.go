@@ -0,0 +1,87 @@
+package metrics
+
+import (
+	"sync"
+
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Names of the database pools
+const (
+	WriterPool = "writer"
+	ReaderPool = "reader"
+)
+
+// poolStat is the part of a pgxpool.Pool the pool metrics are read from
+type poolStat interface {
+	Stat() *pgxpool.Stat
+}
+
+// poolCollector reads the metrics of each registered pool from its stats as they are collected. pgxpool does not count
+// the callers waiting for a connection, so waits are counted by the acquires that found no idle connection.
+type poolCollector struct {
+	mu    sync.Mutex
+	pools map[string]poolStat
+
+	acquired     *prometheus.Desc
+	idle         *prometheus.Desc
+	constructing *prometheus.Desc
+	total        *prometheus.Desc
+	max          *prometheus.Desc
+	acquires     *prometheus.Desc
+	emptyAcquire *prometheus.Desc
+	cancelled    *prometheus.Desc
+	acquireTime  *prometheus.Desc
+}
+
+func newPoolCollector() *poolCollector {
+	desc := func(name, help string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName(namespace, "db_pool", name), help, []string{"pool"}, nil)
+	}
+	return &poolCollector{
+		pools:        make(map[string]poolStat),
+		acquired:     desc("acquired_connections", "Connections currently in use."),
+		idle:         desc("idle_connections", "Connections currently idle."),
+		constructing: desc("constructing_connections", "Connections currently being opened."),
+		total:        desc("total_connections", "Connections currently open or being opened."),
+		max:          desc("max_connections", "Most connections the pool opens."),
+		acquires:     desc("acquires_total", "Connections acquired from the pool."),
+		emptyAcquire: desc("waited_acquires_total", "Acquires that waited because no connection was idle."),
+		cancelled:    desc("cancelled_acquires_total", "Acquires cancelled before a connection was available."),
+		acquireTime:  desc("acquire_duration_seconds_total", "Total time spent acquiring connections."),
+	}
+}
+
+// RegisterPool reports the stats of a database pool under name, replacing any pool registered before under the same
+// name
+func RegisterPool(name string, pool poolStat) {
+	pools.mu.Lock()
+	defer pools.mu.Unlock()
+	pools.pools[name] = pool
+}
+
+// Describe implements prometheus.Collector
+func (c *poolCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, d := range []*prometheus.Desc{c.acquired, c.idle, c.constructing, c.total, c.max, c.acquires, c.emptyAcquire, c.cancelled, c.acquireTime} {
+		ch <- d
+	}
+}
+
+// Collect implements prometheus.Collector
+func (c *poolCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for name, pool := range c.pools {
+		stat := pool.Stat()
+		ch <- prometheus.MustNewConstMetric(c.acquired, prometheus.GaugeValue, float64(stat.AcquiredConns()), name)
+		ch <- prometheus.MustNewConstMetric(c.idle, prometheus.GaugeValue, float64(stat.IdleConns()), name)
+		ch <- prometheus.MustNewConstMetric(c.constructing, prometheus.GaugeValue, float64(stat.ConstructingConns()), name)
+		ch <- prometheus.MustNewConstMetric(c.total, prometheus.GaugeValue, float64(stat.TotalConns()), name)
+		ch <- prometheus.MustNewConstMetric(c.max, prometheus.GaugeValue, float64(stat.MaxConns()), name)
+		ch <- prometheus.MustNewConstMetric(c.acquires, prometheus.CounterValue, float64(stat.AcquireCount()), name)
+		ch <- prometheus.MustNewConstMetric(c.emptyAcquire, prometheus.CounterValue, float64(stat.EmptyAcquireCount()), name)
+		ch <- prometheus.MustNewConstMetric(c.cancelled, prometheus.CounterValue, float64(stat.CanceledAcquireCount()), name)
+		ch <- prometheus.MustNewConstMetric(c.acquireTime, prometheus.CounterValue, stat.AcquireDuration().Seconds(), name)
+	}
+}
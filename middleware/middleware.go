@@ -0,0 +1,47 @@
+// Package middleware holds what the middlewares of the service's mux.Router share: the route a request matched and
+// what was written in response to it.
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+// RouteTemplate returns the path template of the route a request matched, or its path when the route has none
+func RouteTemplate(req *http.Request) string {
+	if route := mux.CurrentRoute(req); route != nil {
+		if template, err := route.GetPathTemplate(); err == nil {
+			return template
+		}
+	}
+	return req.URL.Path
+}
+
+// ResponseRecorder records the status and body size of a response as it is written
+type ResponseRecorder struct {
+	http.ResponseWriter
+	Status      int
+	Size        int
+	wroteHeader bool
+}
+
+// NewResponseRecorder records the response written to w, whose status is 200 until another is written
+func NewResponseRecorder(w http.ResponseWriter) *ResponseRecorder {
+	return &ResponseRecorder{ResponseWriter: w, Status: http.StatusOK}
+}
+
+func (r *ResponseRecorder) WriteHeader(status int) {
+	if !r.wroteHeader {
+		r.Status = status
+		r.wroteHeader = true
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *ResponseRecorder) Write(b []byte) (int, error) {
+	r.wroteHeader = true
+	n, err := r.ResponseWriter.Write(b)
+	r.Size += n
+	return n, err
+}
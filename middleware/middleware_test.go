@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestRouteTemplate(t *testing.T) {
+	Convey("Given a router with an area route", t, func() {
+		var template string
+		router := mux.NewRouter()
+		router.HandleFunc("/v1/areas/{id}", func(w http.ResponseWriter, req *http.Request) {
+			template = RouteTemplate(req)
+		})
+
+		Convey("When an area is requested", func() {
+			router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/v1/areas/E92000001", nil))
+
+			Convey("Then the route template is returned rather than the path", func() {
+				So(template, ShouldEqual, "/v1/areas/{id}")
+			})
+		})
+	})
+
+	Convey("Given a request that matched no route", t, func() {
+		req := httptest.NewRequest(http.MethodGet, "/unknown", nil)
+
+		Convey("Then its path is returned", func() {
+			So(RouteTemplate(req), ShouldEqual, "/unknown")
+		})
+	})
+}
+
+func TestResponseRecorder(t *testing.T) {
+	Convey("Given a recorded response", t, func() {
+		w := httptest.NewRecorder()
+		recorder := NewResponseRecorder(w)
+
+		Convey("When only a body is written", func() {
+			recorder.Write([]byte("hello"))
+
+			Convey("Then the status is 200 and the size is the body's", func() {
+				So(recorder.Status, ShouldEqual, http.StatusOK)
+				So(recorder.Size, ShouldEqual, 5)
+			})
+		})
+
+		Convey("When a status is written twice", func() {
+			recorder.WriteHeader(http.StatusNotFound)
+			recorder.WriteHeader(http.StatusInternalServerError)
+
+			Convey("Then the first status is recorded", func() {
+				So(recorder.Status, ShouldEqual, http.StatusNotFound)
+				So(w.Code, ShouldEqual, http.StatusNotFound)
+			})
+		})
+	})
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/ONSdigital/dp-areas-api/config"
+	"github.com/ONSdigital/dp-areas-api/migrations"
+	"github.com/ONSdigital/dp-areas-api/rds"
+	"github.com/pkg/errors"
+)
+
+const migrateUsage = "usage: dp-areas-api migrate up | down [steps] | status"
+
+// migrate runs the migrate subcommand against the database in the service configuration
+func migrate(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return errors.New(migrateUsage)
+	}
+
+	cfg, err := config.Get()
+	if err != nil {
+		return errors.Wrap(err, "error getting configuration")
+	}
+
+	store := &rds.RDS{}
+	if err := store.Init(ctx, cfg); err != nil {
+		return errors.Wrap(err, "error connecting to database")
+	}
+	defer store.Close()
+
+	migrator, err := store.Migrator()
+	if err != nil {
+		return errors.Wrap(err, "error loading migrations")
+	}
+
+	switch args[0] {
+	case "up":
+		return store.WithSchemaLock(ctx, func(ctx context.Context) error {
+			applied, err := migrator.Up(ctx)
+			printMigrations("applied", applied)
+			return err
+		})
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			steps, err = strconv.Atoi(args[1])
+			if err != nil || steps < 1 {
+				return errors.New(migrateUsage)
+			}
+		}
+		return store.WithSchemaLock(ctx, func(ctx context.Context) error {
+			reverted, err := migrator.Down(ctx, steps)
+			printMigrations("reverted", reverted)
+			return err
+		})
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		printStatus(statuses)
+		return err
+	default:
+		return errors.New(migrateUsage)
+	}
+}
+
+func printMigrations(action string, ms []migrations.Migration) {
+	if len(ms) == 0 {
+		fmt.Printf("no migrations %s\n", action)
+		return
+	}
+	for _, m := range ms {
+		fmt.Printf("%s %04d_%s\n", action, m.Version, m.Name)
+	}
+}
+
+func printStatus(statuses []migrations.Status) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED AT\tCHECKSUM")
+	for _, s := range statuses {
+		appliedAt := "pending"
+		if s.AppliedAt != nil {
+			appliedAt = s.AppliedAt.Format("2006-01-02 15:04:05")
+		}
+		checksum := "ok"
+		if s.ChecksumMismatch {
+			checksum = "MODIFIED"
+		}
+		fmt.Fprintf(w, "%04d\t%s\t%s\t%s\n", s.Version, s.Name, appliedAt, checksum)
+	}
+	w.Flush()
+}
@@ -0,0 +1,297 @@
+package migrations
+
+import (
+	"context"
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/ONSdigital/dp-areas-api/pgx"
+	"github.com/ONSdigital/log.go/v2/log"
+)
+
+//go:embed sql/*.sql
+var embedded embed.FS
+
+// TableName is the table in which applied migrations are recorded
+const TableName = "schema_migrations"
+
+const (
+	createMigrationsTable = `create table if not exists schema_migrations (
+                                 version int primary key,
+                                 name varchar(100) not null,
+                                 checksum varchar(64) not null,
+                                 applied_at timestamp not null default now())`
+	getAppliedMigrations = "select version, name, checksum, applied_at from schema_migrations order by version"
+	insertMigration      = "insert into schema_migrations(version, name, checksum) values($1, $2, $3)"
+	deleteMigration      = "delete from schema_migrations where version = $1"
+)
+
+var fileNamePattern = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// Errors returned when the migrations or the migration history are inconsistent
+var (
+	ErrChecksumMismatch = errors.New("applied migration has been modified")
+	ErrUnknownMigration = errors.New("database has a migration applied that is not known to this version of the service")
+	ErrMissingUp        = errors.New("migration has no up script")
+	ErrMissingDown      = errors.New("migration has no down script")
+)
+
+// Migration is a single, ordered schema change with the SQL to apply and revert it
+type Migration struct {
+	Version  int
+	Name     string
+	Up       string
+	Down     string
+	Checksum string
+}
+
+// Status describes a known migration and whether it has been applied to the database
+type Status struct {
+	Migration
+	Applied          bool
+	AppliedAt        *time.Time
+	ChecksumMismatch bool
+}
+
+// appliedMigration is a row of schema_migrations
+type appliedMigration struct {
+	version   int
+	name      string
+	checksum  string
+	appliedAt time.Time
+}
+
+// Migrator applies and reverts migrations, recording them in the schema_migrations table
+type Migrator struct {
+	conn       pgx.PGXPool
+	migrations []Migration
+}
+
+// New returns a Migrator for the migrations embedded in the service
+func New(conn pgx.PGXPool) (*Migrator, error) {
+	migrations, err := Load(embedded)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{conn: conn, migrations: migrations}, nil
+}
+
+// NewWithMigrations returns a Migrator for the given migrations
+func NewWithMigrations(conn pgx.PGXPool, migrations []Migration) *Migrator {
+	return &Migrator{conn: conn, migrations: migrations}
+}
+
+// Load reads migrations from files named <version>_<name>.up.sql and <version>_<name>.down.sql in the sql directory
+// of fsys, returning them ordered by version. Every migration must have both scripts.
+func Load(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, "sql")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int]*Migration)
+	for _, entry := range entries {
+		matches := fileNamePattern.FindStringSubmatch(entry.Name())
+		if matches == nil {
+			return nil, fmt.Errorf("unexpected migration file name: %s", entry.Name())
+		}
+		version, _ := strconv.Atoi(matches[1])
+		contents, err := fs.ReadFile(fsys, path.Join("sql", entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: matches[2]}
+			byVersion[version] = m
+		} else if m.Name != matches[2] {
+			return nil, fmt.Errorf("migration %d has more than one name: %s and %s", version, m.Name, matches[2])
+		}
+
+		if matches[3] == "up" {
+			m.Up = string(contents)
+			m.Checksum = checksum(contents)
+		} else {
+			m.Down = string(contents)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" {
+			return nil, fmt.Errorf("%w: %04d_%s", ErrMissingUp, m.Version, m.Name)
+		}
+		if m.Down == "" {
+			return nil, fmt.Errorf("%w: %04d_%s", ErrMissingDown, m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// Up applies all pending migrations in order, each in its own transaction, and returns those applied. It refuses to
+// run if an applied migration has since been changed or is not known to this version of the service.
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var applied []Migration
+	for _, status := range statuses {
+		if status.Applied {
+			continue
+		}
+		if err := m.apply(ctx, status.Migration); err != nil {
+			return applied, err
+		}
+		applied = append(applied, status.Migration)
+	}
+	return applied, nil
+}
+
+// Down reverts the most recently applied migrations, up to steps of them, and returns those reverted
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var reverted []Migration
+	for i := len(statuses) - 1; i >= 0 && len(reverted) < steps; i-- {
+		if !statuses[i].Applied {
+			continue
+		}
+		if err := m.revert(ctx, statuses[i].Migration); err != nil {
+			return reverted, err
+		}
+		reverted = append(reverted, statuses[i].Migration)
+	}
+	return reverted, nil
+}
+
+// Status returns every known migration and whether it has been applied. An error is returned alongside the statuses
+// when the migration history does not match the known migrations.
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	_, err := m.conn.Exec(ctx, createMigrationsTable)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create schema_migrations: %+v", err)
+	}
+
+	applied, err := m.getApplied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	known := make(map[int]bool, len(m.migrations))
+	statuses := make([]Status, len(m.migrations))
+	var statusErr error
+	for i, migration := range m.migrations {
+		known[migration.Version] = true
+		statuses[i] = Status{Migration: migration}
+		if row, ok := applied[migration.Version]; ok {
+			appliedAt := row.appliedAt
+			statuses[i].Applied = true
+			statuses[i].AppliedAt = &appliedAt
+			if row.checksum != migration.Checksum {
+				statuses[i].ChecksumMismatch = true
+				statusErr = fmt.Errorf("%w: %04d_%s", ErrChecksumMismatch, migration.Version, migration.Name)
+			}
+		}
+	}
+
+	for version, row := range applied {
+		if !known[version] {
+			statusErr = fmt.Errorf("%w: %04d_%s", ErrUnknownMigration, version, row.name)
+		}
+	}
+	return statuses, statusErr
+}
+
+func (m *Migrator) getApplied(ctx context.Context) (map[int]appliedMigration, error) {
+	rows, err := m.conn.Query(ctx, getAppliedMigrations)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schema_migrations: %+v", err)
+	}
+	defer rows.Close()
+
+	applied := make(map[int]appliedMigration)
+	for rows.Next() {
+		var row appliedMigration
+		if err := rows.Scan(&row.version, &row.name, &row.checksum, &row.appliedAt); err != nil {
+			return nil, err
+		}
+		applied[row.version] = row
+	}
+	return applied, rows.Err()
+}
+
+func (m *Migrator) apply(ctx context.Context, migration Migration) error {
+	tx, err := m.conn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %+v", err)
+	}
+
+	_, err = tx.Exec(ctx, migration.Up)
+	if err != nil {
+		tx.Rollback(ctx)
+		return fmt.Errorf("failed to apply migration %04d_%s: %+v", migration.Version, migration.Name, err)
+	}
+
+	_, err = tx.Exec(ctx, insertMigration, migration.Version, migration.Name, migration.Checksum)
+	if err != nil {
+		tx.Rollback(ctx)
+		return fmt.Errorf("failed to record migration %04d_%s: %+v", migration.Version, migration.Name, err)
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		tx.Rollback(ctx)
+		return fmt.Errorf("failed to commit: %+v", err)
+	}
+	log.Info(ctx, "migration applied", log.Data{"version": migration.Version, "name": migration.Name})
+	return nil
+}
+
+func (m *Migrator) revert(ctx context.Context, migration Migration) error {
+	tx, err := m.conn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %+v", err)
+	}
+
+	_, err = tx.Exec(ctx, migration.Down)
+	if err != nil {
+		tx.Rollback(ctx)
+		return fmt.Errorf("failed to revert migration %04d_%s: %+v", migration.Version, migration.Name, err)
+	}
+
+	_, err = tx.Exec(ctx, deleteMigration, migration.Version)
+	if err != nil {
+		tx.Rollback(ctx)
+		return fmt.Errorf("failed to remove migration %04d_%s: %+v", migration.Version, migration.Name, err)
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		tx.Rollback(ctx)
+		return fmt.Errorf("failed to commit: %+v", err)
+	}
+	log.Info(ctx, "migration reverted", log.Data{"version": migration.Version, "name": migration.Name})
+	return nil
+}
+
+func checksum(contents []byte) string {
+	sum := sha256.Sum256(contents)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,247 @@
+package migrations
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	pgxMock "github.com/ONSdigital/dp-areas-api/pgx/mock"
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func testMigrations() []Migration {
+	return []Migration{
+		{Version: 1, Name: "first", Up: "create table a()", Down: "drop table a", Checksum: checksum([]byte("create table a()"))},
+		{Version: 2, Name: "second", Up: "create table b()", Down: "drop table b", Checksum: checksum([]byte("create table b()"))},
+	}
+}
+
+// appliedRows returns rows of schema_migrations for the given migrations
+func appliedRows(applied ...Migration) *pgxMock.PGXRowsMock {
+	i := -1
+	return &pgxMock.PGXRowsMock{
+		NextFunc:  func() bool { i++; return i < len(applied) },
+		CloseFunc: func() {},
+		ErrFunc:   func() error { return nil },
+		ScanFunc: func(dest ...interface{}) error {
+			*dest[0].(*int) = applied[i].Version
+			*dest[1].(*string) = applied[i].Name
+			*dest[2].(*string) = applied[i].Checksum
+			*dest[3].(*time.Time) = time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+			return nil
+		},
+	}
+}
+
+func mockConn(rows *pgxMock.PGXRowsMock, tx *pgxMock.PGXTransactionMock) *pgxMock.PGXPoolMock {
+	return &pgxMock.PGXPoolMock{
+		ExecFunc: func(ctx context.Context, sql string, arguments ...interface{}) (pgconn.CommandTag, error) {
+			return pgconn.CommandTag{}, nil
+		},
+		QueryFunc: func(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error) {
+			return rows, nil
+		},
+		BeginFunc: func(ctx context.Context) (pgx.Tx, error) {
+			return tx, nil
+		},
+	}
+}
+
+func mockTransaction() *pgxMock.PGXTransactionMock {
+	return &pgxMock.PGXTransactionMock{
+		ExecFunc: func(ctx context.Context, sql string, arguments ...interface{}) (pgconn.CommandTag, error) {
+			return pgconn.CommandTag{}, nil
+		},
+		CommitFunc:   func(ctx context.Context) error { return nil },
+		RollbackFunc: func(ctx context.Context) error { return nil },
+	}
+}
+
+func TestLoad(t *testing.T) {
+	Convey("Given migration files out of order", t, func() {
+		fsys := fstest.MapFS{
+			"sql/0002_second.up.sql":   {Data: []byte("create table b()")},
+			"sql/0002_second.down.sql": {Data: []byte("drop table b")},
+			"sql/0001_first.up.sql":    {Data: []byte("create table a()")},
+			"sql/0001_first.down.sql":  {Data: []byte("drop table a")},
+		}
+
+		Convey("When Load is invoked", func() {
+			migrations, err := Load(fsys)
+
+			Convey("Then the migrations are returned in version order with checksums of the up scripts", func() {
+				So(err, ShouldBeNil)
+				So(migrations, ShouldResemble, testMigrations())
+			})
+		})
+	})
+
+	Convey("Given a migration without a down script", t, func() {
+		fsys := fstest.MapFS{
+			"sql/0001_first.up.sql": {Data: []byte("create table a()")},
+		}
+
+		Convey("When Load is invoked", func() {
+			_, err := Load(fsys)
+
+			Convey("Then a missing down error is returned", func() {
+				So(errors.Is(err, ErrMissingDown), ShouldBeTrue)
+			})
+		})
+	})
+
+	Convey("Given a file that is not named as a migration", t, func() {
+		fsys := fstest.MapFS{
+			"sql/first.sql": {Data: []byte("create table a()")},
+		}
+
+		Convey("When Load is invoked", func() {
+			_, err := Load(fsys)
+
+			Convey("Then an error is returned", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+	})
+
+	Convey("Given the embedded migrations", t, func() {
+		Convey("When Load is invoked", func() {
+			migrations, err := Load(embedded)
+
+			Convey("Then every migration is loaded with consecutive versions", func() {
+				So(err, ShouldBeNil)
+				So(migrations, ShouldNotBeEmpty)
+				for i, m := range migrations {
+					So(m.Version, ShouldEqual, i+1)
+				}
+			})
+		})
+	})
+}
+
+func TestMigrator_Up(t *testing.T) {
+	Convey("Given a database with the first migration applied", t, func() {
+		tx := mockTransaction()
+		migrator := NewWithMigrations(mockConn(appliedRows(testMigrations()[0]), tx), testMigrations())
+
+		Convey("When Up is invoked", func() {
+			applied, err := migrator.Up(context.Background())
+
+			Convey("Then only the pending migration is applied and recorded", func() {
+				So(err, ShouldBeNil)
+				So(applied, ShouldHaveLength, 1)
+				So(applied[0].Version, ShouldEqual, 2)
+				So(tx.ExecCalls(), ShouldHaveLength, 2)
+				So(tx.ExecCalls()[0].SQL, ShouldEqual, "create table b()")
+				So(tx.ExecCalls()[1].SQL, ShouldEqual, insertMigration)
+				So(tx.CommitCalls(), ShouldHaveLength, 1)
+			})
+		})
+	})
+
+	Convey("Given a migration that fails to apply", t, func() {
+		tx := mockTransaction()
+		tx.ExecFunc = func(ctx context.Context, sql string, arguments ...interface{}) (pgconn.CommandTag, error) {
+			return nil, errors.New("syntax error")
+		}
+		migrator := NewWithMigrations(mockConn(appliedRows(), tx), testMigrations())
+
+		Convey("When Up is invoked", func() {
+			applied, err := migrator.Up(context.Background())
+
+			Convey("Then the transaction is rolled back and no further migrations are applied", func() {
+				So(err, ShouldNotBeNil)
+				So(applied, ShouldBeEmpty)
+				So(tx.RollbackCalls(), ShouldHaveLength, 1)
+				So(tx.CommitCalls(), ShouldBeEmpty)
+			})
+		})
+	})
+
+	Convey("Given an applied migration whose script has since changed", t, func() {
+		modified := testMigrations()[0]
+		modified.Checksum = checksum([]byte("create table a(id int)"))
+		tx := mockTransaction()
+		migrator := NewWithMigrations(mockConn(appliedRows(modified), tx), testMigrations())
+
+		Convey("When Up is invoked", func() {
+			_, err := migrator.Up(context.Background())
+
+			Convey("Then a checksum mismatch is returned and nothing is applied", func() {
+				So(errors.Is(err, ErrChecksumMismatch), ShouldBeTrue)
+				So(tx.ExecCalls(), ShouldBeEmpty)
+			})
+		})
+	})
+
+	Convey("Given a database with a migration this service does not know", t, func() {
+		unknown := Migration{Version: 3, Name: "third", Checksum: "abc"}
+		tx := mockTransaction()
+		migrator := NewWithMigrations(mockConn(appliedRows(unknown), tx), testMigrations())
+
+		Convey("When Up is invoked", func() {
+			_, err := migrator.Up(context.Background())
+
+			Convey("Then an unknown migration error is returned and nothing is applied", func() {
+				So(errors.Is(err, ErrUnknownMigration), ShouldBeTrue)
+				So(tx.ExecCalls(), ShouldBeEmpty)
+			})
+		})
+	})
+}
+
+func TestMigrator_Down(t *testing.T) {
+	Convey("Given a database with both migrations applied", t, func() {
+		tx := mockTransaction()
+		migrator := NewWithMigrations(mockConn(appliedRows(testMigrations()...), tx), testMigrations())
+
+		Convey("When Down is invoked for two steps", func() {
+			reverted, err := migrator.Down(context.Background(), 2)
+
+			Convey("Then the migrations are reverted newest first", func() {
+				So(err, ShouldBeNil)
+				So(reverted, ShouldHaveLength, 2)
+				So(reverted[0].Version, ShouldEqual, 2)
+				So(reverted[1].Version, ShouldEqual, 1)
+				So(tx.ExecCalls()[0].SQL, ShouldEqual, "drop table b")
+				So(tx.ExecCalls()[1].SQL, ShouldEqual, deleteMigration)
+				So(tx.ExecCalls()[2].SQL, ShouldEqual, "drop table a")
+				So(tx.CommitCalls(), ShouldHaveLength, 2)
+			})
+		})
+
+		Convey("When Down is invoked for one step", func() {
+			reverted, err := migrator.Down(context.Background(), 1)
+
+			Convey("Then only the latest migration is reverted", func() {
+				So(err, ShouldBeNil)
+				So(reverted, ShouldHaveLength, 1)
+				So(reverted[0].Version, ShouldEqual, 2)
+			})
+		})
+	})
+}
+
+func TestMigrator_Status(t *testing.T) {
+	Convey("Given a database with the first migration applied", t, func() {
+		migrator := NewWithMigrations(mockConn(appliedRows(testMigrations()[0]), mockTransaction()), testMigrations())
+
+		Convey("When Status is invoked", func() {
+			statuses, err := migrator.Status(context.Background())
+
+			Convey("Then each known migration is reported as applied or pending", func() {
+				So(err, ShouldBeNil)
+				So(statuses, ShouldHaveLength, 2)
+				So(statuses[0].Applied, ShouldBeTrue)
+				So(statuses[0].AppliedAt, ShouldNotBeNil)
+				So(statuses[1].Applied, ShouldBeFalse)
+				So(statuses[1].AppliedAt, ShouldBeNil)
+			})
+		})
+	})
+}
@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS boundaries;
+DROP TABLE IF EXISTS area_name;
+DROP TABLE IF EXISTS area_relationship;
+DROP TABLE IF EXISTS area;
+DROP TABLE IF EXISTS relationship_type;
+DROP TABLE IF EXISTS area_type;
@@ -0,0 +1,8 @@
+-- Tables as originally created by the JSON schema builder. IF NOT EXISTS lets databases created before migrations
+-- were introduced adopt this migration without changes.
+CREATE TABLE IF NOT EXISTS area_type (PRIMARY KEY (id), id SERIAL, name VARCHAR(50));
+CREATE TABLE IF NOT EXISTS relationship_type (PRIMARY KEY (id), id SERIAL, name VARCHAR(50));
+CREATE TABLE IF NOT EXISTS area (PRIMARY KEY (code), active_from TIMESTAMP, active_to TIMESTAMP, area_type_id INT REFERENCES area_type(id), code VARCHAR(50) UNIQUE, geometric_area VARCHAR, visible BOOLEAN);
+CREATE TABLE IF NOT EXISTS area_relationship (PRIMARY KEY (area_code,rel_area_code), area_code VARCHAR(50) REFERENCES area(code), rel_area_code VARCHAR(50) REFERENCES area(code), rel_type_id INT REFERENCES relationship_type(id));
+CREATE TABLE IF NOT EXISTS area_name (PRIMARY KEY (id), active_from TIMESTAMP, active_to TIMESTAMP, area_code VARCHAR(50) REFERENCES area(code), id SERIAL, name VARCHAR(50) UNIQUE);
+CREATE TABLE IF NOT EXISTS boundaries (PRIMARY KEY (area_id), area_id VARCHAR(50), boundary VARCHAR, centroid VARCHAR, centroid_bng VARCHAR);
@@ -0,0 +1 @@
+ALTER TABLE area ADD COLUMN IF NOT EXISTS land_hectares FLOAT(4);
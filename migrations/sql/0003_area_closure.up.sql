@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS area_closure (PRIMARY KEY (ancestor,descendant), ancestor VARCHAR(50) REFERENCES area(code), depth INT NOT NULL, descendant VARCHAR(50) REFERENCES area(code));
+
+-- populate from existing child relationships, limiting depth to guard against cycles
+INSERT INTO area_closure(ancestor, descendant, depth)
+WITH RECURSIVE paths(ancestor, descendant, depth) AS (
+    SELECT code, code, 0 FROM area
+    UNION ALL
+    SELECT ar.area_code, p.descendant, p.depth + 1
+    FROM area_relationship AS ar, paths AS p
+    WHERE ar.rel_area_code = p.ancestor
+    AND ar.rel_type_id = (SELECT id FROM relationship_type WHERE name = 'child')
+    AND p.depth < 32
+)
+SELECT ancestor, descendant, min(depth) FROM paths GROUP BY ancestor, descendant
+ON CONFLICT (ancestor, descendant) DO NOTHING;
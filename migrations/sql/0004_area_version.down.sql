@@ -0,0 +1 @@
+ALTER TABLE area DROP COLUMN IF EXISTS version;
@@ -0,0 +1,5 @@
+ALTER TABLE area_closure DROP CONSTRAINT IF EXISTS area_closure_depth_check;
+DROP INDEX IF EXISTS area_closure_descendant_idx;
+DROP INDEX IF EXISTS area_relationship_rel_area_code_idx;
+DROP INDEX IF EXISTS area_name_area_code_idx;
+DROP INDEX IF EXISTS area_area_type_id_idx;
@@ -0,0 +1,6 @@
+-- indexes on the foreign key columns that queries filter and join on, which postgres does not create automatically
+CREATE INDEX IF NOT EXISTS area_area_type_id_idx ON area USING btree (area_type_id);
+CREATE INDEX IF NOT EXISTS area_name_area_code_idx ON area_name USING btree (area_code);
+CREATE INDEX IF NOT EXISTS area_relationship_rel_area_code_idx ON area_relationship USING btree (rel_area_code);
+CREATE INDEX IF NOT EXISTS area_closure_descendant_idx ON area_closure USING btree (descendant);
+ALTER TABLE area_closure ADD CONSTRAINT area_closure_depth_check CHECK (depth >= 0);
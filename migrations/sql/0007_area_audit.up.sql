@@ -0,0 +1,3 @@
+-- one row per change to an area, kept after the area itself is retired, so there is no foreign key to area
+CREATE TABLE IF NOT EXISTS area_audit (PRIMARY KEY (id), actor VARCHAR(255) NOT NULL, after JSONB , area_code VARCHAR(50) NOT NULL, before JSONB , changed_at TIMESTAMPTZ NOT NULL DEFAULT now(), id BIGSERIAL , operation VARCHAR(20) NOT NULL, CONSTRAINT area_audit_operation_check CHECK (operation IN ('create', 'update', 'patch', 'retire')));
+CREATE INDEX IF NOT EXISTS area_audit_area_code_changed_at_idx ON area_audit USING btree (area_code, changed_at);
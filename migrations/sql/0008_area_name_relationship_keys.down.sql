@@ -0,0 +1,6 @@
+-- fails if an area name or a pair of related areas has been added more than once since the migration was applied
+ALTER TABLE area_relationship DROP CONSTRAINT IF EXISTS area_relationship_pkey;
+ALTER TABLE area_relationship ALTER COLUMN rel_type_id DROP NOT NULL;
+ALTER TABLE area_relationship ADD CONSTRAINT area_relationship_pkey PRIMARY KEY (area_code, rel_area_code);
+DROP INDEX IF EXISTS area_name_area_code_name_active_from_idx;
+ALTER TABLE area_name ADD CONSTRAINT area_name_name_key UNIQUE (name);
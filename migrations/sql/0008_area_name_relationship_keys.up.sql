@@ -0,0 +1,8 @@
+-- an area name is unique per area and period rather than across every area, and two areas may be related by more
+-- than one relationship type
+ALTER TABLE area_name DROP CONSTRAINT IF EXISTS area_name_name_key;
+CREATE UNIQUE INDEX IF NOT EXISTS area_name_area_code_name_active_from_idx ON area_name USING btree (area_code, name, active_from);
+-- a relationship without a type cannot be part of the new primary key
+DELETE FROM area_relationship WHERE rel_type_id IS NULL;
+ALTER TABLE area_relationship DROP CONSTRAINT IF EXISTS area_relationship_pkey;
+ALTER TABLE area_relationship ADD CONSTRAINT area_relationship_pkey PRIMARY KEY (area_code, rel_area_code, rel_type_id);
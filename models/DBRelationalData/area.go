@@ -1,65 +0,0 @@
-package DBRelationalData
-
-// var representing test area data
-var AreaData = map[string]map[string]interface{}{
-	"E92000001": {
-		"columns": "code, active_from, active_to, area_type_id, geometric_area, visible",
-		"values": map[string]interface{}{
-			"active_from":    "2004-10-19 10:23:54 UTC",
-			"active_to":      "",
-			"area_type_id":   1,
-			"geometric_area": "[[[1.641969398993281,52.58775489167773],[1.644388947407413,52.58756063604116],[1.645133525485002,52.58775486399117],[1.64566308607092,52.58576297181493],[1.646311327253605,52.5853945277844],[1.647647600624691,52.58160991437295],[1.649535513522622,52.57947814773447],[1.649488704708769,52.57819054170735],[1.648592755666072,52.57679754972355],[1.647305237887501,52.57581929896951],[1.645285973227831,52.57506542075086],[1.642700831770864,52.57472000754937],[1.63991878792458,52.57521508183044],[1.638355517349843,52.57514455143755],[1.634546643269651,52.57332206573021],[1.632938650829513,52.57111320554571],[1.632820320794441,52.56953484838255],[1.632177525979574,52.56950548373192],[1.63290089100183,52.56932037976652],[1.634140833087102,52.56377593118339],[1.633780060405086,52.56221082640644],[1.632772286860712,52.56085268206686],[1.629372654317972,52.55865996678889],[1.627274699119914,52.5567124518054],[1.626522099682194,52.55684868412025],[1.62716072761341,52.55658858591315],[1.626256562995898,52.55569132404867],[1.625932737002519,52.55578184397925],[1.626254613537188,52.55559082569874],[1.625595367586566,52.55472652370543],[1.623805659176049,52.55333804023797],[1.619727180415795,52.55179592158388],[1.614378132738263,52.55197059970634],[1.610012951245314,52.55097756491441],[1.607491228027784,52.54866936882169],[1.606170850348904,52.54633725724143],[1.605287665921038,52.54322879916899],[1.6062567197791,52.5419423620427],[1.607862128961055,52.54093457173202],[1.610114356198066,52.54024701293805],[1.612954797592154,52.53998667567042],[1.61860553811193,52.54058832645809],[1.61857884142114,52.54020480525407],[1.618848832513387,52.54059949953069],[1.620575866302837,52.53984199728787],[1.619831820175696,52.53986027955533],[1.619966302790036,52.5396052141211],[1.620996158810381,52.53953590481662],[1.621309367081618,52.53722226548212],[1.619754528165279,52.53384860422305],[1.620515686012813,52.53262397712199],[1.617774258057387,52.53412127623121],[1.619593112997306,52.53345724086532],[1.619718735649605,52.53477725640769],[1.618767551892198,52.53547516850519],[1.617379258930364,52.53454622904628],[1.617583116841936,52.53423155069027],[1.616479573495289,52.53471370221397],[1.580356019234919,52.55544864939425],[1.582467873945534,52.55450765671266],[1.582382679282501,52.55600394024417],[1.581987298326357,52.55633108027996],[1.582059793588187,52.55479650564676],[1.581448472532754,52.55611327369054],[1.580161073393058,52.55555357869282],[1.579233160521463,52.55619660972286],[1.584852477115926,52.55786775864423],[1.589029006974366,52.55743743579301],[1.592286104162465,52.55776011539285],[1.594806735778816,52.55938004806007],[1.596703106890332,52.56323485888089],[1.597895023160075,52.56429538060441],[1.598536470953281,52.56412044220554],[1.598145828732104,52.56449039405441],[1.600836089469257,52.56655313928589],[1.601696996357878,52.56714969638348],[1.602365425009846,52.56684808499428],[1.601863557837185,52.56725300468672],[1.603036256850787,52.56776512499842],[1.607497897665254,52.56835225968382],[1.608595750068573,52.56926370283075],[1.608951311799721,52.57043784410597],[1.607408103893978,52.57231749815926],[1.607431714336282,52.5734206400533],[1.608033306715964,52.57329309931719],[1.607440252730624,52.57347065737362],[1.607670568749168,52.57384094021208],[1.610661059169516,52.5747412066877],[1.612600784781907,52.5748734608661],[1.614401438875477,52.57458234777003],[1.617439319800405,52.57335871469293],[1.619925570294166,52.57319413964638],[1.62375702443883,52.57423723997241],[1.62574798188536,52.57513437361954],[1.626902083141576,52.57612096091937],[1.627404211220578,52.57719875706196],[1.62745220675532,52.57902679870827],[1.62838587012342,52.5798547659735],[1.629925057637001,52.58057860591863],[1.632587522533077,52.58043704400693],[1.63482216856323,52.58086369585634],[1.637536816142478,52.5821517582325],[1.638826833617199,52.58416502575085],[1.638718269662901,52.58613813250212],[1.639443894370961,52.58608594148674],[1.638817639165797,52.58625371615323],[1.638871093444678,52.58650122672983],[1.639836334811685,52.58760378590387],[1.641852195608797,52.58781426857822],[1.64186122316584,52.58738385986051],[1.641969398993281,52.58775489167773]]]",
-			"visible":        true,
-		},
-	},
-	"W92000004": {
-		"columns": "code, active_from, active_to, area_type_id, geometric_area, visible",
-		"values": map[string]interface{}{
-			"active_from":    "2011-03-12 10:23:54 UTC",
-			"active_to":      "",
-			"area_type_id":   2,
-			"geometric_area": "[[[-4.333344310304969,51.7249345567844],[-4.333714799280278,51.72459176590723],[-4.333228471051473,51.72485054948815],[-4.332484708106176,51.72356674989395],[-4.331536255737817,51.72317184002521],[-4.331998050964103,51.72328323509102],[-4.331985242138722,51.72303436581642],[-4.331926419513477,51.72324150346475],[-4.331169197082952,51.72279244850181],[-4.332242159236069,51.72127380479937],[-4.332572468607179,51.72125016436441],[-4.332702576920521,51.7210758648653],[-4.332779021783574,51.720677780977],[-4.334653645452485,51.72266301535556],[-4.333985539454364,51.72284809808241],[-4.334603522966874,51.7227584105357],[-4.334424154980614,51.72464599982296],[-4.334172914205174,51.72488393285414],[-4.334206192564066,51.72446153276184],[-4.333894953495085,51.7251385780543],[-4.333344310304969,51.7249345567844]]]",
-			"visible":        true,
-		},
-	},
-	"E34000277": {
-		"columns": "code, active_from, active_to, area_type_id, geometric_area, visible",
-		"values": map[string]interface{}{
-			"active_from":    "2009-11-21 09:13:22 UTC",
-			"active_to":      "",
-			"area_type_id":   3,
-			"geometric_area": "[[[-0.5697494077652143,52.12885174958151],[-0.5697350294148817,52.12930109497107],[-0.5690047437200672,52.12929226327835],[-0.5689903566705548,52.12974169038177],[-0.568975970408872,52.13019103564599],[-0.5689615823580636,52.13064047077068],[-0.5682314069301059,52.13063163687151],[-0.568217014267314,52.13108097292439],[-0.5682026194185016,52.13153031879354],[-0.5681882228206699,52.13197975363027],[-0.5689184190741887,52.13198858806458],[-0.5689040312161694,52.13243792507095],[-0.5688896413095769,52.13288736002667],[-0.5688752524501985,52.13333670505433],[-0.5696055997901306,52.1333455363327],[-0.5695912161617743,52.13379497119484],[-0.5703213080944322,52.13380379479107],[-0.5703069268776431,52.13425312717582],[-0.5695768275884008,52.13424430342548],[-0.5688464642932859,52.13423547285269],[-0.5681162310099027,52.13422663663427],[-0.5673858594764819,52.1342177848078],[-0.5674002697386749,52.13376845284204],[-0.5674146746379212,52.13331901712647],[-0.5681450339766284,52.13332787009576],[-0.5681594287687884,52.13287852521922],[-0.5674290777286967,52.13286967217603],[-0.5674434819110752,52.13242023640468],[-0.566713136607854,52.13241137872085],[-0.5667275440996722,52.13196204169427],[-0.5659974382080882,52.13195318220878],[-0.5659830238808441,52.1324025193268],[-0.5659686074825846,52.1328519552989],[-0.565238372896932,52.13284309006544],[-0.5645081386697989,52.13283422030204],[-0.5637776565160098,52.13282534298784],[-0.5637920934599446,52.13237590584291],[-0.5630619821671149,52.13236702833147],[-0.5630475398466286,52.13281646558534],[-0.562317306696126,52.13280758223131],[-0.5623028615296705,52.13325692968956],[-0.5630331000566084,52.13326581293469],[-0.5630186570171721,52.13371525013281],[-0.5622884116228368,52.13370636787756],[-0.5622739582139418,52.13415570263473],[-0.5615437064018384,52.13414681598347],[-0.5615292419456316,52.13459624933699],[-0.561514778532746,52.13504559366739],[-0.5607843979673083,52.13503670126288],[-0.5600541346080244,52.13502780575174],[-0.5593238715806246,52.13501890660908],[-0.5593383526941228,52.13456956106928],[-0.5600686088286762,52.13457846120231],[-0.5600830870161322,52.13412902766534],[-0.5600975593511398,52.1336796906909],[-0.5608278036382869,52.13368858743585],[-0.5608422657626755,52.13323914904799],[-0.5615726218725188,52.13324804279539],[-0.5615870739048374,52.13279869434731],[-0.5616015299305152,52.13234925601251],[-0.5616159809252004,52.13189991739512],[-0.5616304362312668,52.13145047900455],[-0.5616448867958024,52.13100113134374],[-0.5616593382008191,52.1305517917618],[-0.562389539670704,52.13056068193409],[-0.5624039870602586,52.13011124355092],[-0.5624184297101276,52.12966189589754],[-0.5631486193266659,52.12967078174024],[-0.5638786910093238,52.12967966161399],[-0.563864260598248,52.13012900816856],[-0.5638498254505843,52.13057844545289],[-0.5645802763001742,52.13058732415779],[-0.564594704579374,52.13013788786352],[-0.5653249006766431,52.13014675910912],[-0.5660550971035345,52.13015562672397],[-0.5660695084655427,52.12970628046114],[-0.5660839235248402,52.1292568532992],[-0.5660983356300184,52.12880750699833],[-0.5668283965371552,52.12881636895694],[-0.5668428043320007,52.12836693284299],[-0.5675730923918152,52.12837579410019],[-0.5675874898216405,52.12792645691417],[-0.5683177679090747,52.12793531182385],[-0.5690479003245901,52.12794414537615],[-0.5690335172663821,52.12839348270435],[-0.5697637871898317,52.12840231336509],[-0.5697494077652143,52.12885174958151]]]",
-			"visible":        true,
-		},
-	},
-	"W37000382": {
-		"columns": "code, active_from, active_to, area_type_id, geometric_area, visible",
-		"values": map[string]interface{}{
-			"active_from":    "2011-03-27 00:00:00 UTC",
-			"active_to":      "",
-			"area_type_id":   2,
-			"geometric_area": "[[[-4.034106098364683,51.65910228788033],[-4.034126224128561,51.65955162358695],[-4.033403611497793,51.65956413805225],[-4.033423727016852,51.66001338411314],[-4.034146346367034,51.66000086939424],[-4.034166468702245,51.66045010617287],[-4.034888848938035,51.66043759134119],[-4.035611228930962,51.66042507237591],[-4.036333723995822,51.66041254696704],[-4.036313580886132,51.65996331087219]]]",
-			"visible":        true,
-		},
-	},
-	"E12000003": {
-		"columns": "code, active_from, active_to, area_type_id, geometric_area, visible",
-		"values": map[string]interface{}{
-			"active_from":    "2009-01-01 00:00:00 UTC",
-			"active_to":      "",
-			"area_type_id":   1,
-			"geometric_area": "[[[-0.7922270380335271,54.55946559293788],[-0.7896215459846694,54.55857137770264],[-0.7883233617230232,54.55875251572692],[-0.7885928064433368,54.55899971090531],[-0.7876821166001565,54.55873533319792],[-0.7855745631327212,54.55878068033757],[-0.7836849576369228,54.55934768891805],[-0.7816213652955453,54.55802284584383],[-0.7788591569285034,54.55710435562029],[-0.7762833460718025,54.55693631666141],[-0.7756411974714829,54.55777642691579],[-0.7745359758820383,54.55739946390996],[-0.7753360743792509,54.55474546833006],[-0.7741840636924986,54.55325272300649],[-0.7719072091506599,54.55209722790718],[-0.7693944190198142,54.55205551071037],[-0.7682623521288834,54.55083248632642],[-0.7651366234013267,54.54866612947091],[-0.7652021018845148,54.54833963168446],[-0.7658864888737806,54.54897303507646],[-0.7672407628112726,54.54856267338886],[-0.7675321067384391,54.54812799159371],[-0.7655944724006691,54.54825019458885],[-0.767714223321463,54.54726613873827],[-0.767257297448893,54.54642383637363],[-0.7648670648903985,54.54526687747287],[-0.762214157151164,54.54398264389263],[-0.7551252201427253,54.54351620623444],[-0.7531258329839171,54.54242162742217],[-0.7509480436433142,54.54190571917023],[-0.7495603419281971,54.54012449015704],[-0.7489846000341429,54.53766948457152],[-0.7478178226843573,54.5370103164078],[-0.7471192826157153,54.53569364346149],[-0.7478635279962351,54.53425345577081],[-0.7496302649011539,54.53333433880079],[-0.7495551671886617,54.53270623685493],[-0.7475607884019233,54.53035966082556],[-0.7446572896490014,54.52854016422976],[-0.7412507800213843,54.52786125989611],[-0.7360947271024321,54.52744801526431]]]",
-			"visible":        true,
-		},
-	},
-	"E08000019": {
-		"columns": "code, active_from, active_to, area_type_id, geometric_area, visible",
-		"values": map[string]interface{}{
-			"active_from":    "2009-01-01 00:00:00 UTC",
-			"active_to":      "",
-			"area_type_id":   1,
-			"geometric_area": "[[[-1.69845750935049,53.5025418261425],[-1.69337126022761,53.5001883206165],[-1.69071129936715,53.4994597602318],[-1.69051560696996,53.4998430432364],[-1.68897608906294,53.4996916999788],[-1.68879631378782,53.4991204522667],[-1.68739952047144,53.4990359546728],[-1.68446725821812,53.4994677902944],[-1.6839847191014,53.4988867534324],[-1.6811157315059,53.4999272848244],[-1.68044939311894,53.5005222669371],[-1.66905289349772,53.5006703206656],[-1.6588426425518,53.4983954212497],[-1.6550899928061,53.4967963723104],[-1.64797637334012,53.4948161215437],[-1.64126140383285,53.4940805778943]]]",
-			"visible":        true,
-		},
-	},
-}
@@ -1,68 +0,0 @@
-package DBRelationalData
-
-// var representing test area name data
-var AreaNameData = map[string]map[string]interface{}{
-	"England": {
-		"columns": "area_code, name, active_from, active_to",
-		"values": map[string]interface{}{
-			"area_code":   "E92000001",
-			"area_name":   "England",
-			"active_from": "2004-10-19 10:23:54 UTC",
-			"active_to":   "",
-		},
-	},
-	"Wales": {
-		"columns": "area_code, name, active_from, active_to",
-		"values": map[string]interface{}{
-			"area_code":   "W92000004",
-			"area_name":   "Wales",
-			"active_from": "2011-03-12 10:23:54 UTC",
-			"active_to":   "",
-		},
-	},
-	"Stagsden": {
-		"columns": "area_code, name, active_from, active_to",
-		"values": map[string]interface{}{
-			"area_code":   "E34000277",
-			"area_name":   "Stagsden",
-			"active_from": "2009-11-21 09:13:22 UTC",
-			"active_to":   "",
-		},
-	},
-	"Gorseinon": {
-		"columns": "area_code, name, active_from, active_to",
-		"values": map[string]interface{}{
-			"area_code":   "W37000382",
-			"area_name":   "Gorseinon",
-			"active_from": "2011-03-27 00:00:00 UTC",
-			"active_to":   "",
-		},
-	},
-	"Loughor": {
-		"columns": "area_code, name, active_from, active_to",
-		"values": map[string]interface{}{
-			"area_code":   "W38000028",
-			"area_name":   "Loughor",
-			"active_from": "2011-03-27 00:00:00 UTC",
-			"active_to":   "",
-		},
-	},
-	"Yorkshire and the Humber": {
-		"columns": "area_code, name, active_from, active_to",
-		"values": map[string]interface{}{
-			"area_code":   "E12000003",
-			"area_name":   "Yorkshire and the Humber",
-			"active_from": "2009-01-01 00:00:00 UTC",
-			"active_to":   "",
-		},
-	},
-	"Sheffield": {
-		"columns": "area_code, name, active_from, active_to",
-		"values": map[string]interface{}{
-			"area_code":   "E08000019",
-			"area_name":   "Sheffield",
-			"active_from": "2009-01-01 00:00:00 UTC",
-			"active_to":   "",
-		},
-	},
-}
@@ -1,55 +0,0 @@
-package DBRelationalData
-
-// var representing test area_type data
-var AreaTypeData = map[string]map[string]interface{}{
-	"Country": {
-		"creation_order": 0,
-		"columns":        "name",
-		"values":         "Country",
-	},
-	"Region": {
-		"creation_order": 1,
-		"columns":        "name",
-		"values":         "Region",
-	},
-	"Unitary Authorities": {
-		"creation_order": 2,
-		"columns":        "name",
-		"values":         "Unitary Authorities",
-	},
-	"Combined Authorities": {
-		"creation_order": 3,
-		"columns":        "name",
-		"values":         "Combined Authorities",
-	},
-	"Metropolitan Counties": {
-		"creation_order": 4,
-		"columns":        "name",
-		"values":         "Metropolitan Counties",
-	},
-	"Counties": {
-		"creation_order": 5,
-		"columns":        "name",
-		"values":         "Counties",
-	},
-	"London Boroughs": {
-		"creation_order": 6,
-		"columns":        "name",
-		"values":         "London Boroughs",
-	},
-	"Metropolitan Districts": {
-		"creation_order": 7,
-		"columns":        "name",
-		"values":         "Metropolitan Districts",
-	},
-	"Non-metropolitan Districts": {
-		"creation_order": 8,
-		"columns":        "name",
-		"values":         "Non-metropolitan Districts",
-	},
-	"Electoral Wards": {
-		"creation_order": 9,
-		"columns":        "name",
-		"values":         "Electoral Wards",
-	},
-}
@@ -1,14 +0,0 @@
-package DBRelationalData
-
-// var representing test baoundries data
-var BoundariesData = map[string]map[string]interface{}{
-	"E92000001": {
-		"columns": "area_id, centroid_bng, centroid, boundary",
-		"values": map[string]interface{}{
-			"area_id":      "E92000001",
-			"centroid_bng": "[-4.333344310304969,51.7249345567844]",
-			"centroid":     "[-4.333344310304969,51.7249345567844]",
-			"boundary":     "[[[-4.333344310304969,51.7249345567844],[-4.333714799280278,51.72459176590723],[-4.333228471051473,51.72485054948815],[-4.332484708106176,51.72356674989395],[-4.331536255737817,51.72317184002521],[-4.331998050964103,51.72328323509102],[-4.331985242138722,51.72303436581642],[-4.331926419513477,51.72324150346475],[-4.331169197082952,51.72279244850181],[-4.332242159236069,51.72127380479937],[-4.332572468607179,51.72125016436441],[-4.332702576920521,51.7210758648653],[-4.332779021783574,51.720677780977],[-4.334653645452485,51.72266301535556],[-4.333985539454364,51.72284809808241],[-4.334603522966874,51.7227584105357],[-4.334424154980614,51.72464599982296],[-4.334172914205174,51.72488393285414],[-4.334206192564066,51.72446153276184],[-4.333894953495085,51.7251385780543],[-4.333344310304969,51.7249345567844]]]",
-		},
-	},
-}
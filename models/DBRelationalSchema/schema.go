@@ -29,6 +29,12 @@ const DBSchema = `{
             "area": {
                 "creation_order": 2,
                 "primary_keys": "code",
+                "indexes": [
+                    {
+                        "name": "area_area_type_id_idx",
+                        "columns": ["area_type_id"]
+                    }
+                ],
                 "columns": {
                     "code": {
                         "data_type": "VARCHAR(50)",
@@ -57,12 +63,31 @@ const DBSchema = `{
                     "land_hectares": {
                         "data_type": "FLOAT(4)",
                         "constraints": ""
+                    },
+                    "version": {
+                        "data_type": "INT",
+                        "constraints": "NOT NULL DEFAULT 1"
+                    },
+                    "updated_at": {
+                        "data_type": "TIMESTAMPTZ",
+                        "constraints": "NOT NULL DEFAULT now()"
                     }
                 }
             },
             "area_name": {
                 "creation_order": 4,
                 "primary_keys": "id",
+                "indexes": [
+                    {
+                        "name": "area_name_area_code_idx",
+                        "columns": ["area_code"]
+                    },
+                    {
+                        "name": "area_name_area_code_name_active_from_idx",
+                        "columns": ["area_code", "name", "active_from"],
+                        "unique": true
+                    }
+                ],
                 "columns": {
                     "id": {
                         "data_type": "SERIAL",
@@ -74,7 +99,7 @@ const DBSchema = `{
                     },
                     "name": {
                         "data_type": "VARCHAR(50)",
-                        "constraints": "UNIQUE"
+                        "constraints": ""
                     },
                     "active_from": {
                         "data_type": "TIMESTAMP",
@@ -88,7 +113,13 @@ const DBSchema = `{
             },
             "area_relationship": {
                 "creation_order": 3,
-                "primary_keys": "area_code,rel_area_code",
+                "primary_keys": "area_code,rel_area_code,rel_type_id",
+                "indexes": [
+                    {
+                        "name": "area_relationship_rel_area_code_idx",
+                        "columns": ["rel_area_code"]
+                    }
+                ],
                 "columns": {
                     "area_code": {
                         "data_type": "VARCHAR(50)",
@@ -118,6 +149,82 @@ const DBSchema = `{
                     }
                 }
             },
+            "area_closure": {
+                "creation_order": 6,
+                "primary_keys": "ancestor,descendant",
+                "indexes": [
+                    {
+                        "name": "area_closure_descendant_idx",
+                        "columns": ["descendant"]
+                    }
+                ],
+                "checks": [
+                    {
+                        "name": "area_closure_depth_check",
+                        "expression": "depth >= 0"
+                    }
+                ],
+                "columns": {
+                    "ancestor": {
+                        "data_type": "VARCHAR(50)",
+                        "constraints": "REFERENCES area(code)"
+                    },
+                    "descendant": {
+                        "data_type": "VARCHAR(50)",
+                        "constraints": "REFERENCES area(code)"
+                    },
+                    "depth": {
+                        "data_type": "INT",
+                        "constraints": "NOT NULL"
+                    }
+                }
+            },
+            "area_audit": {
+                "creation_order": 7,
+                "primary_keys": "id",
+                "indexes": [
+                    {
+                        "name": "area_audit_area_code_changed_at_idx",
+                        "columns": ["area_code", "changed_at"]
+                    }
+                ],
+                "checks": [
+                    {
+                        "name": "area_audit_operation_check",
+                        "expression": "operation IN ('create', 'update', 'patch', 'retire')"
+                    }
+                ],
+                "columns": {
+                    "id": {
+                        "data_type": "BIGSERIAL",
+                        "constraints": ""
+                    },
+                    "area_code": {
+                        "data_type": "VARCHAR(50)",
+                        "constraints": "NOT NULL"
+                    },
+                    "actor": {
+                        "data_type": "VARCHAR(255)",
+                        "constraints": "NOT NULL"
+                    },
+                    "operation": {
+                        "data_type": "VARCHAR(20)",
+                        "constraints": "NOT NULL"
+                    },
+                    "changed_at": {
+                        "data_type": "TIMESTAMPTZ",
+                        "constraints": "NOT NULL DEFAULT now()"
+                    },
+                    "before": {
+                        "data_type": "JSONB",
+                        "constraints": ""
+                    },
+                    "after": {
+                        "data_type": "JSONB",
+                        "constraints": ""
+                    }
+                }
+            },
             "relationship_type": {
                 "creation_order": 1,
                 "primary_keys": "id",
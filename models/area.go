@@ -2,6 +2,10 @@ package models
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
 	"time"
 )
 
@@ -25,8 +29,14 @@ var areaTypeAndCode = map[string]string{
 type AreaType int
 
 var (
-	AcceptLanguageHeaderName = "Accept-Language"
-	AcceptLanguageMapping    = map[string]string{
+	ETagHeaderName            = "ETag"
+	IfMatchHeaderName         = "If-Match"
+	IfNoneMatchHeaderName     = "If-None-Match"
+	IfModifiedSinceHeaderName = "If-Modified-Since"
+	LastModifiedHeaderName    = "Last-Modified"
+	CacheControlHeaderName    = "Cache-Control"
+	AcceptLanguageHeaderName  = "Accept-Language"
+	AcceptLanguageMapping     = map[string]string{
 		"en": "English",
 		"cy": "Cymraeg",
 	}
@@ -53,6 +63,28 @@ type AreaParams struct {
 }
 
 func (a *AreaParams) ValidateAreaRequest(ctx context.Context) []error {
+	validationErrs := a.validateRequiredFields(ctx)
+
+	if a.AreaName != nil {
+		if a.AreaName.ActiveFrom == nil {
+			validationErrs = append(validationErrs, NewValidationError(ctx, AreaNameActiveFromNotProvidedError, AreaNameActiveFromNotProvidedErrorDescription))
+		}
+
+		if a.AreaName.ActiveTo == nil {
+			validationErrs = append(validationErrs, NewValidationError(ctx, AreaNameActiveToNotProvidedError, AreaNameActiveToNotProvidedErrorDescription))
+		}
+	}
+
+	return validationErrs
+}
+
+// ValidatePatchedArea validates an area after a patch has been applied. Unlike a full update, existing areas may have
+// open ended names so the area name dates are not required.
+func (a *AreaParams) ValidatePatchedArea(ctx context.Context) []error {
+	return a.validateRequiredFields(ctx)
+}
+
+func (a *AreaParams) validateRequiredFields(ctx context.Context) []error {
 	var validationErrs []error
 	if a.Code == "" {
 		validationErrs = append(validationErrs, NewValidationError(ctx, InvalidAreaCodeError, InvalidAreaCodeErrorDescription))
@@ -64,19 +96,8 @@ func (a *AreaParams) ValidateAreaRequest(ctx context.Context) []error {
 
 	if a.AreaName == nil {
 		validationErrs = append(validationErrs, NewValidationError(ctx, AreaNameDetailsNotProvidedError, AreaNameDetailsNotProvidedErrorDescription))
-	} else {
-
-		if a.AreaName.Name == "" {
-			validationErrs = append(validationErrs, NewValidationError(ctx, AreaNameNotProvidedError, AreaNameNotProvidedErrorDescription))
-		}
-
-		if a.AreaName.ActiveFrom == nil {
-			validationErrs = append(validationErrs, NewValidationError(ctx, AreaNameActiveFromNotProvidedError, AreaNameActiveFromNotProvidedErrorDescription))
-		}
-
-		if a.AreaName.ActiveTo == nil {
-			validationErrs = append(validationErrs, NewValidationError(ctx, AreaNameActiveToNotProvidedError, AreaNameActiveToNotProvidedErrorDescription))
-		}
+	} else if a.AreaName.Name == "" {
+		validationErrs = append(validationErrs, NewValidationError(ctx, AreaNameNotProvidedError, AreaNameNotProvidedErrorDescription))
 	}
 
 	return validationErrs
@@ -99,6 +120,55 @@ type AreasDataResults struct {
 	Visible       *bool            `json:"visible"`
 	AreaType      *string          `json:"area_type"`
 	Ancestors     []AreasAncestors `json:"ancestors"`
+	Version       int              `json:"-"`
+	UpdatedAt     time.Time        `json:"-"`
+	// LastModified is when the area or one of its ancestors was last written, set by GetAreaDetails
+	LastModified time.Time `json:"-"`
+}
+
+// AreaDetailsOptions selects what is fetched with an area's details
+type AreaDetailsOptions struct {
+	IncludeInactive bool
+	Children        bool
+	Boundary        bool
+}
+
+// AreaDetails is an area composed with its names and ancestors, and its children when requested. The area's geometry
+// is only set when its boundary is requested.
+type AreaDetails struct {
+	Area     *AreasDataResults
+	Names    []AreaName
+	Children []*AreaBasicData
+}
+
+// AreaETag returns the entity tag for a version of an area
+func AreaETag(version int) string {
+	return fmt.Sprintf(`"%d"`, version)
+}
+
+// AreaDetailsETag returns the entity tag of an area's details: its version, which writes are conditional on, followed
+// by a hash of its ancestry, which changes without the area's version when an ancestor is renamed or moved
+func AreaDetailsETag(version int, ancestry []byte) string {
+	sum := sha256.Sum256(ancestry)
+	return fmt.Sprintf(`"%d-%s"`, version, hex.EncodeToString(sum[:8]))
+}
+
+// ETagMatches reports whether an If-Match header value matches the given version of an area. An empty value places
+// no condition on the write; "*" matches any existing area. The tag of an area's details matches by its version, as
+// writes to an area do not depend on its ancestry.
+func ETagMatches(ifMatch string, version int) bool {
+	if ifMatch == "" || strings.TrimSpace(ifMatch) == "*" {
+		return true
+	}
+	eTag := AreaETag(version)
+	detailsPrefix := fmt.Sprintf(`"%d-`, version)
+	for _, candidate := range strings.Split(ifMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == eTag || (strings.HasPrefix(candidate, detailsPrefix) && strings.HasSuffix(candidate, `"`)) {
+			return true
+		}
+	}
+	return false
 }
 
 // BoundaryDataResults represents the structure for a boundary in api v1.
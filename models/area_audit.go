@@ -0,0 +1,65 @@
+package models
+
+import "time"
+
+// Operations recorded in the audit log of an area
+const (
+	AreaAuditCreate = "create"
+	AreaAuditUpdate = "update"
+	AreaAuditPatch  = "patch"
+	AreaAuditRetire = "retire"
+)
+
+// Limits on the page size of an area's audit log
+const (
+	DefaultAreaAuditLimit = 20
+	MaxAreaAuditLimit     = 1000
+)
+
+// AreaAuditState is an area as it was before or after a change, in the form it is written
+type AreaAuditState struct {
+	Code          string     `json:"code"`
+	AreaType      string     `json:"area_type"`
+	ParentCode    string     `json:"parent_code"`
+	ActiveFrom    *time.Time `json:"active_from"`
+	ActiveTo      *time.Time `json:"active_to"`
+	Visible       *bool      `json:"visible"`
+	AreaHectares  float64    `json:"area_hectares"`
+	GeometricData string     `json:"geometry"`
+	Version       int        `json:"version"`
+	Names         []AreaName `json:"names"`
+}
+
+// AreaAuditEntry records one change to an area. Before is nil when the change created the area.
+type AreaAuditEntry struct {
+	ID        int64           `json:"id"`
+	AreaCode  string          `json:"area_code"`
+	Actor     string          `json:"actor"`
+	Operation string          `json:"operation"`
+	ChangedAt time.Time       `json:"changed_at"`
+	Before    *AreaAuditState `json:"before"`
+	After     *AreaAuditState `json:"after"`
+}
+
+// AreaAuditQuery selects a page of an area's audit log. From and To, when set, keep changes made at or after From and
+// before To.
+type AreaAuditQuery struct {
+	From   *time.Time
+	To     *time.Time
+	Offset int
+	Limit  int
+}
+
+// Includes reports whether a change made at changedAt is within the query's dates
+func (q AreaAuditQuery) Includes(changedAt time.Time) bool {
+	return (q.From == nil || !changedAt.Before(*q.From)) && (q.To == nil || changedAt.Before(*q.To))
+}
+
+// AreaAuditPage is a page of an area's audit log, most recent change first
+type AreaAuditPage struct {
+	Count      int              `json:"count"`
+	Offset     int              `json:"offset"`
+	Limit      int              `json:"limit"`
+	TotalCount int              `json:"total_count"`
+	Items      []AreaAuditEntry `json:"items"`
+}
@@ -0,0 +1,140 @@
+package models
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+)
+
+// NDJSONContentType is the content type for newline delimited JSON bulk requests
+const NDJSONContentType = "application/x-ndjson"
+
+// Statuses reported for each item of a bulk upsert
+const (
+	BulkAreaCreated    = "created"
+	BulkAreaUpdated    = "updated"
+	BulkAreaInvalid    = "invalid"
+	BulkAreaFailed     = "failed"
+	BulkAreaRolledBack = "rolled_back"
+	BulkAreaSkipped    = "skipped"
+)
+
+// BulkAreaResult reports the outcome of a single area in a bulk upsert, identified by its position in the request
+type BulkAreaResult struct {
+	Index  int     `json:"index"`
+	Code   string  `json:"code"`
+	Status string  `json:"status"`
+	Errors []error `json:"errors,omitempty"`
+}
+
+// BulkAreaResponse is the body returned by a bulk upsert
+type BulkAreaResponse struct {
+	Results []BulkAreaResult `json:"results"`
+}
+
+// ParseBulkAreas decodes a bulk upsert body, either as a JSON array of areas or as newline delimited JSON with one
+// area per line
+func ParseBulkAreas(contentType string, body []byte) ([]AreaParams, error) {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	trimmed := bytes.TrimSpace(body)
+	if mediaType != NDJSONContentType && bytes.HasPrefix(trimmed, []byte("[")) {
+		var areas []AreaParams
+		if err := json.Unmarshal(trimmed, &areas); err != nil {
+			return nil, err
+		}
+		return areas, nil
+	}
+
+	var areas []AreaParams
+	decoder := json.NewDecoder(bytes.NewReader(trimmed))
+	for {
+		var area AreaParams
+		err := decoder.Decode(&area)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("item %d: %w", len(areas), err)
+		}
+		areas = append(areas, area)
+	}
+	return areas, nil
+}
+
+// ValidateBulkAreas validates every area as a PUT would, then rejects duplicate codes and parent cycles within the
+// request. It returns the order in which the areas should be applied so that parents come before their children, and
+// a result for each area that failed validation.
+func ValidateBulkAreas(ctx context.Context, areas []AreaParams) ([]int, []BulkAreaResult) {
+	var invalid []BulkAreaResult
+	codes := make(map[string]int, len(areas))
+
+	for i := range areas {
+		areas[i].SetAreaType(ctx)
+		validationErrs := areas[i].ValidateAreaRequest(ctx)
+
+		if first, ok := codes[areas[i].Code]; ok && areas[i].Code != "" {
+			validationErrs = append(validationErrs, NewValidationError(ctx, DuplicateAreaCodeError, fmt.Sprintf("%s, first seen at index %d", DuplicateAreaCodeErrorDescription, first)))
+		} else {
+			codes[areas[i].Code] = i
+		}
+
+		if len(validationErrs) != 0 {
+			invalid = append(invalid, BulkAreaResult{Index: i, Code: areas[i].Code, Status: BulkAreaInvalid, Errors: validationErrs})
+		}
+	}
+	if len(invalid) != 0 {
+		return nil, invalid
+	}
+
+	order, cyclic := sortAreasByParent(areas, codes)
+	for _, i := range cyclic {
+		invalid = append(invalid, BulkAreaResult{
+			Index:  i,
+			Code:   areas[i].Code,
+			Status: BulkAreaInvalid,
+			Errors: []error{NewValidationError(ctx, AreaParentCycleError, AreaParentCycleErrorDescription)},
+		})
+	}
+	if len(invalid) != 0 {
+		return nil, invalid
+	}
+	return order, nil
+}
+
+// sortAreasByParent topologically sorts areas so that any parent included in the request is applied before its
+// children. Parents outside the request are assumed to exist already. Areas that are part of a parent cycle cannot be
+// ordered and are returned separately.
+func sortAreasByParent(areas []AreaParams, codes map[string]int) (order []int, cyclic []int) {
+	children := make(map[int][]int)
+	waiting := make([]bool, len(areas))
+	var queue []int
+
+	for i, area := range areas {
+		if parent, ok := codes[area.ParentCode]; ok && area.ParentCode != "" {
+			children[parent] = append(children[parent], i)
+			waiting[i] = true
+			continue
+		}
+		queue = append(queue, i)
+	}
+
+	for len(queue) > 0 {
+		i := queue[0]
+		queue = queue[1:]
+		order = append(order, i)
+		for _, child := range children[i] {
+			waiting[child] = false
+			queue = append(queue, child)
+		}
+	}
+
+	for i := range areas {
+		if waiting[i] {
+			cyclic = append(cyclic, i)
+		}
+	}
+	return order, cyclic
+}
@@ -0,0 +1,113 @@
+package models_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ONSdigital/dp-areas-api/models"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestParseBulkAreas(t *testing.T) {
+	Convey("Given a JSON array of areas", t, func() {
+		body := []byte(` [{"code": "E12000003"}, {"code": "E08000019", "parent_code": "E12000003"}]`)
+
+		Convey("When it is parsed", func() {
+			areas, err := models.ParseBulkAreas("application/json", body)
+
+			Convey("Then every area is returned", func() {
+				So(err, ShouldBeNil)
+				So(areas, ShouldHaveLength, 2)
+				So(areas[1].ParentCode, ShouldEqual, "E12000003")
+			})
+		})
+	})
+
+	Convey("Given newline delimited areas", t, func() {
+		body := []byte("{\"code\": \"E12000003\"}\n{\"code\": \"E08000019\"}\n")
+
+		Convey("When it is parsed", func() {
+			areas, err := models.ParseBulkAreas(models.NDJSONContentType, body)
+
+			Convey("Then every area is returned", func() {
+				So(err, ShouldBeNil)
+				So(areas, ShouldHaveLength, 2)
+				So(areas[0].Code, ShouldEqual, "E12000003")
+			})
+		})
+
+		Convey("When a line is malformed", func() {
+			_, err := models.ParseBulkAreas(models.NDJSONContentType, append(body, []byte("{\"code\": ")...))
+
+			Convey("Then an error identifying the item is returned", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldStartWith, "item 2")
+			})
+		})
+	})
+}
+
+func TestValidateBulkAreas(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	bulkArea := func(code, parent string) models.AreaParams {
+		return models.AreaParams{Code: code, ParentCode: parent, AreaName: &models.AreaName{Name: code, ActiveFrom: &now, ActiveTo: &now}}
+	}
+
+	Convey("Given children listed before their parents", t, func() {
+		areas := []models.AreaParams{
+			bulkArea("E05000001", "E08000019"),
+			bulkArea("E08000019", "E12000003"),
+			bulkArea("E12000003", "E92000001"),
+		}
+
+		Convey("When the areas are validated", func() {
+			order, invalid := models.ValidateBulkAreas(ctx, areas)
+
+			Convey("Then parents are ordered before their children", func() {
+				So(invalid, ShouldBeEmpty)
+				So(order, ShouldResemble, []int{2, 1, 0})
+				So(areas[0].AreaType, ShouldEqual, "Electoral Wards")
+			})
+		})
+	})
+
+	Convey("Given an invalid area and a duplicate code", t, func() {
+		areas := []models.AreaParams{
+			bulkArea("E08000019", ""),
+			{Code: "E12000003"},
+			bulkArea("E08000019", ""),
+		}
+
+		Convey("When the areas are validated", func() {
+			order, invalid := models.ValidateBulkAreas(ctx, areas)
+
+			Convey("Then nothing is ordered and each problem is reported", func() {
+				So(order, ShouldBeNil)
+				So(invalid, ShouldHaveLength, 2)
+				So(invalid[0].Index, ShouldEqual, 1)
+				So(invalid[1].Index, ShouldEqual, 2)
+				So(invalid[1].Errors[0].(*models.Error).Code, ShouldEqual, models.DuplicateAreaCodeError)
+			})
+		})
+	})
+
+	Convey("Given areas whose parents form a cycle", t, func() {
+		areas := []models.AreaParams{
+			bulkArea("E12000003", ""),
+			bulkArea("E08000019", "E05000001"),
+			bulkArea("E05000001", "E08000019"),
+		}
+
+		Convey("When the areas are validated", func() {
+			order, invalid := models.ValidateBulkAreas(ctx, areas)
+
+			Convey("Then the areas in the cycle are rejected", func() {
+				So(order, ShouldBeNil)
+				So(invalid, ShouldHaveLength, 2)
+				So(invalid[0].Errors[0].(*models.Error).Code, ShouldEqual, models.AreaParentCycleError)
+			})
+		})
+	})
+}
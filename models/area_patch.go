@@ -0,0 +1,245 @@
+package models
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"sort"
+	"time"
+)
+
+// Patch content types accepted by PATCH /v1/areas/{id}
+const (
+	JSONPatchContentType  = "application/json-patch+json"
+	MergePatchContentType = "application/merge-patch+json"
+)
+
+// JSON Patch operations supported for areas
+const (
+	PatchOpAdd     = "add"
+	PatchOpReplace = "replace"
+	PatchOpRemove  = "remove"
+)
+
+// PatchOperation represents a single RFC 6902 JSON Patch operation
+type PatchOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// AreaPatch is an ordered list of patch operations to be applied to an area
+type AreaPatch []PatchOperation
+
+// areaPatchField describes a whitelisted patch path and how a value is applied to it
+type areaPatchField struct {
+	// removable fields are nullable and may be the target of a remove operation
+	removable bool
+	apply     func(area *AreaParams, value json.RawMessage) error
+}
+
+var areaPatchFields = map[string]areaPatchField{
+	"/visible": {apply: func(area *AreaParams, value json.RawMessage) error {
+		var visible bool
+		if err := unmarshalPatchValue(value, &visible); err != nil {
+			return err
+		}
+		area.Visible = &visible
+		return nil
+	}},
+	"/active_from": {removable: true, apply: func(area *AreaParams, value json.RawMessage) error {
+		return applyPatchTime(value, &area.ActiveFrom)
+	}},
+	"/active_to": {removable: true, apply: func(area *AreaParams, value json.RawMessage) error {
+		return applyPatchTime(value, &area.ActiveTo)
+	}},
+	"/area_hectares": {apply: func(area *AreaParams, value json.RawMessage) error {
+		return unmarshalPatchValue(value, &area.AreaHectares)
+	}},
+	"/geometry": {apply: func(area *AreaParams, value json.RawMessage) error {
+		return unmarshalPatchValue(value, &area.GeometricData)
+	}},
+	"/parent_code": {apply: func(area *AreaParams, value json.RawMessage) error {
+		var parentCode string
+		if err := unmarshalPatchValue(value, &parentCode); err != nil {
+			return err
+		}
+		if parentCode == "" || parentCode == area.Code {
+			return errors.New("parent_code must be a different, non-empty area code")
+		}
+		area.ParentCode = parentCode
+		return nil
+	}},
+	"/area_name/name": {apply: func(area *AreaParams, value json.RawMessage) error {
+		var name string
+		if err := unmarshalPatchValue(value, &name); err != nil {
+			return err
+		}
+		if name == "" {
+			return errors.New(AreaNameNotProvidedErrorDescription)
+		}
+		areaName(area).Name = name
+		return nil
+	}},
+	"/area_name/active_from": {removable: true, apply: func(area *AreaParams, value json.RawMessage) error {
+		return applyPatchTime(value, &areaName(area).ActiveFrom)
+	}},
+	"/area_name/active_to": {removable: true, apply: func(area *AreaParams, value json.RawMessage) error {
+		return applyPatchTime(value, &areaName(area).ActiveTo)
+	}},
+}
+
+// ParseAreaPatch decodes a PATCH request body as either a JSON Patch or, for any other content type, a merge patch
+func ParseAreaPatch(contentType string, body []byte) (AreaPatch, error) {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	if mediaType == JSONPatchContentType {
+		var patch AreaPatch
+		if err := json.Unmarshal(body, &patch); err != nil {
+			return nil, err
+		}
+		return patch, nil
+	}
+	return newAreaMergePatch(body)
+}
+
+// newAreaMergePatch converts an RFC 7386 merge patch document into the equivalent JSON Patch operations, so that both
+// formats go through the same validation. Null members become remove operations.
+func newAreaMergePatch(body []byte) (AreaPatch, error) {
+	var document map[string]json.RawMessage
+	if err := json.Unmarshal(body, &document); err != nil {
+		return nil, err
+	}
+	if document == nil {
+		return nil, errors.New("merge patch must be a JSON object")
+	}
+
+	var patch AreaPatch
+	for _, key := range sortedKeys(document) {
+		value := document[key]
+		if key == "area_name" && !isJSONNull(value) {
+			var nameDocument map[string]json.RawMessage
+			if err := json.Unmarshal(value, &nameDocument); err != nil {
+				return nil, fmt.Errorf("area_name: %w", err)
+			}
+			for _, nameKey := range sortedKeys(nameDocument) {
+				patch = append(patch, mergePatchOperation("/area_name/"+nameKey, nameDocument[nameKey]))
+			}
+			continue
+		}
+		patch = append(patch, mergePatchOperation("/"+key, value))
+	}
+	return patch, nil
+}
+
+func mergePatchOperation(path string, value json.RawMessage) PatchOperation {
+	if isJSONNull(value) {
+		return PatchOperation{Op: PatchOpRemove, Path: path}
+	}
+	return PatchOperation{Op: PatchOpReplace, Path: path, Value: value}
+}
+
+// Validate checks every operation against the whitelist of patchable paths and that its value has the right type,
+// without needing the current state of the area
+func (p AreaPatch) Validate(ctx context.Context) []error {
+	var validationErrs []error
+	if len(p) == 0 {
+		return append(validationErrs, NewValidationError(ctx, InvalidPatchError, EmptyPatchErrorDescription))
+	}
+
+	for i, operation := range p {
+		scratch := &AreaParams{}
+		if err := operation.apply(scratch); err != nil {
+			validationErrs = append(validationErrs, NewValidationError(ctx, InvalidPatchError, fmt.Sprintf("operation %d: %s", i, err.Error())))
+		}
+	}
+	return validationErrs
+}
+
+// Apply applies the patch operations to the area in order
+func (p AreaPatch) Apply(area *AreaParams) error {
+	for i, operation := range p {
+		if err := operation.apply(area); err != nil {
+			return fmt.Errorf("operation %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// SetsParent reports whether the patch sets the area's parent, which moves the area from any other parent
+func (p AreaPatch) SetsParent() bool {
+	for _, operation := range p {
+		if operation.Path == "/parent_code" {
+			return true
+		}
+	}
+	return false
+}
+
+func (o PatchOperation) apply(area *AreaParams) error {
+	field, ok := areaPatchFields[o.Path]
+	if !ok {
+		return fmt.Errorf("path %q cannot be patched", o.Path)
+	}
+
+	switch o.Op {
+	case PatchOpAdd, PatchOpReplace:
+		if len(o.Value) == 0 {
+			return fmt.Errorf("%s %q requires a value", o.Op, o.Path)
+		}
+		if isJSONNull(o.Value) && !field.removable {
+			return fmt.Errorf("path %q cannot be null", o.Path)
+		}
+		return field.apply(area, o.Value)
+	case PatchOpRemove:
+		if !field.removable {
+			return fmt.Errorf("path %q cannot be removed", o.Path)
+		}
+		return field.apply(area, json.RawMessage("null"))
+	default:
+		return fmt.Errorf("unsupported op %q", o.Op)
+	}
+}
+
+// areaName returns the area's name details, creating them when the area has none yet
+func areaName(area *AreaParams) *AreaName {
+	if area.AreaName == nil {
+		area.AreaName = &AreaName{}
+	}
+	return area.AreaName
+}
+
+func applyPatchTime(value json.RawMessage, target **time.Time) error {
+	if isJSONNull(value) {
+		*target = nil
+		return nil
+	}
+	var t time.Time
+	if err := unmarshalPatchValue(value, &t); err != nil {
+		return err
+	}
+	*target = &t
+	return nil
+}
+
+func unmarshalPatchValue(value json.RawMessage, target interface{}) error {
+	if err := json.Unmarshal(value, target); err != nil {
+		return fmt.Errorf("invalid value: %w", err)
+	}
+	return nil
+}
+
+func isJSONNull(value json.RawMessage) bool {
+	return bytes.Equal(bytes.TrimSpace(value), []byte("null"))
+}
+
+func sortedKeys(document map[string]json.RawMessage) []string {
+	keys := make([]string, 0, len(document))
+	for key := range document {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,142 @@
+package models_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ONSdigital/dp-areas-api/models"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestParseAreaPatch(t *testing.T) {
+	Convey("Given a JSON Patch document", t, func() {
+		body := []byte(`[{"op": "replace", "path": "/visible", "value": false}, {"op": "remove", "path": "/active_to"}]`)
+
+		Convey("When it is parsed", func() {
+			patch, err := models.ParseAreaPatch(models.JSONPatchContentType, body)
+
+			Convey("Then the operations are returned in order", func() {
+				So(err, ShouldBeNil)
+				So(patch, ShouldHaveLength, 2)
+				So(patch[0].Op, ShouldEqual, models.PatchOpReplace)
+				So(patch[0].Path, ShouldEqual, "/visible")
+				So(patch[1].Op, ShouldEqual, models.PatchOpRemove)
+			})
+		})
+	})
+
+	Convey("Given a merge patch document", t, func() {
+		body := []byte(`{"visible": false, "active_to": null, "area_name": {"name": "Sheffield City"}}`)
+
+		Convey("When it is parsed", func() {
+			patch, err := models.ParseAreaPatch(models.MergePatchContentType+"; charset=utf-8", body)
+
+			Convey("Then it is converted to the equivalent JSON Patch operations", func() {
+				So(err, ShouldBeNil)
+				So(patch, ShouldResemble, models.AreaPatch{
+					{Op: models.PatchOpRemove, Path: "/active_to"},
+					{Op: models.PatchOpReplace, Path: "/area_name/name", Value: []byte(`"Sheffield City"`)},
+					{Op: models.PatchOpReplace, Path: "/visible", Value: []byte(`false`)},
+				})
+			})
+		})
+	})
+
+	Convey("Given a merge patch that is not an object", t, func() {
+		Convey("When it is parsed", func() {
+			_, err := models.ParseAreaPatch(models.MergePatchContentType, []byte(`null`))
+
+			Convey("Then an error is returned", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+	})
+}
+
+func TestAreaPatch_Validate(t *testing.T) {
+	ctx := context.Background()
+
+	Convey("Given a patch restricted to whitelisted paths", t, func() {
+		patch := models.AreaPatch{
+			{Op: models.PatchOpReplace, Path: "/visible", Value: []byte(`false`)},
+			{Op: models.PatchOpAdd, Path: "/area_name/active_to", Value: []byte(`"2022-01-01T00:00:00Z"`)},
+		}
+
+		Convey("Then it is valid", func() {
+			So(patch.Validate(ctx), ShouldBeEmpty)
+		})
+	})
+
+	Convey("Given a patch with invalid operations", t, func() {
+		patch := models.AreaPatch{
+			{Op: models.PatchOpReplace, Path: "/code", Value: []byte(`"E92000002"`)},
+			{Op: models.PatchOpRemove, Path: "/visible"},
+			{Op: models.PatchOpReplace, Path: "/area_hectares", Value: []byte(`"lots"`)},
+			{Op: "move", Path: "/active_to"},
+			{Op: models.PatchOpReplace, Path: "/area_name/name", Value: []byte(`""`)},
+		}
+
+		Convey("Then an error is returned for each operation", func() {
+			So(patch.Validate(ctx), ShouldHaveLength, 5)
+		})
+	})
+
+	Convey("Given an empty patch", t, func() {
+		Convey("Then it is rejected", func() {
+			So(models.AreaPatch{}.Validate(ctx), ShouldHaveLength, 1)
+		})
+	})
+}
+
+func TestAreaPatch_Apply(t *testing.T) {
+	Convey("Given an existing area", t, func() {
+		visible := true
+		activeTo := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+		area := models.AreaParams{
+			Code:          "E08000019",
+			AreaType:      "Metropolitan Districts",
+			GeometricData: "[[[-1.8,53.5]]]",
+			ActiveTo:      &activeTo,
+			Visible:       &visible,
+			AreaName:      &models.AreaName{Name: "Sheffield"},
+		}
+
+		Convey("When a patch is applied", func() {
+			patch, err := models.ParseAreaPatch(models.MergePatchContentType, []byte(`{"visible": false, "active_to": null, "area_name": {"name": "Sheffield City"}}`))
+			So(err, ShouldBeNil)
+			err = patch.Apply(&area)
+
+			Convey("Then only the patched fields change", func() {
+				So(err, ShouldBeNil)
+				So(*area.Visible, ShouldBeFalse)
+				So(area.ActiveTo, ShouldBeNil)
+				So(area.AreaName.Name, ShouldEqual, "Sheffield City")
+				So(area.GeometricData, ShouldEqual, "[[[-1.8,53.5]]]")
+				So(area.ValidatePatchedArea(context.Background()), ShouldBeEmpty)
+			})
+		})
+
+		Convey("When the area is made its own parent", func() {
+			err := models.AreaPatch{{Op: models.PatchOpReplace, Path: "/parent_code", Value: []byte(`"E08000019"`)}}.Apply(&area)
+
+			Convey("Then an error is returned", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+	})
+}
+
+func TestAreaPatch_SetsParent(t *testing.T) {
+	Convey("Given merge patches with and without a parent", t, func() {
+		moves, err := models.ParseAreaPatch(models.MergePatchContentType, []byte(`{"visible": false, "parent_code": "W92000004"}`))
+		So(err, ShouldBeNil)
+		hides, err := models.ParseAreaPatch(models.MergePatchContentType, []byte(`{"visible": false}`))
+		So(err, ShouldBeNil)
+
+		Convey("Then only the patch with a parent sets it", func() {
+			So(moves.SetsParent(), ShouldBeTrue)
+			So(hides.SetsParent(), ShouldBeFalse)
+		})
+	})
+}
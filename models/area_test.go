@@ -0,0 +1,40 @@
+package models_test
+
+import (
+	"testing"
+
+	"github.com/ONSdigital/dp-areas-api/models"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestETagMatches(t *testing.T) {
+	Convey("Given version 3 of an area", t, func() {
+		version := 3
+
+		Convey("Then its ETag is the quoted version", func() {
+			So(models.AreaETag(version), ShouldEqual, `"3"`)
+		})
+
+		Convey("Then an unconditional request matches", func() {
+			So(models.ETagMatches("", version), ShouldBeTrue)
+			So(models.ETagMatches("*", version), ShouldBeTrue)
+		})
+
+		Convey("Then If-Match matches the current ETag, including within a list", func() {
+			So(models.ETagMatches(`"3"`, version), ShouldBeTrue)
+			So(models.ETagMatches(`"1", "3"`, version), ShouldBeTrue)
+		})
+
+		Convey("Then the ETag of its details matches whatever its ancestry, and that of another version does not", func() {
+			So(models.AreaDetailsETag(version, []byte("null")), ShouldNotEqual, models.AreaDetailsETag(version, []byte("[]")))
+			So(models.ETagMatches(models.AreaDetailsETag(version, []byte("[]")), version), ShouldBeTrue)
+			So(models.ETagMatches(models.AreaDetailsETag(30, []byte("[]")), version), ShouldBeFalse)
+		})
+
+		Convey("Then stale, unquoted and weak ETags do not match", func() {
+			So(models.ETagMatches(`"2"`, version), ShouldBeFalse)
+			So(models.ETagMatches(`3`, version), ShouldBeFalse)
+			So(models.ETagMatches(`W/"3"`, version), ShouldBeFalse)
+		})
+	})
+}
@@ -8,16 +8,49 @@ import (
 	"strings"
 )
 
-var (
-	CreateTableQuery = "CREATE TABLE IF NOT EXISTS %s (PRIMARY KEY (%s), %s)"
-)
-
 // DatabaseSchema database schema model
 type DatabaseSchema struct {
 	DBName,
 	SchemaString string
-	ExecutionList []string
-	Tables        map[string]map[string]interface{}
+	Tables map[string]map[string]interface{}
+	// Indexes, ForeignKeys and Checks are keyed by table and sorted by name
+	Indexes     map[string][]IndexSchema      `json:"-"`
+	ForeignKeys map[string][]ForeignKeySchema `json:"-"`
+	Checks      map[string][]CheckSchema      `json:"-"`
+}
+
+// IndexSchema is a secondary index of a table. Columns may be expressions and may name an operator class, such as
+// "name gin_trgm_ops" for a trigram index. Where makes the index partial.
+type IndexSchema struct {
+	Name    string   `json:"name"`
+	Columns []string `json:"columns"`
+	Unique  bool     `json:"unique"`
+	Method  string   `json:"method"`
+	Where   string   `json:"where"`
+}
+
+// ForeignKeySchema is a foreign key of one or more columns, for constraints that cannot be declared on a single column
+type ForeignKeySchema struct {
+	Name              string   `json:"name"`
+	Columns           []string `json:"columns"`
+	ReferencedTable   string   `json:"referenced_table"`
+	ReferencedColumns []string `json:"referenced_columns"`
+	OnDelete          string   `json:"on_delete"`
+}
+
+// CheckSchema is a check constraint of a table
+type CheckSchema struct {
+	Name       string `json:"name"`
+	Expression string `json:"expression"`
+}
+
+// tableDefinitions holds the typed parts of each table in the schema string
+type tableDefinitions struct {
+	Tables map[string]struct {
+		Indexes     []IndexSchema      `json:"indexes"`
+		ForeignKeys []ForeignKeySchema `json:"foreign_keys"`
+		Checks      []CheckSchema      `json:"checks"`
+	} `json:"tables"`
 }
 
 // BuildDatabaseSchemaModel build db schema model
@@ -32,27 +65,128 @@ func (db *DatabaseSchema) BuildDatabaseSchemaModel() error {
 		return err
 	}
 	db.Tables = dbSchemaData[db.DBName].Tables
+
+	definitions := make(map[string]tableDefinitions, 1)
+	err = json.Unmarshal([]byte(*str), &definitions)
+	if err != nil {
+		return err
+	}
+	db.Indexes = make(map[string][]IndexSchema)
+	db.ForeignKeys = make(map[string][]ForeignKeySchema)
+	db.Checks = make(map[string][]CheckSchema)
+	for table, definition := range definitions[db.DBName].Tables {
+		if len(definition.Indexes) != 0 {
+			sort.Slice(definition.Indexes, func(i, j int) bool { return definition.Indexes[i].Name < definition.Indexes[j].Name })
+			db.Indexes[table] = definition.Indexes
+		}
+		if len(definition.ForeignKeys) != 0 {
+			sort.Slice(definition.ForeignKeys, func(i, j int) bool { return definition.ForeignKeys[i].Name < definition.ForeignKeys[j].Name })
+			db.ForeignKeys[table] = definition.ForeignKeys
+		}
+		if len(definition.Checks) != 0 {
+			sort.Slice(definition.Checks, func(i, j int) bool { return definition.Checks[i].Name < definition.Checks[j].Name })
+			db.Checks[table] = definition.Checks
+		}
+	}
+	return db.validateDefinitions()
+}
+
+// validateDefinitions checks that indexes, foreign keys and checks are named and refer to tables and columns that are
+// in the model
+func (db *DatabaseSchema) validateDefinitions() error {
+	names := make(map[string]bool)
+	checkName := func(table, name string) error {
+		if name == "" {
+			return fmt.Errorf("table %s has an index or constraint without a name", table)
+		}
+		if names[name] {
+			return fmt.Errorf("index or constraint name %s is used more than once", name)
+		}
+		names[name] = true
+		return nil
+	}
+
+	for table, indexes := range db.Indexes {
+		if _, ok := db.Tables[table]; !ok {
+			return fmt.Errorf("index defined for unknown table %s", table)
+		}
+		for _, index := range indexes {
+			if err := checkName(table, index.Name); err != nil {
+				return err
+			}
+			if len(index.Columns) == 0 {
+				return fmt.Errorf("index %s has no columns", index.Name)
+			}
+		}
+	}
+
+	for table, foreignKeys := range db.ForeignKeys {
+		if _, ok := db.Tables[table]; !ok {
+			return fmt.Errorf("foreign key defined for unknown table %s", table)
+		}
+		for _, fk := range foreignKeys {
+			if err := checkName(table, fk.Name); err != nil {
+				return err
+			}
+			if len(fk.Columns) == 0 || len(fk.Columns) != len(fk.ReferencedColumns) {
+				return fmt.Errorf("foreign key %s must reference the same number of columns as it has", fk.Name)
+			}
+			if err := db.checkColumns(table, fk.Columns); err != nil {
+				return fmt.Errorf("foreign key %s: %w", fk.Name, err)
+			}
+			if _, ok := db.Tables[fk.ReferencedTable]; !ok {
+				return fmt.Errorf("foreign key %s references unknown table %s", fk.Name, fk.ReferencedTable)
+			}
+			if err := db.checkColumns(fk.ReferencedTable, fk.ReferencedColumns); err != nil {
+				return fmt.Errorf("foreign key %s: %w", fk.Name, err)
+			}
+		}
+	}
+
+	for table, checks := range db.Checks {
+		if _, ok := db.Tables[table]; !ok {
+			return fmt.Errorf("check defined for unknown table %s", table)
+		}
+		for _, check := range checks {
+			if err := checkName(table, check.Name); err != nil {
+				return err
+			}
+			if strings.TrimSpace(check.Expression) == "" {
+				return fmt.Errorf("check %s has no expression", check.Name)
+			}
+		}
+	}
 	return nil
 }
 
-// TableSchemaBuilder builds table schema
-func (db *DatabaseSchema) TableSchemaBuilder() {
-	//as we're applied a FK constraint, order of creation is important, so build array of fixed size to preserve
-	db.ExecutionList = make([]string, len(db.Tables))
-	for table := range db.Tables {
-		var (
-			schemaHandleCols = db.Tables[table]["columns"].(map[string]interface{})
-			colArray         = make([]string, 0)
-		)
-		for name, data := range schemaHandleCols {
-			d := data.(map[string]interface{})
-			colString := fmt.Sprintf("%s %s %s", name, d["data_type"].(string), d["constraints"].(string))
-			colArray = append(colArray, colString)
+func (db *DatabaseSchema) checkColumns(table string, columns []string) error {
+	tableColumns := db.Tables[table]["columns"].(map[string]interface{})
+	for _, column := range columns {
+		if _, ok := tableColumns[column]; !ok {
+			return fmt.Errorf("unknown column %s.%s", table, column)
 		}
-		sort.Strings(colArray)
-		columnData := strings.Join(colArray, ", ")
-		db.ExecutionList[int(db.Tables[table]["creation_order"].(float64))] = fmt.Sprintf(CreateTableQuery, table, db.Tables[table]["primary_keys"], columnData)
 	}
+	return nil
+}
+
+// accessMethod returns the index access method, which defaults to btree
+func (index IndexSchema) accessMethod() string {
+	if index.Method == "" {
+		return "btree"
+	}
+	return strings.ToLower(index.Method)
+}
+
+func (index IndexSchema) createQuery(table string) string {
+	unique := ""
+	if index.Unique {
+		unique = "UNIQUE "
+	}
+	query := fmt.Sprintf("CREATE %sINDEX IF NOT EXISTS %s ON %s USING %s (%s)", unique, index.Name, table, index.accessMethod(), strings.Join(index.Columns, ", "))
+	if index.Where != "" {
+		query += " WHERE " + index.Where
+	}
+	return query
 }
 
 // cleanString cleans strings of unwanted chars matched in localised char set
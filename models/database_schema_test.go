@@ -8,13 +8,42 @@ import (
 	. "github.com/smartystreets/goconvey/convey"
 )
 
-const (
-	area_query              = "CREATE TABLE IF NOT EXISTS area (PRIMARY KEY (code), active_from TIMESTAMP , active_to TIMESTAMP , area_type_id INT REFERENCES area_type(id), code VARCHAR(50) UNIQUE, geometric_area VARCHAR , land_hectares FLOAT(4) , visible BOOLEAN )"
-	area_type_query         = "CREATE TABLE IF NOT EXISTS area_type (PRIMARY KEY (id), id SERIAL , name VARCHAR(50) )"
-	area_name_query         = "CREATE TABLE IF NOT EXISTS area_name (PRIMARY KEY (id), active_from TIMESTAMP , active_to TIMESTAMP , area_code VARCHAR(50) REFERENCES area(code), id SERIAL , name VARCHAR(50) UNIQUE)"
-	relationship_type_query = "CREATE TABLE IF NOT EXISTS relationship_type (PRIMARY KEY (id), id SERIAL , name VARCHAR(50) )"
-	area_relationship_query = "CREATE TABLE IF NOT EXISTS area_relationship (PRIMARY KEY (area_code,rel_area_code), area_code VARCHAR(50) REFERENCES area(code), rel_area_code VARCHAR(50) REFERENCES area(code), rel_type_id INT REFERENCES relationship_type(id))"
-)
+// definitionsSchema has two tables with a composite foreign key, a check constraint, and partial and trigram indexes
+const definitionsSchema = `{
+	"test": {
+		"tables": {
+			"child": {
+				"creation_order": 1,
+				"primary_keys": "id",
+				"indexes": [
+					{"name": "child_name_trgm_idx", "columns": ["name gin_trgm_ops"], "method": "gin"},
+					{"name": "child_active_idx", "columns": ["parent_code", "parent_version"], "unique": true, "where": "active"}
+				],
+				"foreign_keys": [
+					{"name": "child_parent_fkey", "columns": ["parent_code", "parent_version"], "referenced_table": "parent", "referenced_columns": ["code", "version"], "on_delete": "cascade"}
+				],
+				"checks": [
+					{"name": "child_name_check", "expression": "name <> ''"}
+				],
+				"columns": {
+					"id": {"data_type": "SERIAL", "constraints": ""},
+					"name": {"data_type": "VARCHAR", "constraints": ""},
+					"active": {"data_type": "BOOLEAN", "constraints": ""},
+					"parent_code": {"data_type": "VARCHAR(50)", "constraints": ""},
+					"parent_version": {"data_type": "INT", "constraints": ""}
+				}
+			},
+			"parent": {
+				"creation_order": 0,
+				"primary_keys": "code,version",
+				"columns": {
+					"code": {"data_type": "VARCHAR(50)", "constraints": ""},
+					"version": {"data_type": "INT", "constraints": ""}
+				}
+			}
+		}
+	}
+}`
 
 func TestSetup(t *testing.T) {
 	Convey("Ensure database schema model is built correctly", t, func() {
@@ -29,7 +58,7 @@ func TestSetup(t *testing.T) {
 			// sample from built schema model
 			So(databaseSchema.Tables["area"]["creation_order"].(float64), ShouldEqual, 2)
 			So(databaseSchema.Tables["area"]["primary_keys"].(string), ShouldEqual, "code")
-			So(len(databaseSchema.Tables["area"]["columns"].(map[string]interface{})), ShouldEqual, 7)
+			So(len(databaseSchema.Tables["area"]["columns"].(map[string]interface{})), ShouldEqual, 9)
 		})
 
 		Convey("When an invalid schema string is used - error generated", func() {
@@ -61,19 +90,50 @@ func TestSetup(t *testing.T) {
 			So(err.Error(), ShouldEqual, "json: cannot unmarshal string into Go value of type map[string]models.DatabaseSchema")
 		})
 
-		Convey("Ensure execution list built successfully", func() {
-			databaseSchema := models.DatabaseSchema{
-				DBName:       "dp-areas-api",
-				SchemaString: DBRelationalSchema.DBSchema,
-			}
-			_ = databaseSchema.BuildDatabaseSchemaModel()
-			databaseSchema.TableSchemaBuilder()
-			So(len(databaseSchema.ExecutionList), ShouldBeGreaterThanOrEqualTo, 5)
-			So(databaseSchema.ExecutionList[0], ShouldEqual, area_type_query)
-			So(databaseSchema.ExecutionList[1], ShouldEqual, relationship_type_query)
-			So(databaseSchema.ExecutionList[2], ShouldEqual, area_query)
-			So(databaseSchema.ExecutionList[3], ShouldEqual, area_relationship_query)
-			So(databaseSchema.ExecutionList[4], ShouldEqual, area_name_query)
+	})
+}
+
+func TestTableDefinitions(t *testing.T) {
+	Convey("Given a schema with indexes, a composite foreign key and a check constraint", t, func() {
+		databaseSchema := models.DatabaseSchema{
+			DBName:       "test",
+			SchemaString: definitionsSchema,
+		}
+
+		Convey("When the schema model is built", func() {
+			err := databaseSchema.BuildDatabaseSchemaModel()
+
+			Convey("Then the definitions are parsed and sorted by name", func() {
+				So(err, ShouldBeNil)
+				So(databaseSchema.Indexes["child"], ShouldHaveLength, 2)
+				So(databaseSchema.Indexes["child"][0].Name, ShouldEqual, "child_active_idx")
+				So(databaseSchema.ForeignKeys["child"][0].ReferencedColumns, ShouldResemble, []string{"code", "version"})
+				So(databaseSchema.Checks["child"][0].Expression, ShouldEqual, "name <> ''")
+			})
 		})
+
+	})
+
+	Convey("Given definitions that do not match the tables", t, func() {
+		invalid := map[string]string{
+			"an index without columns":                         `"indexes": [{"name": "x_idx", "columns": []}]`,
+			"an unnamed index":                                 `"indexes": [{"columns": ["id"]}]`,
+			"a foreign key to an unknown table":                `"foreign_keys": [{"name": "x_fkey", "columns": ["id"], "referenced_table": "missing", "referenced_columns": ["id"]}]`,
+			"a foreign key with an unknown column":             `"foreign_keys": [{"name": "x_fkey", "columns": ["missing"], "referenced_table": "parent", "referenced_columns": ["id"]}]`,
+			"a foreign key with mismatched referenced columns": `"foreign_keys": [{"name": "x_fkey", "columns": ["id"], "referenced_table": "parent", "referenced_columns": ["id", "id"]}]`,
+			"a check without an expression":                    `"checks": [{"name": "x_check", "expression": ""}]`,
+		}
+		for description, definition := range invalid {
+			schemaString := `{"test": {"tables": {"parent": {"creation_order": 0, "primary_keys": "id", ` + definition + `, "columns": {"id": {"data_type": "INT", "constraints": ""}}}}}}`
+
+			Convey("When the schema model is built with "+description, func() {
+				databaseSchema := models.DatabaseSchema{DBName: "test", SchemaString: schemaString}
+				err := databaseSchema.BuildDatabaseSchemaModel()
+
+				Convey("Then an error is returned", func() {
+					So(err, ShouldNotBeNil)
+				})
+			})
+		}
 	})
 }
@@ -5,7 +5,6 @@ const (
 	AcceptLanguageHeaderError          = "ErrorAcceptLanguageHeader"
 	AreaDataIdGetError                 = "ErrorRetrievingAreaCode"
 	AreaDataIdUpsertError              = "AreaDataIdUpsertError"
-	AncestryDataGetError               = "ErrorRetrievingAncestryData"
 	MarshallingAreaDataError           = "ErrorMarshallingAreaData"
 	MarshallingAreaRelationshipsError  = "ErrorMarshallingAreaRelationshipData"
 	MarshallingAreaBoundaryError       = "ErrorMarshallingAreaBoundaryData"
@@ -17,7 +16,29 @@ const (
 	AreaNameDetailsNotProvidedError    = "AreaNameDetailsNotProvidedError"
 	BodyCloseError                     = "BodyCloseError"
 	BodyReadError                      = "RequestBodyReadError"
+	RequestBodyTooLargeError           = "RequestBodyTooLarge"
 	JSONUnmarshalError                 = "JSONUnmarshalError"
+	AreaRetireError                    = "AreaRetireError"
+	AreaHasLiveChildrenError           = "AreaHasLiveChildrenError"
+	InvalidQueryParameterError         = "InvalidQueryParameter"
+	InvalidPatchError                  = "InvalidPatch"
+	AreaPatchError                     = "AreaPatchError"
+	DuplicateAreaCodeError             = "DuplicateAreaCode"
+	AreaParentCycleError               = "AreaParentCycle"
+	BulkUpsertError                    = "BulkUpsertError"
+	PreconditionFailedError            = "PreconditionFailed"
+	SchemaDriftError                   = "SchemaDriftError"
+	QueryTimeoutError                  = "QueryTimeout"
+	QueryCancelledError                = "QueryCancelled"
+	DataConflictError                  = "DataConflict"
+	InvalidReferenceError              = "InvalidReference"
+	InvalidDataError                   = "InvalidData"
+	DatabaseUnavailableError           = "DatabaseUnavailable"
+	UnauthenticatedError               = "Unauthenticated"
+	ForbiddenError                     = "Forbidden"
+	AuthenticationError                = "AuthenticationError"
+	AreaAuditGetError                  = "ErrorRetrievingAreaAudit"
+	MarshallingAreaAuditError          = "ErrorMarshallingAreaAudit"
 )
 
 // API error descriptions
@@ -27,6 +48,7 @@ const (
 	AreaDataGetErrorDescription                   = "area code not found"
 	BodyClosedFailedDescription                   = "the request body failed to close"
 	BodyReadFailedDescription                     = "endpoint returned an error reading the request body"
+	RequestBodyTooLargeErrorDescription           = "the request body is larger than the service accepts"
 	ErrorUnmarshalFailedDescription               = "failed to unmarshal the request body"
 	InvalidAreaCodeErrorDescription               = "the area code could not be validated"
 	AreaNameDetailsNotProvidedErrorDescription    = "required field area_name not provided"
@@ -34,4 +56,20 @@ const (
 	AreaNameActiveFromNotProvidedErrorDescription = "required field area_name.active_from not provided"
 	AreaNameActiveToNotProvidedErrorDescription   = "required field area_name.active_to not provided"
 	InvalidAreaTypeErrorDescription               = "failed to derive area type from area code"
+	AreaHasLiveChildrenErrorDescription           = "area has live child areas, retire them first or set cascade=true"
+	InvalidQueryParameterErrorDescription         = "invalid query parameter"
+	EmptyPatchErrorDescription                    = "patch document contains no operations"
+	DuplicateAreaCodeErrorDescription             = "area code appears more than once in the request"
+	AreaParentCycleErrorDescription               = "area is part of a parent_code cycle"
+	EmptyBulkRequestErrorDescription              = "bulk request contains no areas"
+	PreconditionFailedErrorDescription            = "If-Match does not match the current ETag of the area"
+	QueryTimeoutErrorDescription                  = "the database did not respond in time"
+	QueryCancelledErrorDescription                = "the request was cancelled before the database responded"
+	DataConflictErrorDescription                  = "the request conflicts with existing data"
+	InvalidReferenceErrorDescription              = "the request refers to data that does not exist"
+	InvalidDataErrorDescription                   = "the request breaks a data constraint"
+	DatabaseUnavailableErrorDescription           = "the database is unavailable, try again later"
+	UnauthenticatedErrorDescription               = "a valid Florence or service token is required"
+	ForbiddenErrorDescription                     = "the caller does not have permission to make this request"
+	AuthenticationErrorDescription                = "the caller's token could not be checked, try again later"
 )
@@ -20,6 +20,10 @@ func (e *Error) Error() string {
 	return e.Code + ": " + e.Description
 }
 
+func (e *Error) Unwrap() error {
+	return e.Cause
+}
+
 func NewError(ctx context.Context, cause error, code string, description string) *Error {
 	err := &Error{
 		Cause:       cause,
@@ -2,8 +2,11 @@ package models
 
 import (
 	"context"
-	errs "github.com/ONSdigital/dp-areas-api/apierrors"
+	"errors"
+	"fmt"
 	"net/http"
+
+	errs "github.com/ONSdigital/dp-areas-api/apierrors"
 )
 
 type ErrorResponse struct {
@@ -21,15 +24,57 @@ func NewErrorResponse(statusCode int, headers map[string]string, errors ...error
 }
 
 func NewDBReadError(ctx context.Context, err error) *ErrorResponse {
-	if err.Error() == errs.ErrNoRows.Error() {
-		responseErr := NewError(ctx, err, InvalidAreaCodeError, err.Error())
-		return NewErrorResponse(http.StatusNotFound, nil, responseErr)
+	if errorResponse := NewStoreErrorResponse(ctx, err); errorResponse != nil {
+		return errorResponse
 	}
 	responseErr := NewError(ctx, err, AreaDataIdGetError, err.Error())
 	return NewErrorResponse(http.StatusInternalServerError, nil, responseErr)
 
 }
 
+// NewStoreErrorResponse returns the response for a store call that failed with a classified error: 404 when the area
+// was not found, 409 on a conflict, 422 for an invalid reference or data, 503 when the database is unavailable or the
+// request was cancelled and 504 when a query timed out. It returns nil for any other error.
+func NewStoreErrorResponse(ctx context.Context, err error) *ErrorResponse {
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		responseErr := NewError(ctx, err, QueryTimeoutError, QueryTimeoutErrorDescription)
+		return NewErrorResponse(http.StatusGatewayTimeout, nil, responseErr)
+	case errors.Is(err, context.Canceled):
+		responseErr := NewError(ctx, err, QueryCancelledError, QueryCancelledErrorDescription)
+		return NewErrorResponse(http.StatusServiceUnavailable, nil, responseErr)
+	}
+
+	var storeErr *errs.StoreError
+	if !errors.As(err, &storeErr) {
+		return nil
+	}
+	switch storeErr.Kind {
+	case errs.ErrNotFound:
+		responseErr := NewError(ctx, err, InvalidAreaCodeError, err.Error())
+		return NewErrorResponse(http.StatusNotFound, nil, responseErr)
+	case errs.ErrConflict:
+		return newStoreErrorResponse(ctx, storeErr, http.StatusConflict, DataConflictError, DataConflictErrorDescription)
+	case errs.ErrInvalidReference:
+		return newStoreErrorResponse(ctx, storeErr, http.StatusUnprocessableEntity, InvalidReferenceError, InvalidReferenceErrorDescription)
+	case errs.ErrInvalidData:
+		return newStoreErrorResponse(ctx, storeErr, http.StatusUnprocessableEntity, InvalidDataError, InvalidDataErrorDescription)
+	case errs.ErrUnavailable:
+		return newStoreErrorResponse(ctx, storeErr, http.StatusServiceUnavailable, DatabaseUnavailableError, DatabaseUnavailableErrorDescription)
+	case errs.ErrTimeout:
+		return newStoreErrorResponse(ctx, storeErr, http.StatusGatewayTimeout, QueryTimeoutError, QueryTimeoutErrorDescription)
+	}
+	return nil
+}
+
+// newStoreErrorResponse names the violated constraint, when known, so that clients can tell which field to correct
+func newStoreErrorResponse(ctx context.Context, err *errs.StoreError, status int, code, description string) *ErrorResponse {
+	if err.Constraint != "" {
+		description = fmt.Sprintf("%s (%s)", description, err.Constraint)
+	}
+	return NewErrorResponse(status, nil, NewError(ctx, err, code, description))
+}
+
 func NewBodyReadError(ctx context.Context, err error) *ErrorResponse {
 	return NewErrorResponse(http.StatusInternalServerError,
 		nil,
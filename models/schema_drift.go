@@ -0,0 +1,383 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Kinds of difference reported by a schema drift check
+const (
+	DriftMissingTable      = "missing_table"
+	DriftExtraTable        = "extra_table"
+	DriftMissingColumn     = "missing_column"
+	DriftExtraColumn       = "extra_column"
+	DriftTypeMismatch      = "type_mismatch"
+	DriftNullableMismatch  = "nullable_mismatch"
+	DriftMissingConstraint = "missing_constraint"
+	DriftExtraConstraint   = "extra_constraint"
+	DriftMissingIndex      = "missing_index"
+	DriftExtraIndex        = "extra_index"
+	DriftIndexMismatch     = "index_mismatch"
+)
+
+// Constraint types as reported by information_schema.table_constraints
+const (
+	PrimaryKeyConstraint = "PRIMARY KEY"
+	UniqueConstraint     = "UNIQUE"
+	ForeignKeyConstraint = "FOREIGN KEY"
+	CheckConstraint      = "CHECK"
+)
+
+var (
+	declaredTypePattern = regexp.MustCompile(`^([A-Z ]+?)\s*(?:\((\d+)\))?$`)
+	referencesPattern   = regexp.MustCompile(`REFERENCES\s+(\w+)\s*\(\s*(\w+)\s*\)`)
+	indexMethodPattern  = regexp.MustCompile(` USING (\w+) `)
+)
+
+// LiveColumn is a column of the live database, read from information_schema.columns
+type LiveColumn struct {
+	Table                  string
+	Name                   string
+	DataType               string
+	CharacterMaximumLength *int
+	Nullable               bool
+}
+
+// LiveConstraint is a primary key, unique, foreign key or check constraint of the live database, read from
+// information_schema.table_constraints. References is set to table(columns) for foreign keys, along with OnDelete
+// when the delete rule is not NO ACTION.
+type LiveConstraint struct {
+	Table      string
+	Name       string
+	Type       string
+	Columns    []string
+	References string
+	OnDelete   string
+}
+
+// LiveIndex is an index of the live database, read from pg_indexes
+type LiveIndex struct {
+	Table      string
+	Name       string
+	Definition string
+}
+
+// LiveSchema describes the tables of the live database
+type LiveSchema struct {
+	Tables      []string
+	Columns     []LiveColumn
+	Constraints []LiveConstraint
+	Indexes     []LiveIndex
+}
+
+// SchemaDrift is a single difference between the schema model and the live database
+type SchemaDrift struct {
+	Kind     string `json:"kind"`
+	Table    string `json:"table"`
+	Column   string `json:"column,omitempty"`
+	Expected string `json:"expected,omitempty"`
+	Actual   string `json:"actual,omitempty"`
+}
+
+// SchemaDriftReport lists every difference between the schema model and the live database
+type SchemaDriftReport struct {
+	InSync bool          `json:"in_sync"`
+	Drift  []SchemaDrift `json:"drift"`
+}
+
+// expectedColumn is a column of the schema model in the form information_schema reports it
+type expectedColumn struct {
+	dataType string
+	nullable bool
+}
+
+// Diff compares the schema model, which must already have been built, against the live database. Tables named in
+// ignoreTables, such as those owned by the migrator, are not reported as extra.
+func (db *DatabaseSchema) Diff(live LiveSchema, ignoreTables ...string) SchemaDriftReport {
+	drift := make([]SchemaDrift, 0)
+
+	ignored := make(map[string]bool, len(ignoreTables))
+	for _, table := range ignoreTables {
+		ignored[table] = true
+	}
+
+	liveTables := make(map[string]bool, len(live.Tables))
+	for _, table := range live.Tables {
+		liveTables[table] = true
+		if _, ok := db.Tables[table]; !ok && !ignored[table] {
+			drift = append(drift, SchemaDrift{Kind: DriftExtraTable, Table: table})
+		}
+	}
+
+	liveColumns := make(map[string]map[string]LiveColumn)
+	for _, column := range live.Columns {
+		if liveColumns[column.Table] == nil {
+			liveColumns[column.Table] = make(map[string]LiveColumn)
+		}
+		liveColumns[column.Table][column.Name] = column
+	}
+
+	liveConstraints := make(map[string]map[string]bool)
+	constraintNames := make(map[string]bool)
+	for _, constraint := range live.Constraints {
+		if liveConstraints[constraint.Table] == nil {
+			liveConstraints[constraint.Table] = make(map[string]bool)
+		}
+		liveConstraints[constraint.Table][describeConstraint(constraint)] = true
+		constraintNames[constraint.Name] = true
+	}
+
+	for table := range db.Tables {
+		if !liveTables[table] {
+			drift = append(drift, SchemaDrift{Kind: DriftMissingTable, Table: table})
+			continue
+		}
+		drift = append(drift, diffColumns(table, db.expectedColumns(table), liveColumns[table])...)
+		drift = append(drift, diffConstraints(table, db.expectedConstraints(table), liveConstraints[table])...)
+	}
+
+	drift = append(drift, db.diffIndexes(live.Indexes, constraintNames, ignored)...)
+
+	sort.Slice(drift, func(i, j int) bool {
+		a, b := drift[i], drift[j]
+		if a.Table != b.Table {
+			return a.Table < b.Table
+		}
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		if a.Column != b.Column {
+			return a.Column < b.Column
+		}
+		return a.Expected+a.Actual < b.Expected+b.Actual
+	})
+
+	return SchemaDriftReport{InSync: len(drift) == 0, Drift: drift}
+}
+
+func diffColumns(table string, expected map[string]expectedColumn, live map[string]LiveColumn) []SchemaDrift {
+	var drift []SchemaDrift
+	for name, column := range expected {
+		liveColumn, ok := live[name]
+		if !ok {
+			drift = append(drift, SchemaDrift{Kind: DriftMissingColumn, Table: table, Column: name, Expected: column.dataType})
+			continue
+		}
+		if actual := liveColumnType(liveColumn); actual != column.dataType {
+			drift = append(drift, SchemaDrift{Kind: DriftTypeMismatch, Table: table, Column: name, Expected: column.dataType, Actual: actual})
+		}
+		if liveColumn.Nullable != column.nullable {
+			drift = append(drift, SchemaDrift{Kind: DriftNullableMismatch, Table: table, Column: name, Expected: nullability(column.nullable), Actual: nullability(liveColumn.Nullable)})
+		}
+	}
+	for name, liveColumn := range live {
+		if _, ok := expected[name]; !ok {
+			drift = append(drift, SchemaDrift{Kind: DriftExtraColumn, Table: table, Column: name, Actual: liveColumnType(liveColumn)})
+		}
+	}
+	return drift
+}
+
+// diffIndexes compares indexes by name, and for those in both by uniqueness and access method. Columns and predicates
+// are not compared as postgres rewrites the expressions it stores.
+func (db *DatabaseSchema) diffIndexes(live []LiveIndex, constraintNames, ignored map[string]bool) []SchemaDrift {
+	var drift []SchemaDrift
+
+	expected := make(map[string]IndexSchema)
+	expectedTable := make(map[string]string)
+	for table, indexes := range db.Indexes {
+		for _, index := range indexes {
+			expected[index.Name] = index
+			expectedTable[index.Name] = table
+		}
+	}
+
+	found := make(map[string]bool)
+	for _, index := range live {
+		// indexes backing primary key and unique constraints share the constraint name
+		if constraintNames[index.Name] || ignored[index.Table] {
+			continue
+		}
+		if _, ok := db.Tables[index.Table]; !ok {
+			continue
+		}
+		want, ok := expected[index.Name]
+		if !ok || expectedTable[index.Name] != index.Table {
+			drift = append(drift, SchemaDrift{Kind: DriftExtraIndex, Table: index.Table, Actual: index.Definition})
+			continue
+		}
+		found[index.Name] = true
+		if describeIndex(want) != describeLiveIndex(index) {
+			drift = append(drift, SchemaDrift{Kind: DriftIndexMismatch, Table: index.Table, Expected: want.createQuery(index.Table), Actual: index.Definition})
+		}
+	}
+
+	for name, index := range expected {
+		if !found[name] {
+			drift = append(drift, SchemaDrift{Kind: DriftMissingIndex, Table: expectedTable[name], Expected: index.createQuery(expectedTable[name])})
+		}
+	}
+	return drift
+}
+
+func describeIndex(index IndexSchema) string {
+	return fmt.Sprintf("%s %t %s", index.Name, index.Unique, index.accessMethod())
+}
+
+func describeLiveIndex(index LiveIndex) string {
+	method := ""
+	if matches := indexMethodPattern.FindStringSubmatch(index.Definition); matches != nil {
+		method = strings.ToLower(matches[1])
+	}
+	return fmt.Sprintf("%s %t %s", index.Name, strings.HasPrefix(strings.ToUpper(index.Definition), "CREATE UNIQUE INDEX"), method)
+}
+
+func diffConstraints(table string, expected, live map[string]bool) []SchemaDrift {
+	var drift []SchemaDrift
+	for constraint := range expected {
+		if !live[constraint] {
+			drift = append(drift, SchemaDrift{Kind: DriftMissingConstraint, Table: table, Expected: constraint})
+		}
+	}
+	for constraint := range live {
+		if !expected[constraint] {
+			drift = append(drift, SchemaDrift{Kind: DriftExtraConstraint, Table: table, Actual: constraint})
+		}
+	}
+	return drift
+}
+
+// expectedColumns returns the columns of a table in the model, with their types as information_schema reports them
+func (db *DatabaseSchema) expectedColumns(table string) map[string]expectedColumn {
+	primaryKeys := make(map[string]bool)
+	for _, key := range db.primaryKeys(table) {
+		primaryKeys[key] = true
+	}
+
+	columns := make(map[string]expectedColumn)
+	for name, data := range db.Tables[table]["columns"].(map[string]interface{}) {
+		d := data.(map[string]interface{})
+		declared := strings.ToUpper(strings.TrimSpace(d["data_type"].(string)))
+		constraints := strings.ToUpper(d["constraints"].(string))
+		columns[name] = expectedColumn{
+			dataType: normaliseDataType(declared),
+			nullable: !primaryKeys[name] && declared != "SERIAL" && !strings.Contains(constraints, "NOT NULL"),
+		}
+	}
+	return columns
+}
+
+// expectedConstraints returns the primary key, unique, foreign key and check constraints of a table in the model
+func (db *DatabaseSchema) expectedConstraints(table string) map[string]bool {
+	constraints := map[string]bool{
+		describeConstraint(LiveConstraint{Type: PrimaryKeyConstraint, Columns: db.primaryKeys(table)}): true,
+	}
+	for name, data := range db.Tables[table]["columns"].(map[string]interface{}) {
+		declared := data.(map[string]interface{})["constraints"].(string)
+		if strings.Contains(strings.ToUpper(declared), "UNIQUE") {
+			constraints[describeConstraint(LiveConstraint{Type: UniqueConstraint, Columns: []string{name}})] = true
+		}
+		if matches := referencesPattern.FindStringSubmatch(declared); matches != nil {
+			references := fmt.Sprintf("%s(%s)", matches[1], matches[2])
+			constraints[describeConstraint(LiveConstraint{Type: ForeignKeyConstraint, Columns: []string{name}, References: references})] = true
+		}
+	}
+	for _, fk := range db.ForeignKeys[table] {
+		references := fmt.Sprintf("%s(%s)", fk.ReferencedTable, strings.Join(fk.ReferencedColumns, ","))
+		constraints[describeConstraint(LiveConstraint{Type: ForeignKeyConstraint, Columns: fk.Columns, References: references, OnDelete: strings.ToUpper(fk.OnDelete)})] = true
+	}
+	for _, check := range db.Checks[table] {
+		constraints[describeConstraint(LiveConstraint{Type: CheckConstraint, Name: check.Name})] = true
+	}
+	return constraints
+}
+
+func (db *DatabaseSchema) primaryKeys(table string) []string {
+	keys := strings.Split(db.Tables[table]["primary_keys"].(string), ",")
+	for i := range keys {
+		keys[i] = strings.TrimSpace(keys[i])
+	}
+	return keys
+}
+
+// describeConstraint renders a constraint without its name, so that constraints can be compared by what they enforce.
+// Check constraints are compared by name, as postgres rewrites the expressions it stores.
+func describeConstraint(constraint LiveConstraint) string {
+	if constraint.Type == CheckConstraint {
+		return fmt.Sprintf("CONSTRAINT %s CHECK", constraint.Name)
+	}
+	description := fmt.Sprintf("%s (%s)", constraint.Type, strings.Join(constraint.Columns, ","))
+	if constraint.References != "" {
+		description += " REFERENCES " + constraint.References
+	}
+	if constraint.OnDelete != "" && constraint.OnDelete != "NO ACTION" {
+		description += " ON DELETE " + constraint.OnDelete
+	}
+	return description
+}
+
+// normaliseDataType maps a type declared in the schema model to the data type information_schema reports for it,
+// including the length of character types
+func normaliseDataType(declared string) string {
+	matches := declaredTypePattern.FindStringSubmatch(declared)
+	if matches == nil {
+		return strings.ToLower(declared)
+	}
+	name, size := matches[1], matches[2]
+
+	switch name {
+	case "VARCHAR", "CHARACTER VARYING":
+		name = "character varying"
+	case "CHAR", "CHARACTER":
+		name = "character"
+		if size == "" {
+			size = "1"
+		}
+	case "INT", "INTEGER", "INT4", "SERIAL":
+		return "integer"
+	case "BIGINT", "INT8", "BIGSERIAL":
+		return "bigint"
+	case "SMALLINT", "INT2", "SMALLSERIAL":
+		return "smallint"
+	case "BOOL", "BOOLEAN":
+		return "boolean"
+	case "TIMESTAMP":
+		return "timestamp without time zone"
+	case "TIMESTAMPTZ":
+		return "timestamp with time zone"
+	case "FLOAT":
+		// float(1) to float(24) are stored as real, anything larger or unsized as double precision
+		if precision, err := strconv.Atoi(size); err == nil && precision <= 24 {
+			return "real"
+		}
+		return "double precision"
+	case "FLOAT4", "REAL":
+		return "real"
+	case "FLOAT8", "DOUBLE PRECISION":
+		return "double precision"
+	default:
+		name = strings.ToLower(name)
+	}
+
+	if size != "" {
+		return fmt.Sprintf("%s(%s)", name, size)
+	}
+	return name
+}
+
+func liveColumnType(column LiveColumn) string {
+	if column.CharacterMaximumLength != nil {
+		return fmt.Sprintf("%s(%d)", column.DataType, *column.CharacterMaximumLength)
+	}
+	return column.DataType
+}
+
+func nullability(nullable bool) string {
+	if nullable {
+		return "NULL"
+	}
+	return "NOT NULL"
+}
@@ -0,0 +1,210 @@
+package models_test
+
+import (
+	"testing"
+
+	"github.com/ONSdigital/dp-areas-api/models"
+	"github.com/ONSdigital/dp-areas-api/models/DBRelationalSchema"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func intPtr(i int) *int {
+	return &i
+}
+
+// migratedSchema is the live schema of a database with every migration applied
+func migratedSchema() models.LiveSchema {
+	varchar50 := func(table, name string, nullable bool) models.LiveColumn {
+		return models.LiveColumn{Table: table, Name: name, DataType: "character varying", CharacterMaximumLength: intPtr(50), Nullable: nullable}
+	}
+	column := func(table, name, dataType string, nullable bool) models.LiveColumn {
+		return models.LiveColumn{Table: table, Name: name, DataType: dataType, Nullable: nullable}
+	}
+	constraint := func(table, name, constraintType, references string, columns ...string) models.LiveConstraint {
+		return models.LiveConstraint{Table: table, Name: name, Type: constraintType, Columns: columns, References: references}
+	}
+	index := func(table, name string) models.LiveIndex {
+		return models.LiveIndex{Table: table, Name: name, Definition: "CREATE UNIQUE INDEX " + name + " ON public." + table + " USING btree (id)"}
+	}
+	secondaryIndex := func(table, name, column string) models.LiveIndex {
+		return models.LiveIndex{Table: table, Name: name, Definition: "CREATE INDEX " + name + " ON public." + table + " USING btree (" + column + ")"}
+	}
+
+	return models.LiveSchema{
+		Tables: []string{"area", "area_audit", "area_closure", "area_name", "area_relationship", "area_type", "boundaries", "relationship_type", "schema_migrations"},
+		Columns: []models.LiveColumn{
+			varchar50("area", "code", false),
+			column("area", "active_from", "timestamp without time zone", true),
+			column("area", "active_to", "timestamp without time zone", true),
+			column("area", "area_type_id", "integer", true),
+			column("area", "geometric_area", "character varying", true),
+			column("area", "visible", "boolean", true),
+			column("area", "land_hectares", "real", true),
+			column("area", "version", "integer", false),
+			column("area", "updated_at", "timestamp with time zone", false),
+			column("area_audit", "id", "bigint", false),
+			varchar50("area_audit", "area_code", false),
+			models.LiveColumn{Table: "area_audit", Name: "actor", DataType: "character varying", CharacterMaximumLength: intPtr(255)},
+			models.LiveColumn{Table: "area_audit", Name: "operation", DataType: "character varying", CharacterMaximumLength: intPtr(20)},
+			column("area_audit", "changed_at", "timestamp with time zone", false),
+			column("area_audit", "before", "jsonb", true),
+			column("area_audit", "after", "jsonb", true),
+			varchar50("area_closure", "ancestor", false),
+			varchar50("area_closure", "descendant", false),
+			column("area_closure", "depth", "integer", false),
+			column("area_name", "id", "integer", false),
+			varchar50("area_name", "area_code", true),
+			varchar50("area_name", "name", true),
+			column("area_name", "active_from", "timestamp without time zone", true),
+			column("area_name", "active_to", "timestamp without time zone", true),
+			varchar50("area_relationship", "area_code", false),
+			varchar50("area_relationship", "rel_area_code", false),
+			column("area_relationship", "rel_type_id", "integer", false),
+			column("area_type", "id", "integer", false),
+			varchar50("area_type", "name", true),
+			varchar50("boundaries", "area_id", false),
+			column("boundaries", "centroid_bng", "character varying", true),
+			column("boundaries", "centroid", "character varying", true),
+			column("boundaries", "boundary", "character varying", true),
+			column("relationship_type", "id", "integer", false),
+			varchar50("relationship_type", "name", true),
+			column("schema_migrations", "version", "integer", false),
+		},
+		Constraints: []models.LiveConstraint{
+			constraint("area", "area_pkey", models.PrimaryKeyConstraint, "", "code"),
+			constraint("area", "area_code_key", models.UniqueConstraint, "", "code"),
+			constraint("area", "area_area_type_id_fkey", models.ForeignKeyConstraint, "area_type(id)", "area_type_id"),
+			constraint("area_audit", "area_audit_pkey", models.PrimaryKeyConstraint, "", "id"),
+			constraint("area_audit", "area_audit_operation_check", models.CheckConstraint, ""),
+			constraint("area_closure", "area_closure_pkey", models.PrimaryKeyConstraint, "", "ancestor", "descendant"),
+			constraint("area_closure", "area_closure_ancestor_fkey", models.ForeignKeyConstraint, "area(code)", "ancestor"),
+			constraint("area_closure", "area_closure_descendant_fkey", models.ForeignKeyConstraint, "area(code)", "descendant"),
+			constraint("area_closure", "area_closure_depth_check", models.CheckConstraint, ""),
+			constraint("area_name", "area_name_pkey", models.PrimaryKeyConstraint, "", "id"),
+			constraint("area_name", "area_name_area_code_fkey", models.ForeignKeyConstraint, "area(code)", "area_code"),
+			constraint("area_relationship", "area_relationship_pkey", models.PrimaryKeyConstraint, "", "area_code", "rel_area_code", "rel_type_id"),
+			constraint("area_relationship", "area_relationship_area_code_fkey", models.ForeignKeyConstraint, "area(code)", "area_code"),
+			constraint("area_relationship", "area_relationship_rel_area_code_fkey", models.ForeignKeyConstraint, "area(code)", "rel_area_code"),
+			constraint("area_relationship", "area_relationship_rel_type_id_fkey", models.ForeignKeyConstraint, "relationship_type(id)", "rel_type_id"),
+			constraint("area_type", "area_type_pkey", models.PrimaryKeyConstraint, "", "id"),
+			constraint("boundaries", "boundaries_pkey", models.PrimaryKeyConstraint, "", "area_id"),
+			constraint("relationship_type", "relationship_type_pkey", models.PrimaryKeyConstraint, "", "id"),
+			constraint("schema_migrations", "schema_migrations_pkey", models.PrimaryKeyConstraint, "", "version"),
+		},
+		Indexes: []models.LiveIndex{
+			index("area", "area_pkey"),
+			index("area", "area_code_key"),
+			secondaryIndex("area", "area_area_type_id_idx", "area_type_id"),
+			index("area_audit", "area_audit_pkey"),
+			secondaryIndex("area_audit", "area_audit_area_code_changed_at_idx", "area_code, changed_at"),
+			secondaryIndex("area_closure", "area_closure_descendant_idx", "descendant"),
+			secondaryIndex("area_name", "area_name_area_code_idx", "area_code"),
+			secondaryIndex("area_relationship", "area_relationship_rel_area_code_idx", "rel_area_code"),
+			{Table: "area_name", Name: "area_name_area_code_name_active_from_idx", Definition: "CREATE UNIQUE INDEX area_name_area_code_name_active_from_idx ON public.area_name USING btree (area_code, name, active_from)"},
+			index("schema_migrations", "schema_migrations_pkey"),
+		},
+	}
+}
+
+func builtSchema() *models.DatabaseSchema {
+	schema := &models.DatabaseSchema{
+		DBName:       "dp-areas-api",
+		SchemaString: DBRelationalSchema.DBSchema,
+	}
+	So(schema.BuildDatabaseSchemaModel(), ShouldBeNil)
+	return schema
+}
+
+func TestDatabaseSchema_Diff(t *testing.T) {
+	Convey("Given the schema model and a database with every migration applied", t, func() {
+		schema := builtSchema()
+		live := migratedSchema()
+
+		Convey("When the migrator's table is ignored", func() {
+			report := schema.Diff(live, "schema_migrations")
+
+			Convey("Then no drift is reported", func() {
+				So(report.Drift, ShouldBeEmpty)
+				So(report.InSync, ShouldBeTrue)
+			})
+		})
+
+		Convey("When the migrator's table is not ignored", func() {
+			report := schema.Diff(live)
+
+			Convey("Then it is reported as an extra table", func() {
+				So(report.InSync, ShouldBeFalse)
+				So(report.Drift, ShouldResemble, []models.SchemaDrift{{Kind: models.DriftExtraTable, Table: "schema_migrations"}})
+			})
+		})
+	})
+
+	Convey("Given a database that has drifted from the schema model", t, func() {
+		schema := builtSchema()
+		live := migratedSchema()
+
+		// drop boundaries entirely
+		live.Tables = removeTable(live.Tables, "boundaries")
+		for i := range live.Columns {
+			switch {
+			case live.Columns[i].Table == "area" && live.Columns[i].Name == "land_hectares":
+				live.Columns[i].Name = "hectares"
+			case live.Columns[i].Table == "area_name" && live.Columns[i].Name == "name":
+				live.Columns[i].CharacterMaximumLength = intPtr(100)
+			case live.Columns[i].Table == "area_closure" && live.Columns[i].Name == "depth":
+				live.Columns[i].Nullable = true
+			}
+		}
+		var constraints []models.LiveConstraint
+		for _, c := range live.Constraints {
+			if c.Name != "area_relationship_rel_type_id_fkey" {
+				constraints = append(constraints, c)
+			}
+		}
+		var indexes []models.LiveIndex
+		for _, index := range live.Indexes {
+			switch index.Name {
+			case "area_name_area_code_name_active_from_idx", "area_closure_descendant_idx":
+			case "area_area_type_id_idx":
+				index.Definition = "CREATE INDEX area_area_type_id_idx ON public.area USING hash (area_type_id)"
+				indexes = append(indexes, index)
+			default:
+				indexes = append(indexes, index)
+			}
+		}
+		live.Indexes = indexes
+		live.Constraints = append(constraints, models.LiveConstraint{Table: "area_type", Name: "area_type_name_key", Type: models.UniqueConstraint, Columns: []string{"name"}})
+		live.Indexes = append(live.Indexes, models.LiveIndex{Table: "area_relationship", Name: "area_relationship_rel_type_id_idx", Definition: "CREATE INDEX area_relationship_rel_type_id_idx ON public.area_relationship USING btree (rel_type_id)"})
+
+		Convey("When Diff is invoked", func() {
+			report := schema.Diff(live, "schema_migrations")
+
+			Convey("Then every difference is reported in a stable order", func() {
+				So(report.InSync, ShouldBeFalse)
+				So(report.Drift, ShouldResemble, []models.SchemaDrift{
+					{Kind: models.DriftExtraColumn, Table: "area", Column: "hectares", Actual: "real"},
+					{Kind: models.DriftIndexMismatch, Table: "area", Expected: "CREATE INDEX IF NOT EXISTS area_area_type_id_idx ON area USING btree (area_type_id)", Actual: "CREATE INDEX area_area_type_id_idx ON public.area USING hash (area_type_id)"},
+					{Kind: models.DriftMissingColumn, Table: "area", Column: "land_hectares", Expected: "real"},
+					{Kind: models.DriftMissingIndex, Table: "area_closure", Expected: "CREATE INDEX IF NOT EXISTS area_closure_descendant_idx ON area_closure USING btree (descendant)"},
+					{Kind: models.DriftNullableMismatch, Table: "area_closure", Column: "depth", Expected: "NOT NULL", Actual: "NULL"},
+					{Kind: models.DriftMissingIndex, Table: "area_name", Expected: "CREATE UNIQUE INDEX IF NOT EXISTS area_name_area_code_name_active_from_idx ON area_name USING btree (area_code, name, active_from)"},
+					{Kind: models.DriftTypeMismatch, Table: "area_name", Column: "name", Expected: "character varying(50)", Actual: "character varying(100)"},
+					{Kind: models.DriftExtraIndex, Table: "area_relationship", Actual: "CREATE INDEX area_relationship_rel_type_id_idx ON public.area_relationship USING btree (rel_type_id)"},
+					{Kind: models.DriftMissingConstraint, Table: "area_relationship", Expected: "FOREIGN KEY (rel_type_id) REFERENCES relationship_type(id)"},
+					{Kind: models.DriftExtraConstraint, Table: "area_type", Actual: "UNIQUE (name)"},
+					{Kind: models.DriftMissingTable, Table: "boundaries"},
+				})
+			})
+		})
+	})
+}
+
+func removeTable(tables []string, table string) []string {
+	var remaining []string
+	for _, t := range tables {
+		if t != table {
+			remaining = append(remaining, t)
+		}
+	}
+	return remaining
+}
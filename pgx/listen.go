@@ -0,0 +1,67 @@
+package pgx
+
+import (
+	"context"
+
+	"github.com/ONSdigital/dp-areas-api/config"
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+//go:generate moq -out mock/listen.go -pkg mock . PGXListenConn
+
+// PGXListenConn is a connection held to receive notifications
+type PGXListenConn interface {
+	Exec(ctx context.Context, sql string, arguments ...interface{}) (pgconn.CommandTag, error)
+	WaitForNotification(ctx context.Context) (*pgconn.Notification, error)
+	Release()
+}
+
+// ListenPool hands out connections to receive notifications on. They are made to the writer, as a notification is only
+// delivered by the instance its transaction committed on.
+type ListenPool struct {
+	pool *pgxpool.Pool
+}
+
+// NewListenPool creates a pool holding a single connection to the writer, built and authenticated like any other pool.
+// It connects lazily, so an unreachable database is reported by Acquire rather than here.
+func NewListenPool(ctx context.Context, cfg *config.Config) (*ListenPool, error) {
+	poolConfig, err := NewPoolConfig(cfg, Writer)
+	if err != nil {
+		return nil, err
+	}
+	poolConfig.MaxConns = 1
+	poolConfig.MinConns = 0
+	poolConfig.LazyConnect = true
+	// a released connection may still be listening, so it is closed rather than reused
+	poolConfig.AfterRelease = func(*pgx.Conn) bool { return false }
+
+	pool, err := pgxpool.ConnectConfig(ctx, poolConfig)
+	if err != nil {
+		return nil, err
+	}
+	return &ListenPool{pool: pool}, nil
+}
+
+// Acquire connects a connection to listen on, which is closed when released
+func (p *ListenPool) Acquire(ctx context.Context) (PGXListenConn, error) {
+	conn, err := p.pool.Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return listenConn{conn}, nil
+}
+
+func (p *ListenPool) Close() {
+	p.pool.Close()
+}
+
+// listenConn exposes the notifications of a pool connection
+type listenConn struct {
+	*pgxpool.Conn
+}
+
+func (c listenConn) WaitForNotification(ctx context.Context) (*pgconn.Notification, error) {
+	return c.Conn.Conn().WaitForNotification(ctx)
+}
@@ -0,0 +1,231 @@
+// Code generated by moq; DO NOT EDIT.
+// github.com/matryer/moq
+
+package mock
+
+import (
+	pgx "github.com/ONSdigital/dp-areas-api/pgx"
+	"github.com/jackc/pgconn"
+	v4 "github.com/jackc/pgx/v4"
+	"sync"
+)
+
+// Ensure, that PGXBatchResultsMock does implement pgx.PGXBatchResults.
+// If this is not the case, regenerate this file with moq.
+var _ pgx.PGXBatchResults = &PGXBatchResultsMock{}
+
+// PGXBatchResultsMock is a mock implementation of pgx.PGXBatchResults.
+//
+// 	func TestSomethingThatUsesPGXBatchResults(t *testing.T) {
+//
+// 		// make and configure a mocked pgx.PGXBatchResults
+// 		mockedPGXBatchResults := &PGXBatchResultsMock{
+// 			CloseFunc: func() error {
+// 				panic("mock out the Close method")
+// 			},
+// 			ExecFunc: func() (pgconn.CommandTag, error) {
+// 				panic("mock out the Exec method")
+// 			},
+// 			QueryMock: func() (v4.Rows, error) {
+// 				panic("mock out the Query method")
+// 			},
+// 			QueryFuncMock: func(scans []interface{}, f func(v4.QueryFuncRow) error) (pgconn.CommandTag, error) {
+// 				panic("mock out the QueryFunc method")
+// 			},
+// 			QueryRowFunc: func() v4.Row {
+// 				panic("mock out the QueryRow method")
+// 			},
+// 		}
+//
+// 		// use mockedPGXBatchResults in code that requires pgx.PGXBatchResults
+// 		// and then make assertions.
+//
+// 	}
+type PGXBatchResultsMock struct {
+	// CloseFunc mocks the Close method.
+	CloseFunc func() error
+
+	// ExecFunc mocks the Exec method.
+	ExecFunc func() (pgconn.CommandTag, error)
+
+	// QueryFunc mocks the Query method.
+	QueryMock func() (v4.Rows, error)
+
+	// QueryFuncFunc mocks the QueryFunc method.
+	QueryFuncMock func(scans []interface{}, f func(v4.QueryFuncRow) error) (pgconn.CommandTag, error)
+
+	// QueryRowFunc mocks the QueryRow method.
+	QueryRowFunc func() v4.Row
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// Close holds details about calls to the Close method.
+		Close []struct {
+		}
+		// Exec holds details about calls to the Exec method.
+		Exec []struct {
+		}
+		// Query holds details about calls to the Query method.
+		Query []struct {
+		}
+		// QueryFunc holds details about calls to the QueryFunc method.
+		QueryFunc []struct {
+			// Scans is the scans argument value.
+			Scans []interface{}
+			// F is the f argument value.
+			F func(v4.QueryFuncRow) error
+		}
+		// QueryRow holds details about calls to the QueryRow method.
+		QueryRow []struct {
+		}
+	}
+	lockClose     sync.RWMutex
+	lockExec      sync.RWMutex
+	lockQuery     sync.RWMutex
+	lockQueryFunc sync.RWMutex
+	lockQueryRow  sync.RWMutex
+}
+
+// Close calls CloseFunc.
+func (mock *PGXBatchResultsMock) Close() error {
+	if mock.CloseFunc == nil {
+		panic("PGXBatchResultsMock.CloseFunc: method is nil but PGXBatchResults.Close was just called")
+	}
+	callInfo := struct {
+	}{}
+	mock.lockClose.Lock()
+	mock.calls.Close = append(mock.calls.Close, callInfo)
+	mock.lockClose.Unlock()
+	return mock.CloseFunc()
+}
+
+// CloseCalls gets all the calls that were made to Close.
+// Check the length with:
+//
+// 	len(mockedPGXBatchResults.CloseCalls())
+func (mock *PGXBatchResultsMock) CloseCalls() []struct {
+} {
+	var calls []struct {
+	}
+	mock.lockClose.RLock()
+	calls = mock.calls.Close
+	mock.lockClose.RUnlock()
+	return calls
+}
+
+// Exec calls ExecFunc.
+func (mock *PGXBatchResultsMock) Exec() (pgconn.CommandTag, error) {
+	if mock.ExecFunc == nil {
+		panic("PGXBatchResultsMock.ExecFunc: method is nil but PGXBatchResults.Exec was just called")
+	}
+	callInfo := struct {
+	}{}
+	mock.lockExec.Lock()
+	mock.calls.Exec = append(mock.calls.Exec, callInfo)
+	mock.lockExec.Unlock()
+	return mock.ExecFunc()
+}
+
+// ExecCalls gets all the calls that were made to Exec.
+// Check the length with:
+//
+// 	len(mockedPGXBatchResults.ExecCalls())
+func (mock *PGXBatchResultsMock) ExecCalls() []struct {
+} {
+	var calls []struct {
+	}
+	mock.lockExec.RLock()
+	calls = mock.calls.Exec
+	mock.lockExec.RUnlock()
+	return calls
+}
+
+// Query calls QueryFunc.
+func (mock *PGXBatchResultsMock) Query() (v4.Rows, error) {
+	if mock.QueryMock == nil {
+		panic("PGXBatchResultsMock.QueryFunc: method is nil but PGXBatchResults.Query was just called")
+	}
+	callInfo := struct {
+	}{}
+	mock.lockQuery.Lock()
+	mock.calls.Query = append(mock.calls.Query, callInfo)
+	mock.lockQuery.Unlock()
+	return mock.QueryMock()
+}
+
+// QueryCalls gets all the calls that were made to Query.
+// Check the length with:
+//
+// 	len(mockedPGXBatchResults.QueryCalls())
+func (mock *PGXBatchResultsMock) QueryCalls() []struct {
+} {
+	var calls []struct {
+	}
+	mock.lockQuery.RLock()
+	calls = mock.calls.Query
+	mock.lockQuery.RUnlock()
+	return calls
+}
+
+// QueryFunc calls QueryFuncFunc.
+func (mock *PGXBatchResultsMock) QueryFunc(scans []interface{}, f func(v4.QueryFuncRow) error) (pgconn.CommandTag, error) {
+	if mock.QueryFuncMock == nil {
+		panic("PGXBatchResultsMock.QueryFuncFunc: method is nil but PGXBatchResults.QueryFunc was just called")
+	}
+	callInfo := struct {
+		Scans []interface{}
+		F     func(v4.QueryFuncRow) error
+	}{
+		Scans: scans,
+		F:     f,
+	}
+	mock.lockQueryFunc.Lock()
+	mock.calls.QueryFunc = append(mock.calls.QueryFunc, callInfo)
+	mock.lockQueryFunc.Unlock()
+	return mock.QueryFuncMock(scans, f)
+}
+
+// QueryFuncCalls gets all the calls that were made to QueryFunc.
+// Check the length with:
+//
+// 	len(mockedPGXBatchResults.QueryFuncCalls())
+func (mock *PGXBatchResultsMock) QueryFuncCalls() []struct {
+	Scans []interface{}
+	F     func(v4.QueryFuncRow) error
+} {
+	var calls []struct {
+		Scans []interface{}
+		F     func(v4.QueryFuncRow) error
+	}
+	mock.lockQueryFunc.RLock()
+	calls = mock.calls.QueryFunc
+	mock.lockQueryFunc.RUnlock()
+	return calls
+}
+
+// QueryRow calls QueryRowFunc.
+func (mock *PGXBatchResultsMock) QueryRow() v4.Row {
+	if mock.QueryRowFunc == nil {
+		panic("PGXBatchResultsMock.QueryRowFunc: method is nil but PGXBatchResults.QueryRow was just called")
+	}
+	callInfo := struct {
+	}{}
+	mock.lockQueryRow.Lock()
+	mock.calls.QueryRow = append(mock.calls.QueryRow, callInfo)
+	mock.lockQueryRow.Unlock()
+	return mock.QueryRowFunc()
+}
+
+// QueryRowCalls gets all the calls that were made to QueryRow.
+// Check the length with:
+//
+// 	len(mockedPGXBatchResults.QueryRowCalls())
+func (mock *PGXBatchResultsMock) QueryRowCalls() []struct {
+} {
+	var calls []struct {
+	}
+	mock.lockQueryRow.RLock()
+	calls = mock.calls.QueryRow
+	mock.lockQueryRow.RUnlock()
+	return calls
+}
@@ -0,0 +1,170 @@
+// Code generated by moq; DO NOT EDIT.
+// github.com/matryer/moq
+
+package mock
+
+import (
+	"context"
+	"github.com/ONSdigital/dp-areas-api/pgx"
+	"github.com/jackc/pgconn"
+	"sync"
+)
+
+// Ensure, that PGXListenConnMock does implement pgx.PGXListenConn.
+// If this is not the case, regenerate this file with moq.
+var _ pgx.PGXListenConn = &PGXListenConnMock{}
+
+// PGXListenConnMock is a mock implementation of pgx.PGXListenConn.
+//
+// 	func TestSomethingThatUsesPGXListenConn(t *testing.T) {
+//
+// 		// make and configure a mocked pgx.PGXListenConn
+// 		mockedPGXListenConn := &PGXListenConnMock{
+// 			ExecFunc: func(ctx context.Context, sql string, arguments ...interface{}) (pgconn.CommandTag, error) {
+// 				panic("mock out the Exec method")
+// 			},
+// 			ReleaseFunc: func()  {
+// 				panic("mock out the Release method")
+// 			},
+// 			WaitForNotificationFunc: func(ctx context.Context) (*pgconn.Notification, error) {
+// 				panic("mock out the WaitForNotification method")
+// 			},
+// 		}
+//
+// 		// use mockedPGXListenConn in code that requires pgx.PGXListenConn
+// 		// and then make assertions.
+//
+// 	}
+type PGXListenConnMock struct {
+	// ExecFunc mocks the Exec method.
+	ExecFunc func(ctx context.Context, sql string, arguments ...interface{}) (pgconn.CommandTag, error)
+
+	// ReleaseFunc mocks the Release method.
+	ReleaseFunc func()
+
+	// WaitForNotificationFunc mocks the WaitForNotification method.
+	WaitForNotificationFunc func(ctx context.Context) (*pgconn.Notification, error)
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// Exec holds details about calls to the Exec method.
+		Exec []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// SQL is the sql argument value.
+			SQL string
+			// Arguments is the arguments argument value.
+			Arguments []interface{}
+		}
+		// Release holds details about calls to the Release method.
+		Release []struct {
+		}
+		// WaitForNotification holds details about calls to the WaitForNotification method.
+		WaitForNotification []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+		}
+	}
+	lockExec                sync.RWMutex
+	lockRelease             sync.RWMutex
+	lockWaitForNotification sync.RWMutex
+}
+
+// Exec calls ExecFunc.
+func (mock *PGXListenConnMock) Exec(ctx context.Context, sql string, arguments ...interface{}) (pgconn.CommandTag, error) {
+	if mock.ExecFunc == nil {
+		panic("PGXListenConnMock.ExecFunc: method is nil but PGXListenConn.Exec was just called")
+	}
+	callInfo := struct {
+		Ctx       context.Context
+		SQL       string
+		Arguments []interface{}
+	}{
+		Ctx:       ctx,
+		SQL:       sql,
+		Arguments: arguments,
+	}
+	mock.lockExec.Lock()
+	mock.calls.Exec = append(mock.calls.Exec, callInfo)
+	mock.lockExec.Unlock()
+	return mock.ExecFunc(ctx, sql, arguments...)
+}
+
+// ExecCalls gets all the calls that were made to Exec.
+// Check the length with:
+//
+// 	len(mockedPGXListenConn.ExecCalls())
+func (mock *PGXListenConnMock) ExecCalls() []struct {
+	Ctx       context.Context
+	SQL       string
+	Arguments []interface{}
+} {
+	var calls []struct {
+		Ctx       context.Context
+		SQL       string
+		Arguments []interface{}
+	}
+	mock.lockExec.RLock()
+	calls = mock.calls.Exec
+	mock.lockExec.RUnlock()
+	return calls
+}
+
+// Release calls ReleaseFunc.
+func (mock *PGXListenConnMock) Release() {
+	if mock.ReleaseFunc == nil {
+		panic("PGXListenConnMock.ReleaseFunc: method is nil but PGXListenConn.Release was just called")
+	}
+	callInfo := struct {
+	}{}
+	mock.lockRelease.Lock()
+	mock.calls.Release = append(mock.calls.Release, callInfo)
+	mock.lockRelease.Unlock()
+	mock.ReleaseFunc()
+}
+
+// ReleaseCalls gets all the calls that were made to Release.
+// Check the length with:
+//
+// 	len(mockedPGXListenConn.ReleaseCalls())
+func (mock *PGXListenConnMock) ReleaseCalls() []struct {
+} {
+	var calls []struct {
+	}
+	mock.lockRelease.RLock()
+	calls = mock.calls.Release
+	mock.lockRelease.RUnlock()
+	return calls
+}
+
+// WaitForNotification calls WaitForNotificationFunc.
+func (mock *PGXListenConnMock) WaitForNotification(ctx context.Context) (*pgconn.Notification, error) {
+	if mock.WaitForNotificationFunc == nil {
+		panic("PGXListenConnMock.WaitForNotificationFunc: method is nil but PGXListenConn.WaitForNotification was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+	}{
+		Ctx: ctx,
+	}
+	mock.lockWaitForNotification.Lock()
+	mock.calls.WaitForNotification = append(mock.calls.WaitForNotification, callInfo)
+	mock.lockWaitForNotification.Unlock()
+	return mock.WaitForNotificationFunc(ctx)
+}
+
+// WaitForNotificationCalls gets all the calls that were made to WaitForNotification.
+// Check the length with:
+//
+// 	len(mockedPGXListenConn.WaitForNotificationCalls())
+func (mock *PGXListenConnMock) WaitForNotificationCalls() []struct {
+	Ctx context.Context
+} {
+	var calls []struct {
+		Ctx context.Context
+	}
+	mock.lockWaitForNotification.RLock()
+	calls = mock.calls.WaitForNotification
+	mock.lockWaitForNotification.RUnlock()
+	return calls
+}
@@ -39,6 +39,9 @@ var _ pgx.PGXPool = &PGXPoolMock{}
 // 			QueryRowFunc: func(ctx context.Context, sql string, args ...interface{}) v4.Row {
 // 				panic("mock out the QueryRow method")
 // 			},
+// 			SendBatchFunc: func(ctx context.Context, b *v4.Batch) v4.BatchResults {
+// 				panic("mock out the SendBatch method")
+// 			},
 // 		}
 //
 // 		// use mockedPGXPool in code that requires pgx.PGXPool
@@ -64,6 +67,9 @@ type PGXPoolMock struct {
 	// QueryRowFunc mocks the QueryRow method.
 	QueryRowFunc func(ctx context.Context, sql string, args ...interface{}) v4.Row
 
+	// SendBatchFunc mocks the SendBatch method.
+	SendBatchFunc func(ctx context.Context, b *v4.Batch) v4.BatchResults
+
 	// calls tracks calls to the methods.
 	calls struct {
 		// Begin holds details about calls to the Begin method.
@@ -106,19 +112,27 @@ type PGXPoolMock struct {
 			// Args is the args argument value.
 			Args []interface{}
 		}
+		// SendBatch holds details about calls to the SendBatch method.
+		SendBatch []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// B is the b argument value.
+			B *v4.Batch
+		}
 	}
-	lockBegin    sync.RWMutex
-	lockClose    sync.RWMutex
-	lockExec     sync.RWMutex
-	lockPing     sync.RWMutex
-	lockQuery    sync.RWMutex
-	lockQueryRow sync.RWMutex
+	lockBegin     sync.RWMutex
+	lockClose     sync.RWMutex
+	lockExec      sync.RWMutex
+	lockPing      sync.RWMutex
+	lockQuery     sync.RWMutex
+	lockQueryRow  sync.RWMutex
+	lockSendBatch sync.RWMutex
 }
 
 // Begin calls BeginFunc.
 func (mock *PGXPoolMock) Begin(ctx context.Context) (v4.Tx, error) {
 	if mock.BeginFunc == nil {
-		panic("PGXPoolMock.BeginMock: method is nil but PGXPool.Begin was just called")
+		panic("PGXPoolMock.BeginFunc: method is nil but PGXPool.Begin was just called")
 	}
 	callInfo := struct {
 		Ctx context.Context
@@ -133,7 +147,8 @@ func (mock *PGXPoolMock) Begin(ctx context.Context) (v4.Tx, error) {
 
 // BeginCalls gets all the calls that were made to Begin.
 // Check the length with:
-//     len(mockedPGXPool.BeginCalls())
+//
+// 	len(mockedPGXPool.BeginCalls())
 func (mock *PGXPoolMock) BeginCalls() []struct {
 	Ctx context.Context
 } {
@@ -161,7 +176,8 @@ func (mock *PGXPoolMock) Close() {
 
 // CloseCalls gets all the calls that were made to Close.
 // Check the length with:
-//     len(mockedPGXPool.CloseCalls())
+//
+// 	len(mockedPGXPool.CloseCalls())
 func (mock *PGXPoolMock) CloseCalls() []struct {
 } {
 	var calls []struct {
@@ -194,7 +210,8 @@ func (mock *PGXPoolMock) Exec(ctx context.Context, sql string, arguments ...inte
 
 // ExecCalls gets all the calls that were made to Exec.
 // Check the length with:
-//     len(mockedPGXPool.ExecCalls())
+//
+// 	len(mockedPGXPool.ExecCalls())
 func (mock *PGXPoolMock) ExecCalls() []struct {
 	Ctx       context.Context
 	SQL       string
@@ -229,7 +246,8 @@ func (mock *PGXPoolMock) Ping(ctx context.Context) error {
 
 // PingCalls gets all the calls that were made to Ping.
 // Check the length with:
-//     len(mockedPGXPool.PingCalls())
+//
+// 	len(mockedPGXPool.PingCalls())
 func (mock *PGXPoolMock) PingCalls() []struct {
 	Ctx context.Context
 } {
@@ -264,7 +282,8 @@ func (mock *PGXPoolMock) Query(ctx context.Context, sql string, args ...interfac
 
 // QueryCalls gets all the calls that were made to Query.
 // Check the length with:
-//     len(mockedPGXPool.QueryCalls())
+//
+// 	len(mockedPGXPool.QueryCalls())
 func (mock *PGXPoolMock) QueryCalls() []struct {
 	Ctx  context.Context
 	SQL  string
@@ -303,7 +322,8 @@ func (mock *PGXPoolMock) QueryRow(ctx context.Context, sql string, args ...inter
 
 // QueryRowCalls gets all the calls that were made to QueryRow.
 // Check the length with:
-//     len(mockedPGXPool.QueryRowCalls())
+//
+// 	len(mockedPGXPool.QueryRowCalls())
 func (mock *PGXPoolMock) QueryRowCalls() []struct {
 	Ctx  context.Context
 	SQL  string
@@ -319,3 +339,39 @@ func (mock *PGXPoolMock) QueryRowCalls() []struct {
 	mock.lockQueryRow.RUnlock()
 	return calls
 }
+
+// SendBatch calls SendBatchFunc.
+func (mock *PGXPoolMock) SendBatch(ctx context.Context, b *v4.Batch) v4.BatchResults {
+	if mock.SendBatchFunc == nil {
+		panic("PGXPoolMock.SendBatchFunc: method is nil but PGXPool.SendBatch was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		B   *v4.Batch
+	}{
+		Ctx: ctx,
+		B:   b,
+	}
+	mock.lockSendBatch.Lock()
+	mock.calls.SendBatch = append(mock.calls.SendBatch, callInfo)
+	mock.lockSendBatch.Unlock()
+	return mock.SendBatchFunc(ctx, b)
+}
+
+// SendBatchCalls gets all the calls that were made to SendBatch.
+// Check the length with:
+//
+// 	len(mockedPGXPool.SendBatchCalls())
+func (mock *PGXPoolMock) SendBatchCalls() []struct {
+	Ctx context.Context
+	B   *v4.Batch
+} {
+	var calls []struct {
+		Ctx context.Context
+		B   *v4.Batch
+	}
+	mock.lockSendBatch.RLock()
+	calls = mock.calls.SendBatch
+	mock.lockSendBatch.RUnlock()
+	return calls
+}
@@ -0,0 +1,240 @@
+package pgx
+
+import (
+	"context"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+)
+
+// Names of the statements that are not queries of their own
+const (
+	BeginStatement  = "begin"
+	CommitStatement = "commit"
+	BatchStatement  = "batch"
+)
+
+// QueryNamer names a query from its sql
+type QueryNamer func(sql string) string
+
+// QueryObserver is told of each query run through an ObservedPool as it starts, with its name and the context it runs
+// in, and returns the func that is told when it finishes, with the error it failed with
+type QueryObserver func(ctx context.Context, name string) (finish func(err error))
+
+// ObservedPool tells observers of the queries run through a pool and the transactions it begins. A query finishes once
+// its result is read: a row once it is scanned, rows once they are closed or run out and a batch once its results are
+// closed. Finding no row is not a failure. Batches are observed as one BatchStatement, as pgx does not expose the
+// queries a pgx.Batch holds, and the results of a Batch are also observed one by one, named by their queries.
+type ObservedPool struct {
+	PGXPool
+	name      QueryNamer
+	observers []QueryObserver
+}
+
+// NewObservedPool wraps pool so that observers are told of each query, named by name
+func NewObservedPool(pool PGXPool, name QueryNamer, observers ...QueryObserver) *ObservedPool {
+	return &ObservedPool{PGXPool: pool, name: name, observers: observers}
+}
+
+func (p *ObservedPool) Begin(ctx context.Context) (pgx.Tx, error) {
+	finish := p.start(ctx, BeginStatement)
+	tx, err := p.PGXPool.Begin(ctx)
+	finish(err)
+	if err != nil {
+		return nil, err
+	}
+	return &observedTx{Tx: tx, pool: p}, nil
+}
+
+func (p *ObservedPool) QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row {
+	finish := p.start(ctx, p.name(sql))
+	return &observedRow{Row: p.PGXPool.QueryRow(ctx, sql, args...), finish: finish}
+}
+
+func (p *ObservedPool) Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error) {
+	finish := p.start(ctx, p.name(sql))
+	return observeRows(finish)(p.PGXPool.Query(ctx, sql, args...))
+}
+
+func (p *ObservedPool) Exec(ctx context.Context, sql string, arguments ...interface{}) (pgconn.CommandTag, error) {
+	finish := p.start(ctx, p.name(sql))
+	tag, err := p.PGXPool.Exec(ctx, sql, arguments...)
+	finish(err)
+	return tag, err
+}
+
+func (p *ObservedPool) SendBatch(ctx context.Context, b *pgx.Batch) pgx.BatchResults {
+	return p.sendBatch(ctx, b, nil)
+}
+
+// sendBatch sends a batch, observing the result of each of its queries when their sql is known
+func (p *ObservedPool) sendBatch(ctx context.Context, b *pgx.Batch, queries []string) pgx.BatchResults {
+	finish := p.start(ctx, BatchStatement)
+	return &observedBatchResults{BatchResults: p.PGXPool.SendBatch(ctx, b), finish: finish, ctx: ctx, pool: p, queries: queries}
+}
+
+// start tells each observer that a query has started, returning the func that tells them it has finished
+func (p *ObservedPool) start(ctx context.Context, name string) func(err error) {
+	finishers := make([]func(err error), len(p.observers))
+	for i, observe := range p.observers {
+		finishers[i] = observe(ctx, name)
+	}
+	return func(err error) {
+		if err == pgx.ErrNoRows {
+			err = nil
+		}
+		for _, finish := range finishers {
+			finish(err)
+		}
+	}
+}
+
+// observedTx tells the observers of its pool of the queries run in a transaction, and its commit
+type observedTx struct {
+	pgx.Tx
+	pool *ObservedPool
+}
+
+func (t *observedTx) Commit(ctx context.Context) error {
+	finish := t.pool.start(ctx, CommitStatement)
+	err := t.Tx.Commit(ctx)
+	finish(err)
+	return err
+}
+
+func (t *observedTx) QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row {
+	finish := t.pool.start(ctx, t.pool.name(sql))
+	return &observedRow{Row: t.Tx.QueryRow(ctx, sql, args...), finish: finish}
+}
+
+func (t *observedTx) Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error) {
+	finish := t.pool.start(ctx, t.pool.name(sql))
+	return observeRows(finish)(t.Tx.Query(ctx, sql, args...))
+}
+
+func (t *observedTx) Exec(ctx context.Context, sql string, arguments ...interface{}) (pgconn.CommandTag, error) {
+	finish := t.pool.start(ctx, t.pool.name(sql))
+	tag, err := t.Tx.Exec(ctx, sql, arguments...)
+	finish(err)
+	return tag, err
+}
+
+func (t *observedTx) SendBatch(ctx context.Context, b *pgx.Batch) pgx.BatchResults {
+	finish := t.pool.start(ctx, BatchStatement)
+	return &observedBatchResults{BatchResults: t.Tx.SendBatch(ctx, b), finish: finish, ctx: ctx, pool: t.pool}
+}
+
+// observedRow finishes its query once its row is scanned
+type observedRow struct {
+	pgx.Row
+	finish func(err error)
+}
+
+func (r *observedRow) Scan(dest ...interface{}) error {
+	err := r.Row.Scan(dest...)
+	r.finish(err)
+	return err
+}
+
+// observeRows returns a func that finishes a query that failed to return rows, and otherwise observes the rows
+func observeRows(finish func(err error)) func(rows pgx.Rows, err error) (pgx.Rows, error) {
+	return func(rows pgx.Rows, err error) (pgx.Rows, error) {
+		if err != nil {
+			finish(err)
+			return rows, err
+		}
+		return &observedRows{Rows: rows, finish: finish}, nil
+	}
+}
+
+// observedRows finishes its query once its rows are closed or run out, whichever is first
+type observedRows struct {
+	pgx.Rows
+	finish   func(err error)
+	finished bool
+}
+
+func (r *observedRows) Next() bool {
+	next := r.Rows.Next()
+	if !next {
+		r.done()
+	}
+	return next
+}
+
+func (r *observedRows) Close() {
+	r.Rows.Close()
+	r.done()
+}
+
+func (r *observedRows) done() {
+	if !r.finished {
+		r.finished = true
+		r.finish(r.Rows.Err())
+	}
+}
+
+// Batch queues queries like a pgx.Batch, keeping their sql so that an ObservedPool can name their results
+type Batch struct {
+	pgx.Batch
+	queries []string
+}
+
+// Queue queues a query to the batch
+func (b *Batch) Queue(query string, arguments ...interface{}) {
+	b.Batch.Queue(query, arguments...)
+	b.queries = append(b.queries, query)
+}
+
+// Send sends the batch on pool. An ObservedPool observes the result of each query as it is read from the results,
+// from when it is read until it is scanned or its rows are closed, as well as the batch as a whole.
+func (b *Batch) Send(ctx context.Context, pool PGXPool) pgx.BatchResults {
+	if observed, ok := pool.(*ObservedPool); ok {
+		return observed.sendBatch(ctx, &b.Batch, b.queries)
+	}
+	return pool.SendBatch(ctx, &b.Batch)
+}
+
+// observedBatchResults finishes its batch once its results are closed, and observes each result read when the sql of
+// the queries is known
+type observedBatchResults struct {
+	pgx.BatchResults
+	finish  func(err error)
+	ctx     context.Context
+	pool    *ObservedPool
+	queries []string
+	read    int
+}
+
+// startResult starts observing the next result read, returning a func that does nothing when its query is not known
+func (b *observedBatchResults) startResult() func(err error) {
+	if b.read >= len(b.queries) {
+		return func(error) {}
+	}
+	name := b.pool.name(b.queries[b.read])
+	b.read++
+	return b.pool.start(b.ctx, name)
+}
+
+func (b *observedBatchResults) Exec() (pgconn.CommandTag, error) {
+	finish := b.startResult()
+	tag, err := b.BatchResults.Exec()
+	finish(err)
+	return tag, err
+}
+
+func (b *observedBatchResults) Query() (pgx.Rows, error) {
+	finish := b.startResult()
+	return observeRows(finish)(b.BatchResults.Query())
+}
+
+func (b *observedBatchResults) QueryRow() pgx.Row {
+	finish := b.startResult()
+	return &observedRow{Row: b.BatchResults.QueryRow(), finish: finish}
+}
+
+func (b *observedBatchResults) Close() error {
+	err := b.BatchResults.Close()
+	b.finish(err)
+	return err
+}
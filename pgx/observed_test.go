@@ -0,0 +1,147 @@
+package pgx_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ONSdigital/dp-areas-api/pgx"
+	"github.com/ONSdigital/dp-areas-api/pgx/mock"
+	"github.com/jackc/pgconn"
+	v4 "github.com/jackc/pgx/v4"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// observation is one query told to a pgx.QueryObserver
+type observation struct {
+	name string
+	err  error
+}
+
+func TestObservedPool(t *testing.T) {
+	ctx := context.Background()
+	errQuery := errors.New("query failed")
+	names := map[string]string{"select 1": "one", "select 2": "two"}
+	namer := func(sql string) string { return names[sql] }
+
+	Convey("Given a pool observed by name", t, func() {
+		var started []string
+		var observed []observation
+		observe := func(ctx context.Context, name string) func(err error) {
+			started = append(started, name)
+			return func(err error) {
+				observed = append(observed, observation{name: name, err: err})
+			}
+		}
+
+		rows := &mock.PGXRowsMock{
+			NextFunc:  func() bool { return false },
+			CloseFunc: func() {},
+			ErrFunc:   func() error { return nil },
+		}
+		tx := &mock.PGXTransactionMock{
+			ExecFunc: func(ctx context.Context, sql string, arguments ...interface{}) (pgconn.CommandTag, error) {
+				return nil, errQuery
+			},
+			CommitFunc: func(ctx context.Context) error { return nil },
+		}
+		pool := pgx.NewObservedPool(&mock.PGXPoolMock{
+			ExecFunc: func(ctx context.Context, sql string, arguments ...interface{}) (pgconn.CommandTag, error) {
+				return pgconn.CommandTag("UPDATE 1"), nil
+			},
+			QueryRowFunc: func(ctx context.Context, sql string, args ...interface{}) v4.Row {
+				return &mock.PGXRowMock{ScanFunc: func(dest ...interface{}) error { return v4.ErrNoRows }}
+			},
+			QueryFunc: func(ctx context.Context, sql string, args ...interface{}) (v4.Rows, error) {
+				return rows, nil
+			},
+			BeginFunc: func(ctx context.Context) (v4.Tx, error) { return tx, nil },
+			SendBatchFunc: func(ctx context.Context, b *v4.Batch) v4.BatchResults {
+				return &mock.PGXBatchResultsMock{
+					QueryRowFunc: func() v4.Row {
+						return &mock.PGXRowMock{ScanFunc: func(dest ...interface{}) error { return nil }}
+					},
+					QueryMock: func() (v4.Rows, error) { return rows, nil },
+					CloseFunc: func() error { return nil },
+				}
+			},
+		}, namer, observe)
+
+		Convey("When a statement is executed", func() {
+			_, err := pool.Exec(ctx, "select 1")
+
+			Convey("Then it is observed by name", func() {
+				So(err, ShouldBeNil)
+				So(observed, ShouldResemble, []observation{{name: "one"}})
+			})
+		})
+
+		Convey("When a row is queried", func() {
+			row := pool.QueryRow(ctx, "select 2")
+
+			Convey("Then it only finishes once it is scanned, and finding no row is not a failure", func() {
+				So(started, ShouldResemble, []string{"two"})
+				So(observed, ShouldBeEmpty)
+				So(row.Scan(), ShouldEqual, v4.ErrNoRows)
+				So(observed, ShouldResemble, []observation{{name: "two"}})
+			})
+		})
+
+		Convey("When rows are queried", func() {
+			result, err := pool.Query(ctx, "select 1")
+			So(err, ShouldBeNil)
+
+			Convey("Then they are observed once, when they run out or are closed", func() {
+				So(observed, ShouldBeEmpty)
+				So(result.Next(), ShouldBeFalse)
+				result.Close()
+				So(observed, ShouldResemble, []observation{{name: "one"}})
+				So(rows.CloseCalls(), ShouldHaveLength, 1)
+			})
+		})
+
+		Convey("When a transaction runs a statement and commits", func() {
+			t, err := pool.Begin(ctx)
+			So(err, ShouldBeNil)
+			_, err = t.Exec(ctx, "select 2")
+			So(err, ShouldEqual, errQuery)
+			So(t.Commit(ctx), ShouldBeNil)
+
+			Convey("Then the begin, the statement with its error and the commit are observed", func() {
+				So(observed, ShouldResemble, []observation{
+					{name: pgx.BeginStatement},
+					{name: "two", err: errQuery},
+					{name: pgx.CommitStatement},
+				})
+			})
+		})
+
+		Convey("When a batch is sent", func() {
+			results := pool.SendBatch(ctx, &v4.Batch{})
+
+			Convey("Then it is observed as a batch once its results are closed", func() {
+				So(observed, ShouldBeEmpty)
+				So(results.Close(), ShouldBeNil)
+				So(observed, ShouldResemble, []observation{{name: pgx.BatchStatement}})
+			})
+		})
+
+		Convey("When a batch that knows its queries is sent and read", func() {
+			batch := &pgx.Batch{}
+			batch.Queue("select 1")
+			batch.Queue("select 2")
+			results := batch.Send(ctx, pool)
+			So(batch.Len(), ShouldEqual, 2)
+			So(results.QueryRow().Scan(), ShouldBeNil)
+			result, err := results.Query()
+			So(err, ShouldBeNil)
+			result.Close()
+			So(results.Close(), ShouldBeNil)
+
+			Convey("Then each result is observed by its query, within the batch", func() {
+				So(started, ShouldResemble, []string{pgx.BatchStatement, "one", "two"})
+				So(observed, ShouldResemble, []observation{{name: "one"}, {name: "two"}, {name: pgx.BatchStatement}})
+			})
+		})
+	})
+}
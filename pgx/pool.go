@@ -0,0 +1,157 @@
+package pgx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ONSdigital/dp-areas-api/config"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/service/rds/rdsutils"
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgconn/stmtcache"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// Endpoint is the database endpoint a pool connects to
+type Endpoint int
+
+const (
+	Writer Endpoint = iota
+	Reader
+)
+
+// Ways a pool can authenticate
+const (
+	AuthDSN      = "dsn"
+	AuthPassword = "password"
+	AuthIAM      = "iam"
+)
+
+// statementCacheCapacity is the number of statements each connection caches
+const statementCacheCapacity = 512
+
+// minMaxConnections is the smallest pool that can migrate the schema: the schema lock is held by a transaction on one
+// connection while the migrations run on another
+const minMaxConnections = 2
+
+// ErrNoReader is returned for a reader pool when no reader endpoint is configured
+var ErrNoReader = errors.New("no database reader endpoint configured")
+
+// buildAuthToken signs an RDS IAM auth token
+var buildAuthToken = rdsutils.BuildAuthToken
+
+// AuthMode returns how pools authenticate: with the plain connection string when one is configured, with a password
+// against a local postgres instance, or otherwise with IAM against RDS
+func AuthMode(cfg *config.Config) string {
+	switch {
+	case cfg.DBDSN != "":
+		return AuthDSN
+	case cfg.DPPostgresLocal:
+		return AuthPassword
+	default:
+		return AuthIAM
+	}
+}
+
+// NewPoolConfig builds the config for a pool on an endpoint. The pool tuning in cfg is applied whichever way the pool
+// authenticates.
+func NewPoolConfig(cfg *config.Config, endpoint Endpoint) (*pgxpool.Config, error) {
+	if endpoint == Reader && !cfg.HasDBReader() {
+		return nil, ErrNoReader
+	}
+
+	var poolConfig *pgxpool.Config
+	var err error
+	switch AuthMode(cfg) {
+	case AuthDSN:
+		poolConfig, err = pgxpool.ParseConfig(cfg.DBDSN)
+	case AuthPassword:
+		poolConfig, err = pgxpool.ParseConfig(cfg.GetLocalDBConnectionString())
+	default:
+		if endpoint == Reader {
+			poolConfig, err = newIAMPoolConfig(cfg, cfg.GetRemoteDBReaderConnectionString(), cfg.GetDBReaderEndpoint())
+		} else {
+			poolConfig, err = newIAMPoolConfig(cfg, cfg.GetRemoteDBConnectionString(), cfg.GetDBEndpoint())
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse connection string: %w", err)
+	}
+
+	if err = applyPoolTuning(poolConfig, cfg); err != nil {
+		return nil, err
+	}
+	return poolConfig, nil
+}
+
+// NewPool connects a pool to an endpoint
+func NewPool(ctx context.Context, cfg *config.Config, endpoint Endpoint) (*pgxpool.Pool, error) {
+	poolConfig, err := NewPoolConfig(cfg, endpoint)
+	if err != nil {
+		return nil, err
+	}
+	return pgxpool.ConnectConfig(ctx, poolConfig)
+}
+
+// newIAMPoolConfig parses the config for a pool on an RDS endpoint that authenticates with IAM. Auth tokens expire
+// after 15 minutes, so rather than one token for the life of the pool a fresh token is signed for every new connection.
+func newIAMPoolConfig(cfg *config.Config, connectionString, endpoint string) (*pgxpool.Config, error) {
+	poolConfig, err := pgxpool.ParseConfig(connectionString)
+	if err != nil {
+		return nil, err
+	}
+
+	creds := credentials.NewEnvCredentials()
+	poolConfig.BeforeConnect = func(ctx context.Context, connConfig *pgx.ConnConfig) error {
+		authToken, err := buildAuthToken(endpoint, cfg.AWSRegion, cfg.RDSDBUser, creds)
+		if err != nil {
+			return fmt.Errorf("failed to build auth token for %s: %w", endpoint, err)
+		}
+		connConfig.Password = authToken
+		return nil
+	}
+	return poolConfig, nil
+}
+
+// applyPoolTuning sets the pool size, connection lifetimes and statement cache from cfg. Unset values keep the pgxpool
+// defaults. A pool too small to migrate the schema is refused rather than left to deadlock.
+func applyPoolTuning(poolConfig *pgxpool.Config, cfg *config.Config) error {
+	if cfg.RDSDBMaxConnections > 0 && cfg.RDSDBMaxConnections < minMaxConnections {
+		return fmt.Errorf("RDSMAXCONNECTIONS must be at least %d, got %d", minMaxConnections, cfg.RDSDBMaxConnections)
+	}
+	if cfg.RDSDBMaxConnections > 0 {
+		poolConfig.MaxConns = int32(cfg.RDSDBMaxConnections)
+	}
+	if cfg.RDSDBMinConnections > 0 {
+		poolConfig.MinConns = int32(cfg.RDSDBMinConnections)
+	}
+	if cfg.RDSDBConnectionTTL > 0 {
+		poolConfig.MaxConnLifetime = cfg.RDSDBConnectionTTL
+	}
+	if cfg.RDSDBMaxConnIdleTime > 0 {
+		poolConfig.MaxConnIdleTime = cfg.RDSDBMaxConnIdleTime
+	}
+	if cfg.RDSDBHealthCheckPeriod > 0 {
+		poolConfig.HealthCheckPeriod = cfg.RDSDBHealthCheckPeriod
+	}
+
+	switch cfg.RDSDBStatementCacheMode {
+	case "", config.StatementCachePrepare:
+		poolConfig.ConnConfig.BuildStatementCache = newStatementCache(stmtcache.ModePrepare)
+	case config.StatementCacheDescribe:
+		poolConfig.ConnConfig.BuildStatementCache = newStatementCache(stmtcache.ModeDescribe)
+	case config.StatementCacheNone:
+		poolConfig.ConnConfig.BuildStatementCache = nil
+	default:
+		return fmt.Errorf("unknown statement cache mode %q", cfg.RDSDBStatementCacheMode)
+	}
+	return nil
+}
+
+func newStatementCache(mode int) pgx.BuildStatementCacheFunc {
+	return func(conn *pgconn.PgConn) stmtcache.Cache {
+		return stmtcache.New(conn, mode, statementCacheCapacity)
+	}
+}
@@ -0,0 +1,47 @@
+package rds
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/ONSdigital/dp-areas-api/config"
+	"github.com/ONSdigital/dp-areas-api/storetest"
+)
+
+// truncateTables empties every table written by the store so that each conformance scenario starts from the fixtures
+const truncateTables = "truncate area_audit, area_closure, area_relationship, area_name, boundaries, area, area_type, relationship_type restart identity cascade"
+
+// TestRDS_Conformance runs the store conformance suite against the Postgres database described by the service config.
+// It needs a database it may empty, so it only runs when STORETEST_POSTGRES is set to true.
+func TestRDS_Conformance(t *testing.T) {
+	if os.Getenv("STORETEST_POSTGRES") != "true" {
+		t.Skip("set STORETEST_POSTGRES=true to run the store conformance suite against Postgres")
+	}
+
+	storetest.Run(t, func(t *testing.T) storetest.Store {
+		ctx := context.Background()
+		cfg, err := config.Get()
+		if err != nil {
+			t.Fatalf("failed to get config: %v", err)
+		}
+
+		r := &RDS{}
+		if err = r.Init(ctx, cfg); err != nil {
+			t.Fatalf("failed to connect to postgres: %v", err)
+		}
+		t.Cleanup(r.Close)
+
+		migrator, err := r.Migrator()
+		if err != nil {
+			t.Fatalf("failed to create migrator: %v", err)
+		}
+		if _, err = migrator.Up(ctx); err != nil {
+			t.Fatalf("failed to migrate: %v", err)
+		}
+		if _, err = r.conn.Exec(ctx, truncateTables); err != nil {
+			t.Fatalf("failed to empty tables: %v", err)
+		}
+		return r
+	})
+}
@@ -0,0 +1,111 @@
+package rds
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/ONSdigital/dp-areas-api/apierrors"
+	"github.com/jackc/pgconn"
+	v4 "github.com/jackc/pgx/v4"
+)
+
+// SQLSTATE codes and classes the store errors are classified by, see
+// https://www.postgresql.org/docs/current/errcodes-appendix.html
+const (
+	notNullViolation          = "23502"
+	foreignKeyViolation       = "23503"
+	uniqueViolation           = "23505"
+	checkViolation            = "23514"
+	exclusionViolation        = "23P01"
+	serializationFailure      = "40001"
+	deadlockDetected          = "40P01"
+	lockNotAvailable          = "55P03"
+	queryCanceled             = "57014"
+	adminShutdown             = "57P01"
+	crashShutdown             = "57P02"
+	cannotConnectNow          = "57P03"
+	dataExceptionClass        = "22"
+	connectionExceptionClass  = "08"
+	insufficientResourceClass = "53"
+)
+
+// contextError wraps the context's error around err when a store call failed because its context ended, so that a
+// timeout or cancellation can be told apart from a database error with errors.Is
+func contextError(ctx context.Context, err error) error {
+	if err == nil || ctx.Err() == nil || errors.Is(err, ctx.Err()) {
+		return err
+	}
+	return fmt.Errorf("%w: %v", ctx.Err(), err)
+}
+
+// storeError is deferred by every store call to classify the error it returns. Errors from an ended context are left
+// to the caller's context handling; no rows, Postgres errors and connection failures become apierrors.StoreError;
+// anything else is returned unchanged.
+func storeError(ctx context.Context, err error) error {
+	err = contextError(ctx, err)
+	if err == nil || errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+		return err
+	}
+	var storeErr *apierrors.StoreError
+	if errors.As(err, &storeErr) {
+		return err
+	}
+
+	if errors.Is(err, v4.ErrNoRows) {
+		return apierrors.NewStoreError(apierrors.ErrNotFound, err)
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		kind := pgErrorKind(pgErr.Code)
+		if kind == nil {
+			return err
+		}
+		return &apierrors.StoreError{Kind: kind, Constraint: pgErr.ConstraintName, Err: err}
+	}
+
+	if pgconn.Timeout(err) {
+		return apierrors.NewStoreError(apierrors.ErrTimeout, err)
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) || pgconn.SafeToRetry(err) {
+		return apierrors.NewStoreError(apierrors.ErrUnavailable, err)
+	}
+	return err
+}
+
+// referenceError classifies a missing lookup row, such as an unknown area type, as an invalid reference rather than
+// the area itself not being found
+func referenceError(err error) error {
+	if errors.Is(err, v4.ErrNoRows) {
+		return apierrors.NewStoreError(apierrors.ErrInvalidReference, err)
+	}
+	return err
+}
+
+// pgErrorKind returns the store error kind for a SQLSTATE code, or nil when the code is not classified
+func pgErrorKind(code string) error {
+	switch code {
+	case uniqueViolation, exclusionViolation, serializationFailure, deadlockDetected:
+		return apierrors.ErrConflict
+	case foreignKeyViolation:
+		return apierrors.ErrInvalidReference
+	case notNullViolation, checkViolation:
+		return apierrors.ErrInvalidData
+	case queryCanceled, lockNotAvailable:
+		return apierrors.ErrTimeout
+	case adminShutdown, crashShutdown, cannotConnectNow:
+		return apierrors.ErrUnavailable
+	}
+
+	switch {
+	case strings.HasPrefix(code, dataExceptionClass):
+		return apierrors.ErrInvalidData
+	case strings.HasPrefix(code, connectionExceptionClass), strings.HasPrefix(code, insufficientResourceClass):
+		return apierrors.ErrUnavailable
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package rds
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/ONSdigital/dp-areas-api/apierrors"
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestStoreError(t *testing.T) {
+	ctx := context.Background()
+
+	Convey("Given errors returned by Postgres", t, func() {
+		tests := []struct {
+			code string
+			kind error
+		}{
+			{uniqueViolation, apierrors.ErrConflict},
+			{deadlockDetected, apierrors.ErrConflict},
+			{foreignKeyViolation, apierrors.ErrInvalidReference},
+			{checkViolation, apierrors.ErrInvalidData},
+			{"22001", apierrors.ErrInvalidData},
+			{queryCanceled, apierrors.ErrTimeout},
+			{adminShutdown, apierrors.ErrUnavailable},
+			{"08006", apierrors.ErrUnavailable},
+			{"53300", apierrors.ErrUnavailable},
+		}
+
+		Convey("Then each is classified by its SQLSTATE code and keeps the violated constraint", func() {
+			for _, tc := range tests {
+				pgErr := &pgconn.PgError{Code: tc.code, ConstraintName: "area_name_area_code_name_active_from_idx"}
+				err := storeError(ctx, fmt.Errorf("failed to upsert into area_name: %w", pgErr))
+
+				So(errors.Is(err, tc.kind), ShouldBeTrue)
+				var storeErr *apierrors.StoreError
+				So(errors.As(err, &storeErr), ShouldBeTrue)
+				So(storeErr.Constraint, ShouldEqual, "area_name_area_code_name_active_from_idx")
+				So(errors.As(err, &pgErr), ShouldBeTrue)
+			}
+		})
+
+		Convey("Then an unclassified code is returned unchanged", func() {
+			pgErr := &pgconn.PgError{Code: "42P01"}
+			So(storeError(ctx, pgErr), ShouldEqual, pgErr)
+		})
+	})
+
+	Convey("Given no rows", t, func() {
+		err := storeError(ctx, pgx.ErrNoRows)
+
+		Convey("Then it is not found and keeps its message", func() {
+			So(errors.Is(err, apierrors.ErrNotFound), ShouldBeTrue)
+			So(err.Error(), ShouldEqual, apierrors.ErrNoRows.Error())
+		})
+
+		Convey("Then a missing lookup row is an invalid reference instead", func() {
+			err := storeError(ctx, referenceError(fmt.Errorf("failed to get area type: %w", pgx.ErrNoRows)))
+			So(errors.Is(err, apierrors.ErrInvalidReference), ShouldBeTrue)
+			So(errors.Is(err, apierrors.ErrNotFound), ShouldBeFalse)
+		})
+	})
+
+	Convey("Given the database cannot be reached", t, func() {
+		err := storeError(ctx, fmt.Errorf("failed to start transaction: %w", &net.OpError{Op: "dial", Err: errors.New("connection refused")}))
+
+		Convey("Then it is unavailable", func() {
+			So(errors.Is(err, apierrors.ErrUnavailable), ShouldBeTrue)
+		})
+	})
+
+	Convey("Given a store call whose context has ended", t, func() {
+		ctx, cancel := context.WithCancel(ctx)
+		cancel()
+		err := storeError(ctx, &pgconn.PgError{Code: queryCanceled})
+
+		Convey("Then the cancellation is returned rather than a classified error", func() {
+			So(errors.Is(err, context.Canceled), ShouldBeTrue)
+			var storeErr *apierrors.StoreError
+			So(errors.As(err, &storeErr), ShouldBeFalse)
+		})
+	})
+
+	Convey("Given any other error", t, func() {
+		err := errors.New("failed to unmarshal boundary")
+
+		Convey("Then it is returned unchanged", func() {
+			So(storeError(ctx, err), ShouldEqual, err)
+		})
+	})
+}
@@ -0,0 +1,72 @@
+package rds
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ONSdigital/dp-areas-api/fixtures"
+	"github.com/ONSdigital/log.go/v2/log"
+)
+
+// LoadFixtures writes a fixture set in one transaction, parents before children, and then rebuilds area_closure.
+// Rows that already exist are updated, so loading the same set twice is harmless.
+func (r *RDS) LoadFixtures(ctx context.Context, set *fixtures.Set) error {
+	tx, err := r.conn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %+v", err)
+	}
+
+	for _, areaType := range set.AreaTypes {
+		if _, err = tx.Exec(ctx, areaTypeInsertTransaction, areaType.Name, areaType.Name); err != nil {
+			tx.Rollback(ctx)
+			return fmt.Errorf("failed to insert area type %s: %+v", areaType.Name, err)
+		}
+	}
+
+	for _, relationshipType := range set.RelationshipTypes {
+		if _, err = tx.Exec(ctx, relationshipTypeInsertTransaction, relationshipType.Name, relationshipType.Name); err != nil {
+			tx.Rollback(ctx)
+			return fmt.Errorf("failed to insert relationship type %s: %+v", relationshipType.Name, err)
+		}
+	}
+
+	for _, area := range set.Areas {
+		_, err = tx.Exec(ctx, areaInsertTransaction, area.Code, area.ActiveFrom, area.ActiveTo, area.AreaType, area.GeometricArea, bool(area.Visible))
+		if err != nil {
+			tx.Rollback(ctx)
+			return fmt.Errorf("failed to insert area %s: %+v", area.Code, err)
+		}
+	}
+
+	for _, name := range set.AreaNames {
+		_, err = tx.Exec(ctx, areaNameInsertTransaction, name.AreaCode, name.Name, name.ActiveFrom, name.ActiveTo)
+		if err != nil {
+			tx.Rollback(ctx)
+			return fmt.Errorf("failed to insert area name %s: %+v", name.Name, err)
+		}
+	}
+
+	for _, relationship := range set.AreaRelationships {
+		_, err = tx.Exec(ctx, fixtureRelationshipInsert, relationship.AreaCode, relationship.RelAreaCode, relationship.RelType)
+		if err != nil {
+			tx.Rollback(ctx)
+			return fmt.Errorf("failed to insert area relationship %s/%s: %+v", relationship.AreaCode, relationship.RelAreaCode, err)
+		}
+	}
+
+	for _, boundary := range set.Boundaries {
+		_, err = tx.Exec(ctx, boundariesInsertTransaction, boundary.AreaID, boundary.CentroidBng, boundary.Centroid, boundary.Boundary)
+		if err != nil {
+			tx.Rollback(ctx)
+			return fmt.Errorf("failed to insert boundary %s: %+v", boundary.AreaID, err)
+		}
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		tx.Rollback(ctx)
+		return fmt.Errorf("failed to commit: %+v", err)
+	}
+	log.Info(ctx, "fixtures loaded successfully", log.Data{"version": set.Version, "areas": len(set.Areas)})
+
+	return r.RebuildAreaClosure(ctx)
+}
@@ -0,0 +1,92 @@
+package rds
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ONSdigital/dp-areas-api/fixtures"
+	pgxMock "github.com/ONSdigital/dp-areas-api/pgx/mock"
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestRDS_LoadFixtures(t *testing.T) {
+	set := &fixtures.Set{
+		Version:           "v1",
+		AreaTypes:         []fixtures.AreaType{{Name: "Country"}},
+		RelationshipTypes: []fixtures.RelationshipType{{Name: "child"}},
+		Areas:             []fixtures.Area{{Code: "E92000001", AreaType: "Country", Visible: true}, {Code: "E12000003", AreaType: "Country"}},
+		AreaNames:         []fixtures.AreaName{{AreaCode: "E92000001", Name: "England"}},
+		AreaRelationships: []fixtures.AreaRelationship{{AreaCode: "E92000001", RelAreaCode: "E12000003", RelType: "child"}},
+		Boundaries:        []fixtures.Boundary{{AreaID: "E92000001"}},
+	}
+
+	Convey("Given a fixture set", t, func() {
+		transactionMock := &pgxMock.PGXTransactionMock{
+			ExecFunc: func(ctx context.Context, sql string, arguments ...interface{}) (pgconn.CommandTag, error) {
+				return nil, nil
+			},
+			CommitFunc: func(ctx context.Context) error { return nil },
+		}
+
+		rds := RDS{conn: &pgxMock.PGXPoolMock{
+			BeginFunc: func(ctx context.Context) (pgx.Tx, error) { return transactionMock, nil },
+		}}
+
+		Convey("When it is loaded", func() {
+			err := rds.LoadFixtures(context.Background(), set)
+
+			Convey("Then parents are written before children and the closure is rebuilt", func() {
+				So(err, ShouldBeNil)
+				var queries []string
+				for _, call := range transactionMock.ExecCalls() {
+					queries = append(queries, call.SQL)
+				}
+				So(queries, ShouldResemble, []string{
+					areaTypeInsertTransaction,
+					relationshipTypeInsertTransaction,
+					areaInsertTransaction,
+					areaInsertTransaction,
+					areaNameInsertTransaction,
+					fixtureRelationshipInsert,
+					boundariesInsertTransaction,
+					deleteAreaClosure,
+					rebuildAreaClosure,
+				})
+				So(transactionMock.ExecCalls()[2].Arguments, ShouldResemble, []interface{}{"E92000001", (*string)(nil), (*string)(nil), "Country", "", true})
+				So(transactionMock.ExecCalls()[5].Arguments, ShouldResemble, []interface{}{"E92000001", "E12000003", "child"})
+				So(transactionMock.CommitCalls(), ShouldHaveLength, 2)
+			})
+		})
+	})
+
+	Convey("Given a fixture row that violates a constraint", t, func() {
+		transactionMock := &pgxMock.PGXTransactionMock{
+			ExecFunc: func(ctx context.Context, sql string, arguments ...interface{}) (pgconn.CommandTag, error) {
+				if sql == areaNameInsertTransaction {
+					return nil, errors.New("violates foreign key constraint")
+				}
+				return nil, nil
+			},
+			RollbackFunc: func(ctx context.Context) error { return nil },
+		}
+
+		rds := RDS{conn: &pgxMock.PGXPoolMock{
+			BeginFunc: func(ctx context.Context) (pgx.Tx, error) { return transactionMock, nil },
+		}}
+
+		Convey("When it is loaded", func() {
+			err := rds.LoadFixtures(context.Background(), set)
+
+			Convey("Then the error is returned and nothing is committed", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldContainSubstring, "England")
+				So(transactionMock.RollbackCalls(), ShouldHaveLength, 1)
+				So(transactionMock.CommitCalls(), ShouldHaveLength, 0)
+			})
+		})
+	})
+}
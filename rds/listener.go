@@ -0,0 +1,108 @@
+package rds
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ONSdigital/dp-areas-api/config"
+	"github.com/ONSdigital/dp-areas-api/pgx"
+	"github.com/ONSdigital/log.go/v2/log"
+)
+
+// AreaChangeHandler is told of the areas changed by any instance
+type AreaChangeHandler interface {
+	Invalidate(areaCodes ...string)
+	Purge()
+}
+
+// Listener keeps an AreaChangeHandler, such as the cache of area reads, up to date with the writes of every instance.
+// Each write notifies the areas it changed in its transaction, so a notification is only received once the write has
+// committed. The listener reconnects after losing its connection, and as notifications sent while it was disconnected
+// are lost it has the handler purge everything whenever it starts listening.
+type Listener struct {
+	acquire       func(ctx context.Context) (pgx.PGXListenConn, error)
+	closePool     func()
+	handler       AreaChangeHandler
+	retryInterval time.Duration
+	cancel        context.CancelFunc
+	done          chan struct{}
+}
+
+// NewListener creates a listener on the writer for handler
+func NewListener(ctx context.Context, cfg *config.Config, handler AreaChangeHandler) (*Listener, error) {
+	pool, err := pgx.NewListenPool(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &Listener{
+		acquire:       pool.Acquire,
+		closePool:     pool.Close,
+		handler:       handler,
+		retryInterval: cfg.CacheListenerRetryInterval,
+	}, nil
+}
+
+// Start listens in a new goroutine until the listener is closed
+func (l *Listener) Start(ctx context.Context) {
+	ctx, l.cancel = context.WithCancel(ctx)
+	l.done = make(chan struct{})
+	go l.run(ctx)
+}
+
+// Close stops listening, waiting for the listener's goroutine to finish until ctx is done
+func (l *Listener) Close(ctx context.Context) error {
+	if l.cancel != nil {
+		l.cancel()
+		select {
+		case <-l.done:
+		case <-ctx.Done():
+			return fmt.Errorf("failed to stop area change listener: %w", ctx.Err())
+		}
+	}
+	if l.closePool != nil {
+		l.closePool()
+	}
+	return nil
+}
+
+func (l *Listener) run(ctx context.Context) {
+	defer close(l.done)
+	for {
+		err := l.listen(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		log.Error(ctx, "area change listener disconnected", err, log.Data{"retry_interval": l.retryInterval.String()})
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(l.retryInterval):
+		}
+	}
+}
+
+// listen holds a connection listening for area changes until it fails or ctx is done
+func (l *Listener) listen(ctx context.Context) error {
+	conn, err := l.acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to connect: %w", err)
+	}
+	defer conn.Release()
+
+	_, err = conn.Exec(ctx, listenAreasChanged)
+	if err != nil {
+		return fmt.Errorf("failed to listen: %w", err)
+	}
+	l.handler.Purge()
+	log.Info(ctx, "listening for area changes", log.Data{"channel": areasChangedChannel})
+
+	for {
+		notification, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to wait for notification: %w", err)
+		}
+		l.handler.Invalidate(notification.Payload)
+	}
+}
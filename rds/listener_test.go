@@ -0,0 +1,149 @@
+package rds
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ONSdigital/dp-areas-api/pgx"
+	pgxMock "github.com/ONSdigital/dp-areas-api/pgx/mock"
+	"github.com/jackc/pgconn"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// handlerFake reports each call made by the listener on events
+type handlerFake struct {
+	events chan string
+}
+
+func (h handlerFake) Invalidate(areaCodes ...string) {
+	for _, areaCode := range areaCodes {
+		h.events <- "invalidate " + areaCode
+	}
+}
+
+func (h handlerFake) Purge() {
+	h.events <- "purge"
+}
+
+// nextEvent waits for the next call made on h, or returns "timeout"
+func (h handlerFake) nextEvent() string {
+	select {
+	case event := <-h.events:
+		return event
+	case <-time.After(time.Second):
+		return "timeout"
+	}
+}
+
+// newListenConnMock returns a connection receiving the payloads sent on notifications until it is closed, when the
+// connection is lost
+func newListenConnMock(notifications chan string) *pgxMock.PGXListenConnMock {
+	return &pgxMock.PGXListenConnMock{
+		ExecFunc: func(ctx context.Context, sql string, arguments ...interface{}) (pgconn.CommandTag, error) {
+			return nil, nil
+		},
+		WaitForNotificationFunc: func(ctx context.Context) (*pgconn.Notification, error) {
+			select {
+			case payload, ok := <-notifications:
+				if !ok {
+					return nil, errors.New("connection lost")
+				}
+				return &pgconn.Notification{Channel: areasChangedChannel, Payload: payload}, nil
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		},
+		ReleaseFunc: func() {},
+	}
+}
+
+func TestListener(t *testing.T) {
+	ctx := context.Background()
+
+	Convey("Given a listener on a connection", t, func() {
+		handler := handlerFake{events: make(chan string, 10)}
+		notifications := make(chan string)
+		conn := newListenConnMock(notifications)
+		poolClosed := false
+		listener := &Listener{
+			acquire:       func(ctx context.Context) (pgx.PGXListenConn, error) { return conn, nil },
+			closePool:     func() { poolClosed = true },
+			handler:       handler,
+			retryInterval: time.Millisecond,
+		}
+
+		Convey("When it is started and an area change is notified", func() {
+			listener.Start(ctx)
+			So(handler.nextEvent(), ShouldEqual, "purge")
+			notifications <- "E08000019"
+
+			Convey("Then the changed area is invalidated", func() {
+				So(handler.nextEvent(), ShouldEqual, "invalidate E08000019")
+				So(conn.ExecCalls(), ShouldHaveLength, 1)
+				So(conn.ExecCalls()[0].SQL, ShouldEqual, listenAreasChanged)
+				So(listener.Close(ctx), ShouldBeNil)
+			})
+
+			Convey("Then closing it releases the connection and closes the pool", func() {
+				So(handler.nextEvent(), ShouldEqual, "invalidate E08000019")
+				So(listener.Close(ctx), ShouldBeNil)
+				So(conn.ReleaseCalls(), ShouldHaveLength, 1)
+				So(poolClosed, ShouldBeTrue)
+			})
+		})
+
+		Convey("When the connection is lost", func() {
+			reconnected := make(chan string)
+			listener.acquire = func(ctx context.Context) (pgx.PGXListenConn, error) {
+				if len(conn.ReleaseCalls()) == 0 {
+					return conn, nil
+				}
+				return newListenConnMock(reconnected), nil
+			}
+			listener.Start(ctx)
+			So(handler.nextEvent(), ShouldEqual, "purge")
+			close(notifications)
+
+			Convey("Then it reconnects, purges the changes it may have missed and carries on invalidating", func() {
+				So(handler.nextEvent(), ShouldEqual, "purge")
+				reconnected <- "E12000003"
+				So(handler.nextEvent(), ShouldEqual, "invalidate E12000003")
+				So(conn.ReleaseCalls(), ShouldHaveLength, 1)
+				So(listener.Close(ctx), ShouldBeNil)
+			})
+		})
+
+		Convey("When it cannot connect", func() {
+			attempts := make(chan struct{}, 10)
+			listener.acquire = func(ctx context.Context) (pgx.PGXListenConn, error) {
+				select {
+				case attempts <- struct{}{}:
+				default:
+				}
+				return nil, errors.New("connection refused")
+			}
+			listener.Start(ctx)
+
+			Convey("Then it retries until it is closed", func() {
+				<-attempts
+				<-attempts
+				So(listener.Close(ctx), ShouldBeNil)
+				So(handler.events, ShouldBeEmpty)
+				So(poolClosed, ShouldBeTrue)
+			})
+		})
+	})
+
+	Convey("Given a listener that was never started", t, func() {
+		poolClosed := false
+		listener := &Listener{closePool: func() { poolClosed = true }}
+
+		Convey("Then closing it closes the pool", func() {
+			So(listener.Close(ctx), ShouldBeNil)
+			So(poolClosed, ShouldBeTrue)
+		})
+	})
+}
@@ -0,0 +1,112 @@
+package rds
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"syscall"
+	"testing"
+
+	"github.com/ONSdigital/dp-areas-api/api"
+	"github.com/ONSdigital/dp-areas-api/config"
+	"github.com/ONSdigital/dp-areas-api/models"
+	pgxMock "github.com/ONSdigital/dp-areas-api/pgx/mock"
+	"github.com/ONSdigital/dp-areas-api/service/healthcheck"
+	health "github.com/ONSdigital/dp-healthcheck/healthcheck"
+	"github.com/gorilla/mux"
+	"github.com/jackc/pgx/v4"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// failingPool returns a pool that serves England until down is set, after which every call fails as when the database
+// cannot be reached
+func failingPool(down *bool) *pgxMock.PGXPoolMock {
+	errConnRefused := &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}
+	england, country, visible := "England", "Country", true
+
+	return &pgxMock.PGXPoolMock{
+		SendBatchFunc: func(ctx context.Context, b *pgx.Batch) pgx.BatchResults {
+			if *down {
+				return &pgxMock.PGXBatchResultsMock{
+					QueryRowFunc: func() pgx.Row {
+						return &pgxMock.PGXRowMock{ScanFunc: func(dest ...interface{}) error { return errConnRefused }}
+					},
+					CloseFunc: func() error { return errConnRefused },
+				}
+			}
+			rowCalls := 0
+			return &pgxMock.PGXBatchResultsMock{
+				QueryRowFunc: func() pgx.Row {
+					return &pgxMock.PGXRowMock{ScanFunc: func(dest ...interface{}) error {
+						if rowCalls++; rowCalls == 1 {
+							return scanValues(dest, []interface{}{"E92000001", &england, &visible, &country, 1})
+						}
+						return scanValues(dest, []interface{}{""})
+					}}
+				},
+				QueryMock: func() (pgx.Rows, error) { return rowsOf(), nil },
+				CloseFunc: func() error { return nil },
+			}
+		},
+		PingFunc: func(ctx context.Context) error {
+			if *down {
+				return errConnRefused
+			}
+			return nil
+		},
+	}
+}
+
+func TestRDS_Outage(t *testing.T) {
+	ctx := context.Background()
+
+	Convey("Given the API serving an area from a database that then becomes unreachable", t, func() {
+		down := false
+		store := &RDS{conn: failingPool(&down)}
+		cfg := &config.Config{FixturesVersion: "v1", StaleResponsesSize: 10}
+		areaApi, err := api.Setup(ctx, cfg, mux.NewRouter(), store, nil)
+		So(err, ShouldBeNil)
+
+		getArea := func(areaCode string) *httptest.ResponseRecorder {
+			r := httptest.NewRequest(http.MethodGet, "http://localhost:25500/v1/areas/"+areaCode, nil)
+			r.Header.Set(models.AcceptLanguageHeaderName, "en")
+			w := httptest.NewRecorder()
+			areaApi.Router.ServeHTTP(w, r)
+			return w
+		}
+		good := getArea("E92000001")
+		So(good.Code, ShouldEqual, http.StatusOK)
+		down = true
+
+		Convey("When the area is read again", func() {
+			w := getArea("E92000001")
+
+			Convey("Then the last good response is served as stale", func() {
+				So(w.Code, ShouldEqual, http.StatusOK)
+				So(w.Body.String(), ShouldEqual, good.Body.String())
+				So(w.Header().Get("Warning"), ShouldContainSubstring, "Response is Stale")
+			})
+
+			Convey("Then an area that was never read fails as unavailable", func() {
+				So(getArea("W92000004").Code, ShouldEqual, http.StatusServiceUnavailable)
+			})
+		})
+
+		Convey("When the database is health checked", func() {
+			readerState := health.NewCheckState("dp-areas-api-test")
+			readerErr := healthcheck.RDSReaderHealthCheck(store, areaApi.StaleResponses)(ctx, readerState)
+			writerState := health.NewCheckState("dp-areas-api-test")
+			writerErr := healthcheck.RDSHealthCheck(store)(ctx, writerState)
+
+			Convey("Then reads are reported degraded and writes critical", func() {
+				So(readerErr, ShouldNotBeNil)
+				So(readerState.Status(), ShouldEqual, health.StatusWarning)
+				So(readerState.Message(), ShouldStartWith, "degraded")
+				So(writerErr, ShouldNotBeNil)
+				So(writerState.Status(), ShouldEqual, health.StatusCritical)
+			})
+		})
+	})
+}
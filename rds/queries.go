@@ -1,32 +1,223 @@
 package rds
 
-import "fmt"
+import (
+	"fmt"
+
+	"github.com/ONSdigital/dp-areas-api/metrics"
+)
+
+// activeArea matches areas (aliased as a) that have not been retired, i.e. are still visible or have no end date in the past
+const activeArea = "(a.visible is not false or a.active_to is null or a.active_to > now())"
+
+// areasChangedChannel is notified of the code of every area a write may have changed, see Listener
+const areasChangedChannel = "areas_changed"
 
 const (
-	getArea = `select code, area_name.name, geometric_area, visible, area_type.name
-               from area
-               left join area_name on area.code = area_name.area_code
-               left join area_type on area.area_type_id = area_type.id
-               where code = $1`
-	getAreaCode                       = "select code from area where code = $1"
+	getArea = `select a.code, area_name.name, a.geometric_area, a.visible, area_type.name, a.version, a.updated_at
+               from area as a
+               left join area_name on a.code = area_name.area_code
+               left join area_type on a.area_type_id = area_type.id
+               where a.code = $1 and ($2 or ` + activeArea + `)`
+	getAreaSummary = `select a.code, area_name.name, a.visible, area_type.name, a.version, a.updated_at,
+               greatest(a.updated_at, (select max(anc.updated_at) from area_closure as ac, area as anc
+                   where ac.ancestor = anc.code and ac.descendant = a.code and ac.depth > 0))
+               from area as a
+               left join area_name on a.code = area_name.area_code
+               left join area_type on a.area_type_id = area_type.id
+               where a.code = $1 and ($2 or ` + activeArea + `)`
+	getAreaCode                       = "select a.code from area as a where a.code = $1 and ($2 or " + activeArea + ")"
 	getAreaType                       = "select id from area_type where name = $1"
-	getRelationShipAreas              = "select an.area_code, an.name from area_name as an, area_relationship as ar where ar.rel_area_code = an.area_code and ar.area_code =$1"
-	getRelationShipAreasWithParameter = "select an.area_code, an.name from area_name as an, area_relationship as ar where ar.rel_area_code = an.area_code and ar.area_code = $1 and ar.rel_type_id = (select id from relationship_type where name = $2)"
-	upsertAreaName                    = "insert into area_name(area_code, name, active_from, active_to) values($1, $2, $3, $4) on conflict(name) do update set active_from=$3,active_to=$4"
+	getRelationShipAreas              = "select an.area_code, an.name from area_name as an, area_relationship as ar, area as a where ar.rel_area_code = an.area_code and an.area_code = a.code and ar.area_code = $1 and ($2 or " + activeArea + ")"
+	getRelationShipAreasWithParameter = "select an.area_code, an.name from area_name as an, area_relationship as ar, area as a where ar.rel_area_code = an.area_code and an.area_code = a.code and ar.area_code = $1 and ar.rel_type_id = (select id from relationship_type where name = $2) and ($3 or " + activeArea + ")"
+	upsertAreaName                    = "with updated as (update area_name set active_from=$3,active_to=$4 where area_code = $1 and name = $2 returning id) insert into area_name(area_code, name, active_from, active_to) select $1, $2, $3, $4 where not exists (select * from updated)"
 	insertArea                        = "insert into area(code, active_from, active_to, geometric_area, area_type_id, visible, land_hectares) values($1, $2, $3, $4, $5, $6, $7)"
-	updateAreaOnConflict              = "on conflict(code) do update set active_from=$2, active_to=$3,geometric_area=$4,area_type_id=$5, visible=$6, land_hectares=$7 returning (xmax = 0) as inserted"
+	updateAreaOnConflict              = "on conflict(code) do update set active_from=$2, active_to=$3,geometric_area=$4,area_type_id=$5, visible=$6, land_hectares=$7, version=area.version + 1, updated_at=now() returning (xmax = 0) as inserted"
 	areaTypeInsertTransaction         = "insert into area_type(name) select $1 where not exists (select * from area_type where name = $2)"
 	areaInsertTransaction             = `insert into area(code, active_from, active_to, area_type_id, geometric_area, visible)
-                                 VALUES($1, $2, $3, $4, $5, $6)
+                                 VALUES($1, $2, $3, (select id from area_type where name = $4), $5, $6)
                                  on conflict (code) do update
-                                 set active_from=$2,active_to=$3, area_type_id=$4,geometric_area=$5`
+                                 set active_from=$2,active_to=$3, area_type_id=excluded.area_type_id,geometric_area=$5`
 	relationshipTypeInsertTransaction = "insert into relationship_type(name) select $1 where not exists (select * from relationship_type where name = $2)"
 
-	areaNameInsertTransaction         = "insert into area_name(area_code, name, active_from, active_to) VALUES($1, $2, $3, $4)"
-	areaRelationshipInsertTransaction = "insert into area_relationship(area_code, rel_area_code, rel_type_id) VALUES($1, $2, $3) on conflict(area_code, rel_area_code) do update set rel_type_id = $3"
+	areaNameInsertTransaction         = "insert into area_name(area_code, name, active_from, active_to) select $1, $2, $3, $4 where not exists (select * from area_name where area_code = $1 and name = $2 and active_from is not distinct from $3)"
+	areaRelationshipInsertTransaction = "insert into area_relationship(area_code, rel_area_code, rel_type_id) VALUES($1, $2, $3) on conflict(area_code, rel_area_code, rel_type_id) do nothing"
+	fixtureRelationshipInsert         = "insert into area_relationship(area_code, rel_area_code, rel_type_id) VALUES($1, $2, (select id from relationship_type where name = $3)) on conflict(area_code, rel_area_code, rel_type_id) do nothing"
 	getRelationShipId                 = "select id from relationship_type where name = 'child'"
-	getAncestors                      = "with recursive ancestors as (select area_code from area_relationship where rel_area_code = $1 and rel_type_id = (select id from relationship_type where name = 'child') union select ar.area_code from area_relationship ar inner join ancestors a on a.area_code = ar.rel_area_code ) select a.area_code, an.name from ancestors as a, area_name as an where a.area_code = an.area_code"
+	getAncestors                      = "select ac.ancestor, an.name from area_closure as ac, area_name as an where ac.ancestor = an.area_code and ac.descendant = $1 and ac.depth > 0 order by ac.depth"
+	getChildAreas                     = "select an.area_code, an.name from area_closure as ac, area_name as an, area as a where ac.descendant = an.area_code and an.area_code = a.code and ac.ancestor = $1 and ac.depth = 1 and ($2 or " + activeArea + ")"
+	getAreaNames                      = "select name, active_from, active_to from area_name where area_code = $1 order by active_from nulls first, name"
+	getAreaBoundary                   = "select geometric_area from area where code = $1"
+	listenAreasChanged                = "listen " + areasChangedChannel
+	notifyAreaChanged                 = "select pg_notify('" + areasChangedChannel + "', $1)"
+	notifyDescendantAreasChanged      = "select pg_notify('" + areasChangedChannel + "', descendant) from area_closure where ancestor = $1 and depth > 0"
+	getAreaVersionForUpdate           = "select version from area where code = $1 for update"
+	renameAreaName                    = "update area_name set name = $3 where area_code = $1 and name = $2"
+	countLiveChildAreas               = "select count(*) from area_closure as ac, area as a where ac.descendant = a.code and ac.ancestor = $1 and ac.depth = 1 and " + activeArea
+	getAreaRetirementForUpdate        = "select a.version, " + activeArea + " from area as a where a.code = $1 for update"
+	retireArea                        = "update area as a set active_to = now(), visible = false, version = a.version + 1, updated_at = now() where a.code = $1 and " + activeArea
+	retireDescendantAreas             = "update area as a set active_to = now(), visible = false, version = a.version + 1, updated_at = now() where a.code in (select descendant from area_closure where ancestor = $1 and depth > 0) and " + activeArea
+	insertAreaClosureSelf             = "insert into area_closure(ancestor, descendant, depth) values($1, $1, 0) on conflict(ancestor, descendant) do nothing"
 	boundariesInsertTransaction       = "insert into boundaries(area_id, centroid_bng, centroid, boundary) values($1, $2, $3, $4) on conflict(area_id) do update set centroid_bng=$2,centroid=$3,boundary=$4"
+	insertAreaClosurePaths            = `insert into area_closure(ancestor, descendant, depth)
+                                 select p.ancestor, c.descendant, p.depth + c.depth + 1
+                                 from area_closure as p, area_closure as c
+                                 where p.descendant = $1 and c.ancestor = $2
+                                 on conflict(ancestor, descendant) do update set depth = least(area_closure.depth, excluded.depth)`
+	isAreaDescendant           = "select exists(select * from area_closure where ancestor = $1 and descendant = $2)"
+	deleteOtherAreaParents     = "delete from area_relationship where rel_area_code = $1 and area_code <> $2 and rel_type_id = $3"
+	deleteAreaClosureAncestors = `delete from area_closure
+                                 where descendant in (select descendant from area_closure where ancestor = $1)
+                                 and ancestor in (select ancestor from area_closure where descendant = $1 and depth > 0)`
+	deleteAreaClosure  = "delete from area_closure"
+	rebuildAreaClosure = `insert into area_closure(ancestor, descendant, depth)
+                                 with recursive paths(ancestor, descendant, depth) as (
+                                     select code, code, 0 from area
+                                     union all
+                                     select ar.area_code, p.descendant, p.depth + 1
+                                     from area_relationship as ar, paths as p
+                                     where ar.rel_area_code = p.ancestor
+                                     and ar.rel_type_id = (select id from relationship_type where name = 'child')
+                                     and p.depth < $1
+                                 )
+                                 select ancestor, descendant, min(depth) from paths group by ancestor, descendant`
+	getAreaForUpdate = `select a.code, a.active_from, a.active_to, a.geometric_area, a.visible, a.land_hectares, area_type.name, area_name.name, area_name.active_from, area_name.active_to, a.version,
+                                 (select min(ar.area_code) from area_relationship as ar
+                                     where ar.rel_area_code = a.code
+                                     and ar.rel_type_id = (select id from relationship_type where name = 'child'))
+                                 from area as a
+                                 left join area_name on a.code = area_name.area_code
+                                 left join area_type on a.area_type_id = area_type.id
+                                 where a.code = $1
+                                 for update of a`
+	trySchemaLock  = "select pg_try_advisory_xact_lock($1)"
+	getLiveTables  = "select table_name from information_schema.tables where table_schema = current_schema() and table_type = 'BASE TABLE' order by table_name"
+	getLiveColumns = `select table_name, column_name, data_type, character_maximum_length, is_nullable = 'YES'
+                                 from information_schema.columns
+                                 where table_schema = current_schema()
+                                 order by table_name, ordinal_position`
+	getLiveConstraints = `select tc.table_name, tc.constraint_name, tc.constraint_type, kcu.column_name,
+                                 coalesce(rku.table_name, ''), coalesce(rku.column_name, ''), coalesce(rc.delete_rule, '')
+                                 from information_schema.table_constraints as tc
+                                 join information_schema.key_column_usage as kcu
+                                 on kcu.constraint_name = tc.constraint_name and kcu.table_schema = tc.table_schema
+                                 left join information_schema.referential_constraints as rc
+                                 on rc.constraint_name = tc.constraint_name and rc.constraint_schema = tc.table_schema
+                                 left join information_schema.key_column_usage as rku
+                                 on rku.constraint_name = rc.unique_constraint_name and rku.constraint_schema = rc.unique_constraint_schema
+                                 and rku.ordinal_position = kcu.position_in_unique_constraint
+                                 where tc.table_schema = current_schema() and tc.constraint_type in ('PRIMARY KEY', 'UNIQUE', 'FOREIGN KEY')
+                                 order by tc.table_name, tc.constraint_name, kcu.ordinal_position`
+	getLiveChecks = `select table_name, constraint_name from information_schema.table_constraints
+                                 where table_schema = current_schema() and constraint_type = 'CHECK' and constraint_name not like '%_not_null'
+                                 order by table_name, constraint_name`
+	getLiveIndexes = "select tablename, indexname, indexdef from pg_indexes where schemaname = current_schema() order by tablename, indexname"
 )
 
 var upsertArea = fmt.Sprintf("%s %s", insertArea, updateAreaOnConflict)
+
+// Queries that record the audit log. An entry is inserted with the state of its area before a write, and completed
+// with the state after it once the write is done, so that both are taken in the write's transaction. An entry only has
+// no after state until then, and entries of other transactions are not visible until they commit, so a transaction
+// completes only its own entries.
+var (
+	insertAreaAudit = `insert into area_audit(area_code, actor, operation, before)
+                                 select $1::varchar, $2::varchar, case when b.state is null then 'create' else $3::varchar end, b.state
+                                 from (select ` + areaAuditState("$1::varchar") + ` as state) as b`
+	insertDescendantAreaAudits = `insert into area_audit(area_code, actor, operation, before)
+                                 select a.code, $2::varchar, 'retire', ` + areaAuditState("a.code") + `
+                                 from area as a
+                                 where a.code in (select descendant from area_closure where ancestor = $1 and depth > 0) and ` + activeArea
+	setAreaAuditAfter            = "update area_audit as au set after = " + areaAuditState("au.area_code") + " where au.area_code = $1 and au.after is null"
+	setDescendantAreaAuditsAfter = "update area_audit as au set after = " + areaAuditState("au.area_code") + " where au.area_code in (select descendant from area_closure where ancestor = $1 and depth > 0) and au.after is null"
+	countAreaAudit               = "select count(*) from area_audit where area_code = $1 and ($2::timestamptz is null or changed_at >= $2) and ($3::timestamptz is null or changed_at < $3)"
+	getAreaAudit                 = `select id, area_code, actor, operation, changed_at, before, after
+                                 from area_audit
+                                 where area_code = $1 and ($2::timestamptz is null or changed_at >= $2) and ($3::timestamptz is null or changed_at < $3)
+                                 order by changed_at desc, id desc
+                                 offset $4 limit $5`
+)
+
+// areaAuditState selects the area with the given code as a json models.AreaAuditState, or null when there is no such
+// area. Timestamps are converted to timestamptz so that they are encoded with their zone.
+func areaAuditState(code string) string {
+	return `(select jsonb_build_object(
+                                     'code', s.code,
+                                     'area_type', (select name from area_type where id = s.area_type_id),
+                                     'parent_code', (select min(ar.area_code) from area_relationship as ar
+                                         where ar.rel_area_code = s.code and ar.area_code <> s.code
+                                         and ar.rel_type_id = (select id from relationship_type where name = 'child')),
+                                     'active_from', s.active_from at time zone 'UTC',
+                                     'active_to', s.active_to at time zone 'UTC',
+                                     'visible', s.visible,
+                                     'area_hectares', s.land_hectares,
+                                     'geometry', s.geometric_area,
+                                     'version', s.version,
+                                     'names', coalesce((select jsonb_agg(jsonb_build_object(
+                                         'name', an.name,
+                                         'active_from', an.active_from at time zone 'UTC',
+                                         'active_to', an.active_to at time zone 'UTC'
+                                     ) order by an.active_from nulls first, an.name) from area_name as an where an.area_code = s.code), '[]'::jsonb))
+                                 from area as s where s.code = ` + code + `)`
+}
+
+// queryNames names the queries above in the database metrics. Queries that are only parts of others are left out.
+var queryNames = map[string]string{
+	getArea:                           "getArea",
+	getAreaSummary:                    "getAreaSummary",
+	getAreaCode:                       "getAreaCode",
+	getAreaType:                       "getAreaType",
+	getRelationShipAreas:              "getRelationShipAreas",
+	getRelationShipAreasWithParameter: "getRelationShipAreasWithParameter",
+	upsertAreaName:                    "upsertAreaName",
+	upsertArea:                        "upsertArea",
+	areaTypeInsertTransaction:         "areaTypeInsertTransaction",
+	areaInsertTransaction:             "areaInsertTransaction",
+	relationshipTypeInsertTransaction: "relationshipTypeInsertTransaction",
+	areaNameInsertTransaction:         "areaNameInsertTransaction",
+	areaRelationshipInsertTransaction: "areaRelationshipInsertTransaction",
+	fixtureRelationshipInsert:         "fixtureRelationshipInsert",
+	getRelationShipId:                 "getRelationShipId",
+	getAncestors:                      "getAncestors",
+	getChildAreas:                     "getChildAreas",
+	getAreaNames:                      "getAreaNames",
+	getAreaBoundary:                   "getAreaBoundary",
+	listenAreasChanged:                "listenAreasChanged",
+	notifyAreaChanged:                 "notifyAreaChanged",
+	notifyDescendantAreasChanged:      "notifyDescendantAreasChanged",
+	getAreaVersionForUpdate:           "getAreaVersionForUpdate",
+	renameAreaName:                    "renameAreaName",
+	countLiveChildAreas:               "countLiveChildAreas",
+	getAreaRetirementForUpdate:        "getAreaRetirementForUpdate",
+	retireArea:                        "retireArea",
+	retireDescendantAreas:             "retireDescendantAreas",
+	insertAreaClosureSelf:             "insertAreaClosureSelf",
+	boundariesInsertTransaction:       "boundariesInsertTransaction",
+	insertAreaClosurePaths:            "insertAreaClosurePaths",
+	isAreaDescendant:                  "isAreaDescendant",
+	deleteOtherAreaParents:            "deleteOtherAreaParents",
+	deleteAreaClosureAncestors:        "deleteAreaClosureAncestors",
+	deleteAreaClosure:                 "deleteAreaClosure",
+	rebuildAreaClosure:                "rebuildAreaClosure",
+	getAreaForUpdate:                  "getAreaForUpdate",
+	trySchemaLock:                     "trySchemaLock",
+	getLiveTables:                     "getLiveTables",
+	getLiveColumns:                    "getLiveColumns",
+	getLiveConstraints:                "getLiveConstraints",
+	getLiveChecks:                     "getLiveChecks",
+	getLiveIndexes:                    "getLiveIndexes",
+	insertAreaAudit:                   "insertAreaAudit",
+	insertDescendantAreaAudits:        "insertDescendantAreaAudits",
+	setAreaAuditAfter:                 "setAreaAuditAfter",
+	setDescendantAreaAuditsAfter:      "setDescendantAreaAuditsAfter",
+	countAreaAudit:                    "countAreaAudit",
+	getAreaAudit:                      "getAreaAudit",
+}
+
+// queryName returns the name of a query in the database metrics, or metrics.OtherQuery for sql that is not one of the
+// queries above, such as that of a schema migration
+func queryName(sql string) string {
+	if name, ok := queryNames[sql]; ok {
+		return name
+	}
+	return metrics.OtherQuery
+}
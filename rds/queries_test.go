@@ -0,0 +1,32 @@
+package rds
+
+import (
+	"testing"
+
+	"github.com/ONSdigital/dp-areas-api/metrics"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestQueryName(t *testing.T) {
+	Convey("Given the queries of the store", t, func() {
+		Convey("Then each is named by the variable that holds it", func() {
+			So(queryName(getArea), ShouldEqual, "getArea")
+			So(queryName(upsertArea), ShouldEqual, "upsertArea")
+			So(queryName(insertAreaAudit), ShouldEqual, "insertAreaAudit")
+		})
+
+		Convey("Then no two have the same name", func() {
+			seen := make(map[string]bool)
+			for _, name := range queryNames {
+				So(seen[name], ShouldBeFalse)
+				seen[name] = true
+			}
+		})
+	})
+
+	Convey("Given sql that is not one of the queries of the store", t, func() {
+		Convey("Then it is named as another query", func() {
+			So(queryName("create table if not exists schema_migrations(version bigint)"), ShouldEqual, metrics.OtherQuery)
+		})
+	})
+}
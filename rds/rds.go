@@ -3,399 +3,969 @@ package rds
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"time"
 
+	"github.com/ONSdigital/dp-areas-api/apierrors"
+	"github.com/ONSdigital/dp-areas-api/auth"
 	"github.com/ONSdigital/dp-areas-api/config"
+	"github.com/ONSdigital/dp-areas-api/fixtures"
+	"github.com/ONSdigital/dp-areas-api/metrics"
+	"github.com/ONSdigital/dp-areas-api/migrations"
 	"github.com/ONSdigital/dp-areas-api/models"
-	"github.com/ONSdigital/dp-areas-api/models/DBRelationalData"
 	"github.com/ONSdigital/dp-areas-api/pgx"
+	"github.com/ONSdigital/dp-areas-api/tracing"
 	"github.com/ONSdigital/log.go/v2/log"
-	"github.com/aws/aws-sdk-go/aws/credentials"
-	"github.com/aws/aws-sdk-go/service/rds/rdsutils"
+	v4 "github.com/jackc/pgx/v4"
 	"github.com/jackc/pgx/v4/pgxpool"
 )
 
+const (
+	childRelationship = "child"
+	// maxClosureDepth guards the closure rebuild against cycles in area_relationship
+	maxClosureDepth = 32
+	// schemaLockID identifies the advisory lock held while the schema is migrated and seeded
+	schemaLockID int64 = 0x6470617265617331
+)
+
+// schemaLockPollInterval is how often an instance retries the schema lock while another instance holds it
+var schemaLockPollInterval = 500 * time.Millisecond
+
 type RDS struct {
-	conn             pgx.PGXPool
-	useLocalPostgres bool
-	loadSampleData   bool
+	conn              pgx.PGXPool
+	reader            pgx.PGXPool
+	useLocalPostgres  bool
+	loadSampleData    bool
+	schemaLockTimeout time.Duration
+	fixturesVersion   string
+	fixturesDir       string
+	queryTimeout      time.Duration
 }
 
 func (r *RDS) Init(ctx context.Context, cfg *config.Config) error {
-	var connectionString string
-	if cfg.DPPostgresLocal {
-		connectionString = cfg.GetLocalDBConnectionString()
-		r.useLocalPostgres = true
-	} else {
-		authToken, err := rdsutils.BuildAuthToken(cfg.GetDBEndpoint(), cfg.AWSRegion, cfg.RDSDBUser, credentials.NewEnvCredentials())
+	rdsConn, err := pgx.NewPool(ctx, cfg, pgx.Writer)
+	if err != nil {
+		log.Error(ctx, "error connecting to rds instance", err, log.Data{"auth": pgx.AuthMode(cfg)})
+		return err
+	}
+
+	var readerConn *pgxpool.Pool
+	if cfg.HasDBReader() {
+		readerConn, err = pgx.NewPool(ctx, cfg, pgx.Reader)
 		if err != nil {
-			log.Error(ctx, "error building auth token for rds connection", err)
+			rdsConn.Close()
+			log.Error(ctx, "error connecting to rds reader instance", err, log.Data{"auth": pgx.AuthMode(cfg)})
 			return err
 		}
-		connectionString = cfg.GetRemoteDBConnectionString(authToken)
-		r.useLocalPostgres = false
-	}
-
-	rdsConn, err := pgxpool.Connect(ctx, connectionString)
-	if err != nil {
-		log.Error(ctx, "error connecting to rds instance", err)
-		return err
 	}
 
+	r.useLocalPostgres = cfg.DPPostgresLocal
 	if cfg.LoadSampleData {
 		r.loadSampleData = true
 	}
-
-	r.conn = rdsConn
+	r.schemaLockTimeout = cfg.SchemaLockTimeout
+	r.fixturesVersion = cfg.FixturesVersion
+	r.fixturesDir = cfg.FixturesDir
+	r.queryTimeout = cfg.QueryTimeout
+
+	metrics.RegisterPool(metrics.WriterPool, rdsConn)
+	r.conn = pgx.NewObservedPool(rdsConn, queryName, metrics.ObserveQuery, tracing.TraceQuery)
+	if readerConn != nil {
+		metrics.RegisterPool(metrics.ReaderPool, readerConn)
+		r.reader = pgx.NewObservedPool(readerConn, queryName, metrics.ObserveQuery, tracing.TraceQuery)
+	}
 	return nil
 }
 
+// Migrator returns a migrator for the service's schema migrations using this connection
+func (r *RDS) Migrator() (*migrations.Migrator, error) {
+	return migrations.New(r.conn)
+}
+
+// queryContext bounds a store call, including every query of its transaction, by the query timeout
+func (r *RDS) queryContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if r.queryTimeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, r.queryTimeout)
+}
+
+// readConn returns the pool that serves reads: the reader when one is configured, otherwise the writer
+func (r *RDS) readConn() pgx.PGXPool {
+	if r.reader != nil {
+		return r.reader
+	}
+	return r.conn
+}
+
 func (r *RDS) Close() {
 	r.conn.Close()
+	if r.reader != nil {
+		r.reader.Close()
+	}
 }
 
-func (r *RDS) ValidateArea(areaCode string) error {
+// ValidateArea returns pgx.ErrNoRows unless the area exists and, when includeInactive is not set, is still active
+func (r *RDS) ValidateArea(ctx context.Context, areaCode string, includeInactive bool) (err error) {
+	ctx, cancel := r.queryContext(ctx)
+	defer cancel()
+	defer func() { err = storeError(ctx, err) }()
+
 	var code string
-	err := r.conn.QueryRow(context.Background(), getAreaCode, areaCode).Scan(&code)
-	return err
+	return r.readConn().QueryRow(ctx, getAreaCode, areaCode, includeInactive).Scan(&code)
 }
 
-func (r *RDS) GetArea(ctx context.Context, areaId string) (*models.AreasDataResults, error) {
+func (r *RDS) GetArea(ctx context.Context, areaId string, includeInactive bool) (_ *models.AreasDataResults, err error) {
+	ctx, cancel := r.queryContext(ctx)
+	defer cancel()
+	defer func() { err = storeError(ctx, err) }()
+
 	area := models.AreasDataResults{}
 	var BoundaryDataBlob string
-	GeometricData := make([][][2]float64, 0)
 
-	err := r.conn.QueryRow(ctx, getArea, areaId).Scan(&area.Code, &area.Name, &BoundaryDataBlob, &area.Visible, &area.AreaType)
+	err = r.readConn().QueryRow(ctx, getArea, areaId, includeInactive).Scan(&area.Code, &area.Name, &BoundaryDataBlob, &area.Visible, &area.AreaType, &area.Version, &area.UpdatedAt)
 	if err != nil {
 		return nil, err
 	}
 
-	if len(BoundaryDataBlob) != 0 {
-		err = json.Unmarshal([]byte(BoundaryDataBlob), &GeometricData)
+	area.GeometricData, err = unmarshalBoundary(BoundaryDataBlob)
+	if err != nil {
+		return nil, err
+	}
 
-		if err != nil {
+	return &area, nil
+}
+
+// unmarshalBoundary decodes an area's stored geometry, returning nil when the area has none
+func unmarshalBoundary(blob string) ([][][2]float64, error) {
+	if len(blob) == 0 {
+		return nil, nil
+	}
+	geometricData := make([][][2]float64, 0)
+	if err := json.Unmarshal([]byte(blob), &geometricData); err != nil {
+		return nil, err
+	}
+	return geometricData, nil
+}
+
+func (r *RDS) GetRelationships(ctx context.Context, areaCode string, relationshipParameter string, includeInactive bool) (_ []*models.AreaBasicData, err error) {
+	ctx, cancel := r.queryContext(ctx)
+	defer cancel()
+	defer func() { err = storeError(ctx, err) }()
+
+	query, args := getRelationShipAreas, []interface{}{areaCode, includeInactive}
+	if relationshipParameter == childRelationship {
+		query = getChildAreas
+	} else if relationshipParameter != "" {
+		query, args = getRelationShipAreasWithParameter, []interface{}{areaCode, relationshipParameter, includeInactive}
+	}
+
+	rows, err := r.readConn().Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var relationships []*models.AreaBasicData
+	for rows.Next() {
+		var rs models.AreaBasicData
+		if err = rows.Scan(&rs.Code, &rs.Name); err != nil {
 			return nil, err
 		}
-		area.GeometricData = GeometricData
+		relationships = append(relationships, &rs)
+	}
+	// a query stopped by its context ends the rows early, which is only reported here
+	if err = rows.Err(); err != nil {
+		return nil, err
 	}
 
-	return &area, nil
+	return relationships, nil
 }
 
-func (r *RDS) GetRelationships(areaCode string, relationshipParameter string) ([]*models.AreaBasicData, error) {
-	var relationships []*models.AreaBasicData
+// BuildTables brings the schema up to date by applying any pending migrations, then seeds local instances with test
+// data. It holds the schema lock throughout, so instances starting together wait for the first to finish and then find
+// nothing left to do.
+func (r *RDS) BuildTables(ctx context.Context) error {
+	return r.WithSchemaLock(ctx, r.buildTables)
+}
 
-	if relationshipParameter == "" {
+// WithSchemaLock runs fn while holding a Postgres advisory lock, so that only one instance at a time migrates or seeds
+// the database. If another instance holds the lock it waits up to the schema lock timeout, returning
+// apierrors.ErrSchemaLockTimeout if the lock is not released in time. The lock is held by a transaction on one pool
+// connection, so fn must be able to use another; pgx.NewPool refuses pools of fewer than two connections.
+func (r *RDS) WithSchemaLock(ctx context.Context, fn func(ctx context.Context) error) error {
+	tx, err := r.conn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	// ending the transaction releases the lock
+	defer tx.Rollback(ctx)
 
-		rows, err := r.conn.Query(context.Background(), getRelationShipAreas, areaCode)
+	deadline := time.Now().Add(r.schemaLockTimeout)
+	for {
+		var acquired bool
+		err = tx.QueryRow(ctx, trySchemaLock, schemaLockID).Scan(&acquired)
 		if err != nil {
-			return nil, err
+			return fmt.Errorf("failed to acquire schema lock: %w", err)
 		}
-		defer rows.Close()
-		for rows.Next() {
-			var rs models.AreaBasicData
-			rows.Scan(&rs.Code, &rs.Name)
-			relationships = append(relationships, &rs)
+		if acquired {
+			break
 		}
-	} else {
-		rows, err := r.conn.Query(context.Background(), getRelationShipAreasWithParameter, areaCode, relationshipParameter)
-		if err != nil {
-			return nil, err
+		if !time.Now().Before(deadline) {
+			return apierrors.ErrSchemaLockTimeout
 		}
-		defer rows.Close()
-		for rows.Next() {
-			var rs models.AreaBasicData
-			rows.Scan(&rs.Code, &rs.Name)
-			relationships = append(relationships, &rs)
+
+		log.Info(ctx, "waiting for another instance to finish building the schema")
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(schemaLockPollInterval):
 		}
 	}
 
-	return relationships, nil
+	return fn(ctx)
 }
 
-func (r *RDS) BuildTables(ctx context.Context, executionList []string) error {
-	var err error
-	for index := range executionList {
-		logData := log.Data{"exceuting query": executionList[index]}
-		_, err = r.conn.Exec(ctx, executionList[index])
-		if err != nil {
-			return err
-		}
-		log.Info(ctx, "query executed successfully:", logData)
+func (r *RDS) buildTables(ctx context.Context) error {
+	migrator, err := r.Migrator()
+	if err != nil {
+		return err
+	}
+	applied, err := migrator.Up(ctx)
+	if err != nil {
+		return err
 	}
+	log.Info(ctx, "schema migrations applied", log.Data{"applied": len(applied)})
+
 	//  seed local instance with test data
 	if r.useLocalPostgres || r.loadSampleData {
-		err = r.insertAreaTypeTestData(ctx)
-		if err != nil {
-			return err
-		}
-		err = r.upsertAreaTestData(ctx)
+		set, err := fixtures.Open(r.fixturesVersion, r.fixturesDir)
 		if err != nil {
-			return err
+			return fmt.Errorf("failed to open fixtures: %w", err)
 		}
-		err = r.insertRelationshipTypeTestData(ctx)
+		err = r.LoadFixtures(ctx, set)
 		if err != nil {
 			return err
 		}
-		err = r.insertAreaNameTestData(ctx)
+	}
+	return nil
+}
+
+// Ping checks the writer pool
+func (r *RDS) Ping(ctx context.Context) error {
+	return r.conn.Ping(ctx)
+}
+
+// PingReader checks the pool that serves reads, which is the writer when no reader is configured
+func (r *RDS) PingReader(ctx context.Context) error {
+	return r.readConn().Ping(ctx)
+}
+
+// UpsertArea creates or replaces an area. When ifMatch is set the area must already exist with a matching ETag,
+// otherwise apierrors.ErrPreconditionFailed is returned.
+func (r *RDS) UpsertArea(ctx context.Context, area models.AreaParams, ifMatch string) (_ bool, err error) {
+	ctx, cancel := r.queryContext(ctx)
+	defer cancel()
+	defer func() { err = storeError(ctx, err) }()
+
+	tx, err := r.conn.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to start transaction: %w", err)
+	}
+
+	if ifMatch != "" {
+		err = checkAreaETag(ctx, tx, area.Code, ifMatch)
 		if err != nil {
-			return err
+			tx.Rollback(ctx)
+			if errors.Is(err, v4.ErrNoRows) {
+				return false, apierrors.ErrPreconditionFailed
+			}
+			return false, err
 		}
-		err = r.insertAreaRelationshipTestData(ctx)
-		if err != nil {
-			return err
+	}
+
+	isInserted, err := upsertAuditedAreaInTx(ctx, tx, area)
+	if err != nil {
+		tx.Rollback(ctx)
+		return isInserted, err
+	}
+
+	err = tx.Commit(ctx)
+
+	if err != nil {
+		tx.Rollback(ctx)
+		return isInserted, fmt.Errorf("failed to commit: %w", err)
+	}
+	return isInserted, nil
+}
+
+// BulkUpsertAreas upserts areas in the order given using the same semantics as UpsertArea. Areas are written in
+// batches of batchSize per transaction, or all in one transaction when batchSize is 0. When a batch fails it is rolled
+// back and the remaining batches are skipped; batches already committed are kept. A result is returned for every area.
+func (r *RDS) BulkUpsertAreas(ctx context.Context, areas []models.AreaParams, batchSize int) ([]models.BulkAreaResult, error) {
+	results := make([]models.BulkAreaResult, len(areas))
+	for i, area := range areas {
+		results[i] = models.BulkAreaResult{Index: i, Code: area.Code, Status: models.BulkAreaSkipped}
+	}
+
+	if batchSize <= 0 {
+		batchSize = len(areas)
+	}
+
+	for start := 0; start < len(areas); start += batchSize {
+		end := start + batchSize
+		if end > len(areas) {
+			end = len(areas)
 		}
-		err = r.insertBoundariesTestData(ctx)
+
+		err := r.upsertAreaBatch(ctx, areas[start:end], results[start:end])
 		if err != nil {
-			return err
+			return results, err
 		}
+		log.Info(ctx, "bulk upsert batch committed", log.Data{"from": start, "to": end})
 	}
-	return nil
+	return results, nil
 }
 
-func (r *RDS) insertBoundariesTestData(ctx context.Context) error {
-	boundariesData := DBRelationalData.BoundariesData
-	// build queries
-	for area_id := range boundariesData {
-		queryValues := boundariesData[area_id]["values"].(map[string]interface{})
-		logData := log.Data{"exceuting query": area_id}
-
-		rows, err := r.conn.Query(
-			ctx,
-			boundariesInsertTransaction,
-			area_id,
-			queryValues["centroid_bng"],
-			queryValues["centroid"],
-			queryValues["boundary"],
-		)
+// upsertAreaBatch writes a batch of areas in one transaction, recording the outcome of each area in results
+func (r *RDS) upsertAreaBatch(ctx context.Context, areas []models.AreaParams, results []models.BulkAreaResult) (err error) {
+	ctx, cancel := r.queryContext(ctx)
+	defer cancel()
+	defer func() { err = storeError(ctx, err) }()
+
+	tx, err := r.conn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+
+	for i, area := range areas {
+		isInserted, err := upsertAuditedAreaInTx(ctx, tx, area)
 		if err != nil {
+			tx.Rollback(ctx)
+			for j := 0; j < i; j++ {
+				results[j].Status = models.BulkAreaRolledBack
+			}
+			results[i].Status = models.BulkAreaFailed
+			results[i].Errors = []error{models.NewError(ctx, err, models.BulkUpsertError, err.Error())}
 			return err
 		}
-		rows.Close()
-		log.Info(ctx, "boundaries table query executed successfully:", logData)
+		if isInserted {
+			results[i].Status = models.BulkAreaCreated
+		} else {
+			results[i].Status = models.BulkAreaUpdated
+		}
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		tx.Rollback(ctx)
+		for i := range results {
+			results[i].Status = models.BulkAreaFailed
+		}
+		return fmt.Errorf("failed to commit: %w", err)
 	}
 	return nil
 }
-func (r *RDS) insertAreaTypeTestData(ctx context.Context) error {
-	areaTypeData := DBRelationalData.AreaTypeData
-	executionList := make([]string, len(areaTypeData))
-	// build queries in order - only insert if doesn't already exist in area_type table
-	for key := range areaTypeData {
-		executionList[areaTypeData[key]["creation_order"].(int)] = areaTypeData[key]["values"].(string)
-	}
-	// execute queries
-	for index := range executionList {
-		logData := log.Data{"exceuting query": executionList[index]}
-		rows, err := r.conn.Query(
-			ctx,
-			areaTypeInsertTransaction,
-			executionList[index],
-			executionList[index],
-		)
+
+// PatchArea applies a validated patch to an existing area. The area row is locked while the patch is applied and the
+// result is written with the same upsert used by UpsertArea, all in one transaction. A patch of /parent_code moves the
+// area, detaching it from any other parent. pgx.ErrNoRows is returned when the
+// area does not exist, apierrors.ErrPreconditionFailed when ifMatch is set and does not match the area's ETag and
+// apierrors.ErrInvalidAreaPatch when the patched area fails validation.
+func (r *RDS) PatchArea(ctx context.Context, areaCode string, patch models.AreaPatch, ifMatch string) (err error) {
+	ctx, cancel := r.queryContext(ctx)
+	defer cancel()
+	defer func() { err = storeError(ctx, err) }()
+
+	tx, err := r.conn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+
+	area, version, err := getAreaParamsForUpdate(ctx, tx, areaCode)
+	if err != nil {
+		tx.Rollback(ctx)
+		return err
+	}
+
+	if !models.ETagMatches(ifMatch, version) {
+		tx.Rollback(ctx)
+		return apierrors.ErrPreconditionFailed
+	}
+
+	var currentName string
+	if area.AreaName != nil {
+		currentName = area.AreaName.Name
+	}
+
+	err = patch.Apply(area)
+	if err != nil {
+		tx.Rollback(ctx)
+		return fmt.Errorf("%w: %v", apierrors.ErrInvalidAreaPatch, err)
+	}
+
+	if validationErrs := area.ValidatePatchedArea(ctx); len(validationErrs) != 0 {
+		tx.Rollback(ctx)
+		return fmt.Errorf("%w: %v", apierrors.ErrInvalidAreaPatch, validationErrs)
+	}
+
+	err = auditArea(ctx, tx, areaCode, models.AreaAuditPatch)
+	if err != nil {
+		tx.Rollback(ctx)
+		return err
+	}
+
+	// area names are upserted by area and name, so a rename has to update the existing row rather than insert a second one
+	if currentName != "" && currentName != area.AreaName.Name {
+		_, err = tx.Exec(ctx, renameAreaName, area.Code, currentName, area.AreaName.Name)
 		if err != nil {
-			return err
+			tx.Rollback(ctx)
+			return fmt.Errorf("failed to rename area_name: %w", err)
 		}
-		rows.Close()
-		log.Info(ctx, "area_type table query executed successfully:", logData)
 	}
-	return nil
-}
 
-func (r *RDS) upsertAreaTestData(ctx context.Context) error {
-	areaData := DBRelationalData.AreaData
-	// build queries
-	for code := range areaData {
-		queryValues := areaData[code]["values"].(map[string]interface{})
-		logData := log.Data{"exceuting query": code}
-
-		// handle scenario where dates not set => pointer to sql null
-		var active_from *string
-		if queryValues["active_from"].(string) != "" {
-			af := queryValues["active_from"].(string)
-			active_from = &af
-		}
-		var active_to *string
-		if queryValues["active_to"].(string) != "" {
-			at := queryValues["active_to"].(string)
-			active_to = &at
-		}
-
-		rows, err := r.conn.Query(
-			ctx,
-			areaInsertTransaction,
-			code,
-			active_from,
-			active_to,
-			queryValues["area_type_id"].(int),
-			queryValues["geometric_area"].(string),
-			queryValues["visible"].(bool),
-		)
+	if patch.SetsParent() {
+		err = moveAreaInTx(ctx, tx, areaCode, area.ParentCode)
 		if err != nil {
+			tx.Rollback(ctx)
 			return err
 		}
-		rows.Close()
-		log.Info(ctx, "area table query executed successfully:", logData)
+	}
+
+	_, err = upsertAreaInTx(ctx, tx, *area)
+	if err != nil {
+		tx.Rollback(ctx)
+		return err
+	}
+
+	err = completeAreaAudit(ctx, tx, areaCode)
+	if err != nil {
+		tx.Rollback(ctx)
+		return err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		tx.Rollback(ctx)
+		return fmt.Errorf("failed to commit: %w", err)
 	}
 	return nil
 }
 
-func (r *RDS) Ping(ctx context.Context) error {
-	return r.conn.Ping(ctx)
+// getAreaParamsForUpdate loads the current state, parent and version of an area, locking its row until the transaction ends
+func getAreaParamsForUpdate(ctx context.Context, tx pgx.PGXTransaction, areaCode string) (*models.AreaParams, int, error) {
+	area := models.AreaParams{}
+	var geometry, areaType, name, parentCode *string
+	var hectares *float64
+	var version int
+	areaName := models.AreaName{}
+
+	err := tx.QueryRow(ctx, getAreaForUpdate, areaCode).Scan(&area.Code, &area.ActiveFrom, &area.ActiveTo, &geometry, &area.Visible, &hectares, &areaType, &name, &areaName.ActiveFrom, &areaName.ActiveTo, &version, &parentCode)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if geometry != nil {
+		area.GeometricData = *geometry
+	}
+	if hectares != nil {
+		area.AreaHectares = *hectares
+	}
+	if areaType != nil {
+		area.AreaType = *areaType
+	}
+	if name != nil {
+		areaName.Name = *name
+		area.AreaName = &areaName
+	}
+	if parentCode != nil {
+		area.ParentCode = *parentCode
+	}
+	return &area, version, nil
 }
 
-func (r *RDS) UpsertArea(ctx context.Context, area models.AreaParams) (bool, error) {
-	var areaTypeId int
-	var isInserted bool
-	tx, err := r.conn.Begin(ctx)
+// checkAreaETag locks an area and returns apierrors.ErrPreconditionFailed unless ifMatch matches its current version
+func checkAreaETag(ctx context.Context, tx pgx.PGXTransaction, areaCode, ifMatch string) error {
+	var version int
+	err := tx.QueryRow(ctx, getAreaVersionForUpdate, areaCode).Scan(&version)
 	if err != nil {
-		return isInserted, fmt.Errorf("failed to start transaction: %+v", err)
+		return err
+	}
+	if !models.ETagMatches(ifMatch, version) {
+		return apierrors.ErrPreconditionFailed
 	}
+	return nil
+}
+
+// upsertAreaInTx writes an area, its name and its parent relationship within an existing transaction
+func upsertAreaInTx(ctx context.Context, tx pgx.PGXTransaction, area models.AreaParams) (bool, error) {
+	var areaTypeId int
+	var isInserted bool
 
-	err = tx.QueryRow(ctx, getAreaType, area.AreaType).Scan(&areaTypeId)
+	err := tx.QueryRow(ctx, getAreaType, area.AreaType).Scan(&areaTypeId)
 	if err != nil {
-		return isInserted, fmt.Errorf("failed to get area type: %+v", err)
+		return isInserted, referenceError(fmt.Errorf("failed to get area type: %w", err))
 	}
 	areaDetails := []interface{}{area.Code, area.ActiveFrom, area.ActiveTo, area.GeometricData, areaTypeId, area.Visible, area.AreaHectares}
 
 	err = tx.QueryRow(ctx, upsertArea, areaDetails...).Scan(&isInserted)
 
 	if err != nil {
-		tx.Rollback(ctx)
-		return isInserted, fmt.Errorf("failed to upsert into area: %+v", err)
+		return isInserted, fmt.Errorf("failed to upsert into area: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, insertAreaClosureSelf, area.Code)
+
+	if err != nil {
+		return isInserted, fmt.Errorf("failed to upsert into area_closure: %w", err)
 	}
 
 	_, err = tx.Exec(ctx, upsertAreaName, area.Code, area.AreaName.Name, area.AreaName.ActiveFrom, area.AreaName.ActiveTo)
 
 	if err != nil {
-		tx.Rollback(ctx)
-		return isInserted, fmt.Errorf("failed to upsert into area_name: %+v", err)
+		return isInserted, fmt.Errorf("failed to upsert into area_name: %w", err)
 	}
 
 	if area.ParentCode != "" {
 		var relationshipId int
 		err = tx.QueryRow(ctx, getRelationShipId, "child").Scan(&relationshipId)
 		if err != nil {
-			return isInserted, fmt.Errorf("failed to get child relationshipid: %+v", err)
+			return isInserted, referenceError(fmt.Errorf("failed to get child relationshipid: %w", err))
+		}
+
+		err = checkAreaParentInTx(ctx, tx, area.Code, area.ParentCode)
+		if err != nil {
+			return isInserted, err
 		}
 
 		_, err = tx.Exec(ctx, areaRelationshipInsertTransaction, area.ParentCode, area.Code, relationshipId)
 
 		if err != nil {
-			tx.Rollback(ctx)
-			return isInserted, fmt.Errorf("failed to upsert into area relationship: %+v", err)
+			return isInserted, fmt.Errorf("failed to upsert into area relationship: %w", err)
+		}
+
+		err = insertAreaClosurePath(ctx, tx, area.ParentCode, area.Code)
+		if err != nil {
+			return isInserted, err
 		}
 	}
 
-	err = tx.Commit(ctx)
+	err = notifyAreasChanged(ctx, tx, area.Code, area.ParentCode)
+	return isInserted, err
+}
 
+// checkAreaParentInTx refuses a parent that is the area itself or one of its descendants with
+// apierrors.ErrAreaParentCycle
+func checkAreaParentInTx(ctx context.Context, tx pgx.PGXTransaction, areaCode, parentCode string) error {
+	var isCycle bool
+	err := tx.QueryRow(ctx, isAreaDescendant, areaCode, parentCode).Scan(&isCycle)
 	if err != nil {
-		tx.Rollback(ctx)
-		return isInserted, fmt.Errorf("failed to commit: %+v", err)
+		return fmt.Errorf("failed to check area_closure for a cycle: %w", err)
 	}
-	return isInserted, nil
+	if isCycle {
+		return &apierrors.StoreError{Kind: apierrors.ErrInvalidData, Constraint: "parent_code", Err: apierrors.ErrAreaParentCycle}
+	}
+	return nil
+}
+
+// moveAreaInTx detaches an area from any parent other than parentCode before it is linked to parentCode, removing its
+// relationship to them and the area_closure paths from their ancestors to the area and its descendants. Only a patch
+// of /parent_code moves an area; upserts add the parent to any the area already has.
+func moveAreaInTx(ctx context.Context, tx pgx.PGXTransaction, areaCode, parentCode string) error {
+	var relationshipId int
+	err := tx.QueryRow(ctx, getRelationShipId, "child").Scan(&relationshipId)
+	if err != nil {
+		return referenceError(fmt.Errorf("failed to get child relationshipid: %w", err))
+	}
+
+	tag, err := tx.Exec(ctx, deleteOtherAreaParents, areaCode, parentCode, relationshipId)
+	if err != nil {
+		return fmt.Errorf("failed to delete previous parent from area relationship: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return nil
+	}
+
+	_, err = tx.Exec(ctx, deleteAreaClosureAncestors, areaCode)
+	if err != nil {
+		return fmt.Errorf("failed to delete previous ancestors from area_closure: %w", err)
+	}
+	return nil
 }
 
-func (r *RDS) insertRelationshipTypeTestData(ctx context.Context) error {
-	relationshipTypeData := DBRelationalData.RelationshipTypeData
-	executionList := make([]string, len(relationshipTypeData))
-	for key := range relationshipTypeData {
-		executionList[relationshipTypeData[key]["creation_order"].(int)] = relationshipTypeData[key]["values"].(string)
-	}
-	// execute queries
-	for index := range executionList {
-		logData := log.Data{"exceuting query": executionList[index]}
-		rows, err := r.conn.Query(
-			ctx,
-			relationshipTypeInsertTransaction,
-			executionList[index],
-			executionList[index],
-		)
+// upsertAuditedAreaInTx writes an area with upsertAreaInTx, recording the change in the area's audit log
+func upsertAuditedAreaInTx(ctx context.Context, tx pgx.PGXTransaction, area models.AreaParams) (bool, error) {
+	err := auditArea(ctx, tx, area.Code, models.AreaAuditUpdate)
+	if err != nil {
+		return false, err
+	}
+
+	isInserted, err := upsertAreaInTx(ctx, tx, area)
+	if err != nil {
+		return isInserted, err
+	}
+	return isInserted, completeAreaAudit(ctx, tx, area.Code)
+}
+
+// auditArea starts an entry in the audit log of an area that is about to be written, recording the state it is in and
+// the actor of ctx. The operation is recorded as models.AreaAuditCreate when the area does not exist yet. The entry
+// must be completed by completeAreaAudit once the area is written.
+func auditArea(ctx context.Context, tx pgx.PGXTransaction, areaCode, operation string) error {
+	_, err := tx.Exec(ctx, insertAreaAudit, areaCode, auth.Actor(ctx), operation)
+	if err != nil {
+		return fmt.Errorf("failed to insert into area_audit: %w", err)
+	}
+	return nil
+}
+
+// completeAreaAudit records the state an area was written in on the audit entries started for it in the transaction
+func completeAreaAudit(ctx context.Context, tx pgx.PGXTransaction, areaCode string) error {
+	_, err := tx.Exec(ctx, setAreaAuditAfter, areaCode)
+	if err != nil {
+		return fmt.Errorf("failed to complete area_audit: %w", err)
+	}
+	return nil
+}
+
+// notifyAreasChanged tells the listener of every instance that the areas may have changed, once the transaction commits
+func notifyAreasChanged(ctx context.Context, tx pgx.PGXTransaction, areaCodes ...string) error {
+	for _, areaCode := range areaCodes {
+		if areaCode == "" {
+			continue
+		}
+		_, err := tx.Exec(ctx, notifyAreaChanged, areaCode)
 		if err != nil {
-			return err
+			return fmt.Errorf("failed to notify area change: %w", err)
 		}
-		rows.Close()
-		log.Info(ctx, "relationship_type table query executed successfully:", logData)
 	}
 	return nil
 }
 
-func (r *RDS) insertAreaNameTestData(ctx context.Context) error {
-	areaNameData := DBRelationalData.AreaNameData
-	// build queries
-	for name := range areaNameData {
-		queryValues := areaNameData[name]["values"].(map[string]interface{})
-		logData := log.Data{"exceuting query": name}
-
-		// handle scenario where dates not set => pointer to sql null
-		var active_from *string
-		if queryValues["active_from"].(string) != "" {
-			af := queryValues["active_from"].(string)
-			active_from = &af
-		}
-		var active_to *string
-		if queryValues["active_to"].(string) != "" {
-			at := queryValues["active_to"].(string)
-			active_to = &at
-		}
-
-		rows, err := r.conn.Query(
-			ctx,
-			areaNameInsertTransaction,
-			queryValues["area_code"],
-			name,
-			active_from,
-			active_to,
-		)
+func (r *RDS) GetAncestors(ctx context.Context, areaCode string) (_ []models.AreasAncestors, err error) {
+	ctx, cancel := r.queryContext(ctx)
+	defer cancel()
+	defer func() { err = storeError(ctx, err) }()
+
+	rows, err := r.readConn().Query(ctx, getAncestors, areaCode)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ancestors []models.AreasAncestors
+	for rows.Next() {
+		var rs models.AreasAncestors
+		if err = rows.Scan(&rs.Id, &rs.Name); err != nil {
+			return nil, err
+		}
+		ancestors = append(ancestors, rs)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return ancestors, nil
+}
+
+// GetAreaDetails fetches an area with its names and ancestors, and its children and boundary when requested. The
+// queries are sent as one batch so that the details take a single round trip. pgx.ErrNoRows is returned when the area
+// does not exist, or is inactive and opts.IncludeInactive is not set.
+func (r *RDS) GetAreaDetails(ctx context.Context, areaCode string, opts models.AreaDetailsOptions) (_ *models.AreaDetails, err error) {
+	ctx, cancel := r.queryContext(ctx)
+	defer cancel()
+	defer func() { err = storeError(ctx, err) }()
+
+	batch := &pgx.Batch{}
+	batch.Queue(getAreaSummary, areaCode, opts.IncludeInactive)
+	batch.Queue(getAreaNames, areaCode)
+	batch.Queue(getAncestors, areaCode)
+	if opts.Children {
+		batch.Queue(getChildAreas, areaCode, opts.IncludeInactive)
+	}
+	if opts.Boundary {
+		batch.Queue(getAreaBoundary, areaCode)
+	}
+
+	results := batch.Send(ctx, r.readConn())
+	details, err := readAreaDetails(results, opts)
+	// closing reads past any results left unread, so it must happen even when reading failed
+	closeErr := results.Close()
+	if err != nil {
+		return nil, err
+	}
+	if closeErr != nil {
+		return nil, fmt.Errorf("failed to close batch: %w", closeErr)
+	}
+	return details, nil
+}
+
+// readAreaDetails reads the results of the batch sent by GetAreaDetails in the order its queries were queued
+func readAreaDetails(results v4.BatchResults, opts models.AreaDetailsOptions) (*models.AreaDetails, error) {
+	area := models.AreasDataResults{}
+	err := results.QueryRow().Scan(&area.Code, &area.Name, &area.Visible, &area.AreaType, &area.Version, &area.UpdatedAt, &area.LastModified)
+	if err != nil {
+		return nil, err
+	}
+	details := &models.AreaDetails{Area: &area}
+
+	err = readBatchRows(results, func(rows v4.Rows) error {
+		var name models.AreaName
+		if err := rows.Scan(&name.Name, &name.ActiveFrom, &name.ActiveTo); err != nil {
+			return err
+		}
+		details.Names = append(details.Names, name)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get area names: %w", err)
+	}
+
+	err = readBatchRows(results, func(rows v4.Rows) error {
+		var ancestor models.AreasAncestors
+		if err := rows.Scan(&ancestor.Id, &ancestor.Name); err != nil {
+			return err
+		}
+		area.Ancestors = append(area.Ancestors, ancestor)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ancestors: %w", err)
+	}
+
+	if opts.Children {
+		err = readBatchRows(results, func(rows v4.Rows) error {
+			var child models.AreaBasicData
+			if err := rows.Scan(&child.Code, &child.Name); err != nil {
+				return err
+			}
+			details.Children = append(details.Children, &child)
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get child areas: %w", err)
+		}
+	}
+
+	if opts.Boundary {
+		var boundaryDataBlob string
+		err = results.QueryRow().Scan(&boundaryDataBlob)
 		if err != nil {
+			return nil, fmt.Errorf("failed to get boundary: %w", err)
+		}
+		area.GeometricData, err = unmarshalBoundary(boundaryDataBlob)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return details, nil
+}
+
+// readBatchRows reads the rows of the next result of a batch, calling scan for each
+func readBatchRows(results v4.BatchResults, scan func(rows v4.Rows) error) error {
+	rows, err := results.Query()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		if err = scan(rows); err != nil {
 			return err
 		}
-		rows.Close()
-		log.Info(ctx, "area_name table query executed successfully:", logData)
 	}
-	return nil
+	return rows.Err()
 }
 
-func (r *RDS) insertAreaRelationshipTestData(ctx context.Context) error {
-	areaRelationshipData := DBRelationalData.AreaRelationshipData
-	// build queries
-	for code := range areaRelationshipData {
-		queryValues := areaRelationshipData[code]["values"].(map[string]interface{})
-		logData := log.Data{"exceuting query": code}
-
-		rows, err := r.conn.Query(
-			ctx,
-			areaRelationshipInsertTransaction,
-			code,
-			queryValues["rel_area_code"],
-			queryValues["rel_type_id"],
-		)
+// RetireArea soft-deletes an area by ending it now and hiding it. Unless cascade is set, areas with live children are
+// refused with apierrors.ErrAreaHasLiveChildren so that they are not orphaned; with cascade all live descendants are
+// retired in the same transaction. When ifMatch is set it must match the area's current ETag. An area that is already
+// retired is left as it is, without a new version or audit entry.
+func (r *RDS) RetireArea(ctx context.Context, areaCode string, cascade bool, ifMatch string) (err error) {
+	ctx, cancel := r.queryContext(ctx)
+	defer cancel()
+	defer func() { err = storeError(ctx, err) }()
+
+	tx, err := r.conn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+
+	var version int
+	var isActive bool
+	err = tx.QueryRow(ctx, getAreaRetirementForUpdate, areaCode).Scan(&version, &isActive)
+	if err != nil {
+		tx.Rollback(ctx)
+		return err
+	}
+	if !models.ETagMatches(ifMatch, version) {
+		tx.Rollback(ctx)
+		return apierrors.ErrPreconditionFailed
+	}
+	if !isActive {
+		tx.Rollback(ctx)
+		return nil
+	}
+
+	if cascade {
+		_, err = tx.Exec(ctx, insertDescendantAreaAudits, areaCode, auth.Actor(ctx))
 		if err != nil {
-			return err
+			tx.Rollback(ctx)
+			return fmt.Errorf("failed to insert descendant areas into area_audit: %w", err)
 		}
-		rows.Close()
-		log.Info(ctx, "area_relationship table query executed successfully:", logData)
+		_, err = tx.Exec(ctx, retireDescendantAreas, areaCode)
+		if err != nil {
+			tx.Rollback(ctx)
+			return fmt.Errorf("failed to retire descendant areas: %w", err)
+		}
+		_, err = tx.Exec(ctx, setDescendantAreaAuditsAfter, areaCode)
+		if err != nil {
+			tx.Rollback(ctx)
+			return fmt.Errorf("failed to complete descendant areas in area_audit: %w", err)
+		}
+		_, err = tx.Exec(ctx, notifyDescendantAreasChanged, areaCode)
+		if err != nil {
+			tx.Rollback(ctx)
+			return fmt.Errorf("failed to notify descendant area changes: %w", err)
+		}
+	} else {
+		var liveChildren int
+		err = tx.QueryRow(ctx, countLiveChildAreas, areaCode).Scan(&liveChildren)
+		if err != nil {
+			tx.Rollback(ctx)
+			return fmt.Errorf("failed to count live child areas: %w", err)
+		}
+		if liveChildren > 0 {
+			tx.Rollback(ctx)
+			return apierrors.ErrAreaHasLiveChildren
+		}
+	}
+
+	err = auditArea(ctx, tx, areaCode, models.AreaAuditRetire)
+	if err != nil {
+		tx.Rollback(ctx)
+		return err
+	}
+
+	_, err = tx.Exec(ctx, retireArea, areaCode)
+	if err != nil {
+		tx.Rollback(ctx)
+		return fmt.Errorf("failed to retire area: %w", err)
+	}
+
+	err = completeAreaAudit(ctx, tx, areaCode)
+	if err != nil {
+		tx.Rollback(ctx)
+		return err
+	}
+
+	err = notifyAreasChanged(ctx, tx, areaCode)
+	if err != nil {
+		tx.Rollback(ctx)
+		return err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		tx.Rollback(ctx)
+		return fmt.Errorf("failed to commit: %w", err)
 	}
 	return nil
 }
 
-func (r *RDS) GetAncestors(areaCode string) ([]models.AreasAncestors, error) {
-	var ancestors []*models.AreasAncestors
+// GetAreaAudit returns a page of the audit log of an area, most recent change first, with the number of changes that
+// match the query's dates in total
+func (r *RDS) GetAreaAudit(ctx context.Context, areaCode string, query models.AreaAuditQuery) (_ *models.AreaAuditPage, err error) {
+	ctx, cancel := r.queryContext(ctx)
+	defer cancel()
+	defer func() { err = storeError(ctx, err) }()
 
-	rows, err := r.conn.Query(context.Background(), getAncestors, areaCode)
+	page := &models.AreaAuditPage{Offset: query.Offset, Limit: query.Limit, Items: []models.AreaAuditEntry{}}
+	err = r.readConn().QueryRow(ctx, countAreaAudit, areaCode, query.From, query.To).Scan(&page.TotalCount)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.readConn().Query(ctx, getAreaAudit, areaCode, query.From, query.To, query.Offset, query.Limit)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
+
 	for rows.Next() {
-		var rs models.AreasAncestors
-		rows.Scan(&rs.Id, &rs.Name)
-		ancestors = append(ancestors, &rs)
+		var entry models.AreaAuditEntry
+		var before, after []byte
+		if err = rows.Scan(&entry.ID, &entry.AreaCode, &entry.Actor, &entry.Operation, &entry.ChangedAt, &before, &after); err != nil {
+			return nil, err
+		}
+		if entry.Before, err = unmarshalAreaAuditState(before); err != nil {
+			return nil, err
+		}
+		if entry.After, err = unmarshalAreaAuditState(after); err != nil {
+			return nil, err
+		}
+		page.Items = append(page.Items, entry)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	page.Count = len(page.Items)
+	return page, nil
+}
+
+// unmarshalAreaAuditState decodes the state recorded in area_audit, which is null for the state before a create
+func unmarshalAreaAuditState(blob []byte) (*models.AreaAuditState, error) {
+	if blob == nil {
+		return nil, nil
 	}
+	state := &models.AreaAuditState{}
+	if err := json.Unmarshal(blob, state); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal area_audit state: %w", err)
+	}
+	return state, nil
+}
 
-	var a []models.AreasAncestors
-	for _, data := range ancestors {
-		a = append(a, *data)
+// RebuildAreaClosure recalculates the area_closure table from the child relationships held in area_relationship.
+// It should be run after any bulk load that writes to area_relationship without going through UpsertArea.
+func (r *RDS) RebuildAreaClosure(ctx context.Context) error {
+	tx, err := r.conn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
 	}
 
-	return a, nil
+	_, err = tx.Exec(ctx, deleteAreaClosure)
+	if err != nil {
+		tx.Rollback(ctx)
+		return fmt.Errorf("failed to clear area_closure: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, rebuildAreaClosure, maxClosureDepth)
+	if err != nil {
+		tx.Rollback(ctx)
+		return fmt.Errorf("failed to rebuild area_closure: %w", err)
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		tx.Rollback(ctx)
+		return fmt.Errorf("failed to commit: %w", err)
+	}
+	log.Info(ctx, "area_closure table rebuilt successfully")
+	return nil
+}
+
+// insertAreaClosurePath links every ancestor of parentCode to every descendant of code
+func insertAreaClosurePath(ctx context.Context, tx pgx.PGXTransaction, parentCode, code string) error {
+	_, err := tx.Exec(ctx, insertAreaClosureSelf, parentCode)
+	if err != nil {
+		return fmt.Errorf("failed to upsert parent into area_closure: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, insertAreaClosurePaths, parentCode, code)
+	if err != nil {
+		return fmt.Errorf("failed to upsert area_closure paths: %w", err)
+	}
+	return nil
 }
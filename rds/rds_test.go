@@ -3,10 +3,16 @@ package rds
 import (
 	"context"
 	"errors"
+	"fmt"
+	"math"
 	"testing"
+	"time"
 
+	"github.com/ONSdigital/dp-areas-api/apierrors"
+	"github.com/ONSdigital/dp-areas-api/auth"
 	"github.com/ONSdigital/dp-areas-api/models"
 	pgxMock "github.com/ONSdigital/dp-areas-api/pgx/mock"
+	dprequest "github.com/ONSdigital/dp-net/request"
 	"github.com/jackc/pgconn"
 	"github.com/jackc/pgx/v4"
 
@@ -36,7 +42,7 @@ func TestRDS_GetArea(t *testing.T) {
 					return rowMock
 				},
 			}}
-		area, err := rds.GetArea(context.Background(), "W92000004")
+		area, err := rds.GetArea(context.Background(), "W92000004", false)
 
 		Convey("When GetArea is invoked", func() {
 
@@ -65,7 +71,7 @@ func TestRDS_GetArea(t *testing.T) {
 					return rowMock
 				},
 			}}
-		area, err := rds.GetArea(context.Background(), "123")
+		area, err := rds.GetArea(context.Background(), "123", false)
 
 		Convey("When GetArea is invoked", func() {
 
@@ -96,7 +102,7 @@ func TestRDS_ValidateArea(t *testing.T) {
 					return rowMock
 				},
 			}}
-		err := rds.ValidateArea("W92000004")
+		err := rds.ValidateArea(context.Background(), "W92000004", false)
 
 		Convey("When area code is validated", func() {
 
@@ -120,7 +126,7 @@ func TestRDS_ValidateArea(t *testing.T) {
 					return rowMock
 				},
 			}}
-		err := rds.ValidateArea("invalid")
+		err := rds.ValidateArea(context.Background(), "invalid", false)
 
 		Convey("When invalid area  code is validated", func() {
 
@@ -137,14 +143,15 @@ func TestRDS_GetRelationships(t *testing.T) {
 		callCount := 0
 
 		relationships := []*models.AreaBasicData{
-			{"E12000001", "North East"},
-			{"E12000002", "North West"},
-			{"E12000003", "Yorkshire and The Humbe"},
+			{Code: "E12000001", Name: "North East"},
+			{Code: "E12000002", Name: "North West"},
+			{Code: "E12000003", Name: "Yorkshire and The Humbe"},
 		}
 
 		rowMock := &pgxMock.PGXRowsMock{
 			CloseFunc: func() {
 			},
+			ErrFunc: func() error { return nil },
 			NextFunc: func() bool {
 				response := callCount < len(relationships)
 				return response
@@ -167,7 +174,7 @@ func TestRDS_GetRelationships(t *testing.T) {
 					return rowMock, nil
 				},
 			}}
-		actualRelationships, err := rds.GetRelationships("E92000001", "")
+		actualRelationships, err := rds.GetRelationships(context.Background(), "E92000001", "", false)
 
 		Convey("When relationships are fetched", func() {
 
@@ -186,7 +193,7 @@ func TestRDS_GetRelationships(t *testing.T) {
 					return nil, errors.New(errorMsg)
 				},
 			}}
-		actualRelationships, err := rds.GetRelationships("E92000001", "")
+		actualRelationships, err := rds.GetRelationships(context.Background(), "E92000001", "", false)
 
 		Convey("When failed to connect to DB", func() {
 
@@ -202,6 +209,7 @@ func TestRDS_GetRelationships(t *testing.T) {
 		rowMock := &pgxMock.PGXRowsMock{
 			CloseFunc: func() {
 			},
+			ErrFunc: func() error { return nil },
 			NextFunc: func() bool {
 				return false
 			},
@@ -213,7 +221,7 @@ func TestRDS_GetRelationships(t *testing.T) {
 					return rowMock, nil
 				},
 			}}
-		actualRelationships, err := rds.GetRelationships("E92000001", "")
+		actualRelationships, err := rds.GetRelationships(context.Background(), "E92000001", "", false)
 
 		Convey("When relationships are fetched", func() {
 
@@ -230,12 +238,13 @@ func TestRDS_GetAncestors(t *testing.T) {
 		callCount := 0
 
 		ancestors := []models.AreasAncestors{
-			{"E92000001", "England"},
+			{Id: "E92000001", Name: "England"},
 		}
 
 		rowMock := &pgxMock.PGXRowsMock{
 			CloseFunc: func() {
 			},
+			ErrFunc: func() error { return nil },
 			NextFunc: func() bool {
 				response := callCount < len(ancestors)
 				return response
@@ -258,7 +267,7 @@ func TestRDS_GetAncestors(t *testing.T) {
 					return rowMock, nil
 				},
 			}}
-		actualAncestors, err := rds.GetAncestors("E92000001")
+		actualAncestors, err := rds.GetAncestors(context.Background(), "E92000001")
 
 		Convey("When ancestors are fetched", func() {
 
@@ -277,7 +286,7 @@ func TestRDS_GetAncestors(t *testing.T) {
 					return nil, errors.New(errorMsg)
 				},
 			}}
-		actualAncestors, err := rds.GetAncestors("E92000001")
+		actualAncestors, err := rds.GetAncestors(context.Background(), "E92000001")
 
 		Convey("When failed to connect to DB", func() {
 
@@ -293,6 +302,7 @@ func TestRDS_GetAncestors(t *testing.T) {
 		rowMock := &pgxMock.PGXRowsMock{
 			CloseFunc: func() {
 			},
+			ErrFunc: func() error { return nil },
 			NextFunc: func() bool {
 				return false
 			},
@@ -304,7 +314,7 @@ func TestRDS_GetAncestors(t *testing.T) {
 					return rowMock, nil
 				},
 			}}
-		actualAncestors, err := rds.GetAncestors("E92000001")
+		actualAncestors, err := rds.GetAncestors(context.Background(), "E92000001")
 
 		Convey("When ancestors are fetched", func() {
 
@@ -316,6 +326,136 @@ func TestRDS_GetAncestors(t *testing.T) {
 	})
 }
 
+func TestRDS_GetAreaDetails(t *testing.T) {
+	ctx := context.Background()
+	sheffield, metropolitanDistrict, visible := "Sheffield", "Metropolitan District", true
+	activeFrom := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	updatedAt := time.Date(2022, 6, 1, 9, 30, 0, 0, time.UTC)
+	area := []interface{}{"E08000019", &sheffield, &visible, &metropolitanDistrict, 3, updatedAt}
+
+	newBatchResults := func(rows ...*pgxMock.PGXRowsMock) *pgxMock.PGXBatchResultsMock {
+		rowCalls := 0
+		return &pgxMock.PGXBatchResultsMock{
+			QueryRowFunc: func() pgx.Row {
+				return &pgxMock.PGXRowMock{ScanFunc: func(dest ...interface{}) error {
+					if rowCalls++; rowCalls == 1 {
+						return scanValues(dest, area)
+					}
+					return scanValues(dest, []interface{}{`[[[-1.5,53.4],[-1.4,53.4]]]`})
+				}}
+			},
+			QueryMock: func() (pgx.Rows, error) {
+				next := rows[0]
+				rows = rows[1:]
+				return next, nil
+			},
+			CloseFunc: func() error { return nil },
+		}
+	}
+
+	Convey("Given an area with names, ancestors and children", t, func() {
+		results := newBatchResults(
+			rowsOf([]interface{}{"Sheffield", &activeFrom, (*time.Time)(nil)}),
+			rowsOf([]interface{}{"E11000003", "South Yorkshire"}, []interface{}{"E92000001", "England"}),
+			rowsOf([]interface{}{"E05010863", "Beighton"}),
+		)
+		pool := &pgxMock.PGXPoolMock{
+			SendBatchFunc: func(ctx context.Context, b *pgx.Batch) pgx.BatchResults {
+				return results
+			},
+		}
+		rds := RDS{conn: pool}
+
+		Convey("When its details are fetched with children and boundary", func() {
+			details, err := rds.GetAreaDetails(ctx, "E08000019", models.AreaDetailsOptions{Children: true, Boundary: true})
+
+			Convey("Then every part is fetched in a single batch", func() {
+				So(err, ShouldBeNil)
+				So(pool.SendBatchCalls(), ShouldHaveLength, 1)
+				So(pool.SendBatchCalls()[0].B.Len(), ShouldEqual, 5)
+				So(results.CloseCalls(), ShouldHaveLength, 1)
+			})
+
+			Convey("Then the composed details are returned", func() {
+				So(details.Area.Code, ShouldEqual, "E08000019")
+				So(*details.Area.Name, ShouldEqual, "Sheffield")
+				So(details.Area.Version, ShouldEqual, 3)
+				So(details.Area.UpdatedAt, ShouldEqual, updatedAt)
+				So(details.Area.Ancestors, ShouldResemble, []models.AreasAncestors{{Id: "E11000003", Name: "South Yorkshire"}, {Id: "E92000001", Name: "England"}})
+				So(details.Area.GeometricData, ShouldResemble, [][][2]float64{{{-1.5, 53.4}, {-1.4, 53.4}}})
+				So(details.Names, ShouldResemble, []models.AreaName{{Name: "Sheffield", ActiveFrom: &activeFrom}})
+				So(details.Children, ShouldResemble, []*models.AreaBasicData{{Code: "E05010863", Name: "Beighton"}})
+			})
+		})
+	})
+
+	Convey("Given an area fetched without children or boundary", t, func() {
+		results := newBatchResults(rowsOf(), rowsOf())
+		pool := &pgxMock.PGXPoolMock{
+			SendBatchFunc: func(ctx context.Context, b *pgx.Batch) pgx.BatchResults {
+				return results
+			},
+		}
+		rds := RDS{conn: pool}
+
+		Convey("When its details are fetched", func() {
+			details, err := rds.GetAreaDetails(ctx, "E08000019", models.AreaDetailsOptions{})
+
+			Convey("Then only the area, names and ancestors are queried", func() {
+				So(err, ShouldBeNil)
+				So(pool.SendBatchCalls()[0].B.Len(), ShouldEqual, 3)
+				So(results.QueryRowCalls(), ShouldHaveLength, 1)
+				So(details.Area.GeometricData, ShouldBeNil)
+				So(details.Children, ShouldBeNil)
+			})
+		})
+	})
+
+	Convey("Given an unknown area", t, func() {
+		results := &pgxMock.PGXBatchResultsMock{
+			QueryRowFunc: func() pgx.Row {
+				return &pgxMock.PGXRowMock{ScanFunc: func(dest ...interface{}) error { return pgx.ErrNoRows }}
+			},
+			CloseFunc: func() error { return nil },
+		}
+		rds := RDS{conn: &pgxMock.PGXPoolMock{
+			SendBatchFunc: func(ctx context.Context, b *pgx.Batch) pgx.BatchResults {
+				return results
+			},
+		}}
+
+		Convey("When its details are fetched", func() {
+			details, err := rds.GetAreaDetails(ctx, "E00000000", models.AreaDetailsOptions{})
+
+			Convey("Then it is not found and the batch is still closed", func() {
+				So(errors.Is(err, apierrors.ErrNotFound), ShouldBeTrue)
+				So(details, ShouldBeNil)
+				So(results.CloseCalls(), ShouldHaveLength, 1)
+			})
+		})
+	})
+
+	Convey("Given a batch that fails to close", t, func() {
+		results := newBatchResults(rowsOf(), rowsOf())
+		results.CloseFunc = func() error { return errors.New("conn closed") }
+		rds := RDS{conn: &pgxMock.PGXPoolMock{
+			SendBatchFunc: func(ctx context.Context, b *pgx.Batch) pgx.BatchResults {
+				return results
+			},
+		}}
+
+		Convey("When its details are fetched", func() {
+			details, err := rds.GetAreaDetails(ctx, "E08000019", models.AreaDetailsOptions{})
+
+			Convey("Then the close error is returned", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, "failed to close batch: conn closed")
+				So(details, ShouldBeNil)
+			})
+		})
+	})
+}
+
 func TestRDS_UpsertArea(t *testing.T) {
 
 	Convey("Given an area details for existing area", t, func() {
@@ -351,12 +491,21 @@ func TestRDS_UpsertArea(t *testing.T) {
 		}}
 
 		Convey("When area is upserted in rds", func() {
-			upsertResult, err := rds.UpsertArea(context.Background(), models.AreaParams{Code: areaCode, AreaName: &models.AreaName{Name: "England"}})
+			ctx := context.WithValue(context.Background(), dprequest.UserIdentityKey, "publisher@ons.gov.uk")
+			upsertResult, err := rds.UpsertArea(ctx, models.AreaParams{Code: areaCode, AreaName: &models.AreaName{Name: "England"}}, "")
 
 			Convey("Then area details are updated to the existing area", func() {
 				So(err, ShouldBeNil)
 				So(upsertResult, ShouldEqual, false)
 			})
+
+			Convey("Then the change is audited by the user in the same transaction", func() {
+				execCalls := transactionMock.ExecCalls()
+				So(execCalls[0].SQL, ShouldEqual, insertAreaAudit)
+				So(execCalls[0].Arguments, ShouldResemble, []interface{}{areaCode, "publisher@ons.gov.uk", models.AreaAuditUpdate})
+				So(execCalls[len(execCalls)-1].SQL, ShouldEqual, setAreaAuditAfter)
+				So(execCalls[len(execCalls)-1].Arguments, ShouldResemble, []interface{}{areaCode})
+			})
 		})
 	})
 
@@ -392,7 +541,7 @@ func TestRDS_UpsertArea(t *testing.T) {
 		}}
 
 		Convey("When area is upserted in rds", func() {
-			upsertResult, err := rds.UpsertArea(context.Background(), models.AreaParams{Code: areaCode, AreaName: &models.AreaName{Name: "England"}})
+			upsertResult, err := rds.UpsertArea(context.Background(), models.AreaParams{Code: areaCode, AreaName: &models.AreaName{Name: "England"}}, "")
 
 			Convey("Then new area detail and area name details should be inserted to DB", func() {
 				So(err, ShouldBeNil)
@@ -434,10 +583,1069 @@ func TestRDS_UpsertArea(t *testing.T) {
 		}}
 
 		Convey("When an error occurs while upserting area data", func() {
-			_, err := rds.UpsertArea(context.Background(), models.AreaParams{Code: areaCode, AreaName: &models.AreaName{Name: "England"}})
+			_, err := rds.UpsertArea(context.Background(), models.AreaParams{Code: areaCode, AreaName: &models.AreaName{Name: "England"}}, "")
+
+			Convey("Then error is returned and transaction should be rolled back", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+	})
+}
+
+func TestRDS_UpsertAreaMaintainsClosure(t *testing.T) {
+	// newTransactionMock returns a transaction in which the area has the given previous parents and the new parent is
+	// a descendant of the area when isCycle is set
+	newTransactionMock := func(previousParents int64, isCycle bool) *pgxMock.PGXTransactionMock {
+		return &pgxMock.PGXTransactionMock{
+			QueryRowFunc: func(ctx context.Context, sql string, args ...interface{}) pgx.Row {
+				return &pgxMock.PGXRowMock{
+					ScanFunc: func(dest ...interface{}) error {
+						switch sql {
+						case upsertArea:
+							*dest[0].(*bool) = true
+						case isAreaDescendant:
+							*dest[0].(*bool) = isCycle
+						default:
+							*dest[0].(*int) = 1
+						}
+						return nil
+					},
+				}
+			},
+			ExecFunc: func(ctx context.Context, sql string, arguments ...interface{}) (pgconn.CommandTag, error) {
+				if sql == deleteOtherAreaParents {
+					return pgconn.CommandTag(fmt.Sprintf("DELETE %d", previousParents)), nil
+				}
+				return nil, nil
+			},
+			CommitFunc:   func(ctx context.Context) error { return nil },
+			RollbackFunc: func(ctx context.Context) error { return nil },
+		}
+	}
+
+	Convey("Given a new area details with a parent area", t, func() {
+		transactionMock := newTransactionMock(0, false)
+
+		rds := RDS{conn: &pgxMock.PGXPoolMock{
+			BeginFunc: func(ctx context.Context) (pgx.Tx, error) { return transactionMock, nil },
+		}}
+
+		Convey("When area is upserted in rds", func() {
+			_, err := rds.UpsertArea(context.Background(), models.AreaParams{Code: "E08000019", ParentCode: "E12000003", AreaName: &models.AreaName{Name: "Sheffield"}}, "")
+
+			Convey("Then the area and its paths from the parent are written to area_closure in the same transaction", func() {
+				So(err, ShouldBeNil)
+				var closureCalls [][]interface{}
+				for _, call := range transactionMock.ExecCalls() {
+					if call.SQL == insertAreaClosureSelf || call.SQL == insertAreaClosurePaths {
+						closureCalls = append(closureCalls, call.Arguments)
+					}
+				}
+				So(closureCalls, ShouldResemble, [][]interface{}{
+					{"E08000019"},
+					{"E12000003"},
+					{"E12000003", "E08000019"},
+				})
+				So(transactionMock.CommitCalls(), ShouldHaveLength, 1)
+			})
+
+			Convey("Then the area and its parent are notified as changed in the same transaction", func() {
+				So(err, ShouldBeNil)
+				var notifyCalls [][]interface{}
+				for _, call := range transactionMock.ExecCalls() {
+					if call.SQL == notifyAreaChanged {
+						notifyCalls = append(notifyCalls, call.Arguments)
+					}
+				}
+				So(notifyCalls, ShouldResemble, [][]interface{}{{"E08000019"}, {"E12000003"}})
+			})
+
+			Convey("Then no previous ancestors are removed from area_closure", func() {
+				for _, call := range transactionMock.ExecCalls() {
+					So(call.SQL, ShouldNotEqual, deleteAreaClosureAncestors)
+				}
+			})
+		})
+	})
+
+	Convey("Given an area that already has another parent", t, func() {
+		transactionMock := newTransactionMock(1, false)
+		rds := RDS{conn: &pgxMock.PGXPoolMock{
+			BeginFunc: func(ctx context.Context) (pgx.Tx, error) { return transactionMock, nil },
+		}}
+
+		Convey("When area is upserted in rds", func() {
+			_, err := rds.UpsertArea(context.Background(), models.AreaParams{Code: "E08000019", ParentCode: "W92000004", AreaName: &models.AreaName{Name: "Sheffield"}}, "")
+
+			Convey("Then the new parent is added without removing the existing one", func() {
+				So(err, ShouldBeNil)
+				for _, call := range transactionMock.ExecCalls() {
+					So(call.SQL, ShouldNotEqual, deleteOtherAreaParents)
+					So(call.SQL, ShouldNotEqual, deleteAreaClosureAncestors)
+				}
+				So(transactionMock.CommitCalls(), ShouldHaveLength, 1)
+			})
+		})
+	})
+
+	Convey("Given an area that is moved under one of its descendants", t, func() {
+		transactionMock := newTransactionMock(1, true)
+		rds := RDS{conn: &pgxMock.PGXPoolMock{
+			BeginFunc: func(ctx context.Context) (pgx.Tx, error) { return transactionMock, nil },
+		}}
+
+		Convey("When area is upserted in rds", func() {
+			_, err := rds.UpsertArea(context.Background(), models.AreaParams{Code: "E12000003", ParentCode: "E08000019", AreaName: &models.AreaName{Name: "Yorkshire and the Humber"}}, "")
+
+			Convey("Then it is refused as invalid data and the transaction is rolled back", func() {
+				So(errors.Is(err, apierrors.ErrInvalidData), ShouldBeTrue)
+				So(errors.Is(err, apierrors.ErrAreaParentCycle), ShouldBeTrue)
+				So(transactionMock.RollbackCalls(), ShouldHaveLength, 1)
+				So(transactionMock.CommitCalls(), ShouldHaveLength, 0)
+			})
+		})
+	})
+
+	Convey("Given area_closure cannot be written to", t, func() {
+		count := 0
+		queryRowMock := &pgxMock.PGXRowsMock{
+			ScanFunc: func(dest ...interface{}) error {
+				if count < 1 {
+					*dest[0].(*int) = 1
+				} else {
+					*dest[0].(*bool) = true
+				}
+				count += 1
+				return nil
+			},
+		}
+
+		transactionMock := &pgxMock.PGXTransactionMock{
+			QueryRowFunc: func(ctx context.Context, sql string, args ...interface{}) pgx.Row { return queryRowMock },
+			ExecFunc: func(ctx context.Context, sql string, arguments ...interface{}) (pgconn.CommandTag, error) {
+				if sql == insertAreaClosureSelf {
+					return nil, errors.New("relation area_closure does not exist")
+				}
+				return nil, nil
+			},
+			RollbackFunc: func(ctx context.Context) error { return nil },
+		}
+
+		rds := RDS{conn: &pgxMock.PGXPoolMock{
+			BeginFunc: func(ctx context.Context) (pgx.Tx, error) { return transactionMock, nil },
+		}}
+
+		Convey("When area is upserted in rds", func() {
+			_, err := rds.UpsertArea(context.Background(), models.AreaParams{Code: "E92000001", AreaName: &models.AreaName{Name: "England"}}, "")
 
 			Convey("Then error is returned and transaction should be rolled back", func() {
 				So(err, ShouldNotBeNil)
+				So(transactionMock.RollbackCalls(), ShouldHaveLength, 1)
+			})
+		})
+	})
+}
+
+func TestRDS_RebuildAreaClosure(t *testing.T) {
+	Convey("Given a database with area relationships", t, func() {
+		transactionMock := &pgxMock.PGXTransactionMock{
+			ExecFunc: func(ctx context.Context, sql string, arguments ...interface{}) (pgconn.CommandTag, error) {
+				return nil, nil
+			},
+			CommitFunc: func(ctx context.Context) error { return nil },
+		}
+
+		rds := RDS{conn: &pgxMock.PGXPoolMock{
+			BeginFunc: func(ctx context.Context) (pgx.Tx, error) { return transactionMock, nil },
+		}}
+
+		Convey("When the area closure is rebuilt", func() {
+			err := rds.RebuildAreaClosure(context.Background())
+
+			Convey("Then the table is cleared and repopulated in a single transaction", func() {
+				So(err, ShouldBeNil)
+				So(transactionMock.ExecCalls(), ShouldHaveLength, 2)
+				So(transactionMock.ExecCalls()[0].SQL, ShouldEqual, deleteAreaClosure)
+				So(transactionMock.ExecCalls()[1].SQL, ShouldEqual, rebuildAreaClosure)
+				So(transactionMock.ExecCalls()[1].Arguments, ShouldResemble, []interface{}{maxClosureDepth})
+				So(transactionMock.CommitCalls(), ShouldHaveLength, 1)
+			})
+		})
+	})
+
+	Convey("Given the rebuild query fails", t, func() {
+		transactionMock := &pgxMock.PGXTransactionMock{
+			ExecFunc: func(ctx context.Context, sql string, arguments ...interface{}) (pgconn.CommandTag, error) {
+				if sql == rebuildAreaClosure {
+					return nil, errors.New("statement timeout")
+				}
+				return nil, nil
+			},
+			RollbackFunc: func(ctx context.Context) error { return nil },
+		}
+
+		rds := RDS{conn: &pgxMock.PGXPoolMock{
+			BeginFunc: func(ctx context.Context) (pgx.Tx, error) { return transactionMock, nil },
+		}}
+
+		Convey("When the area closure is rebuilt", func() {
+			err := rds.RebuildAreaClosure(context.Background())
+
+			Convey("Then error is returned and the existing closure is kept", func() {
+				So(err, ShouldNotBeNil)
+				So(transactionMock.RollbackCalls(), ShouldHaveLength, 1)
+				So(transactionMock.CommitCalls(), ShouldHaveLength, 0)
+			})
+		})
+	})
+}
+
+func TestRDS_GetChildRelationships(t *testing.T) {
+	Convey("Given an area code with child areas", t, func() {
+		rowMock := &pgxMock.PGXRowsMock{
+			CloseFunc: func() {},
+			NextFunc:  func() bool { return false },
+			ErrFunc:   func() error { return nil },
+		}
+
+		poolMock := &pgxMock.PGXPoolMock{
+			QueryFunc: func(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error) {
+				return rowMock, nil
+			},
+		}
+		rds := RDS{conn: poolMock}
+
+		Convey("When child relationships are fetched", func() {
+			_, err := rds.GetRelationships(context.Background(), "E12000003", "child", false)
+
+			Convey("Then the closure table is queried for direct descendants", func() {
+				So(err, ShouldBeNil)
+				So(poolMock.QueryCalls(), ShouldHaveLength, 1)
+				So(poolMock.QueryCalls()[0].SQL, ShouldEqual, getChildAreas)
+				So(poolMock.QueryCalls()[0].Args, ShouldResemble, []interface{}{"E12000003", false})
+			})
+		})
+	})
+}
+
+func TestRDS_RetireArea(t *testing.T) {
+	newTransactionMock := func(liveChildren int, isActive bool) *pgxMock.PGXTransactionMock {
+		return &pgxMock.PGXTransactionMock{
+			QueryRowFunc: func(ctx context.Context, sql string, args ...interface{}) pgx.Row {
+				return &pgxMock.PGXRowMock{
+					ScanFunc: func(dest ...interface{}) error {
+						switch sql {
+						case getAreaRetirementForUpdate:
+							*dest[0].(*int) = 3
+							*dest[1].(*bool) = isActive
+						case countLiveChildAreas:
+							*dest[0].(*int) = liveChildren
+						}
+						return nil
+					},
+				}
+			},
+			ExecFunc: func(ctx context.Context, sql string, arguments ...interface{}) (pgconn.CommandTag, error) {
+				return nil, nil
+			},
+			CommitFunc:   func(ctx context.Context) error { return nil },
+			RollbackFunc: func(ctx context.Context) error { return nil },
+		}
+	}
+
+	Convey("Given an area without live children", t, func() {
+		transactionMock := newTransactionMock(0, true)
+		rds := RDS{conn: &pgxMock.PGXPoolMock{
+			BeginFunc: func(ctx context.Context) (pgx.Tx, error) { return transactionMock, nil },
+		}}
+
+		Convey("When the area is retired", func() {
+			err := rds.RetireArea(context.Background(), "E08000019", false, "")
+
+			Convey("Then the area is audited, ended, hidden and notified as changed without touching its descendants", func() {
+				So(err, ShouldBeNil)
+				So(transactionMock.ExecCalls(), ShouldHaveLength, 4)
+				So(transactionMock.ExecCalls()[0].SQL, ShouldEqual, insertAreaAudit)
+				So(transactionMock.ExecCalls()[0].Arguments, ShouldResemble, []interface{}{"E08000019", auth.SystemActor, models.AreaAuditRetire})
+				So(transactionMock.ExecCalls()[1].SQL, ShouldEqual, retireArea)
+				So(transactionMock.ExecCalls()[1].Arguments, ShouldResemble, []interface{}{"E08000019"})
+				So(transactionMock.ExecCalls()[2].SQL, ShouldEqual, setAreaAuditAfter)
+				So(transactionMock.ExecCalls()[2].Arguments, ShouldResemble, []interface{}{"E08000019"})
+				So(transactionMock.ExecCalls()[3].SQL, ShouldEqual, notifyAreaChanged)
+				So(transactionMock.ExecCalls()[3].Arguments, ShouldResemble, []interface{}{"E08000019"})
+				So(transactionMock.CommitCalls(), ShouldHaveLength, 1)
+			})
+		})
+	})
+
+	Convey("Given an area with live children", t, func() {
+		transactionMock := newTransactionMock(2, true)
+		rds := RDS{conn: &pgxMock.PGXPoolMock{
+			BeginFunc: func(ctx context.Context) (pgx.Tx, error) { return transactionMock, nil },
+		}}
+
+		Convey("When the area is retired without cascade", func() {
+			err := rds.RetireArea(context.Background(), "E12000003", false, "")
+
+			Convey("Then the retirement is refused and rolled back", func() {
+				So(err, ShouldEqual, apierrors.ErrAreaHasLiveChildren)
+				So(transactionMock.ExecCalls(), ShouldHaveLength, 0)
+				So(transactionMock.RollbackCalls(), ShouldHaveLength, 1)
+				So(transactionMock.CommitCalls(), ShouldHaveLength, 0)
+			})
+		})
+
+		Convey("When the area is retired with cascade", func() {
+			ctx := context.WithValue(context.Background(), dprequest.UserIdentityKey, "publisher@ons.gov.uk")
+			err := rds.RetireArea(ctx, "E12000003", true, "")
+
+			Convey("Then the descendants are audited and retired before the area in the same transaction", func() {
+				So(err, ShouldBeNil)
+				So(transactionMock.ExecCalls(), ShouldHaveLength, 8)
+				So(transactionMock.ExecCalls()[0].SQL, ShouldEqual, insertDescendantAreaAudits)
+				So(transactionMock.ExecCalls()[0].Arguments, ShouldResemble, []interface{}{"E12000003", "publisher@ons.gov.uk"})
+				So(transactionMock.ExecCalls()[1].SQL, ShouldEqual, retireDescendantAreas)
+				So(transactionMock.ExecCalls()[2].SQL, ShouldEqual, setDescendantAreaAuditsAfter)
+				So(transactionMock.ExecCalls()[3].SQL, ShouldEqual, notifyDescendantAreasChanged)
+				So(transactionMock.ExecCalls()[4].SQL, ShouldEqual, insertAreaAudit)
+				So(transactionMock.ExecCalls()[4].Arguments, ShouldResemble, []interface{}{"E12000003", "publisher@ons.gov.uk", models.AreaAuditRetire})
+				So(transactionMock.ExecCalls()[5].SQL, ShouldEqual, retireArea)
+				So(transactionMock.ExecCalls()[6].SQL, ShouldEqual, setAreaAuditAfter)
+				So(transactionMock.ExecCalls()[7].SQL, ShouldEqual, notifyAreaChanged)
+				So(transactionMock.CommitCalls(), ShouldHaveLength, 1)
+			})
+		})
+	})
+
+	Convey("Given an area that is already retired", t, func() {
+		transactionMock := newTransactionMock(2, false)
+		rds := RDS{conn: &pgxMock.PGXPoolMock{
+			BeginFunc: func(ctx context.Context) (pgx.Tx, error) { return transactionMock, nil },
+		}}
+
+		Convey("When the area is retired again", func() {
+			err := rds.RetireArea(context.Background(), "E12000003", true, `"3"`)
+
+			Convey("Then nothing is written, audited or notified", func() {
+				So(err, ShouldBeNil)
+				So(transactionMock.ExecCalls(), ShouldHaveLength, 0)
+				So(transactionMock.RollbackCalls(), ShouldHaveLength, 1)
+				So(transactionMock.CommitCalls(), ShouldHaveLength, 0)
+			})
+		})
+	})
+
+	Convey("Given an area that does not exist", t, func() {
+		transactionMock := &pgxMock.PGXTransactionMock{
+			QueryRowFunc: func(ctx context.Context, sql string, args ...interface{}) pgx.Row {
+				return &pgxMock.PGXRowMock{ScanFunc: func(dest ...interface{}) error { return pgx.ErrNoRows }}
+			},
+			RollbackFunc: func(ctx context.Context) error { return nil },
+		}
+		rds := RDS{conn: &pgxMock.PGXPoolMock{
+			BeginFunc: func(ctx context.Context) (pgx.Tx, error) { return transactionMock, nil },
+		}}
+
+		Convey("When the area is retired", func() {
+			err := rds.RetireArea(context.Background(), "E99999999", true, "")
+
+			Convey("Then a no rows error is returned, classified as not found", func() {
+				So(errors.Is(err, pgx.ErrNoRows), ShouldBeTrue)
+				So(errors.Is(err, apierrors.ErrNotFound), ShouldBeTrue)
+				So(transactionMock.RollbackCalls(), ShouldHaveLength, 1)
+			})
+		})
+	})
+}
+
+func TestRDS_PatchArea(t *testing.T) {
+	newTransactionMock := func() *pgxMock.PGXTransactionMock {
+		return &pgxMock.PGXTransactionMock{
+			QueryRowFunc: func(ctx context.Context, sql string, args ...interface{}) pgx.Row {
+				return &pgxMock.PGXRowMock{
+					ScanFunc: func(dest ...interface{}) error {
+						switch sql {
+						case getAreaForUpdate:
+							geometry, areaType, name := "[[[-1.8,53.5]]]", "Metropolitan Districts", "Sheffield"
+							visible := true
+							*dest[0].(*string) = "E08000019"
+							*dest[3].(**string) = &geometry
+							*dest[4].(**bool) = &visible
+							*dest[6].(**string) = &areaType
+							*dest[7].(**string) = &name
+						case getAreaType:
+							*dest[0].(*int) = 8
+						case upsertArea:
+							*dest[0].(*bool) = false
+						}
+						return nil
+					},
+				}
+			},
+			ExecFunc: func(ctx context.Context, sql string, arguments ...interface{}) (pgconn.CommandTag, error) {
+				if sql == deleteOtherAreaParents {
+					return pgconn.CommandTag("DELETE 1"), nil
+				}
+				return nil, nil
+			},
+			CommitFunc:   func(ctx context.Context) error { return nil },
+			RollbackFunc: func(ctx context.Context) error { return nil },
+		}
+	}
+
+	Convey("Given an existing area", t, func() {
+		transactionMock := newTransactionMock()
+		rds := RDS{conn: &pgxMock.PGXPoolMock{
+			BeginFunc: func(ctx context.Context) (pgx.Tx, error) { return transactionMock, nil },
+		}}
+
+		Convey("When the visible flag is patched", func() {
+			patch := models.AreaPatch{{Op: models.PatchOpReplace, Path: "/visible", Value: []byte(`false`)}}
+			err := rds.PatchArea(context.Background(), "E08000019", patch, "")
+
+			Convey("Then the area is locked and upserted with the rest of its details unchanged", func() {
+				So(err, ShouldBeNil)
+				queryRowCalls := transactionMock.QueryRowCalls()
+				So(queryRowCalls[0].SQL, ShouldEqual, getAreaForUpdate)
+				So(queryRowCalls[2].SQL, ShouldEqual, upsertArea)
+				So(queryRowCalls[2].Args[3], ShouldEqual, "[[[-1.8,53.5]]]")
+				So(*queryRowCalls[2].Args[5].(*bool), ShouldBeFalse)
+				So(transactionMock.CommitCalls(), ShouldHaveLength, 1)
+			})
+		})
+
+		Convey("When the area is renamed", func() {
+			patch := models.AreaPatch{{Op: models.PatchOpReplace, Path: "/area_name/name", Value: []byte(`"Sheffield City"`)}}
+			err := rds.PatchArea(context.Background(), "E08000019", patch, "")
+
+			Convey("Then the area is audited and the existing area_name row is renamed before the upsert", func() {
+				So(err, ShouldBeNil)
+				execCalls := transactionMock.ExecCalls()
+				So(execCalls[0].SQL, ShouldEqual, insertAreaAudit)
+				So(execCalls[0].Arguments, ShouldResemble, []interface{}{"E08000019", auth.SystemActor, models.AreaAuditPatch})
+				So(execCalls[1].SQL, ShouldEqual, renameAreaName)
+				So(execCalls[1].Arguments, ShouldResemble, []interface{}{"E08000019", "Sheffield", "Sheffield City"})
+				So(execCalls[len(execCalls)-1].SQL, ShouldEqual, setAreaAuditAfter)
+				So(transactionMock.CommitCalls(), ShouldHaveLength, 1)
+			})
+		})
+
+		Convey("When the parent is patched", func() {
+			patch := models.AreaPatch{{Op: models.PatchOpReplace, Path: "/parent_code", Value: []byte(`"W92000004"`)}}
+			err := rds.PatchArea(context.Background(), "E08000019", patch, "")
+
+			Convey("Then its previous parent and ancestry are removed before the new ones are written", func() {
+				So(err, ShouldBeNil)
+				var moveCalls []string
+				for _, call := range transactionMock.ExecCalls() {
+					switch call.SQL {
+					case deleteOtherAreaParents, deleteAreaClosureAncestors, areaRelationshipInsertTransaction, insertAreaClosurePaths:
+						moveCalls = append(moveCalls, call.SQL)
+					}
+				}
+				So(moveCalls, ShouldResemble, []string{deleteOtherAreaParents, deleteAreaClosureAncestors, areaRelationshipInsertTransaction, insertAreaClosurePaths})
+				So(transactionMock.CommitCalls(), ShouldHaveLength, 1)
+			})
+		})
+
+		Convey("When a field other than the parent is patched", func() {
+			err := rds.PatchArea(context.Background(), "E08000019", models.AreaPatch{{Op: models.PatchOpReplace, Path: "/visible", Value: []byte(`false`)}}, "")
+
+			Convey("Then no parent is removed", func() {
+				So(err, ShouldBeNil)
+				for _, call := range transactionMock.ExecCalls() {
+					So(call.SQL, ShouldNotEqual, deleteOtherAreaParents)
+				}
+			})
+		})
+
+		Convey("When the patched area fails validation", func() {
+			patch := models.AreaPatch{{Op: models.PatchOpReplace, Path: "/parent_code", Value: []byte(`"E08000019"`)}}
+			err := rds.PatchArea(context.Background(), "E08000019", patch, "")
+
+			Convey("Then an invalid patch error is returned and the transaction is rolled back", func() {
+				So(errors.Is(err, apierrors.ErrInvalidAreaPatch), ShouldBeTrue)
+				So(transactionMock.RollbackCalls(), ShouldHaveLength, 1)
+				So(transactionMock.CommitCalls(), ShouldHaveLength, 0)
+			})
+		})
+	})
+
+	Convey("Given an area that does not exist", t, func() {
+		transactionMock := &pgxMock.PGXTransactionMock{
+			QueryRowFunc: func(ctx context.Context, sql string, args ...interface{}) pgx.Row {
+				return &pgxMock.PGXRowMock{ScanFunc: func(dest ...interface{}) error { return pgx.ErrNoRows }}
+			},
+			RollbackFunc: func(ctx context.Context) error { return nil },
+		}
+		rds := RDS{conn: &pgxMock.PGXPoolMock{
+			BeginFunc: func(ctx context.Context) (pgx.Tx, error) { return transactionMock, nil },
+		}}
+
+		Convey("When the area is patched", func() {
+			err := rds.PatchArea(context.Background(), "E99999999", models.AreaPatch{{Op: models.PatchOpReplace, Path: "/visible", Value: []byte(`false`)}}, "")
+
+			Convey("Then a no rows error is returned, classified as not found", func() {
+				So(errors.Is(err, pgx.ErrNoRows), ShouldBeTrue)
+				So(errors.Is(err, apierrors.ErrNotFound), ShouldBeTrue)
+				So(transactionMock.RollbackCalls(), ShouldHaveLength, 1)
+			})
+		})
+	})
+}
+
+func TestRDS_BulkUpsertAreas(t *testing.T) {
+	areas := []models.AreaParams{
+		{Code: "E12000003", AreaName: &models.AreaName{Name: "Yorkshire and the Humber"}},
+		{Code: "E08000019", ParentCode: "E12000003", AreaName: &models.AreaName{Name: "Sheffield"}},
+		{Code: "E08000018", ParentCode: "E12000003", AreaName: &models.AreaName{Name: "Rotherham"}},
+	}
+
+	newTransactionMock := func(failingCode string) *pgxMock.PGXTransactionMock {
+		return &pgxMock.PGXTransactionMock{
+			QueryRowFunc: func(ctx context.Context, sql string, args ...interface{}) pgx.Row {
+				return &pgxMock.PGXRowMock{
+					ScanFunc: func(dest ...interface{}) error {
+						switch sql {
+						case upsertArea:
+							if args[0] == failingCode {
+								return errors.New("constraint violation")
+							}
+							*dest[0].(*bool) = true
+						case isAreaDescendant:
+							*dest[0].(*bool) = false
+						default:
+							*dest[0].(*int) = 1
+						}
+						return nil
+					},
+				}
+			},
+			ExecFunc: func(ctx context.Context, sql string, arguments ...interface{}) (pgconn.CommandTag, error) {
+				return nil, nil
+			},
+			CommitFunc:   func(ctx context.Context) error { return nil },
+			RollbackFunc: func(ctx context.Context) error { return nil },
+		}
+	}
+
+	Convey("Given areas to upsert in a single transaction", t, func() {
+		transactionMock := newTransactionMock("")
+		poolMock := &pgxMock.PGXPoolMock{
+			BeginFunc: func(ctx context.Context) (pgx.Tx, error) { return transactionMock, nil },
+		}
+		rds := RDS{conn: poolMock}
+
+		Convey("When the areas are bulk upserted", func() {
+			results, err := rds.BulkUpsertAreas(context.Background(), areas, 0)
+
+			Convey("Then every area is written in one transaction", func() {
+				So(err, ShouldBeNil)
+				So(poolMock.BeginCalls(), ShouldHaveLength, 1)
+				So(transactionMock.CommitCalls(), ShouldHaveLength, 1)
+				So(results, ShouldHaveLength, 3)
+				for _, result := range results {
+					So(result.Status, ShouldEqual, models.BulkAreaCreated)
+				}
+			})
+		})
+	})
+
+	Convey("Given areas to upsert in batches where the second batch fails", t, func() {
+		transactionMock := newTransactionMock("E08000018")
+		poolMock := &pgxMock.PGXPoolMock{
+			BeginFunc: func(ctx context.Context) (pgx.Tx, error) { return transactionMock, nil },
+		}
+		rds := RDS{conn: poolMock}
+
+		Convey("When the areas are bulk upserted with a batch size of 2", func() {
+			results, err := rds.BulkUpsertAreas(context.Background(), append(areas, models.AreaParams{Code: "E08000016", AreaName: &models.AreaName{Name: "Barnsley"}}), 2)
+
+			Convey("Then the first batch is kept, the failing batch is rolled back and nothing else is attempted", func() {
+				So(err, ShouldNotBeNil)
+				So(poolMock.BeginCalls(), ShouldHaveLength, 2)
+				So(transactionMock.CommitCalls(), ShouldHaveLength, 1)
+				So(transactionMock.RollbackCalls(), ShouldHaveLength, 1)
+				So(results[0].Status, ShouldEqual, models.BulkAreaCreated)
+				So(results[1].Status, ShouldEqual, models.BulkAreaCreated)
+				So(results[2].Status, ShouldEqual, models.BulkAreaFailed)
+				So(results[2].Errors, ShouldHaveLength, 1)
+				So(results[3].Status, ShouldEqual, models.BulkAreaSkipped)
+			})
+		})
+	})
+}
+
+func TestRDS_GetAreaAudit(t *testing.T) {
+	Convey("Given an area with a created and a patched audit entry", t, func() {
+		changedAt := time.Date(2022, 3, 1, 12, 0, 0, 0, time.UTC)
+		states := [][2][]byte{
+			{[]byte(`{"code": "E05000001", "version": 1, "names": [{"name": "Ward One"}]}`), []byte(`{"code": "E05000001", "version": 2, "names": [{"name": "Ward 1"}]}`)},
+			{nil, []byte(`{"code": "E05000001", "parent_code": "E08000019", "version": 1, "active_from": "2020-01-01T00:00:00+00:00"}`)},
+		}
+		operations := []string{models.AreaAuditPatch, models.AreaAuditCreate}
+
+		callCount := 0
+		rowsMock := &pgxMock.PGXRowsMock{
+			CloseFunc: func() {},
+			ErrFunc:   func() error { return nil },
+			NextFunc:  func() bool { return callCount < len(states) },
+			ScanFunc: func(dest ...interface{}) error {
+				*dest[0].(*int64) = int64(len(states) - callCount)
+				*dest[1].(*string) = "E05000001"
+				*dest[2].(*string) = "publisher@ons.gov.uk"
+				*dest[3].(*string) = operations[callCount]
+				*dest[4].(*time.Time) = changedAt
+				*dest[5].(*[]byte) = states[callCount][0]
+				*dest[6].(*[]byte) = states[callCount][1]
+				callCount++
+				return nil
+			},
+		}
+
+		poolMock := &pgxMock.PGXPoolMock{
+			QueryRowFunc: func(ctx context.Context, sql string, args ...interface{}) pgx.Row {
+				return &pgxMock.PGXRowMock{ScanFunc: func(dest ...interface{}) error {
+					*dest[0].(*int) = 5
+					return nil
+				}}
+			},
+			QueryFunc: func(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error) {
+				return rowsMock, nil
+			},
+		}
+		rds := RDS{conn: poolMock}
+
+		Convey("When a page of its audit log is read", func() {
+			from := changedAt.Add(-time.Hour)
+			page, err := rds.GetAreaAudit(context.Background(), "E05000001", models.AreaAuditQuery{From: &from, Offset: 3, Limit: 2})
+
+			Convey("Then the page is returned with the before and after states decoded", func() {
+				So(err, ShouldBeNil)
+				So(page.TotalCount, ShouldEqual, 5)
+				So(page.Count, ShouldEqual, 2)
+				So(page.Offset, ShouldEqual, 3)
+				So(page.Limit, ShouldEqual, 2)
+				So(page.Items[0].Operation, ShouldEqual, models.AreaAuditPatch)
+				So(page.Items[0].Before.Names[0].Name, ShouldEqual, "Ward One")
+				So(page.Items[0].After.Names[0].Name, ShouldEqual, "Ward 1")
+				So(page.Items[1].Before, ShouldBeNil)
+				So(page.Items[1].After.ParentCode, ShouldEqual, "E08000019")
+				So(page.Items[1].After.ActiveFrom.Equal(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)), ShouldBeTrue)
+			})
+
+			Convey("Then the dates and page are passed to the query", func() {
+				So(poolMock.QueryCalls()[0].SQL, ShouldEqual, getAreaAudit)
+				So(poolMock.QueryCalls()[0].Args, ShouldResemble, []interface{}{"E05000001", &from, (*time.Time)(nil), 3, 2})
+			})
+		})
+	})
+}
+
+func TestRDS_IfMatch(t *testing.T) {
+	newTransactionMock := func(version int, versionErr error) *pgxMock.PGXTransactionMock {
+		return &pgxMock.PGXTransactionMock{
+			QueryRowFunc: func(ctx context.Context, sql string, args ...interface{}) pgx.Row {
+				return &pgxMock.PGXRowMock{
+					ScanFunc: func(dest ...interface{}) error {
+						switch sql {
+						case getAreaVersionForUpdate:
+							if versionErr != nil {
+								return versionErr
+							}
+							*dest[0].(*int) = version
+						case getAreaRetirementForUpdate:
+							if versionErr != nil {
+								return versionErr
+							}
+							*dest[0].(*int) = version
+							*dest[1].(*bool) = true
+						case getAreaForUpdate:
+							name := "Sheffield"
+							*dest[0].(*string) = "E08000019"
+							*dest[7].(**string) = &name
+							*dest[10].(*int) = version
+						case upsertArea:
+							*dest[0].(*bool) = false
+						default:
+							*dest[0].(*int) = 1
+						}
+						return nil
+					},
+				}
+			},
+			ExecFunc: func(ctx context.Context, sql string, arguments ...interface{}) (pgconn.CommandTag, error) {
+				return nil, nil
+			},
+			CommitFunc:   func(ctx context.Context) error { return nil },
+			RollbackFunc: func(ctx context.Context) error { return nil },
+		}
+	}
+	area := models.AreaParams{Code: "E08000019", AreaType: "Metropolitan Districts", AreaName: &models.AreaName{Name: "Sheffield"}}
+	patch := models.AreaPatch{{Op: models.PatchOpReplace, Path: "/visible", Value: []byte(`false`)}}
+
+	Convey("Given an area at version 2", t, func() {
+		transactionMock := newTransactionMock(2, nil)
+		rds := RDS{conn: &pgxMock.PGXPoolMock{
+			BeginFunc: func(ctx context.Context) (pgx.Tx, error) { return transactionMock, nil },
+		}}
+
+		Convey("When it is upserted with its current ETag", func() {
+			_, err := rds.UpsertArea(context.Background(), area, `"2"`)
+
+			Convey("Then the write is committed", func() {
+				So(err, ShouldBeNil)
+				So(transactionMock.QueryRowCalls()[0].SQL, ShouldEqual, getAreaVersionForUpdate)
+				So(transactionMock.CommitCalls(), ShouldHaveLength, 1)
+			})
+		})
+
+		Convey("When it is upserted with a stale ETag", func() {
+			_, err := rds.UpsertArea(context.Background(), area, `"1"`)
+
+			Convey("Then the precondition fails and nothing is written", func() {
+				So(err, ShouldEqual, apierrors.ErrPreconditionFailed)
+				So(transactionMock.ExecCalls(), ShouldHaveLength, 0)
+				So(transactionMock.RollbackCalls(), ShouldHaveLength, 1)
+			})
+		})
+
+		Convey("When it is patched with a stale ETag", func() {
+			err := rds.PatchArea(context.Background(), "E08000019", patch, `"1"`)
+
+			Convey("Then the precondition fails and nothing is written", func() {
+				So(err, ShouldEqual, apierrors.ErrPreconditionFailed)
+				So(transactionMock.QueryRowCalls(), ShouldHaveLength, 1)
+				So(transactionMock.RollbackCalls(), ShouldHaveLength, 1)
+			})
+		})
+
+		Convey("When it is retired with a stale ETag", func() {
+			err := rds.RetireArea(context.Background(), "E08000019", false, `"1"`)
+
+			Convey("Then the precondition fails and nothing is written", func() {
+				So(err, ShouldEqual, apierrors.ErrPreconditionFailed)
+				So(transactionMock.ExecCalls(), ShouldHaveLength, 0)
+				So(transactionMock.RollbackCalls(), ShouldHaveLength, 1)
+			})
+		})
+	})
+
+	Convey("Given an area that does not exist", t, func() {
+		transactionMock := newTransactionMock(0, pgx.ErrNoRows)
+		rds := RDS{conn: &pgxMock.PGXPoolMock{
+			BeginFunc: func(ctx context.Context) (pgx.Tx, error) { return transactionMock, nil },
+		}}
+
+		Convey("When it is upserted with If-Match", func() {
+			_, err := rds.UpsertArea(context.Background(), area, "*")
+
+			Convey("Then the precondition fails", func() {
+				So(err, ShouldEqual, apierrors.ErrPreconditionFailed)
+				So(transactionMock.RollbackCalls(), ShouldHaveLength, 1)
+			})
+		})
+	})
+}
+
+func TestRDS_WithSchemaLock(t *testing.T) {
+	schemaLockPollInterval = time.Millisecond
+
+	// lockTransaction returns a transaction whose attempts to take the schema lock succeed from the given attempt
+	lockTransaction := func(acquireOnAttempt int) *pgxMock.PGXTransactionMock {
+		attempts := 0
+		return &pgxMock.PGXTransactionMock{
+			QueryRowFunc: func(ctx context.Context, sql string, args ...interface{}) pgx.Row {
+				attempts++
+				return &pgxMock.PGXRowMock{
+					ScanFunc: func(dest ...interface{}) error {
+						*dest[0].(*bool) = attempts >= acquireOnAttempt
+						return nil
+					},
+				}
+			},
+			RollbackFunc: func(ctx context.Context) error { return nil },
+		}
+	}
+
+	Convey("Given the schema lock is free", t, func() {
+		tx := lockTransaction(1)
+		rds := RDS{conn: &pgxMock.PGXPoolMock{
+			BeginFunc: func(ctx context.Context) (pgx.Tx, error) { return tx, nil },
+		}}
+
+		Convey("When WithSchemaLock is invoked", func() {
+			called := false
+			err := rds.WithSchemaLock(context.Background(), func(ctx context.Context) error {
+				called = true
+				return nil
+			})
+
+			Convey("Then the function runs under the lock, which is released afterwards", func() {
+				So(err, ShouldBeNil)
+				So(called, ShouldBeTrue)
+				So(tx.QueryRowCalls(), ShouldHaveLength, 1)
+				So(tx.QueryRowCalls()[0].SQL, ShouldEqual, trySchemaLock)
+				So(tx.QueryRowCalls()[0].Args, ShouldResemble, []interface{}{schemaLockID})
+				So(tx.RollbackCalls(), ShouldHaveLength, 1)
+			})
+		})
+
+		Convey("When the function fails", func() {
+			err := rds.WithSchemaLock(context.Background(), func(ctx context.Context) error {
+				return errors.New("migration failed")
+			})
+
+			Convey("Then its error is returned and the lock released", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, "migration failed")
+				So(tx.RollbackCalls(), ShouldHaveLength, 1)
+			})
+		})
+	})
+
+	Convey("Given another instance releases the schema lock after a short wait", t, func() {
+		tx := lockTransaction(3)
+		rds := RDS{
+			conn: &pgxMock.PGXPoolMock{
+				BeginFunc: func(ctx context.Context) (pgx.Tx, error) { return tx, nil },
+			},
+			schemaLockTimeout: time.Minute,
+		}
+
+		Convey("When WithSchemaLock is invoked", func() {
+			called := false
+			err := rds.WithSchemaLock(context.Background(), func(ctx context.Context) error {
+				called = true
+				return nil
+			})
+
+			Convey("Then it waits for the lock and then runs the function", func() {
+				So(err, ShouldBeNil)
+				So(called, ShouldBeTrue)
+				So(tx.QueryRowCalls(), ShouldHaveLength, 3)
+			})
+		})
+	})
+
+	Convey("Given another instance holds the schema lock past the timeout", t, func() {
+		tx := lockTransaction(math.MaxInt32)
+		rds := RDS{
+			conn: &pgxMock.PGXPoolMock{
+				BeginFunc: func(ctx context.Context) (pgx.Tx, error) { return tx, nil },
+			},
+			schemaLockTimeout: 5 * time.Millisecond,
+		}
+
+		Convey("When WithSchemaLock is invoked", func() {
+			called := false
+			err := rds.WithSchemaLock(context.Background(), func(ctx context.Context) error {
+				called = true
+				return nil
+			})
+
+			Convey("Then a timeout error is returned without running the function", func() {
+				So(err, ShouldEqual, apierrors.ErrSchemaLockTimeout)
+				So(called, ShouldBeFalse)
+				So(tx.RollbackCalls(), ShouldHaveLength, 1)
+			})
+		})
+	})
+}
+
+func TestRDS_QueryContext(t *testing.T) {
+	// blockingRow is a row whose scan waits until the context of its query ends, as a query on a stalled database does
+	blockingRow := func(ctx context.Context) pgx.Row {
+		return &pgxMock.PGXRowMock{
+			ScanFunc: func(dest ...interface{}) error {
+				<-ctx.Done()
+				return errors.New("timeout: " + ctx.Err().Error())
+			},
+		}
+	}
+
+	Convey("Given a query timeout and a database that does not respond", t, func() {
+		rds := RDS{
+			queryTimeout: 10 * time.Millisecond,
+			conn: &pgxMock.PGXPoolMock{
+				QueryRowFunc: func(ctx context.Context, sql string, args ...interface{}) pgx.Row {
+					return blockingRow(ctx)
+				},
+			},
+		}
+
+		Convey("When an area is requested", func() {
+			start := time.Now()
+			_, err := rds.GetArea(context.Background(), "E92000001", false)
+
+			Convey("Then the query is abandoned at the timeout with a deadline exceeded error", func() {
+				So(errors.Is(err, context.DeadlineExceeded), ShouldBeTrue)
+				So(time.Since(start), ShouldBeLessThan, time.Second)
+			})
+		})
+	})
+
+	Convey("Given a request that is cancelled while its query runs", t, func() {
+		var queryCtx context.Context
+		rds := RDS{conn: &pgxMock.PGXPoolMock{
+			QueryFunc: func(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error) {
+				queryCtx = ctx
+				<-ctx.Done()
+				return nil, errors.New("conn closed")
+			},
+		}}
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(10*time.Millisecond, cancel)
+
+		Convey("When relationships are requested", func() {
+			_, err := rds.GetRelationships(ctx, "E92000001", "", false)
+
+			Convey("Then the query sees the cancellation and a cancelled error is returned", func() {
+				So(queryCtx.Err(), ShouldEqual, context.Canceled)
+				So(errors.Is(err, context.Canceled), ShouldBeTrue)
+			})
+		})
+	})
+
+	Convey("Given rows that end early because the request is cancelled", t, func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		rowsMock := &pgxMock.PGXRowsMock{
+			CloseFunc: func() {},
+			NextFunc: func() bool {
+				cancel()
+				return false
+			},
+			ErrFunc: func() error { return errors.New("conn closed") },
+		}
+		rds := RDS{conn: &pgxMock.PGXPoolMock{
+			QueryFunc: func(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error) {
+				return rowsMock, nil
+			},
+		}}
+
+		Convey("When ancestors are requested", func() {
+			ancestors, err := rds.GetAncestors(ctx, "E92000001")
+
+			Convey("Then the cancellation is returned rather than a partial result", func() {
+				So(errors.Is(err, context.Canceled), ShouldBeTrue)
+				So(ancestors, ShouldBeNil)
+			})
+		})
+	})
+
+	Convey("Given a request that is cancelled during a write", t, func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		transactionMock := &pgxMock.PGXTransactionMock{
+			QueryRowFunc: func(ctx context.Context, sql string, args ...interface{}) pgx.Row {
+				cancel()
+				return blockingRow(ctx)
+			},
+			ExecFunc: func(ctx context.Context, sql string, arguments ...interface{}) (pgconn.CommandTag, error) {
+				cancel()
+				return nil, ctx.Err()
+			},
+			RollbackFunc: func(ctx context.Context) error { return nil },
+		}
+		rds := RDS{conn: &pgxMock.PGXPoolMock{
+			BeginFunc: func(ctx context.Context) (pgx.Tx, error) { return transactionMock, nil },
+		}}
+
+		Convey("When an area is upserted", func() {
+			_, err := rds.UpsertArea(ctx, models.AreaParams{Code: "E92000001", AreaType: "Country"}, "")
+
+			Convey("Then the transaction is rolled back and a cancelled error is returned", func() {
+				So(errors.Is(err, context.Canceled), ShouldBeTrue)
+				So(transactionMock.RollbackCalls(), ShouldHaveLength, 1)
+				So(transactionMock.CommitCalls(), ShouldHaveLength, 0)
+			})
+		})
+	})
+}
+
+func TestRDS_ReadWriteSplit(t *testing.T) {
+	ctx := context.Background()
+	newPool := func() *pgxMock.PGXPoolMock {
+		return &pgxMock.PGXPoolMock{
+			QueryRowFunc: func(ctx context.Context, sql string, args ...interface{}) pgx.Row {
+				return &pgxMock.PGXRowMock{ScanFunc: func(dest ...interface{}) error { return nil }}
+			},
+			QueryFunc: func(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error) {
+				return &pgxMock.PGXRowsMock{
+					CloseFunc: func() {},
+					ErrFunc:   func() error { return nil },
+					NextFunc:  func() bool { return false },
+				}, nil
+			},
+			BeginFunc: func(ctx context.Context) (pgx.Tx, error) {
+				return nil, errors.New("no connection")
+			},
+			PingFunc:  func(ctx context.Context) error { return nil },
+			CloseFunc: func() {},
+		}
+	}
+	readAll := func(rds *RDS) {
+		So(rds.ValidateArea(ctx, "E92000001", false), ShouldBeNil)
+		_, err := rds.GetArea(ctx, "E92000001", false)
+		So(err, ShouldBeNil)
+		_, err = rds.GetRelationships(ctx, "E92000001", "", false)
+		So(err, ShouldBeNil)
+		_, err = rds.GetAncestors(ctx, "E92000001")
+		So(err, ShouldBeNil)
+	}
+
+	Convey("Given a store with writer and reader pools", t, func() {
+		writer, reader := newPool(), newPool()
+		rds := &RDS{conn: writer, reader: reader}
+
+		Convey("When areas are read", func() {
+			readAll(rds)
+
+			Convey("Then every read goes to the reader", func() {
+				So(reader.QueryRowCalls(), ShouldHaveLength, 2)
+				So(reader.QueryCalls(), ShouldHaveLength, 2)
+				So(writer.QueryRowCalls(), ShouldBeEmpty)
+				So(writer.QueryCalls(), ShouldBeEmpty)
+			})
+		})
+
+		Convey("When an area is written", func() {
+			_, err := rds.UpsertArea(ctx, models.AreaParams{Code: "E92000001"}, "")
+			So(err, ShouldNotBeNil)
+			err = rds.RetireArea(ctx, "E92000001", false, "")
+			So(err, ShouldNotBeNil)
+
+			Convey("Then the writes go to the writer", func() {
+				So(writer.BeginCalls(), ShouldHaveLength, 2)
+				So(reader.BeginCalls(), ShouldBeEmpty)
+			})
+		})
+
+		Convey("When the pools are pinged and closed", func() {
+			So(rds.Ping(ctx), ShouldBeNil)
+			So(rds.PingReader(ctx), ShouldBeNil)
+			rds.Close()
+
+			Convey("Then each pool is pinged by its own check and both are closed", func() {
+				So(writer.PingCalls(), ShouldHaveLength, 1)
+				So(reader.PingCalls(), ShouldHaveLength, 1)
+				So(writer.CloseCalls(), ShouldHaveLength, 1)
+				So(reader.CloseCalls(), ShouldHaveLength, 1)
+			})
+		})
+	})
+
+	Convey("Given a store with no reader configured", t, func() {
+		writer := newPool()
+		rds := &RDS{conn: writer}
+
+		Convey("When areas are read and the reader is pinged", func() {
+			readAll(rds)
+			So(rds.PingReader(ctx), ShouldBeNil)
+
+			Convey("Then the writer serves them", func() {
+				So(writer.QueryRowCalls(), ShouldHaveLength, 2)
+				So(writer.QueryCalls(), ShouldHaveLength, 2)
+				So(writer.PingCalls(), ShouldHaveLength, 1)
 			})
 		})
 	})
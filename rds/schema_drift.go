@@ -0,0 +1,134 @@
+package rds
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/ONSdigital/dp-areas-api/migrations"
+	"github.com/ONSdigital/dp-areas-api/models"
+	"github.com/ONSdigital/dp-areas-api/models/DBRelationalSchema"
+	v4 "github.com/jackc/pgx/v4"
+)
+
+const databaseName = "dp-areas-api"
+
+// CheckSchemaDrift compares the tables of the live database with the DBRelationalSchema model
+func (r *RDS) CheckSchemaDrift(ctx context.Context) (_ *models.SchemaDriftReport, err error) {
+	ctx, cancel := r.queryContext(ctx)
+	defer cancel()
+	defer func() { err = storeError(ctx, err) }()
+
+	schema := &models.DatabaseSchema{
+		DBName:       databaseName,
+		SchemaString: DBRelationalSchema.DBSchema,
+	}
+	if err = schema.BuildDatabaseSchemaModel(); err != nil {
+		return nil, fmt.Errorf("failed to build database schema model: %+v", err)
+	}
+
+	live, err := r.GetLiveSchema(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	report := schema.Diff(*live, migrations.TableName)
+	return &report, nil
+}
+
+// GetLiveSchema reads the tables, columns, constraints and indexes of the live database from information_schema and
+// pg_indexes
+func (r *RDS) GetLiveSchema(ctx context.Context) (*models.LiveSchema, error) {
+	live := &models.LiveSchema{}
+
+	err := r.scanRows(ctx, getLiveTables, func(rows v4.Rows) error {
+		var table string
+		if err := rows.Scan(&table); err != nil {
+			return err
+		}
+		live.Tables = append(live.Tables, table)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tables: %+v", err)
+	}
+
+	err = r.scanRows(ctx, getLiveColumns, func(rows v4.Rows) error {
+		var column models.LiveColumn
+		if err := rows.Scan(&column.Table, &column.Name, &column.DataType, &column.CharacterMaximumLength, &column.Nullable); err != nil {
+			return err
+		}
+		live.Columns = append(live.Columns, column)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read columns: %+v", err)
+	}
+
+	// one row per constraint column, ordered so that the columns of a constraint are adjacent
+	var referencedColumns []string
+	err = r.scanRows(ctx, getLiveConstraints, func(rows v4.Rows) error {
+		var constraint models.LiveConstraint
+		var column, referencedTable, referencedColumn string
+		if err := rows.Scan(&constraint.Table, &constraint.Name, &constraint.Type, &column, &referencedTable, &referencedColumn, &constraint.OnDelete); err != nil {
+			return err
+		}
+		last := len(live.Constraints) - 1
+		if last < 0 || live.Constraints[last].Table != constraint.Table || live.Constraints[last].Name != constraint.Name {
+			live.Constraints = append(live.Constraints, constraint)
+			last++
+			referencedColumns = nil
+		}
+		live.Constraints[last].Columns = append(live.Constraints[last].Columns, column)
+		if referencedTable != "" {
+			referencedColumns = append(referencedColumns, referencedColumn)
+			live.Constraints[last].References = fmt.Sprintf("%s(%s)", referencedTable, strings.Join(referencedColumns, ","))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read constraints: %+v", err)
+	}
+
+	err = r.scanRows(ctx, getLiveChecks, func(rows v4.Rows) error {
+		constraint := models.LiveConstraint{Type: models.CheckConstraint}
+		if err := rows.Scan(&constraint.Table, &constraint.Name); err != nil {
+			return err
+		}
+		live.Constraints = append(live.Constraints, constraint)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read check constraints: %+v", err)
+	}
+
+	err = r.scanRows(ctx, getLiveIndexes, func(rows v4.Rows) error {
+		var index models.LiveIndex
+		if err := rows.Scan(&index.Table, &index.Name, &index.Definition); err != nil {
+			return err
+		}
+		live.Indexes = append(live.Indexes, index)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read indexes: %+v", err)
+	}
+
+	return live, nil
+}
+
+// scanRows runs a query and calls scan for each row returned
+func (r *RDS) scanRows(ctx context.Context, query string, scan func(rows v4.Rows) error) error {
+	rows, err := r.readConn().Query(ctx, query)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		if err := scan(rows); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
@@ -0,0 +1,98 @@
+package rds
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ONSdigital/dp-areas-api/models"
+	pgxMock "github.com/ONSdigital/dp-areas-api/pgx/mock"
+	"github.com/jackc/pgx/v4"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// rowsOf returns rows that scan each of values in turn into the destinations given
+func rowsOf(values ...[]interface{}) *pgxMock.PGXRowsMock {
+	i := -1
+	return &pgxMock.PGXRowsMock{
+		NextFunc:  func() bool { i++; return i < len(values) },
+		CloseFunc: func() {},
+		ErrFunc:   func() error { return nil },
+		ScanFunc: func(dest ...interface{}) error {
+			return scanValues(dest, values[i])
+		},
+	}
+}
+
+// scanValues sets each destination to the value in the same position
+func scanValues(dest []interface{}, values []interface{}) error {
+	for j, value := range values {
+		switch d := dest[j].(type) {
+		case *string:
+			*d = value.(string)
+		case **string:
+			*d = value.(*string)
+		case *int:
+			*d = value.(int)
+		case **int:
+			*d = value.(*int)
+		case *bool:
+			*d = value.(bool)
+		case **bool:
+			*d = value.(*bool)
+		case *time.Time:
+			*d = value.(time.Time)
+		case **time.Time:
+			*d = value.(*time.Time)
+		}
+	}
+	return nil
+}
+
+func TestRDS_GetLiveSchema(t *testing.T) {
+	Convey("Given a database with composite keys, a foreign key and a check constraint", t, func() {
+		length := 50
+		results := map[string]*pgxMock.PGXRowsMock{
+			getLiveTables: rowsOf([]interface{}{"area_relationship"}),
+			getLiveColumns: rowsOf(
+				[]interface{}{"area_relationship", "area_code", "character varying", &length, false},
+				[]interface{}{"area_relationship", "rel_type_id", "integer", (*int)(nil), true},
+			),
+			getLiveConstraints: rowsOf(
+				[]interface{}{"area_relationship", "area_relationship_pkey", models.PrimaryKeyConstraint, "area_code", "", "", ""},
+				[]interface{}{"area_relationship", "area_relationship_pkey", models.PrimaryKeyConstraint, "rel_area_code", "", "", ""},
+				[]interface{}{"area_relationship", "area_relationship_parent_fkey", models.ForeignKeyConstraint, "area_code", "area_closure", "ancestor", "CASCADE"},
+				[]interface{}{"area_relationship", "area_relationship_parent_fkey", models.ForeignKeyConstraint, "rel_area_code", "area_closure", "descendant", "CASCADE"},
+				[]interface{}{"area_relationship", "area_relationship_rel_type_id_fkey", models.ForeignKeyConstraint, "rel_type_id", "relationship_type", "id", "NO ACTION"},
+			),
+			getLiveChecks:  rowsOf([]interface{}{"area_relationship", "area_relationship_check"}),
+			getLiveIndexes: rowsOf([]interface{}{"area_relationship", "area_relationship_pkey", "CREATE UNIQUE INDEX area_relationship_pkey"}),
+		}
+		rds := RDS{
+			conn: &pgxMock.PGXPoolMock{
+				QueryFunc: func(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error) {
+					return results[sql], nil
+				},
+			}}
+
+		Convey("When GetLiveSchema is invoked", func() {
+			live, err := rds.GetLiveSchema(context.Background())
+
+			Convey("Then the columns of each constraint are grouped in order", func() {
+				So(err, ShouldBeNil)
+				So(live.Tables, ShouldResemble, []string{"area_relationship"})
+				So(live.Columns, ShouldHaveLength, 2)
+				So(*live.Columns[0].CharacterMaximumLength, ShouldEqual, 50)
+				So(live.Columns[1].Nullable, ShouldBeTrue)
+				So(live.Constraints, ShouldResemble, []models.LiveConstraint{
+					{Table: "area_relationship", Name: "area_relationship_pkey", Type: models.PrimaryKeyConstraint, Columns: []string{"area_code", "rel_area_code"}},
+					{Table: "area_relationship", Name: "area_relationship_parent_fkey", Type: models.ForeignKeyConstraint, Columns: []string{"area_code", "rel_area_code"}, References: "area_closure(ancestor,descendant)", OnDelete: "CASCADE"},
+					{Table: "area_relationship", Name: "area_relationship_rel_type_id_fkey", Type: models.ForeignKeyConstraint, Columns: []string{"rel_type_id"}, References: "relationship_type(id)", OnDelete: "NO ACTION"},
+					{Table: "area_relationship", Name: "area_relationship_check", Type: models.CheckConstraint},
+				})
+				So(live.Indexes, ShouldHaveLength, 1)
+			})
+		})
+	})
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/ONSdigital/dp-areas-api/config"
+	"github.com/ONSdigital/dp-areas-api/models"
+	"github.com/ONSdigital/dp-areas-api/rds"
+	"github.com/pkg/errors"
+)
+
+// driftExitCode is the exit code of the schema-drift subcommand when the live database differs from the schema model,
+// distinguishing drift from a failure to run the check
+const driftExitCode = 2
+
+// schemaDrift runs the schema-drift subcommand, returning whether the live database matches the schema model
+func schemaDrift(ctx context.Context, args []string) (bool, error) {
+	flags := flag.NewFlagSet("schema-drift", flag.ContinueOnError)
+	asJSON := flags.Bool("json", false, "write the drift report as JSON")
+	if err := flags.Parse(args); err != nil {
+		return false, err
+	}
+
+	cfg, err := config.Get()
+	if err != nil {
+		return false, errors.Wrap(err, "error getting configuration")
+	}
+
+	store := &rds.RDS{}
+	if err := store.Init(ctx, cfg); err != nil {
+		return false, errors.Wrap(err, "error connecting to database")
+	}
+	defer store.Close()
+
+	report, err := store.CheckSchemaDrift(ctx)
+	if err != nil {
+		return false, errors.Wrap(err, "error checking schema drift")
+	}
+
+	if *asJSON {
+		if err := json.NewEncoder(os.Stdout).Encode(report); err != nil {
+			return false, err
+		}
+	} else {
+		printDrift(report)
+	}
+	return report.InSync, nil
+}
+
+func printDrift(report *models.SchemaDriftReport) {
+	if report.InSync {
+		fmt.Println("database schema matches the schema model")
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "TABLE\tKIND\tCOLUMN\tEXPECTED\tACTUAL")
+	for _, d := range report.Drift {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", d.Table, d.Kind, d.Column, d.Expected, d.Actual)
+	}
+	w.Flush()
+}
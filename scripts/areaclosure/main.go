@@ -0,0 +1,31 @@
+package main
+
+import (
+	"context"
+	"log"
+
+	"github.com/ONSdigital/dp-areas-api/config"
+	"github.com/ONSdigital/dp-areas-api/rds"
+)
+
+// Rebuilds the area_closure table from area_relationship. Run after any bulk import that writes relationships
+// directly to the database, e.g. scripts/arearelationshipimport.
+func main() {
+	ctx := context.Background()
+
+	cfg, err := config.Get()
+	if err != nil {
+		log.Fatalf("failed to get config: %+v", err)
+	}
+
+	rdsStore := &rds.RDS{}
+	if err = rdsStore.Init(ctx, cfg); err != nil {
+		log.Fatalf("failed to connect to database: %+v", err)
+	}
+	defer rdsStore.Close()
+
+	if err = rdsStore.RebuildAreaClosure(ctx); err != nil {
+		log.Fatalf("failed to rebuild area closure: %+v", err)
+	}
+	log.Println("area_closure rebuilt successfully")
+}
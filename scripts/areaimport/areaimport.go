@@ -14,6 +14,7 @@ import (
 	"time"
 
 	"github.com/ONSdigital/dp-areas-api/models"
+	dprequest "github.com/ONSdigital/dp-net/request"
 	"github.com/kelseyhightower/envconfig"
 )
 
@@ -36,6 +37,10 @@ var areaTypeAndCode = map[string]string{
 type Config struct {
 	CSVFilePath   string `envconfig:"CSV_FILE_PATH" required:"true"`
 	AreaUpdateUrl string `envconfig:"AREA_UPDATE_URL" required:"true"`
+	// when set, all areas are sent in a single request to the bulk endpoint (e.g. http://localhost:25500/v1/areas:bulk)
+	AreaBulkUrl string `envconfig:"AREA_BULK_URL"`
+	// service token sent with every request, as the area write endpoints are private
+	ServiceAuthToken string `envconfig:"SERVICE_AUTH_TOKEN" required:"true" json:"-"`
 }
 type logs struct {
 	errors  []string
@@ -50,6 +55,7 @@ func importChangeHistoryAreaInfo(config *Config) logs {
 	var errors []string
 	var success []string
 	var areaChildInfo []models.AreaParams
+	var areaBulkInfo []models.AreaParams
 	csvFile, err := os.Open(config.CSVFilePath)
 	if err != nil {
 		log.Fatalf("Failed to open the CSV on path %+v:", err)
@@ -104,6 +110,11 @@ func importChangeHistoryAreaInfo(config *Config) logs {
 			AreaHectares: hectares,
 		}
 
+		if config.AreaBulkUrl != "" {
+			areaBulkInfo = append(areaBulkInfo, areaInfo)
+			continue
+		}
+
 		if areaInfo.ParentCode != "" {
 			areaChildInfo = append(areaChildInfo, areaInfo)
 			continue
@@ -118,6 +129,14 @@ func importChangeHistoryAreaInfo(config *Config) logs {
 
 	}
 
+	if len(areaBulkInfo) > 0 {
+		status, err := importAreaInfoBulk(config, areaBulkInfo)
+		if err != nil {
+			log.Fatal("Bulk import error:", err)
+		}
+		success = append(success, "Api response for bulk import: "+status)
+	}
+
 	if len(areaChildInfo) > 0 {
 		for _, v := range areaChildInfo {
 			resp, err := importAreaInfo(config, v)
@@ -135,7 +154,9 @@ func importChangeHistoryAreaInfo(config *Config) logs {
 }
 func getConfig() *Config {
 	conf := &Config{}
-	envconfig.Process("", conf)
+	if err := envconfig.Process("", conf); err != nil {
+		log.Fatal(err)
+	}
 	return conf
 }
 func importAreaInfo(config *Config, areaInfo models.AreaParams) (*http.Response, error) {
@@ -153,6 +174,7 @@ func importAreaInfo(config *Config, areaInfo models.AreaParams) (*http.Response,
 	}
 
 	req.Header.Set("Content-Type", "application/json")
+	dprequest.AddServiceTokenHeader(req, config.ServiceAuthToken)
 	resp, err := client.Do(req)
 	if err != nil {
 		return nil, err
@@ -160,6 +182,41 @@ func importAreaInfo(config *Config, areaInfo models.AreaParams) (*http.Response,
 	defer resp.Body.Close()
 	return resp, nil
 }
+
+// importAreaInfoBulk sends every area as newline delimited JSON in one request. The API orders parents before
+// children and applies the import transactionally, so no ordering is needed here.
+func importAreaInfoBulk(config *Config, areas []models.AreaParams) (string, error) {
+	var body bytes.Buffer
+	encoder := json.NewEncoder(&body)
+	for _, area := range areas {
+		if err := encoder.Encode(area); err != nil {
+			return "", err
+		}
+	}
+
+	req, err := http.NewRequest(http.MethodPost, config.AreaBulkUrl, &body)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", models.NDJSONContentType)
+	dprequest.AddServiceTokenHeader(req, config.ServiceAuthToken)
+
+	resp, err := (&http.Client{}).Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	results, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("bulk import failed with status %s: %s", resp.Status, results)
+	}
+	return resp.Status, nil
+}
+
 func main() {
 	config := getConfig()
 	logs := importChangeHistoryAreaInfo(config)
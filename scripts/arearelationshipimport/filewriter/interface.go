@@ -7,4 +7,5 @@ import (
 
 type FileWriter interface {
 	Write(ctx context.Context, file *os.File, tbl string) (int64, error)
+	RebuildAreaClosure(ctx context.Context) error
 }
@@ -8,6 +8,20 @@ import (
 	"os"
 )
 
+// rebuildAreaClosure recalculates area_closure from the imported child relationships, matching
+// rds.RDS.RebuildAreaClosure in the api
+const rebuildAreaClosure = `insert into area_closure(ancestor, descendant, depth)
+	with recursive paths(ancestor, descendant, depth) as (
+		select code, code, 0 from area
+		union all
+		select ar.area_code, p.descendant, p.depth + 1
+		from area_relationship as ar, paths as p
+		where ar.rel_area_code = p.ancestor
+		and ar.rel_type_id = (select id from relationship_type where name = 'child')
+		and p.depth < 32
+	)
+	select ancestor, descendant, min(depth) from paths group by ancestor, descendant`
+
 type PostgresWriter struct {
 	config *config.ImportConfig
 }
@@ -30,3 +44,25 @@ func (p PostgresWriter) Write(ctx context.Context, file *os.File, tbl string) (i
 	}
 	return res.RowsAffected(), nil
 }
+
+func (p PostgresWriter) RebuildAreaClosure(ctx context.Context) error {
+	dbconn, err := pgx.Connect(ctx, p.config.GetDatabaseURI())
+	if err != nil {
+		return err
+	}
+	defer dbconn.Close(ctx)
+
+	tx, err := dbconn.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err = tx.Exec(ctx, "delete from area_closure"); err != nil {
+		return err
+	}
+	if _, err = tx.Exec(ctx, rebuildAreaClosure); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
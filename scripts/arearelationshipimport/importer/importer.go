@@ -52,6 +52,13 @@ func (ai *AreaImporter) StartImport(ctx context.Context) {
 	for _, table := range ai.config.GetAreaRelationshipSchema() {
 		ai.ImportForTable(ctx, table)
 	}
+
+	// relationships are copied directly into the database so area_closure has to be rebuilt from them
+	if err := ai.destination.RebuildAreaClosure(ctx); err != nil {
+		fmt.Printf("error occurred while rebuilding area closure. error: %+v", err)
+		return
+	}
+	fmt.Println("Rebuilt area_closure")
 }
 
 func (ai *AreaImporter) ImportForTable(ctx context.Context, tbl string) {
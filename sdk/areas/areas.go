@@ -1,8 +1,10 @@
 package areas
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
@@ -14,10 +16,15 @@ import (
 	health "github.com/ONSdigital/dp-healthcheck/healthcheck"
 	dprequest "github.com/ONSdigital/dp-net/request"
 	"github.com/ONSdigital/log.go/v2/log"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
 )
 
 const service = "areas-api"
 
+// ErrPreconditionFailed is returned by UpdateArea when the area has been changed since the ETag given was retrieved
+var ErrPreconditionFailed = errors.New("area has been modified since it was retrieved")
+
 // ErrInvalidAreaAPIResponse is returned when the area api does not respond
 // with a valid status
 type ErrInvalidAreaAPIResponse struct {
@@ -101,9 +108,38 @@ func (c *Client) GetArea(ctx context.Context, userAuthToken, serviceAuthToken, c
 	}
 
 	err = json.Unmarshal(b, &areaDetails)
+	areaDetails.ETag = resp.Header.Get("ETag")
 	return
 }
 
+// UpdateArea creates or replaces an area. If ifMatch is set to the ETag of a previously retrieved area, the update is
+// only applied if the area has not changed since; otherwise ErrPreconditionFailed is returned and the caller should
+// get the area again before retrying.
+func (c *Client) UpdateArea(ctx context.Context, userAuthToken, serviceAuthToken, collectionID, areaID string, area AreaParams, ifMatch string) error {
+	uri := fmt.Sprintf("%s/v1/areas/%s", c.hcCli.URL, areaID)
+	clientlog.Do(ctx, "updating area", service, uri)
+
+	payload, err := json.Marshal(area)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.doPutWithAuthHeaders(ctx, userAuthToken, serviceAuthToken, collectionID, uri, payload, ifMatch)
+	if err != nil {
+		return err
+	}
+	defer closeResponseBody(ctx, resp)
+
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusCreated:
+		return nil
+	case http.StatusPreconditionFailed:
+		return ErrPreconditionFailed
+	default:
+		return NewAreaAPIResponse(resp, uri)
+	}
+}
+
 // GetRelations gets the child areas
 func (c *Client) GetRelations(ctx context.Context, userAuthToken, serviceAuthToken, collectionID, areaID, acceptLang string) (relations []Relation, err error) {
 	uri := fmt.Sprintf("%s/v1/areas/%s/relations?relationship=child", c.hcCli.URL, areaID)
@@ -148,6 +184,12 @@ func NewAreaAPIResponse(resp *http.Response, uri string) (e *ErrInvalidAreaAPIRe
 	return
 }
 
+// addTraceContextHeaders propagates the trace of ctx to the areas api with the propagator set for the process, which
+// sends nothing until one is set, e.g. by otel.SetTextMapPropagator
+func addTraceContextHeaders(ctx context.Context, r *http.Request) {
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(r.Header))
+}
+
 func addCollectionIDHeader(r *http.Request, collectionID string) {
 	if len(collectionID) > 0 {
 		r.Header.Add(dprequest.CollectionIDHeaderKey, collectionID)
@@ -155,7 +197,7 @@ func addCollectionIDHeader(r *http.Request, collectionID string) {
 }
 
 // doGetWithAuthHeaders executes a GET request by using clienter.Do for the provided URI and payload body.
-// It sets the user and service authentication and collectionID as a request header. Returns the http.Response and any error.
+// It sets the user and service authentication, collectionID and trace context as request headers. Returns the http.Response and any error.
 // It is the callers responsibility to ensure response.Body is closed on completion.
 // If url.Values are provided, they will be added as query parameters in the URL.
 // NOTE: Only one of the tokens 'userAuthToken' or 'serviceAuthToken' needs to have a value.
@@ -171,6 +213,25 @@ func (c *Client) doGetWithAuthHeaders(ctx context.Context, userAuthToken, servic
 
 	headers.SetIfMatch(req, ifMatch)
 	headers.SetAcceptedLang(req, acceptLang)
+	addTraceContextHeaders(ctx, req)
+	addCollectionIDHeader(req, collectionID)
+	dprequest.AddFlorenceHeader(req, userAuthToken)
+	dprequest.AddServiceTokenHeader(req, serviceAuthToken)
+	return c.hcCli.Client.Do(ctx, req)
+}
+
+// doPutWithAuthHeaders executes a PUT request by using clienter.Do for the provided URI and payload body.
+// It sets the user and service authentication, collectionID, If-Match and trace context as request headers. Returns the http.Response and any error.
+// It is the callers responsibility to ensure response.Body is closed on completion.
+func (c *Client) doPutWithAuthHeaders(ctx context.Context, userAuthToken, serviceAuthToken, collectionID, uri string, payload []byte, ifMatch string) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodPut, uri, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	headers.SetIfMatch(req, ifMatch)
+	addTraceContextHeaders(ctx, req)
 	addCollectionIDHeader(req, collectionID)
 	dprequest.AddFlorenceHeader(req, userAuthToken)
 	dprequest.AddServiceTokenHeader(req, serviceAuthToken)
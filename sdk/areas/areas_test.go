@@ -3,6 +3,7 @@ package areas
 import (
 	"context"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -14,6 +15,9 @@ import (
 	dphttp "github.com/ONSdigital/dp-net/http"
 	"github.com/pkg/errors"
 	. "github.com/smartystreets/goconvey/convey"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
 )
 
 const (
@@ -231,6 +235,34 @@ func TestClient_GetArea(t *testing.T) {
 		})
 	})
 
+	Convey("When an area is returned with an ETag", t, func() {
+		mockedAPI := getMockAreaAPI(http.Request{Method: "GET"}, MockedHTTPResponse{StatusCode: 200, Body: areaBody, Headers: map[string]string{"ETag": `"2"`}})
+		area, err := mockedAPI.GetArea(ctx, userAuthToken, serviceAuthToken, collectionID, "E92000001", acceptedLang)
+		So(err, ShouldBeNil)
+		So(area.ETag, ShouldEqual, `"2"`)
+	})
+
+	Convey("Given a client in a traced process", t, func() {
+		otel.SetTextMapPropagator(propagation.TraceContext{})
+		Reset(func() { otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator()) })
+		httpClient := newMockHTTPClient(&http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader("{}"))}, nil)
+		areasClient := newAreasClient(httpClient)
+
+		Convey("When an area is requested in a trace", func() {
+			traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+			spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+			tracedCtx := trace.ContextWithSpanContext(ctx, trace.NewSpanContext(trace.SpanContextConfig{TraceID: traceID, SpanID: spanID, TraceFlags: trace.FlagsSampled}))
+			_, err := areasClient.GetArea(tracedCtx, userAuthToken, serviceAuthToken, collectionID, "E92000001", acceptedLang)
+
+			Convey("Then the trace is propagated in the traceparent header", func() {
+				So(err, ShouldBeNil)
+				doCalls := httpClient.DoCalls()
+				So(doCalls, ShouldHaveLength, 1)
+				So(doCalls[0].Req.Header.Get("traceparent"), ShouldEqual, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+			})
+		})
+	})
+
 	Convey("given a 200 status with valid empty body is returned", t, func() {
 		mockedAPI := getMockAreaAPI(http.Request{Method: "GET"}, MockedHTTPResponse{StatusCode: 200, Body: "{}"})
 
@@ -245,6 +277,54 @@ func TestClient_GetArea(t *testing.T) {
 	})
 }
 
+func TestClient_UpdateArea(t *testing.T) {
+	visible := true
+	area := AreaParams{Visible: &visible, AreaName: &AreaName{Name: "England"}}
+
+	Convey("When the area is updated", t, func() {
+		mockedAPI := getMockAreaAPI(http.Request{Method: "PUT"}, MockedHTTPResponse{StatusCode: http.StatusOK})
+		err := mockedAPI.UpdateArea(ctx, userAuthToken, serviceAuthToken, collectionID, "E92000001", area, `"1"`)
+		So(err, ShouldBeNil)
+	})
+
+	Convey("When the area is created", t, func() {
+		mockedAPI := getMockAreaAPI(http.Request{Method: "PUT"}, MockedHTTPResponse{StatusCode: http.StatusCreated})
+		err := mockedAPI.UpdateArea(ctx, userAuthToken, serviceAuthToken, collectionID, "E92000001", area, "")
+		So(err, ShouldBeNil)
+	})
+
+	Convey("When the area has been modified since it was retrieved", t, func() {
+		mockedAPI := getMockAreaAPI(http.Request{Method: "PUT"}, MockedHTTPResponse{StatusCode: http.StatusPreconditionFailed})
+		err := mockedAPI.UpdateArea(ctx, userAuthToken, serviceAuthToken, collectionID, "E92000001", area, `"1"`)
+		So(err, ShouldEqual, ErrPreconditionFailed)
+	})
+
+	Convey("When any other error is returned", t, func() {
+		mockedAPI := getMockAreaAPI(http.Request{Method: "PUT"}, MockedHTTPResponse{StatusCode: http.StatusInternalServerError})
+		err := mockedAPI.UpdateArea(ctx, userAuthToken, serviceAuthToken, collectionID, "E92000001", area, "")
+		So(err, ShouldNotBeNil)
+		So(err.(*ErrInvalidAreaAPIResponse).Code(), ShouldEqual, http.StatusInternalServerError)
+	})
+
+	Convey("Given a client", t, func() {
+		httpClient := newMockHTTPClient(&http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil)
+		areasClient := newAreasClient(httpClient)
+
+		Convey("When the area is updated with an ETag", func() {
+			err := areasClient.UpdateArea(ctx, userAuthToken, serviceAuthToken, collectionID, "E92000001", area, `"1"`)
+
+			Convey("Then the ETag is sent as If-Match with the area", func() {
+				So(err, ShouldBeNil)
+				doCalls := httpClient.DoCalls()
+				So(doCalls, ShouldHaveLength, 1)
+				So(doCalls[0].Req.Method, ShouldEqual, http.MethodPut)
+				So(doCalls[0].Req.URL.Path, ShouldEqual, "/v1/areas/E92000001")
+				So(doCalls[0].Req.Header.Get("If-Match"), ShouldEqual, `"1"`)
+			})
+		})
+	})
+}
+
 func TestClient_GetRelations(t *testing.T) {
 
 	relationsBody := `[
@@ -297,6 +377,9 @@ func getMockAreaAPI(expectRequest http.Request, mockedHTTPResponse MockedHTTPRes
 			w.Write([]byte("unexpected HTTP method used"))
 			return
 		}
+		for key, value := range mockedHTTPResponse.Headers {
+			w.Header().Set(key, value)
+		}
 		w.WriteHeader(mockedHTTPResponse.StatusCode)
 		fmt.Fprintln(w, mockedHTTPResponse.Body)
 	}))
@@ -1,5 +1,7 @@
 package areas
 
+import "time"
+
 // AreaDetails represents a response area model from the areas api
 type AreaDetails struct {
 	Code          string         `json:"code,omitempty"`
@@ -11,6 +13,26 @@ type AreaDetails struct {
 	Visible       bool           `json:"visible,omitempty"`
 	AreaType      string         `json:"area_type,omitempty"`
 	Ancestors     []Ancestor     `json:"ancestors,omitempty"`
+	// ETag identifies the version of the area returned, pass it to UpdateArea to avoid overwriting concurrent changes
+	ETag string `json:"-"`
+}
+
+// AreaParams represents the area details sent to the areas api to create or update an area
+type AreaParams struct {
+	AreaName      *AreaName  `json:"area_name,omitempty"`
+	GeometricData string     `json:"geometry,omitempty"`
+	ActiveFrom    *time.Time `json:"active_from,omitempty"`
+	ActiveTo      *time.Time `json:"active_to,omitempty"`
+	Visible       *bool      `json:"visible,omitempty"`
+	ParentCode    string     `json:"parent_code,omitempty"`
+	AreaHectares  float64    `json:"area_hectares,omitempty"`
+}
+
+// AreaName represents the name details of an area sent to the areas api
+type AreaName struct {
+	Name       string     `json:"name"`
+	ActiveFrom *time.Time `json:"active_from"`
+	ActiveTo   *time.Time `json:"active_to"`
 }
 
 // Relation represents a response relation model from area api
@@ -2,41 +2,50 @@ package healthcheck
 
 import (
 	"context"
+	"fmt"
 	"net/http"
-	"regexp"
 
 	"github.com/ONSdigital/dp-areas-api/api"
-	"github.com/ONSdigital/dp-areas-api/config"
 	health "github.com/ONSdigital/dp-healthcheck/healthcheck"
 	"github.com/ONSdigital/log.go/v2/log"
 )
 
 const (
-	RDSHealthy = "RDS Healthy"
-	authError  = "SQLSTATE 28000"
+	RDSHealthy       = "RDS Healthy"
+	RDSReaderHealthy = "RDS Reader Healthy"
+	RDSDegraded      = "degraded, serving stale responses"
 )
 
-var (
-	authErrorRegex, _ = regexp.Compile(authError)
-)
+// StaleResponses reports whether reads can be served from stale responses while the database is unavailable, as
+// api.StaleResponses does
+type StaleResponses interface {
+	Available() bool
+}
+
+// RDSHealthCheck checks the writer pool. Writes cannot be served without it, so a failure is always critical.
+func RDSHealthCheck(rds api.RDSAreaStore) health.Checker {
+	return rdsHealthCheck(rds.Ping, RDSHealthy, nil)
+}
+
+// RDSReaderHealthCheck checks the pool that serves reads. A failure is reported as a warning rather than critical
+// while stale has responses to serve in place of reads, to keep the instance serving them, and as critical otherwise.
+func RDSReaderHealthCheck(rds api.RDSAreaStore, stale StaleResponses) health.Checker {
+	return rdsHealthCheck(rds.PingReader, RDSReaderHealthy, stale)
+}
 
-func RDSHealthCheck(ctx context.Context, cfg *config.Config, rds api.RDSAreaStore) health.Checker {
+// rdsHealthCheck only reports the state of a pool. Pools sign a fresh auth token for each connection, so an expired
+// token does not need the store to be reconnected.
+func rdsHealthCheck(ping func(ctx context.Context) error, healthyMessage string, stale StaleResponses) health.Checker {
 	return func(ctx context.Context, state *health.CheckState) error {
-		err := rds.Ping(ctx)
+		err := ping(ctx)
 		log.Info(context.Background(), "Checking rds connection status...")
 
-		// special case for auth errors
-		if err != nil && authErrorRegex.MatchString(err.Error()) {
-			log.Error(context.Background(), "Attempting to re-connect to rds instance", err)
-			rds.Init(ctx, cfg)
-			err := rds.Ping(ctx)
-			if err != nil {
-				log.Error(context.Background(), "Attempting to re-connect to rds instance on PAM fail", err)
-			}
-		}
-
 		if err != nil {
-			if stateErr := state.Update(health.StatusCritical, err.Error(), http.StatusBadGateway); stateErr != nil {
+			status, message := health.StatusCritical, err.Error()
+			if stale != nil && stale.Available() {
+				status, message = health.StatusWarning, fmt.Sprintf("%s: %s", RDSDegraded, err)
+			}
+			if stateErr := state.Update(status, message, http.StatusBadGateway); stateErr != nil {
 				log.Error(context.Background(), "Error updating state during area service healthcheck", stateErr)
 			}
 			// log the error
@@ -44,7 +53,7 @@ func RDSHealthCheck(ctx context.Context, cfg *config.Config, rds api.RDSAreaStor
 			return err
 		}
 
-		if stateErr := state.Update(health.StatusOK, RDSHealthy, http.StatusOK); stateErr != nil {
+		if stateErr := state.Update(health.StatusOK, healthyMessage, http.StatusOK); stateErr != nil {
 			log.Error(context.Background(), "Error updating state during area service healthcheck", stateErr)
 		}
 
@@ -7,7 +7,6 @@ import (
 	"testing"
 
 	"github.com/ONSdigital/dp-areas-api/api/mock"
-	"github.com/ONSdigital/dp-areas-api/config"
 
 	healthcheck "github.com/ONSdigital/dp-areas-api/service/healthcheck"
 	health "github.com/ONSdigital/dp-healthcheck/healthcheck"
@@ -22,8 +21,6 @@ func TestGetHealthCheck(t *testing.T) {
 
 	m := &mock.RDSAreaStoreMock{}
 
-	cfg, _ := config.Get()
-
 	Convey("dp-areas-api healthchecker reports healthy", t, func() {
 
 		m.PingFunc = func(ctx context.Context) error {
@@ -31,7 +28,7 @@ func TestGetHealthCheck(t *testing.T) {
 		}
 
 		checkState := health.NewCheckState("dp-areas-api-test")
-		checker := healthcheck.RDSHealthCheck(context.Background(), cfg, m)
+		checker := healthcheck.RDSHealthCheck(m)
 		err := checker(ctx, checkState)
 		Convey("When GetHealthCheck is called", func() {
 			Convey("Then the HealthCheck flag is set to true and HealthCheck is returned", func() {
@@ -54,7 +51,7 @@ func TestGetHealthCheck(t *testing.T) {
 
 			checkState := health.NewCheckState("dp-areas-api-test")
 
-			checker := healthcheck.RDSHealthCheck(context.Background(), cfg, m)
+			checker := healthcheck.RDSHealthCheck(m)
 			err := checker(ctx, checkState)
 			Convey("When GetHealthCheck is called", func() {
 				Convey("Then the HealthCheck flag is set to true and HealthCheck is returned", func() {
@@ -66,3 +63,108 @@ func TestGetHealthCheck(t *testing.T) {
 		})
 	})
 }
+
+func TestRDSReaderHealthCheck(t *testing.T) {
+	ctx := context.Background()
+	Convey("Given a reader that cannot be pinged and a healthy writer", t, func() {
+		m := &mock.RDSAreaStoreMock{
+			PingFunc: func(ctx context.Context) error {
+				return nil
+			},
+			PingReaderFunc: func(ctx context.Context) error {
+				return errors.New("connection refused")
+			},
+		}
+
+		Convey("When the reader and writer are checked", func() {
+			readerState := health.NewCheckState("dp-areas-api-test")
+			readerErr := healthcheck.RDSReaderHealthCheck(m, nil)(ctx, readerState)
+			writerState := health.NewCheckState("dp-areas-api-test")
+			writerErr := healthcheck.RDSHealthCheck(m)(ctx, writerState)
+
+			Convey("Then only the reader check is critical", func() {
+				So(readerErr, ShouldNotBeNil)
+				So(readerState.Status(), ShouldEqual, health.StatusCritical)
+				So(writerErr, ShouldBeNil)
+				So(writerState.Status(), ShouldEqual, health.StatusOK)
+				So(writerState.Message(), ShouldEqual, healthcheck.RDSHealthy)
+			})
+		})
+	})
+}
+
+func TestRDSHealthCheckAuthFailure(t *testing.T) {
+	ctx := context.Background()
+
+	Convey("Given a pool whose connections fail to authenticate", t, func() {
+		m := &mock.RDSAreaStoreMock{
+			PingFunc: func(ctx context.Context) error {
+				return errors.New("failed SASL auth (FATAL: PAM authentication failed for user (SQLSTATE 28000))")
+			},
+		}
+
+		Convey("When the pool is checked", func() {
+			checkState := health.NewCheckState("dp-areas-api-test")
+			err := healthcheck.RDSHealthCheck(m)(ctx, checkState)
+
+			Convey("Then it is reported critical without the store being reconnected", func() {
+				So(err, ShouldNotBeNil)
+				So(checkState.Status(), ShouldEqual, health.StatusCritical)
+				So(m.InitCalls(), ShouldBeEmpty)
+			})
+		})
+	})
+}
+
+// staleResponses reports whether it has responses to serve
+type staleResponses bool
+
+func (s staleResponses) Available() bool {
+	return bool(s)
+}
+
+func TestRDSHealthCheckDegraded(t *testing.T) {
+	ctx := context.Background()
+
+	Convey("Given a database that cannot be pinged while degraded mode is enabled", t, func() {
+		m := &mock.RDSAreaStoreMock{
+			PingFunc: func(ctx context.Context) error {
+				return errors.New("connection refused")
+			},
+			PingReaderFunc: func(ctx context.Context) error {
+				return errors.New("connection refused")
+			},
+		}
+
+		Convey("When the reader is checked with stale responses to serve", func() {
+			checkState := health.NewCheckState("dp-areas-api-test")
+			err := healthcheck.RDSReaderHealthCheck(m, staleResponses(true))(ctx, checkState)
+
+			Convey("Then it is reported as a degraded warning rather than critical", func() {
+				So(err, ShouldNotBeNil)
+				So(checkState.Status(), ShouldEqual, health.StatusWarning)
+				So(checkState.Message(), ShouldEqual, healthcheck.RDSDegraded+": connection refused")
+			})
+		})
+
+		Convey("When the reader is checked with no stale responses to serve", func() {
+			checkState := health.NewCheckState("dp-areas-api-test")
+			err := healthcheck.RDSReaderHealthCheck(m, staleResponses(false))(ctx, checkState)
+
+			Convey("Then it is critical, as a cold instance has nothing to serve", func() {
+				So(err, ShouldNotBeNil)
+				So(checkState.Status(), ShouldEqual, health.StatusCritical)
+			})
+		})
+
+		Convey("When the writer is checked", func() {
+			checkState := health.NewCheckState("dp-areas-api-test")
+			err := healthcheck.RDSHealthCheck(m)(ctx, checkState)
+
+			Convey("Then it is critical, as writes cannot be served stale", func() {
+				So(err, ShouldNotBeNil)
+				So(checkState.Status(), ShouldEqual, health.StatusCritical)
+			})
+		})
+	})
+}
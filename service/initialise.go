@@ -7,6 +7,9 @@ import (
 	"net/http"
 
 	"github.com/ONSdigital/dp-areas-api/api"
+	"github.com/ONSdigital/dp-areas-api/auth"
+	"github.com/ONSdigital/dp-areas-api/cache"
+	"github.com/ONSdigital/dp-areas-api/memory"
 	"github.com/ONSdigital/dp-areas-api/rds"
 
 	"github.com/ONSdigital/dp-areas-api/config"
@@ -18,17 +21,19 @@ import (
 
 // ExternalServiceList holds the initialiser and initialisation state of external services.
 type ExternalServiceList struct {
-	HealthCheck bool
-	Init        Initialiser
-	RDS         bool
+	HealthCheck        bool
+	Init               Initialiser
+	RDS                bool
+	AreaChangeListener bool
 }
 
 // NewServiceList creates a new service list with the provided initialiser
 func NewServiceList(initialiser Initialiser) *ExternalServiceList {
 	return &ExternalServiceList{
-		HealthCheck: false,
-		Init:        initialiser,
-		RDS:         false,
+		HealthCheck:        false,
+		Init:               initialiser,
+		RDS:                false,
+		AreaChangeListener: false,
 	}
 }
 
@@ -60,6 +65,23 @@ func (e *ExternalServiceList) getRDSDB(ctx context.Context, cfg *config.Config)
 	return rds, nil
 }
 
+func (e *ExternalServiceList) getAreaChangeListener(ctx context.Context, cfg *config.Config, handler rds.AreaChangeHandler) (AreaChangeListener, error) {
+	listener, err := e.Init.DoGetAreaChangeListener(ctx, cfg, handler)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create area change listener: %w", err)
+	}
+	e.AreaChangeListener = true
+	return listener, nil
+}
+
+func (e *ExternalServiceList) getIdentityVerifier(cfg *config.Config) (auth.Verifier, error) {
+	verifier, err := e.Init.DoGetIdentityVerifier(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create identity verifier: %w", err)
+	}
+	return verifier, nil
+}
+
 func (e *ExternalServiceList) getS3Client(cfg *config.Config) (*s3.Client, error) {
 	return s3.NewClientWithCredentials(cfg.AWSRegion, cfg.S3Bucket, cfg.AWSAccessKey, cfg.AWSSecretKey)
 
@@ -72,15 +94,49 @@ func (e *Init) DoGetHTTPServer(bindAddr string, router http.Handler) HTTPServer
 	return s
 }
 
-// DoGetRDSDB returns a RDSClient
+// DoGetRDSDB returns the area store selected by the config, which is a RDSClient unless the in-memory store is chosen,
+// behind a cache when one is configured
 func (e *Init) DoGetRDSDB(ctx context.Context, cfg *config.Config) (api.RDSAreaStore, error) {
-	rds := &rds.RDS{}
-	err := rds.Init(ctx, cfg)
+	var store api.RDSAreaStore
+	backend := cfg.StoreBackend
+	switch backend {
+	case config.StoreMemory:
+		store = memory.New()
+	case config.StorePostgres, "":
+		backend = config.StorePostgres
+		store = &rds.RDS{}
+	default:
+		return nil, fmt.Errorf("unknown store backend %q", cfg.StoreBackend)
+	}
+
+	err := store.Init(ctx, cfg)
 	if err != nil {
-		log.Error(ctx, "failed to initialise rds", err)
-		return nil, err
+		log.Error(ctx, fmt.Sprintf("failed to initialise %s area store", backend), err, log.Data{"store_backend": backend})
+		return nil, fmt.Errorf("failed to initialise %s area store: %w", backend, err)
+	}
+
+	if cache.Enabled(cfg) {
+		return cache.New(store, cfg), nil
+	}
+	return store, nil
+}
+
+// DoGetAreaChangeListener creates a listener on the database that tells handler of the areas changed by any instance
+func (e *Init) DoGetAreaChangeListener(ctx context.Context, cfg *config.Config, handler rds.AreaChangeHandler) (AreaChangeListener, error) {
+	return rds.NewListener(ctx, cfg, handler)
+}
+
+// DoGetIdentityVerifier returns the verifier of the tokens sent to private endpoints selected by the config
+func (e *Init) DoGetIdentityVerifier(cfg *config.Config) (auth.Verifier, error) {
+	switch cfg.AuthVerifier {
+	case config.AuthVerifierZebedee, "":
+		return auth.NewZebedeeVerifier(cfg)
+	case config.AuthVerifierStub:
+		log.Warn(context.Background(), "private endpoints accept the stub tokens, which must not be used in an environment")
+		return auth.NewStubVerifier(), nil
+	default:
+		return nil, fmt.Errorf("unknown auth verifier %q", cfg.AuthVerifier)
 	}
-	return rds, nil
 }
 
 // DoGetHealthCheck creates a healthcheck with versionInfo
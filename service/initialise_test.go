@@ -6,7 +6,9 @@ import (
 	"testing"
 	"time"
 
+	"github.com/ONSdigital/dp-areas-api/cache"
 	"github.com/ONSdigital/dp-areas-api/config"
+	"github.com/ONSdigital/dp-areas-api/memory"
 	"github.com/ONSdigital/dp-areas-api/service"
 	"github.com/ONSdigital/dp-areas-api/service/mock"
 	"github.com/gorilla/mux"
@@ -157,3 +159,61 @@ func TestGetHealthCheck(t *testing.T) {
 		})
 	})
 }
+
+func TestDoGetRDSDB(t *testing.T) {
+	Convey("Given the in-memory store backend is configured", t, func() {
+		memoryCfg := *cfg
+		memoryCfg.StoreBackend = config.StoreMemory
+
+		Convey("When the store is created", func() {
+			store, err := (&service.Init{}).DoGetRDSDB(context.Background(), &memoryCfg)
+
+			Convey("Then an in-memory store seeded with the fixtures is returned behind the cache", func() {
+				So(err, ShouldBeNil)
+				cachedStore, ok := store.(*cache.Store)
+				So(ok, ShouldBeTrue)
+				So(cachedStore.RDSAreaStore, ShouldHaveSameTypeAs, memory.New())
+				area, err := store.GetArea(context.Background(), "E92000001", false)
+				So(err, ShouldBeNil)
+				So(*area.Name, ShouldEqual, "England")
+			})
+		})
+
+		Convey("When the store is created with every cache disabled", func() {
+			memoryCfg.AreaCache.Size = 0
+			memoryCfg.AncestorsCache.Size = 0
+			memoryCfg.RelationshipsCache.Size = 0
+			memoryCfg.AreaDetailsCache.Size = 0
+			memoryCfg.ValidateAreaCache.Size = 0
+			store, err := (&service.Init{}).DoGetRDSDB(context.Background(), &memoryCfg)
+
+			Convey("Then the in-memory store is returned directly", func() {
+				So(err, ShouldBeNil)
+				So(store, ShouldHaveSameTypeAs, memory.New())
+			})
+		})
+
+		Convey("When the store is created from fixtures that do not exist", func() {
+			memoryCfg.FixturesVersion = "v0"
+			_, err := (&service.Init{}).DoGetRDSDB(context.Background(), &memoryCfg)
+
+			Convey("Then the error names the backend that failed", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldStartWith, "failed to initialise memory area store")
+			})
+		})
+	})
+
+	Convey("Given an unknown store backend is configured", t, func() {
+		unknownCfg := *cfg
+		unknownCfg.StoreBackend = "mongo"
+
+		Convey("When the store is created", func() {
+			_, err := (&service.Init{}).DoGetRDSDB(context.Background(), &unknownCfg)
+
+			Convey("Then an error is returned", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+	})
+}
@@ -5,6 +5,8 @@ import (
 	"net/http"
 
 	"github.com/ONSdigital/dp-areas-api/api"
+	"github.com/ONSdigital/dp-areas-api/auth"
+	"github.com/ONSdigital/dp-areas-api/rds"
 
 	"github.com/ONSdigital/dp-areas-api/config"
 	"github.com/ONSdigital/dp-healthcheck/healthcheck"
@@ -13,12 +15,15 @@ import (
 //go:generate moq -out mock/initialiser.go -pkg mock . Initialiser
 //go:generate moq -out mock/server.go -pkg mock . HTTPServer
 //go:generate moq -out mock/healthCheck.go -pkg mock . HealthChecker
+//go:generate moq -out mock/areaChangeListener.go -pkg mock . AreaChangeListener
 
 // Initialiser defines the methods to initialise external services
 type Initialiser interface {
 	DoGetHTTPServer(bindAddr string, router http.Handler) HTTPServer
 	DoGetHealthCheck(cfg *config.Config, buildTime, gitCommit, version string) (HealthChecker, error)
 	DoGetRDSDB(ctx context.Context, cfg *config.Config) (api.RDSAreaStore, error)
+	DoGetAreaChangeListener(ctx context.Context, cfg *config.Config, handler rds.AreaChangeHandler) (AreaChangeListener, error)
+	DoGetIdentityVerifier(cfg *config.Config) (auth.Verifier, error)
 }
 
 // HTTPServer defines the required methods from the HTTP server
@@ -34,3 +39,9 @@ type HealthChecker interface {
 	Stop()
 	AddCheck(name string, checker healthcheck.Checker) (err error)
 }
+
+// AreaChangeListener defines the required methods from the listener for areas changed by any instance
+type AreaChangeListener interface {
+	Start(ctx context.Context)
+	Close(ctx context.Context) error
+}
@@ -0,0 +1,120 @@
+// Code generated by moq; DO NOT EDIT.
+// github.com/matryer/moq
+
+package mock
+
+import (
+	"context"
+	"github.com/ONSdigital/dp-areas-api/service"
+	"sync"
+)
+
+// Ensure, that AreaChangeListenerMock does implement service.AreaChangeListener.
+// If this is not the case, regenerate this file with moq.
+var _ service.AreaChangeListener = &AreaChangeListenerMock{}
+
+// AreaChangeListenerMock is a mock implementation of service.AreaChangeListener.
+//
+// 	func TestSomethingThatUsesAreaChangeListener(t *testing.T) {
+//
+// 		// make and configure a mocked service.AreaChangeListener
+// 		mockedAreaChangeListener := &AreaChangeListenerMock{
+// 			CloseFunc: func(ctx context.Context) error {
+// 				panic("mock out the Close method")
+// 			},
+// 			StartFunc: func(ctx context.Context)  {
+// 				panic("mock out the Start method")
+// 			},
+// 		}
+//
+// 		// use mockedAreaChangeListener in code that requires service.AreaChangeListener
+// 		// and then make assertions.
+//
+// 	}
+type AreaChangeListenerMock struct {
+	// CloseFunc mocks the Close method.
+	CloseFunc func(ctx context.Context) error
+
+	// StartFunc mocks the Start method.
+	StartFunc func(ctx context.Context)
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// Close holds details about calls to the Close method.
+		Close []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+		}
+		// Start holds details about calls to the Start method.
+		Start []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+		}
+	}
+	lockClose sync.RWMutex
+	lockStart sync.RWMutex
+}
+
+// Close calls CloseFunc.
+func (mock *AreaChangeListenerMock) Close(ctx context.Context) error {
+	if mock.CloseFunc == nil {
+		panic("AreaChangeListenerMock.CloseFunc: method is nil but AreaChangeListener.Close was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+	}{
+		Ctx: ctx,
+	}
+	mock.lockClose.Lock()
+	mock.calls.Close = append(mock.calls.Close, callInfo)
+	mock.lockClose.Unlock()
+	return mock.CloseFunc(ctx)
+}
+
+// CloseCalls gets all the calls that were made to Close.
+// Check the length with:
+//
+// 	len(mockedAreaChangeListener.CloseCalls())
+func (mock *AreaChangeListenerMock) CloseCalls() []struct {
+	Ctx context.Context
+} {
+	var calls []struct {
+		Ctx context.Context
+	}
+	mock.lockClose.RLock()
+	calls = mock.calls.Close
+	mock.lockClose.RUnlock()
+	return calls
+}
+
+// Start calls StartFunc.
+func (mock *AreaChangeListenerMock) Start(ctx context.Context) {
+	if mock.StartFunc == nil {
+		panic("AreaChangeListenerMock.StartFunc: method is nil but AreaChangeListener.Start was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+	}{
+		Ctx: ctx,
+	}
+	mock.lockStart.Lock()
+	mock.calls.Start = append(mock.calls.Start, callInfo)
+	mock.lockStart.Unlock()
+	mock.StartFunc(ctx)
+}
+
+// StartCalls gets all the calls that were made to Start.
+// Check the length with:
+//
+// 	len(mockedAreaChangeListener.StartCalls())
+func (mock *AreaChangeListenerMock) StartCalls() []struct {
+	Ctx context.Context
+} {
+	var calls []struct {
+		Ctx context.Context
+	}
+	mock.lockStart.RLock()
+	calls = mock.calls.Start
+	mock.lockStart.RUnlock()
+	return calls
+}
@@ -6,7 +6,9 @@ package mock
 import (
 	"context"
 	"github.com/ONSdigital/dp-areas-api/api"
+	"github.com/ONSdigital/dp-areas-api/auth"
 	"github.com/ONSdigital/dp-areas-api/config"
+	"github.com/ONSdigital/dp-areas-api/rds"
 	"github.com/ONSdigital/dp-areas-api/service"
 	"net/http"
 	"sync"
@@ -22,12 +24,18 @@ var _ service.Initialiser = &InitialiserMock{}
 //
 // 		// make and configure a mocked service.Initialiser
 // 		mockedInitialiser := &InitialiserMock{
+// 			DoGetAreaChangeListenerFunc: func(ctx context.Context, cfg *config.Config, handler rds.AreaChangeHandler) (service.AreaChangeListener, error) {
+// 				panic("mock out the DoGetAreaChangeListener method")
+// 			},
 // 			DoGetHTTPServerFunc: func(bindAddr string, router http.Handler) service.HTTPServer {
 // 				panic("mock out the DoGetHTTPServer method")
 // 			},
 // 			DoGetHealthCheckFunc: func(cfg *config.Config, buildTime string, gitCommit string, version string) (service.HealthChecker, error) {
 // 				panic("mock out the DoGetHealthCheck method")
 // 			},
+// 			DoGetIdentityVerifierFunc: func(cfg *config.Config) (auth.Verifier, error) {
+// 				panic("mock out the DoGetIdentityVerifier method")
+// 			},
 // 			DoGetRDSDBFunc: func(ctx context.Context, cfg *config.Config) (api.RDSAreaStore, error) {
 // 				panic("mock out the DoGetRDSDB method")
 // 			},
@@ -38,17 +46,32 @@ var _ service.Initialiser = &InitialiserMock{}
 //
 // 	}
 type InitialiserMock struct {
+	// DoGetAreaChangeListenerFunc mocks the DoGetAreaChangeListener method.
+	DoGetAreaChangeListenerFunc func(ctx context.Context, cfg *config.Config, handler rds.AreaChangeHandler) (service.AreaChangeListener, error)
+
 	// DoGetHTTPServerFunc mocks the DoGetHTTPServer method.
 	DoGetHTTPServerFunc func(bindAddr string, router http.Handler) service.HTTPServer
 
 	// DoGetHealthCheckFunc mocks the DoGetHealthCheck method.
 	DoGetHealthCheckFunc func(cfg *config.Config, buildTime string, gitCommit string, version string) (service.HealthChecker, error)
 
+	// DoGetIdentityVerifierFunc mocks the DoGetIdentityVerifier method.
+	DoGetIdentityVerifierFunc func(cfg *config.Config) (auth.Verifier, error)
+
 	// DoGetRDSDBFunc mocks the DoGetRDSDB method.
 	DoGetRDSDBFunc func(ctx context.Context, cfg *config.Config) (api.RDSAreaStore, error)
 
 	// calls tracks calls to the methods.
 	calls struct {
+		// DoGetAreaChangeListener holds details about calls to the DoGetAreaChangeListener method.
+		DoGetAreaChangeListener []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Cfg is the cfg argument value.
+			Cfg *config.Config
+			// Handler is the handler argument value.
+			Handler rds.AreaChangeHandler
+		}
 		// DoGetHTTPServer holds details about calls to the DoGetHTTPServer method.
 		DoGetHTTPServer []struct {
 			// BindAddr is the bindAddr argument value.
@@ -67,6 +90,11 @@ type InitialiserMock struct {
 			// Version is the version argument value.
 			Version string
 		}
+		// DoGetIdentityVerifier holds details about calls to the DoGetIdentityVerifier method.
+		DoGetIdentityVerifier []struct {
+			// Cfg is the cfg argument value.
+			Cfg *config.Config
+		}
 		// DoGetRDSDB holds details about calls to the DoGetRDSDB method.
 		DoGetRDSDB []struct {
 			// Ctx is the ctx argument value.
@@ -75,9 +103,51 @@ type InitialiserMock struct {
 			Cfg *config.Config
 		}
 	}
-	lockDoGetHTTPServer  sync.RWMutex
-	lockDoGetHealthCheck sync.RWMutex
-	lockDoGetRDSDB       sync.RWMutex
+	lockDoGetAreaChangeListener sync.RWMutex
+	lockDoGetHTTPServer         sync.RWMutex
+	lockDoGetHealthCheck        sync.RWMutex
+	lockDoGetIdentityVerifier   sync.RWMutex
+	lockDoGetRDSDB              sync.RWMutex
+}
+
+// DoGetAreaChangeListener calls DoGetAreaChangeListenerFunc.
+func (mock *InitialiserMock) DoGetAreaChangeListener(ctx context.Context, cfg *config.Config, handler rds.AreaChangeHandler) (service.AreaChangeListener, error) {
+	if mock.DoGetAreaChangeListenerFunc == nil {
+		panic("InitialiserMock.DoGetAreaChangeListenerFunc: method is nil but Initialiser.DoGetAreaChangeListener was just called")
+	}
+	callInfo := struct {
+		Ctx     context.Context
+		Cfg     *config.Config
+		Handler rds.AreaChangeHandler
+	}{
+		Ctx:     ctx,
+		Cfg:     cfg,
+		Handler: handler,
+	}
+	mock.lockDoGetAreaChangeListener.Lock()
+	mock.calls.DoGetAreaChangeListener = append(mock.calls.DoGetAreaChangeListener, callInfo)
+	mock.lockDoGetAreaChangeListener.Unlock()
+	return mock.DoGetAreaChangeListenerFunc(ctx, cfg, handler)
+}
+
+// DoGetAreaChangeListenerCalls gets all the calls that were made to DoGetAreaChangeListener.
+// Check the length with:
+//
+// 	len(mockedInitialiser.DoGetAreaChangeListenerCalls())
+func (mock *InitialiserMock) DoGetAreaChangeListenerCalls() []struct {
+	Ctx     context.Context
+	Cfg     *config.Config
+	Handler rds.AreaChangeHandler
+} {
+	var calls []struct {
+		Ctx     context.Context
+		Cfg     *config.Config
+		Handler rds.AreaChangeHandler
+	}
+	mock.lockDoGetAreaChangeListener.RLock()
+	calls = mock.calls.DoGetAreaChangeListener
+	mock.lockDoGetAreaChangeListener.RUnlock()
+	return calls
 }
 
 // DoGetHTTPServer calls DoGetHTTPServerFunc.
@@ -100,7 +170,8 @@ func (mock *InitialiserMock) DoGetHTTPServer(bindAddr string, router http.Handle
 
 // DoGetHTTPServerCalls gets all the calls that were made to DoGetHTTPServer.
 // Check the length with:
-//     len(mockedInitialiser.DoGetHTTPServerCalls())
+//
+// 	len(mockedInitialiser.DoGetHTTPServerCalls())
 func (mock *InitialiserMock) DoGetHTTPServerCalls() []struct {
 	BindAddr string
 	Router   http.Handler
@@ -139,7 +210,8 @@ func (mock *InitialiserMock) DoGetHealthCheck(cfg *config.Config, buildTime stri
 
 // DoGetHealthCheckCalls gets all the calls that were made to DoGetHealthCheck.
 // Check the length with:
-//     len(mockedInitialiser.DoGetHealthCheckCalls())
+//
+// 	len(mockedInitialiser.DoGetHealthCheckCalls())
 func (mock *InitialiserMock) DoGetHealthCheckCalls() []struct {
 	Cfg       *config.Config
 	BuildTime string
@@ -158,6 +230,38 @@ func (mock *InitialiserMock) DoGetHealthCheckCalls() []struct {
 	return calls
 }
 
+// DoGetIdentityVerifier calls DoGetIdentityVerifierFunc.
+func (mock *InitialiserMock) DoGetIdentityVerifier(cfg *config.Config) (auth.Verifier, error) {
+	if mock.DoGetIdentityVerifierFunc == nil {
+		panic("InitialiserMock.DoGetIdentityVerifierFunc: method is nil but Initialiser.DoGetIdentityVerifier was just called")
+	}
+	callInfo := struct {
+		Cfg *config.Config
+	}{
+		Cfg: cfg,
+	}
+	mock.lockDoGetIdentityVerifier.Lock()
+	mock.calls.DoGetIdentityVerifier = append(mock.calls.DoGetIdentityVerifier, callInfo)
+	mock.lockDoGetIdentityVerifier.Unlock()
+	return mock.DoGetIdentityVerifierFunc(cfg)
+}
+
+// DoGetIdentityVerifierCalls gets all the calls that were made to DoGetIdentityVerifier.
+// Check the length with:
+//
+// 	len(mockedInitialiser.DoGetIdentityVerifierCalls())
+func (mock *InitialiserMock) DoGetIdentityVerifierCalls() []struct {
+	Cfg *config.Config
+} {
+	var calls []struct {
+		Cfg *config.Config
+	}
+	mock.lockDoGetIdentityVerifier.RLock()
+	calls = mock.calls.DoGetIdentityVerifier
+	mock.lockDoGetIdentityVerifier.RUnlock()
+	return calls
+}
+
 // DoGetRDSDB calls DoGetRDSDBFunc.
 func (mock *InitialiserMock) DoGetRDSDB(ctx context.Context, cfg *config.Config) (api.RDSAreaStore, error) {
 	if mock.DoGetRDSDBFunc == nil {
@@ -178,7 +282,8 @@ func (mock *InitialiserMock) DoGetRDSDB(ctx context.Context, cfg *config.Config)
 
 // DoGetRDSDBCalls gets all the calls that were made to DoGetRDSDB.
 // Check the length with:
-//     len(mockedInitialiser.DoGetRDSDBCalls())
+//
+// 	len(mockedInitialiser.DoGetRDSDBCalls())
 func (mock *InitialiserMock) DoGetRDSDBCalls() []struct {
 	Ctx context.Context
 	Cfg *config.Config
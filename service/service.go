@@ -2,24 +2,27 @@ package service
 
 import (
 	"context"
+	"net/http"
+
 	s3 "github.com/ONSdigital/dp-s3/v2"
 
 	"github.com/ONSdigital/dp-areas-api/api"
+	"github.com/ONSdigital/dp-areas-api/auth"
+	"github.com/ONSdigital/dp-areas-api/cache"
 	"github.com/ONSdigital/dp-areas-api/config"
-	"github.com/ONSdigital/dp-areas-api/models"
-	"github.com/ONSdigital/dp-areas-api/models/DBRelationalSchema"
+	"github.com/ONSdigital/dp-areas-api/metrics"
+	"github.com/ONSdigital/dp-areas-api/tracing"
 
-	"github.com/ONSdigital/dp-areas-api/pgx"
 	"github.com/ONSdigital/log.go/v2/log"
 	"github.com/gorilla/mux"
 	"github.com/pkg/errors"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 
 	health "github.com/ONSdigital/dp-areas-api/service/healthcheck"
 )
 
 // Service contains all the configs, server and clients to run the dp-areas-api API
 const (
-	databaseName     = "dp-areas-api"
 	dpPublishingUser = "dp-areas-api-publishing"
 )
 
@@ -32,7 +35,10 @@ type Service struct {
 	ServiceList *ExternalServiceList
 	HealthCheck HealthChecker
 	RDS         api.RDSAreaStore
-	PGX         *pgx.PGX
+	// AreaChangeListener evicts the cached areas changed by other instances, nil when nothing is cached
+	AreaChangeListener AreaChangeListener
+	// TracerProvider exports the spans of the requests served
+	TracerProvider *sdktrace.TracerProvider
 }
 
 // Run the service
@@ -42,18 +48,37 @@ func Run(ctx context.Context, cfg *config.Config, serviceList *ExternalServiceLi
 
 	log.Info(ctx, "using service configuration", log.Data{"config": cfg})
 
-	// Get HTTP Server and ... // ADD CODE: Add any middleware that your service requires
+	// Trace the requests served and the queries they run
+	tp, err := tracing.Init(ctx, cfg, version)
+	if err != nil {
+		log.Fatal(ctx, "failed to initialise tracing", err)
+		return nil, err
+	}
+
+	// Get HTTP Server and trace and record the requests served by each route
 	r := mux.NewRouter()
+	r.Use(tracing.Middleware, metrics.Middleware)
 
 	s := serviceList.GetHTTPServer(cfg.BindAddr, r)
 
 	// Get RDS client
 	rds, err := serviceList.getRDSDB(ctx, cfg)
 	if err != nil {
-		log.Fatal(ctx, "failed to initialise rds db client", err)
+		log.Fatal(ctx, "failed to initialise area store", err, log.Data{"store_backend": cfg.StoreBackend})
 		return nil, err
 	}
 
+	// Listen for the areas changed by other instances, which the in-memory store cannot have
+	var listener AreaChangeListener
+	if cachedStore, ok := rds.(*cache.Store); ok && cfg.StoreBackend != config.StoreMemory {
+		listener, err = serviceList.getAreaChangeListener(ctx, cfg, cachedStore)
+		if err != nil {
+			log.Fatal(ctx, "failed to initialise area change listener", err)
+			return nil, err
+		}
+		listener.Start(ctx)
+	}
+
 	// Get S3 client
 	s3Client, err := serviceList.getS3Client(cfg)
 	if err != nil {
@@ -63,30 +88,30 @@ func Run(ctx context.Context, cfg *config.Config, serviceList *ExternalServiceLi
 
 	// only run publishing user or if pointing at local postgres instance
 	if cfg.RDSDBUser == dpPublishingUser || cfg.DPPostgresLocal {
-		// rds table schema builder
-		rdsSchema := &models.DatabaseSchema{
-			DBName:       databaseName,
-			SchemaString: DBRelationalSchema.DBSchema,
-		}
-		err = rdsSchema.BuildDatabaseSchemaModel()
+		// apply pending schema migrations
+		err = rds.BuildTables(ctx)
 		if err != nil {
-			log.Fatal(ctx, "error building database schema model", err)
+			log.Error(ctx, "error migrating database schema", err)
 			return nil, err
 		}
-		rdsSchema.TableSchemaBuilder()
-		if err != nil {
-			log.Fatal(ctx, "error building database table schema", err)
-			return nil, err
-		}
-		err = rds.BuildTables(ctx, rdsSchema.ExecutionList)
+	}
+
+	// Get the verifier of the callers of the private endpoints
+	var verifier auth.Verifier
+	if cfg.EnablePrivateEndpoints {
+		verifier, err = serviceList.getIdentityVerifier(cfg)
 		if err != nil {
-			log.Fatal(ctx, "error building database schema target", err)
+			log.Fatal(ctx, "failed to initialise identity verifier", err)
 			return nil, err
 		}
 	}
 
 	// Setup the API
-	a, _ := api.Setup(ctx, cfg, r, rds)
+	a, err := api.Setup(ctx, cfg, r, rds, verifier)
+	if err != nil {
+		log.Fatal(ctx, "failed to setup api", err)
+		return nil, err
+	}
 
 	hc, err := serviceList.GetHealthCheck(cfg, buildTime, gitCommit, version)
 	if err != nil {
@@ -94,12 +119,17 @@ func Run(ctx context.Context, cfg *config.Config, serviceList *ExternalServiceLi
 		return nil, err
 	}
 
-	if err := registerCheckers(ctx, cfg, hc, rds, s3Client); err != nil {
+	if err := registerCheckers(ctx, cfg, hc, rds, a.StaleResponses, s3Client, verifier); err != nil {
 		return nil, errors.Wrap(err, "unable to register checkers")
 	}
 
 	r.StrictSlash(true).Path("/health").HandlerFunc(hc.Handler)
+	r.Path("/metrics").Methods(http.MethodGet).Handler(metrics.Handler())
+	if cachedStore, ok := rds.(*cache.Store); ok {
+		metrics.RegisterCache(cachedStore)
+	}
 	hc.Start(ctx)
+	a.StaleResponses.Start(ctx)
 
 	// Run the http server in a new go-routine
 	go func() {
@@ -109,13 +139,15 @@ func Run(ctx context.Context, cfg *config.Config, serviceList *ExternalServiceLi
 	}()
 
 	return &Service{
-		Config:      cfg,
-		Router:      r,
-		API:         a,
-		HealthCheck: hc,
-		ServiceList: serviceList,
-		Server:      s,
-		RDS:         rds,
+		Config:             cfg,
+		Router:             r,
+		API:                a,
+		HealthCheck:        hc,
+		ServiceList:        serviceList,
+		Server:             s,
+		RDS:                rds,
+		AreaChangeListener: listener,
+		TracerProvider:     tp,
 	}, nil
 }
 
@@ -142,10 +174,34 @@ func (svc *Service) Close(ctx context.Context) error {
 			hasShutdownError = true
 		}
 
+		// save the stale responses once requests are no longer served
+		if svc.API != nil {
+			if err := svc.API.StaleResponses.Close(ctx); err != nil {
+				log.Error(ctx, "failed to save stale responses", err)
+				hasShutdownError = true
+			}
+		}
+
+		// stop listening before closing the store whose cache the listener evicts
+		if svc.AreaChangeListener != nil {
+			if err := svc.AreaChangeListener.Close(ctx); err != nil {
+				log.Error(ctx, "failed to close area change listener", err)
+				hasShutdownError = true
+			}
+		}
+
 		// close RDS DB connection
 		if svc.RDS != nil {
 			svc.RDS.Close()
 		}
+
+		// export the spans of the requests served before shutdown
+		if svc.TracerProvider != nil {
+			if err := svc.TracerProvider.Shutdown(ctx); err != nil {
+				log.Error(ctx, "failed to shutdown tracer provider", err)
+				hasShutdownError = true
+			}
+		}
 	}()
 
 	// wait for shutdown success (via cancel) or failure (timeout)
@@ -168,17 +224,29 @@ func (svc *Service) Close(ctx context.Context) error {
 	return nil
 }
 
-func registerCheckers(ctx context.Context, cfg *config.Config, hc HealthChecker, rds api.RDSAreaStore, s3Client *s3.Client) (err error) {
+func registerCheckers(ctx context.Context, cfg *config.Config, hc HealthChecker, rds api.RDSAreaStore, stale *api.StaleResponses, s3Client *s3.Client, verifier auth.Verifier) (err error) {
 	hasErrors := false
 
 	if err := hc.AddCheck("S3 healthchecker", s3Client.Checker); err != nil {
 		hasErrors = true
 		log.Error(ctx, "error adding check for s3 client", err)
 	}
-	if err := hc.AddCheck("RDS healthchecker", health.RDSHealthCheck(ctx, cfg, rds)); err != nil {
+	if err := hc.AddCheck("RDS healthchecker", health.RDSHealthCheck(rds)); err != nil {
 		hasErrors = true
 		log.Error(ctx, "error adding check for rds client", err)
 	}
+	if cfg.HasDBReader() {
+		if err := hc.AddCheck("RDS reader healthchecker", health.RDSReaderHealthCheck(rds, stale)); err != nil {
+			hasErrors = true
+			log.Error(ctx, "error adding check for rds reader client", err)
+		}
+	}
+	if zebedee, ok := verifier.(*auth.ZebedeeVerifier); ok {
+		if err := hc.AddCheck("Zebedee", zebedee.Checker); err != nil {
+			hasErrors = true
+			log.Error(ctx, "error adding check for zebedee", err)
+		}
+	}
 
 	if hasErrors {
 		return errors.New("Error(s) registering checkers for healthcheck")
@@ -4,13 +4,17 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"net/http/httptest"
 	"sync"
 	"testing"
 	"time"
 
 	"github.com/ONSdigital/dp-areas-api/api"
 	apiMock "github.com/ONSdigital/dp-areas-api/api/mock"
+	"github.com/ONSdigital/dp-areas-api/auth"
+	"github.com/ONSdigital/dp-areas-api/cache"
 	"github.com/ONSdigital/dp-areas-api/config"
+	"github.com/ONSdigital/dp-areas-api/rds"
 	"github.com/ONSdigital/dp-areas-api/service"
 	"github.com/ONSdigital/dp-areas-api/service/mock"
 	serviceMock "github.com/ONSdigital/dp-areas-api/service/mock"
@@ -37,6 +41,10 @@ var funcDoGetHealthcheckErr = func(cfg *config.Config, buildTime string, gitComm
 	return nil, errHealthcheck
 }
 
+var funcDoGetIdentityVerifierOk = func(cfg *config.Config) (auth.Verifier, error) {
+	return auth.NewStubVerifier(), nil
+}
+
 var funcDoGetHTTPServerNil = func(bindAddr string, router http.Handler) service.HTTPServer {
 	return nil
 }
@@ -65,7 +73,7 @@ func TestRun(t *testing.T) {
 			InitFunc: func(ctx context.Context, cfg *config.Config) error {
 				return nil
 			},
-			BuildTablesFunc: func(ctx context.Context, executionList []string) error {
+			BuildTablesFunc: func(ctx context.Context) error {
 				return nil
 			},
 		}
@@ -93,13 +101,32 @@ func TestRun(t *testing.T) {
 			return rdsDBMock, nil
 		}
 
+		Convey("Given that initialising the identity verifier returns an error", func() {
+			errVerifier := errors.New("unknown auth verifier")
+			initMock := &serviceMock.InitialiserMock{
+				DoGetIdentityVerifierFunc: func(cfg *config.Config) (auth.Verifier, error) { return nil, errVerifier },
+				DoGetHTTPServerFunc:       funcDoGetHTTPServerNil,
+				DoGetRDSDBFunc:            funcDoGetRDSDBOk,
+			}
+
+			svcErrors := make(chan error, 1)
+			svcList := service.NewServiceList(initMock)
+			_, err := service.Run(ctx, cfg, svcList, testBuildTime, testGitCommit, testVersion, svcErrors)
+
+			Convey("Then service Run fails before the API is set up", func() {
+				So(errors.Is(err, errVerifier), ShouldBeTrue)
+				So(initMock.DoGetHealthCheckCalls(), ShouldBeEmpty)
+			})
+		})
+
 		Convey("Given that initialising healthcheck returns an error", func() {
 
 			// setup (run before each `Convey` at this scope / indentation):
 			initMock := &serviceMock.InitialiserMock{
-				DoGetHTTPServerFunc:  funcDoGetHTTPServerNil,
-				DoGetHealthCheckFunc: funcDoGetHealthcheckErr,
-				DoGetRDSDBFunc:       funcDoGetRDSDBOk,
+				DoGetIdentityVerifierFunc: funcDoGetIdentityVerifierOk,
+				DoGetHTTPServerFunc:       funcDoGetHTTPServerNil,
+				DoGetHealthCheckFunc:      funcDoGetHealthcheckErr,
+				DoGetRDSDBFunc:            funcDoGetRDSDBOk,
 			}
 
 			svcErrors := make(chan error, 1)
@@ -120,9 +147,10 @@ func TestRun(t *testing.T) {
 
 			// setup (run before each `Convey` at this scope / indentation):
 			initMock := &serviceMock.InitialiserMock{
-				DoGetHTTPServerFunc:  funcDoGetHTTPServer,
-				DoGetHealthCheckFunc: funcDoGetHealthcheckOk,
-				DoGetRDSDBFunc:       funcDoGetRDSDBOk,
+				DoGetIdentityVerifierFunc: funcDoGetIdentityVerifierOk,
+				DoGetHTTPServerFunc:       funcDoGetHTTPServer,
+				DoGetHealthCheckFunc:      funcDoGetHealthcheckOk,
+				DoGetRDSDBFunc:            funcDoGetRDSDBOk,
 			}
 
 			svcErrors := make(chan error, 1)
@@ -147,6 +175,15 @@ func TestRun(t *testing.T) {
 				So(serverMock.ListenAndServeCalls(), ShouldHaveLength, 1)
 			})
 
+			Convey("Then the metrics of the requests served are served at /metrics", func() {
+				router := initMock.DoGetHTTPServerCalls()[0].Router
+				router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/metrics", nil))
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+				So(w.Code, ShouldEqual, http.StatusOK)
+				So(w.Body.String(), ShouldContainSubstring, `dp_areas_api_http_requests_total{method="GET",route="/metrics",status="200"}`)
+			})
+
 			Reset(func() {
 				// This reset is run after each `Convey` at the same scope (indentation)
 			})
@@ -163,11 +200,12 @@ func TestRun(t *testing.T) {
 			}
 
 			initMock := &serviceMock.InitialiserMock{
-				DoGetHTTPServerFunc: funcDoGetHTTPServerNil,
+				DoGetIdentityVerifierFunc: funcDoGetIdentityVerifierOk,
+				DoGetHTTPServerFunc:       funcDoGetHTTPServerNil,
 				DoGetHealthCheckFunc: func(cfg *config.Config, buildTime string, gitCommit string, version string) (service.HealthChecker, error) {
 					return hcMockAddFail, nil
 				},
-				DoGetRDSDBFunc:     funcDoGetRDSDBOk,
+				DoGetRDSDBFunc: funcDoGetRDSDBOk,
 			}
 			svcErrors := make(chan error, 1)
 			svcList := service.NewServiceList(initMock)
@@ -191,9 +229,10 @@ func TestRun(t *testing.T) {
 
 			// setup (run before each `Convey` at this scope / indentation):
 			initMock := &serviceMock.InitialiserMock{
-				DoGetHealthCheckFunc: funcDoGetHealthcheckOk,
-				DoGetHTTPServerFunc:  funcDoGetFailingHTTPServer,
-				DoGetRDSDBFunc:       funcDoGetRDSDBOk,
+				DoGetIdentityVerifierFunc: funcDoGetIdentityVerifierOk,
+				DoGetHealthCheckFunc:      funcDoGetHealthcheckOk,
+				DoGetHTTPServerFunc:       funcDoGetFailingHTTPServer,
+				DoGetRDSDBFunc:            funcDoGetRDSDBOk,
 			}
 			svcErrors := make(chan error, 1)
 			svcList := service.NewServiceList(initMock)
@@ -214,14 +253,15 @@ func TestRun(t *testing.T) {
 
 		Convey("Given that building the tables returns an error", func() {
 			initMock := &serviceMock.InitialiserMock{
-				DoGetHTTPServerFunc:  funcDoGetHTTPServer,
-				DoGetHealthCheckFunc: funcDoGetHealthcheckOk,
+				DoGetIdentityVerifierFunc: funcDoGetIdentityVerifierOk,
+				DoGetHTTPServerFunc:       funcDoGetHTTPServer,
+				DoGetHealthCheckFunc:      funcDoGetHealthcheckOk,
 				DoGetRDSDBFunc: func(ctx context.Context, cfg *config.Config) (api.RDSAreaStore, error) {
 					return &apiMock.RDSAreaStoreMock{
 						InitFunc: func(ctx context.Context, cfg *config.Config) error {
 							return nil
 						},
-						BuildTablesFunc: func(ctx context.Context, executionList []string) error {
+						BuildTablesFunc: func(ctx context.Context) error {
 							return errorRDS
 						},
 					}, nil
@@ -276,14 +316,15 @@ func TestClose(t *testing.T) {
 				InitFunc: func(ctx context.Context, cfg *config.Config) error {
 					return nil
 				},
-				BuildTablesFunc: func(ctx context.Context, executionList []string) error {
+				BuildTablesFunc: func(ctx context.Context) error {
 					return nil
 				},
 				CloseFunc: func() {},
 			}
 
 			initMock := &mock.InitialiserMock{
-				DoGetHTTPServerFunc: func(bindAddr string, router http.Handler) service.HTTPServer { return serverMock },
+				DoGetIdentityVerifierFunc: funcDoGetIdentityVerifierOk,
+				DoGetHTTPServerFunc:       func(bindAddr string, router http.Handler) service.HTTPServer { return serverMock },
 				DoGetHealthCheckFunc: func(cfg *config.Config, buildTime string, gitCommit string, version string) (service.HealthChecker, error) {
 					return hcMock, nil
 				},
@@ -304,6 +345,49 @@ func TestClose(t *testing.T) {
 			So(rdsDBMock.CloseCalls(), ShouldHaveLength, 1)
 		})
 
+		Convey("Closing a service with a cached store stops listening for area changes before the store is closed", func() {
+
+			rdsDBMock := &apiMock.RDSAreaStoreMock{
+				BuildTablesFunc: func(ctx context.Context) error { return nil },
+				CloseFunc:       func() {},
+			}
+			listenerMock := &mock.AreaChangeListenerMock{
+				StartFunc: func(ctx context.Context) {},
+				CloseFunc: func(ctx context.Context) error {
+					if len(rdsDBMock.CloseCalls()) > 0 {
+						return errors.New("listener closed after the store")
+					}
+					return nil
+				},
+			}
+
+			initMock := &mock.InitialiserMock{
+				DoGetIdentityVerifierFunc: funcDoGetIdentityVerifierOk,
+				DoGetHTTPServerFunc:       func(bindAddr string, router http.Handler) service.HTTPServer { return serverMock },
+				DoGetHealthCheckFunc: func(cfg *config.Config, buildTime string, gitCommit string, version string) (service.HealthChecker, error) {
+					return hcMock, nil
+				},
+				DoGetRDSDBFunc: func(ctx context.Context, cfg *config.Config) (api.RDSAreaStore, error) {
+					return cache.New(rdsDBMock, cfg), nil
+				},
+				DoGetAreaChangeListenerFunc: func(ctx context.Context, cfg *config.Config, handler rds.AreaChangeHandler) (service.AreaChangeListener, error) {
+					return listenerMock, nil
+				},
+			}
+
+			svcErrors := make(chan error, 1)
+			svcList := service.NewServiceList(initMock)
+			svc, err := service.Run(ctx, cfg, svcList, testBuildTime, testGitCommit, testVersion, svcErrors)
+			So(err, ShouldBeNil)
+			So(svcList.AreaChangeListener, ShouldBeTrue)
+			So(listenerMock.StartCalls(), ShouldHaveLength, 1)
+
+			err = svc.Close(context.Background())
+			So(err, ShouldBeNil)
+			So(listenerMock.CloseCalls(), ShouldHaveLength, 1)
+			So(rdsDBMock.CloseCalls(), ShouldHaveLength, 1)
+		})
+
 		Convey("If service times out while shutting down, the Close operation fails with the expected error", func() {
 			cfg.GracefulShutdownTimeout = 100 * time.Millisecond
 			timeoutServerMock := &mock.HTTPServerMock{
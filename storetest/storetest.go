@@ -0,0 +1,766 @@
+// Package storetest is a conformance suite for implementations of api.RDSAreaStore. Every store the API can run
+// against must pass it, so that behaviour seen against one store holds for the others.
+package storetest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/ONSdigital/dp-areas-api/api"
+	"github.com/ONSdigital/dp-areas-api/apierrors"
+	"github.com/ONSdigital/dp-areas-api/auth"
+	"github.com/ONSdigital/dp-areas-api/fixtures"
+	"github.com/ONSdigital/dp-areas-api/models"
+	dprequest "github.com/ONSdigital/dp-net/request"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// Store is an area store under test, which must also load fixtures
+type Store interface {
+	api.RDSAreaStore
+	LoadFixtures(ctx context.Context, set *fixtures.Set) error
+}
+
+// Run runs the conformance suite. newStore must return a store holding no data; the suite seeds a new store with the
+// default fixtures for every scenario, so that scenarios do not see each other's writes.
+func Run(t *testing.T, newStore func(t *testing.T) Store) {
+	tests := []struct {
+		name string
+		test func(t *testing.T, seededStore func() Store)
+	}{
+		{"GetArea", testGetArea},
+		{"ValidateArea", testValidateArea},
+		{"GetRelationships", testGetRelationships},
+		{"GetAncestors", testGetAncestors},
+		{"GetAreaDetails", testGetAreaDetails},
+		{"UpsertArea", testUpsertArea},
+		{"BulkUpsertAreas", testBulkUpsertAreas},
+		{"PatchArea", testPatchArea},
+		{"RetireArea", testRetireArea},
+		{"AreaAudit", testAreaAudit},
+		{"Cancellation", testCancellation},
+	}
+
+	set, err := fixtures.Open(fixtures.DefaultVersion, "")
+	if err != nil {
+		t.Fatalf("failed to open fixtures: %v", err)
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			tc.test(t, func() Store {
+				store := newStore(t)
+				So(store.LoadFixtures(context.Background(), set), ShouldBeNil)
+				return store
+			})
+		})
+	}
+}
+
+func testGetArea(t *testing.T, seededStore func() Store) {
+	ctx := context.Background()
+
+	Convey("Given a seeded store", t, func() {
+		store := seededStore()
+
+		Convey("When an area is requested", func() {
+			area, err := store.GetArea(ctx, "E92000001", false)
+
+			Convey("Then its details, name, type and geometry are returned", func() {
+				So(err, ShouldBeNil)
+				So(area.Code, ShouldEqual, "E92000001")
+				So(*area.Name, ShouldEqual, "England")
+				So(*area.AreaType, ShouldEqual, "Country")
+				So(*area.Visible, ShouldBeTrue)
+				So(area.Version, ShouldEqual, 1)
+				So(area.GeometricData, ShouldNotBeEmpty)
+			})
+		})
+
+		Convey("When an area without geometry is requested", func() {
+			area, err := store.GetArea(ctx, "W38000028", false)
+
+			Convey("Then it is returned without geometry", func() {
+				So(err, ShouldBeNil)
+				So(*area.Name, ShouldEqual, "Loughor")
+				So(area.GeometricData, ShouldBeNil)
+			})
+		})
+
+		Convey("When an unknown area is requested", func() {
+			_, err := store.GetArea(ctx, "E92000002", true)
+
+			Convey("Then no rows is returned", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, apierrors.ErrNoRows.Error())
+				So(errors.Is(err, apierrors.ErrNotFound), ShouldBeTrue)
+			})
+		})
+	})
+}
+
+func testValidateArea(t *testing.T, seededStore func() Store) {
+	ctx := context.Background()
+
+	Convey("Given a seeded store", t, func() {
+		store := seededStore()
+
+		Convey("Then a known area is valid and an unknown one is not", func() {
+			So(store.ValidateArea(ctx, "W92000004", false), ShouldBeNil)
+			err := store.ValidateArea(ctx, "W92000005", false)
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldEqual, apierrors.ErrNoRows.Error())
+			So(errors.Is(err, apierrors.ErrNotFound), ShouldBeTrue)
+		})
+	})
+}
+
+func testGetRelationships(t *testing.T, seededStore func() Store) {
+	ctx := context.Background()
+
+	Convey("Given a seeded store", t, func() {
+		store := seededStore()
+
+		Convey("When all relationships of an area are requested", func() {
+			relationships, err := store.GetRelationships(ctx, "W92000004", "", false)
+
+			Convey("Then its related areas are returned", func() {
+				So(err, ShouldBeNil)
+				So(basicData(relationships), ShouldResemble, []models.AreaBasicData{{Code: "W37000382", Name: "Gorseinon"}})
+			})
+		})
+
+		Convey("When the children of an area are requested", func() {
+			relationships, err := store.GetRelationships(ctx, "E12000003", "child", false)
+
+			Convey("Then its direct children are returned", func() {
+				So(err, ShouldBeNil)
+				So(basicData(relationships), ShouldResemble, []models.AreaBasicData{{Code: "E08000019", Name: "Sheffield"}})
+			})
+		})
+
+		Convey("When fixtures relate the same pair of areas by a second type", func() {
+			set := &fixtures.Set{
+				Version:           "v1",
+				AreaRelationships: []fixtures.AreaRelationship{{AreaCode: "W92000004", RelAreaCode: "W37000382", RelType: "related"}},
+			}
+			So(store.LoadFixtures(ctx, set), ShouldBeNil)
+
+			Convey("Then the area is related by both types", func() {
+				children, err := store.GetRelationships(ctx, "W92000004", "child", false)
+				So(err, ShouldBeNil)
+				So(basicData(children), ShouldResemble, []models.AreaBasicData{{Code: "W37000382", Name: "Gorseinon"}})
+				related, err := store.GetRelationships(ctx, "W92000004", "related", false)
+				So(err, ShouldBeNil)
+				So(basicData(related), ShouldResemble, []models.AreaBasicData{{Code: "W37000382", Name: "Gorseinon"}})
+			})
+		})
+
+		Convey("When relationships of a type the area does not have are requested", func() {
+			relationships, err := store.GetRelationships(ctx, "E12000003", "bordering", false)
+
+			Convey("Then none are returned", func() {
+				So(err, ShouldBeNil)
+				So(relationships, ShouldBeEmpty)
+			})
+		})
+
+		Convey("When relationships of an unknown type are requested", func() {
+			relationships, err := store.GetRelationships(ctx, "E12000003", "cousin", false)
+
+			Convey("Then none are returned", func() {
+				So(err, ShouldBeNil)
+				So(relationships, ShouldBeEmpty)
+			})
+		})
+	})
+}
+
+func testGetAncestors(t *testing.T, seededStore func() Store) {
+	ctx := context.Background()
+
+	Convey("Given a seeded store", t, func() {
+		store := seededStore()
+
+		Convey("When the ancestors of an area are requested", func() {
+			ancestors, err := store.GetAncestors(ctx, "W38000028")
+
+			Convey("Then they are returned nearest first", func() {
+				So(err, ShouldBeNil)
+				So(ancestors, ShouldResemble, []models.AreasAncestors{{Id: "W37000382", Name: "Gorseinon"}, {Id: "W92000004", Name: "Wales"}})
+			})
+		})
+
+		Convey("When the ancestors of a top level area are requested", func() {
+			ancestors, err := store.GetAncestors(ctx, "E92000001")
+
+			Convey("Then none are returned", func() {
+				So(err, ShouldBeNil)
+				So(ancestors, ShouldBeEmpty)
+			})
+		})
+	})
+}
+
+func testGetAreaDetails(t *testing.T, seededStore func() Store) {
+	ctx := context.Background()
+
+	Convey("Given a seeded store", t, func() {
+		store := seededStore()
+
+		Convey("When an area's details are requested with its children and boundary", func() {
+			details, err := store.GetAreaDetails(ctx, "E12000003", models.AreaDetailsOptions{Children: true, Boundary: true})
+
+			Convey("Then the area is returned with its names, ancestors, children and geometry", func() {
+				So(err, ShouldBeNil)
+				So(details.Area.Code, ShouldEqual, "E12000003")
+				So(*details.Area.Name, ShouldEqual, "Yorkshire and the Humber")
+				So(details.Area.Version, ShouldEqual, 1)
+				So(details.Area.GeometricData, ShouldNotBeEmpty)
+				So(details.Area.Ancestors, ShouldResemble, []models.AreasAncestors{{Id: "E92000001", Name: "England"}})
+				So(details.Names, ShouldHaveLength, 1)
+				So(details.Names[0].Name, ShouldEqual, "Yorkshire and the Humber")
+				So(details.Names[0].ActiveFrom.Equal(time.Date(2009, 1, 1, 0, 0, 0, 0, time.UTC)), ShouldBeTrue)
+				So(basicData(details.Children), ShouldResemble, []models.AreaBasicData{{Code: "E08000019", Name: "Sheffield"}})
+			})
+		})
+
+		Convey("When an area's details are requested without its children or boundary", func() {
+			details, err := store.GetAreaDetails(ctx, "E12000003", models.AreaDetailsOptions{})
+
+			Convey("Then neither is returned", func() {
+				So(err, ShouldBeNil)
+				So(details.Area.GeometricData, ShouldBeNil)
+				So(details.Children, ShouldBeNil)
+			})
+		})
+
+		Convey("When the details of an unknown area are requested", func() {
+			details, err := store.GetAreaDetails(ctx, "E00000000", models.AreaDetailsOptions{Children: true, Boundary: true})
+
+			Convey("Then the area is not found", func() {
+				So(errors.Is(err, apierrors.ErrNotFound), ShouldBeTrue)
+				So(details, ShouldBeNil)
+			})
+		})
+	})
+}
+
+func testUpsertArea(t *testing.T, seededStore func() Store) {
+	ctx := context.Background()
+
+	Convey("Given a seeded store", t, func() {
+		store := seededStore()
+
+		Convey("When a new area is upserted under a parent", func() {
+			inserted, err := store.UpsertArea(ctx, newArea("E05000001", "Ward One", "E08000019"), "")
+
+			Convey("Then it is created with the first version and its ancestry", func() {
+				So(err, ShouldBeNil)
+				So(inserted, ShouldBeTrue)
+				area, err := store.GetArea(ctx, "E05000001", false)
+				So(err, ShouldBeNil)
+				So(*area.Name, ShouldEqual, "Ward One")
+				So(*area.AreaType, ShouldEqual, "Electoral Wards")
+				So(area.Version, ShouldEqual, 1)
+				ancestors, err := store.GetAncestors(ctx, "E05000001")
+				So(err, ShouldBeNil)
+				So(ancestorIDs(ancestors), ShouldResemble, []string{"E08000019", "E12000003", "E92000001"})
+			})
+
+			Convey("And it is upserted again with its ETag", func() {
+				inserted, err := store.UpsertArea(ctx, newArea("E05000001", "Ward One", "E08000019"), models.AreaETag(1))
+
+				Convey("Then it is updated and its version incremented", func() {
+					So(err, ShouldBeNil)
+					So(inserted, ShouldBeFalse)
+					area, err := store.GetArea(ctx, "E05000001", false)
+					So(err, ShouldBeNil)
+					So(area.Version, ShouldEqual, 2)
+				})
+			})
+
+			Convey("And it is upserted again with a stale ETag", func() {
+				_, err := store.UpsertArea(ctx, newArea("E05000001", "Ward One", "E08000019"), models.AreaETag(2))
+
+				Convey("Then the precondition fails and the area is unchanged", func() {
+					So(errors.Is(err, apierrors.ErrPreconditionFailed), ShouldBeTrue)
+					area, err := store.GetArea(ctx, "E05000001", false)
+					So(err, ShouldBeNil)
+					So(area.Version, ShouldEqual, 1)
+				})
+			})
+		})
+
+		Convey("When an area is upserted with another parent", func() {
+			_, err := store.UpsertArea(ctx, newArea("E08000019", "Sheffield", "W92000004"), "")
+
+			Convey("Then the parent is added and the area keeps its existing parent", func() {
+				So(err, ShouldBeNil)
+				children, err := store.GetRelationships(ctx, "E12000003", "child", true)
+				So(err, ShouldBeNil)
+				So(basicData(children), ShouldContain, models.AreaBasicData{Code: "E08000019", Name: "Sheffield"})
+				children, err = store.GetRelationships(ctx, "W92000004", "child", true)
+				So(err, ShouldBeNil)
+				So(basicData(children), ShouldContain, models.AreaBasicData{Code: "E08000019", Name: "Sheffield"})
+			})
+		})
+
+		Convey("When an area is moved under its own descendant", func() {
+			_, err := store.UpsertArea(ctx, newArea("E12000003", "Yorkshire and the Humber", "E08000019"), "")
+
+			Convey("Then it is invalid data and its ancestry is unchanged", func() {
+				So(errors.Is(err, apierrors.ErrInvalidData), ShouldBeTrue)
+				So(errors.Is(err, apierrors.ErrAreaParentCycle), ShouldBeTrue)
+				ancestors, err := store.GetAncestors(ctx, "E12000003")
+				So(err, ShouldBeNil)
+				So(ancestorIDs(ancestors), ShouldResemble, []string{"E92000001"})
+			})
+		})
+
+		Convey("When an area that does not exist is upserted with an ETag", func() {
+			_, err := store.UpsertArea(ctx, newArea("E05000002", "Ward Two", ""), "*")
+
+			Convey("Then the precondition fails", func() {
+				So(errors.Is(err, apierrors.ErrPreconditionFailed), ShouldBeTrue)
+			})
+		})
+
+		Convey("When an area of an unknown type is upserted", func() {
+			area := newArea("E05000003", "Ward Three", "")
+			area.AreaType = "Parishes"
+			_, err := store.UpsertArea(ctx, area, "")
+
+			Convey("Then it is an invalid reference and nothing is written", func() {
+				So(errors.Is(err, apierrors.ErrInvalidReference), ShouldBeTrue)
+				So(store.ValidateArea(ctx, "E05000003", true), ShouldNotBeNil)
+			})
+		})
+
+		Convey("When an area is upserted under an unknown parent", func() {
+			_, err := store.UpsertArea(ctx, newArea("E05000004", "Ward Four", "E08000099"), "")
+
+			Convey("Then it is an invalid reference and nothing is written", func() {
+				So(errors.Is(err, apierrors.ErrInvalidReference), ShouldBeTrue)
+				So(store.ValidateArea(ctx, "E05000004", true), ShouldNotBeNil)
+			})
+		})
+	})
+}
+
+func testBulkUpsertAreas(t *testing.T, seededStore func() Store) {
+	ctx := context.Background()
+	invalid := newArea("E05000012", "Ward Twelve", "")
+	invalid.AreaType = "Parishes"
+	areas := []models.AreaParams{newArea("E05000011", "Ward Eleven", ""), invalid, newArea("E05000013", "Ward Thirteen", "")}
+
+	Convey("Given a seeded store", t, func() {
+		store := seededStore()
+
+		Convey("When areas are bulk upserted one per batch and the second fails", func() {
+			results, err := store.BulkUpsertAreas(ctx, areas, 1)
+
+			Convey("Then the first batch is kept and the rest are not written", func() {
+				So(err, ShouldNotBeNil)
+				So(statuses(results), ShouldResemble, []string{models.BulkAreaCreated, models.BulkAreaFailed, models.BulkAreaSkipped})
+				So(store.ValidateArea(ctx, "E05000011", false), ShouldBeNil)
+				So(store.ValidateArea(ctx, "E05000013", true), ShouldNotBeNil)
+			})
+		})
+
+		Convey("When areas are bulk upserted in one batch and the second fails", func() {
+			results, err := store.BulkUpsertAreas(ctx, areas, 0)
+
+			Convey("Then the whole batch is rolled back", func() {
+				So(err, ShouldNotBeNil)
+				So(statuses(results), ShouldResemble, []string{models.BulkAreaRolledBack, models.BulkAreaFailed, models.BulkAreaSkipped})
+				So(store.ValidateArea(ctx, "E05000011", true), ShouldNotBeNil)
+			})
+		})
+
+		Convey("When an existing area is bulk upserted with another parent", func() {
+			_, err := store.BulkUpsertAreas(ctx, []models.AreaParams{newArea("E08000019", "Sheffield", "W92000004")}, 0)
+
+			Convey("Then the parent is added and the area keeps its existing parent", func() {
+				So(err, ShouldBeNil)
+				children, err := store.GetRelationships(ctx, "E12000003", "child", true)
+				So(err, ShouldBeNil)
+				So(basicData(children), ShouldContain, models.AreaBasicData{Code: "E08000019", Name: "Sheffield"})
+			})
+		})
+
+		Convey("When new and existing areas are bulk upserted", func() {
+			existing := newArea("E08000019", "Sheffield", "E12000003")
+			existing.AreaType = "Metropolitan Districts"
+			results, err := store.BulkUpsertAreas(ctx, []models.AreaParams{existing, newArea("E05000011", "Ward Eleven", "")}, 0)
+
+			Convey("Then each is reported as updated or created", func() {
+				So(err, ShouldBeNil)
+				So(statuses(results), ShouldResemble, []string{models.BulkAreaUpdated, models.BulkAreaCreated})
+			})
+		})
+	})
+}
+
+func testPatchArea(t *testing.T, seededStore func() Store) {
+	ctx := context.Background()
+
+	Convey("Given a seeded store", t, func() {
+		store := seededStore()
+
+		Convey("When an area is renamed with its ETag", func() {
+			err := store.PatchArea(ctx, "E08000019", models.AreaPatch{replace("/area_name/name", "City of Sheffield")}, models.AreaETag(1))
+
+			Convey("Then its name is replaced and its version incremented", func() {
+				So(err, ShouldBeNil)
+				area, err := store.GetArea(ctx, "E08000019", false)
+				So(err, ShouldBeNil)
+				So(*area.Name, ShouldEqual, "City of Sheffield")
+				So(*area.AreaType, ShouldEqual, "Country")
+				So(area.Version, ShouldEqual, 2)
+				relationships, err := store.GetRelationships(ctx, "E12000003", "child", false)
+				So(err, ShouldBeNil)
+				So(basicData(relationships), ShouldResemble, []models.AreaBasicData{{Code: "E08000019", Name: "City of Sheffield"}})
+			})
+		})
+
+		Convey("When the parent of an area with a descendant is patched", func() {
+			_, err := store.UpsertArea(ctx, newArea("E05000001", "Ward One", "E08000019"), "")
+			So(err, ShouldBeNil)
+			err = store.PatchArea(ctx, "E08000019", models.AreaPatch{replace("/parent_code", "W92000004")}, "")
+
+			Convey("Then it is moved, and it and its descendant have only the ancestry of the new parent", func() {
+				So(err, ShouldBeNil)
+				ancestors, err := store.GetAncestors(ctx, "E08000019")
+				So(err, ShouldBeNil)
+				So(ancestorIDs(ancestors), ShouldResemble, []string{"W92000004"})
+				ancestors, err = store.GetAncestors(ctx, "E05000001")
+				So(err, ShouldBeNil)
+				So(ancestorIDs(ancestors), ShouldResemble, []string{"E08000019", "W92000004"})
+				children, err := store.GetRelationships(ctx, "E12000003", "child", true)
+				So(err, ShouldBeNil)
+				So(children, ShouldBeEmpty)
+			})
+		})
+
+		Convey("When an area is patched under its own descendant", func() {
+			err := store.PatchArea(ctx, "E12000003", models.AreaPatch{replace("/parent_code", "E08000019")}, "")
+
+			Convey("Then it is invalid data and its ancestry is unchanged", func() {
+				So(errors.Is(err, apierrors.ErrAreaParentCycle), ShouldBeTrue)
+				ancestors, err := store.GetAncestors(ctx, "E12000003")
+				So(err, ShouldBeNil)
+				So(ancestorIDs(ancestors), ShouldResemble, []string{"E92000001"})
+			})
+		})
+
+		Convey("When an area is patched with a stale ETag", func() {
+			err := store.PatchArea(ctx, "E08000019", models.AreaPatch{replace("/visible", false)}, models.AreaETag(2))
+
+			Convey("Then the precondition fails", func() {
+				So(errors.Is(err, apierrors.ErrPreconditionFailed), ShouldBeTrue)
+			})
+		})
+
+		Convey("When an area is renamed to the name of another area", func() {
+			err := store.PatchArea(ctx, "E08000019", models.AreaPatch{replace("/area_name/name", "England")}, "")
+
+			Convey("Then both areas have the name", func() {
+				So(err, ShouldBeNil)
+				area, err := store.GetArea(ctx, "E08000019", false)
+				So(err, ShouldBeNil)
+				So(*area.Name, ShouldEqual, "England")
+				So(area.Version, ShouldEqual, 2)
+				area, err = store.GetArea(ctx, "E92000001", false)
+				So(err, ShouldBeNil)
+				So(*area.Name, ShouldEqual, "England")
+			})
+		})
+
+		Convey("When a patch removes a required field", func() {
+			err := store.PatchArea(ctx, "E08000019", models.AreaPatch{replace("/area_name/name", "")}, "")
+
+			Convey("Then the patch is invalid and the area is unchanged", func() {
+				So(errors.Is(err, apierrors.ErrInvalidAreaPatch), ShouldBeTrue)
+				area, err := store.GetArea(ctx, "E08000019", false)
+				So(err, ShouldBeNil)
+				So(area.Version, ShouldEqual, 1)
+			})
+		})
+
+		Convey("When an unknown area is patched", func() {
+			err := store.PatchArea(ctx, "E08000099", models.AreaPatch{replace("/visible", false)}, "")
+
+			Convey("Then no rows is returned", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, apierrors.ErrNoRows.Error())
+				So(errors.Is(err, apierrors.ErrNotFound), ShouldBeTrue)
+			})
+		})
+	})
+}
+
+func testRetireArea(t *testing.T, seededStore func() Store) {
+	ctx := context.Background()
+
+	Convey("Given a seeded store", t, func() {
+		store := seededStore()
+
+		Convey("When an area with live children is retired without cascade", func() {
+			err := store.RetireArea(ctx, "W92000004", false, "")
+
+			Convey("Then it is refused", func() {
+				So(errors.Is(err, apierrors.ErrAreaHasLiveChildren), ShouldBeTrue)
+				So(store.ValidateArea(ctx, "W92000004", false), ShouldBeNil)
+			})
+		})
+
+		Convey("When an area is retired with cascade", func() {
+			err := store.RetireArea(ctx, "W92000004", true, models.AreaETag(1))
+
+			Convey("Then it and its descendants are hidden unless inactive areas are included", func() {
+				So(err, ShouldBeNil)
+				for _, code := range []string{"W92000004", "W37000382", "W38000028"} {
+					So(store.ValidateArea(ctx, code, false), ShouldNotBeNil)
+					area, err := store.GetArea(ctx, code, true)
+					So(err, ShouldBeNil)
+					So(*area.Visible, ShouldBeFalse)
+					So(area.Version, ShouldEqual, 2)
+				}
+				relationships, err := store.GetRelationships(ctx, "W92000004", "", false)
+				So(err, ShouldBeNil)
+				So(relationships, ShouldBeEmpty)
+				relationships, err = store.GetRelationships(ctx, "W92000004", "", true)
+				So(err, ShouldBeNil)
+				So(relationships, ShouldHaveLength, 1)
+			})
+		})
+
+		Convey("When a retired area is retired again", func() {
+			So(store.RetireArea(ctx, "W38000028", false, ""), ShouldBeNil)
+			retired, err := store.GetArea(ctx, "W38000028", true)
+			So(err, ShouldBeNil)
+			err = store.RetireArea(ctx, "W38000028", false, models.AreaETag(retired.Version))
+
+			Convey("Then it succeeds without writing the area or auditing it again", func() {
+				So(err, ShouldBeNil)
+				area, err := store.GetArea(ctx, "W38000028", true)
+				So(err, ShouldBeNil)
+				So(area.Version, ShouldEqual, retired.Version)
+				So(area.UpdatedAt, ShouldEqual, retired.UpdatedAt)
+				page, err := store.GetAreaAudit(ctx, "W38000028", models.AreaAuditQuery{Limit: models.MaxAreaAuditLimit})
+				So(err, ShouldBeNil)
+				So(page.TotalCount, ShouldEqual, 1)
+			})
+		})
+
+		Convey("When a leaf area is retired", func() {
+			err := store.RetireArea(ctx, "W38000028", false, "")
+
+			Convey("Then its parent can be retired without cascade", func() {
+				So(err, ShouldBeNil)
+				So(store.RetireArea(ctx, "W37000382", false, ""), ShouldBeNil)
+			})
+		})
+
+		Convey("When an unknown area is retired", func() {
+			err := store.RetireArea(ctx, "W38000099", false, "")
+
+			Convey("Then no rows is returned", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, apierrors.ErrNoRows.Error())
+				So(errors.Is(err, apierrors.ErrNotFound), ShouldBeTrue)
+			})
+		})
+	})
+}
+
+func testAreaAudit(t *testing.T, seededStore func() Store) {
+	ctx := context.Background()
+	userCtx := context.WithValue(ctx, dprequest.UserIdentityKey, "publisher@ons.gov.uk")
+	serviceCtx := context.WithValue(ctx, dprequest.CallerIdentityKey, "dp-import-api")
+	all := models.AreaAuditQuery{Limit: models.MaxAreaAuditLimit}
+
+	Convey("Given a seeded store", t, func() {
+		store := seededStore()
+
+		Convey("When an area is created by a user and renamed by a service", func() {
+			_, err := store.UpsertArea(userCtx, newArea("E05000001", "Ward One", "E08000019"), "")
+			So(err, ShouldBeNil)
+			err = store.PatchArea(serviceCtx, "E05000001", models.AreaPatch{replace("/area_name/name", "Ward 1")}, models.AreaETag(1))
+			So(err, ShouldBeNil)
+			_, err = store.UpsertArea(userCtx, newArea("E05000001", "Ward One", "E08000019"), models.AreaETag(1))
+			So(errors.Is(err, apierrors.ErrPreconditionFailed), ShouldBeTrue)
+
+			Convey("Then both changes are recorded with their actor, most recent first, and the failed write is not", func() {
+				page, err := store.GetAreaAudit(ctx, "E05000001", all)
+				So(err, ShouldBeNil)
+				So(page.TotalCount, ShouldEqual, 2)
+				So(page.Count, ShouldEqual, 2)
+
+				patched, created := page.Items[0], page.Items[1]
+				So(created.Operation, ShouldEqual, models.AreaAuditCreate)
+				So(created.Actor, ShouldEqual, "publisher@ons.gov.uk")
+				So(created.Before, ShouldBeNil)
+				So(created.After.Code, ShouldEqual, "E05000001")
+				So(created.After.AreaType, ShouldEqual, "Electoral Wards")
+				So(created.After.ParentCode, ShouldEqual, "E08000019")
+				So(created.After.AreaHectares, ShouldEqual, 12.5)
+				So(created.After.Version, ShouldEqual, 1)
+				So(created.After.Names, ShouldHaveLength, 1)
+				So(created.After.Names[0].Name, ShouldEqual, "Ward One")
+				So(created.After.Names[0].ActiveFrom.Equal(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)), ShouldBeTrue)
+
+				So(patched.Operation, ShouldEqual, models.AreaAuditPatch)
+				So(patched.Actor, ShouldEqual, "dp-import-api")
+				So(patched.Before.Names[0].Name, ShouldEqual, "Ward One")
+				So(patched.After.Names[0].Name, ShouldEqual, "Ward 1")
+				So(patched.After.Version, ShouldEqual, 2)
+				So(patched.ChangedAt.Before(created.ChangedAt), ShouldBeFalse)
+			})
+
+			Convey("Then the log can be read a page at a time", func() {
+				page, err := store.GetAreaAudit(ctx, "E05000001", models.AreaAuditQuery{Offset: 1, Limit: 1})
+				So(err, ShouldBeNil)
+				So(page.TotalCount, ShouldEqual, 2)
+				So(page.Count, ShouldEqual, 1)
+				So(page.Items[0].Operation, ShouldEqual, models.AreaAuditCreate)
+
+				page, err = store.GetAreaAudit(ctx, "E05000001", models.AreaAuditQuery{Offset: 2, Limit: 1})
+				So(err, ShouldBeNil)
+				So(page.TotalCount, ShouldEqual, 2)
+				So(page.Items, ShouldBeEmpty)
+			})
+
+			Convey("Then the log can be filtered by date", func() {
+				hourAgo, inAnHour := time.Now().Add(-time.Hour), time.Now().Add(time.Hour)
+				page, err := store.GetAreaAudit(ctx, "E05000001", models.AreaAuditQuery{From: &hourAgo, To: &inAnHour, Limit: 10})
+				So(err, ShouldBeNil)
+				So(page.TotalCount, ShouldEqual, 2)
+
+				page, err = store.GetAreaAudit(ctx, "E05000001", models.AreaAuditQuery{From: &inAnHour, Limit: 10})
+				So(err, ShouldBeNil)
+				So(page.TotalCount, ShouldEqual, 0)
+				So(page.Items, ShouldBeEmpty)
+
+				page, err = store.GetAreaAudit(ctx, "E05000001", models.AreaAuditQuery{To: &hourAgo, Limit: 10})
+				So(err, ShouldBeNil)
+				So(page.TotalCount, ShouldEqual, 0)
+			})
+		})
+
+		Convey("When an area is retired with cascade", func() {
+			So(store.RetireArea(ctx, "W92000004", true, ""), ShouldBeNil)
+
+			Convey("Then the retirement of it and each descendant is recorded", func() {
+				for _, code := range []string{"W92000004", "W37000382", "W38000028"} {
+					page, err := store.GetAreaAudit(ctx, code, all)
+					So(err, ShouldBeNil)
+					So(page.Items, ShouldHaveLength, 1)
+					So(page.Items[0].Operation, ShouldEqual, models.AreaAuditRetire)
+					So(page.Items[0].Actor, ShouldEqual, auth.SystemActor)
+					So(*page.Items[0].Before.Visible, ShouldBeTrue)
+					So(*page.Items[0].After.Visible, ShouldBeFalse)
+					So(page.Items[0].After.Version, ShouldEqual, page.Items[0].Before.Version+1)
+				}
+			})
+		})
+
+		Convey("When the log of an area without changes is read", func() {
+			page, err := store.GetAreaAudit(ctx, "E92000001", all)
+
+			Convey("Then it is empty", func() {
+				So(err, ShouldBeNil)
+				So(page.TotalCount, ShouldEqual, 0)
+				So(page.Items, ShouldBeEmpty)
+			})
+		})
+	})
+}
+
+func testCancellation(t *testing.T, seededStore func() Store) {
+	Convey("Given a seeded store and a request that has been cancelled", t, func() {
+		store := seededStore()
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		Convey("When an area is read", func() {
+			_, err := store.GetArea(ctx, "E92000001", false)
+
+			Convey("Then the cancellation is returned", func() {
+				So(errors.Is(err, context.Canceled), ShouldBeTrue)
+			})
+		})
+
+		Convey("When relationships are read", func() {
+			_, err := store.GetRelationships(ctx, "E92000001", "", false)
+
+			Convey("Then the cancellation is returned", func() {
+				So(errors.Is(err, context.Canceled), ShouldBeTrue)
+			})
+		})
+
+		Convey("When an area is written", func() {
+			_, err := store.UpsertArea(ctx, newArea("E05000021", "Ward Twenty One", ""), "")
+
+			Convey("Then the cancellation is returned and nothing is written", func() {
+				So(errors.Is(err, context.Canceled), ShouldBeTrue)
+				So(store.ValidateArea(context.Background(), "E05000021", true), ShouldNotBeNil)
+			})
+		})
+	})
+}
+
+// newArea returns a valid area of the type its code implies
+func newArea(code, name, parentCode string) models.AreaParams {
+	activeFrom := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	visible := true
+	area := models.AreaParams{
+		Code:          code,
+		AreaName:      &models.AreaName{Name: name, ActiveFrom: &activeFrom},
+		GeometricData: "[[[-1.5,53.4],[-1.4,53.4],[-1.4,53.3],[-1.5,53.4]]]",
+		ActiveFrom:    &activeFrom,
+		Visible:       &visible,
+		ParentCode:    parentCode,
+		AreaHectares:  12.5,
+	}
+	area.SetAreaType(context.Background())
+	return area
+}
+
+func replace(path string, value interface{}) models.PatchOperation {
+	raw, _ := json.Marshal(value)
+	return models.PatchOperation{Op: models.PatchOpReplace, Path: path, Value: raw}
+}
+
+// basicData returns related areas sorted by code, as stores need not return them in any order
+func basicData(relationships []*models.AreaBasicData) []models.AreaBasicData {
+	data := make([]models.AreaBasicData, 0, len(relationships))
+	for _, relationship := range relationships {
+		data = append(data, *relationship)
+	}
+	sort.Slice(data, func(i, j int) bool { return data[i].Code < data[j].Code })
+	return data
+}
+
+func ancestorIDs(ancestors []models.AreasAncestors) []string {
+	ids := make([]string, 0, len(ancestors))
+	for _, ancestor := range ancestors {
+		ids = append(ids, ancestor.Id)
+	}
+	return ids
+}
+
+func statuses(results []models.BulkAreaResult) []string {
+	s := make([]string, 0, len(results))
+	for _, result := range results {
+		s = append(s, result.Status)
+	}
+	return s
+}
@@ -0,0 +1,39 @@
+package tracing
+
+import (
+	"net/http"
+
+	"github.com/ONSdigital/dp-areas-api/middleware"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// serverName is the name of the service in the attributes of its request spans
+const serverName = "dp-areas-api"
+
+// Middleware starts a span for each request served by the routes of a mux.Router, named by its method and route
+// template, continuing the trace of the traceparent header the request was sent with. The handler serves the request
+// with the span in its context, so that the queries it runs are children of it. It is added with Router.Use, which only
+// runs it for requests that match a route.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		route := middleware.RouteTemplate(req)
+		ctx := otel.GetTextMapPropagator().Extract(req.Context(), propagation.HeaderCarrier(req.Header))
+		ctx, span := tracer().Start(ctx, req.Method+" "+route,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(semconv.HTTPServerAttributesFromHTTPRequest(serverName, route, req)...),
+		)
+		defer span.End()
+
+		recorder := middleware.NewResponseRecorder(w)
+		next.ServeHTTP(recorder, req.WithContext(ctx))
+
+		span.SetAttributes(semconv.HTTPAttributesFromHTTPStatusCode(recorder.Status)...)
+		if code, description := semconv.SpanStatusFromHTTPStatusCodeAndSpanKind(recorder.Status, trace.SpanKindServer); code == codes.Error {
+			span.SetStatus(code, description)
+		}
+	})
+}
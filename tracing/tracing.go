@@ -0,0 +1,84 @@
+// Package tracing traces the requests the service serves and the database queries they run with OpenTelemetry. The
+// trace of a request is continued from the W3C traceparent header it is sent with, each request is a span named by its
+// route, and each query is a child span named as in rds/queries.go. Spans are exported to an OTLP collector when one is
+// configured, and are otherwise dropped.
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ONSdigital/dp-areas-api/config"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName names the tracer of the service
+const instrumentationName = "github.com/ONSdigital/dp-areas-api"
+
+// tracer starts the spans of the service with the global tracer provider, which is set by Init
+func tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+// Init sets the global tracer provider and the W3C trace context propagator. Spans are exported in batches to the
+// collector at cfg.OTExporterOTLPEndpoint when it is set and are otherwise dropped, but trace contexts are propagated
+// either way. The provider returned must be shut down to export the spans not yet sent.
+func Init(ctx context.Context, cfg *config.Config, version string) (*sdktrace.TracerProvider, error) {
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(semconv.SchemaURL,
+		semconv.ServiceNameKey.String(cfg.OTServiceName),
+		semconv.ServiceVersionKey.String(version),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe service for tracing: %w", err)
+	}
+
+	options := []sdktrace.TracerProviderOption{sdktrace.WithResource(res)}
+	if cfg.OTExporterOTLPEndpoint != "" {
+		exporter, err := newExporter(ctx, cfg)
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, sdktrace.WithBatcher(exporter, sdktrace.WithBatchTimeout(cfg.OTBatchTimeout)))
+	}
+
+	provider := sdktrace.NewTracerProvider(options...)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	return provider, nil
+}
+
+// newExporter returns an exporter of spans to the OTLP/HTTP collector at cfg.OTExporterOTLPEndpoint
+func newExporter(ctx context.Context, cfg *config.Config) (sdktrace.SpanExporter, error) {
+	options := []otlptracehttp.Option{otlptracehttp.WithEndpoint(cfg.OTExporterOTLPEndpoint)}
+	if cfg.OTExporterOTLPInsecure {
+		options = append(options, otlptracehttp.WithInsecure())
+	}
+	exporter, err := otlptracehttp.New(ctx, options...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create otlp exporter for %s: %w", cfg.OTExporterOTLPEndpoint, err)
+	}
+	return exporter, nil
+}
+
+// TraceQuery starts a child span of the span in ctx for a database query, named by the query. It is a
+// pgx.QueryObserver.
+func TraceQuery(ctx context.Context, name string) func(err error) {
+	_, span := tracer().Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(semconv.DBSystemPostgreSQL, semconv.DBOperationKey.String(name)),
+	)
+	return func(err error) {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}
+}
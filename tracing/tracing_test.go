@@ -0,0 +1,117 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ONSdigital/dp-areas-api/config"
+	"github.com/gorilla/mux"
+	. "github.com/smartystreets/goconvey/convey"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	traceID    = "4bf92f3577b34da6a3ce929d0e0e4736"
+	parentSpan = "00f067aa0ba902b7"
+)
+
+func TestMiddleware(t *testing.T) {
+	Convey("Given a router whose requests are traced", t, func() {
+		recorder := tracetest.NewSpanRecorder()
+		otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+		otel.SetTextMapPropagator(propagation.TraceContext{})
+
+		r := mux.NewRouter()
+		r.Use(Middleware)
+		r.Path("/v1/areas/{id}").Methods(http.MethodGet).HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			if mux.Vars(req)["id"] == "broken" {
+				TraceQuery(req.Context(), "getArea")(errors.New("connection refused"))
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			TraceQuery(req.Context(), "getArea")(nil)
+			TraceQuery(req.Context(), "getAncestors")(nil)
+		})
+
+		Convey("When an area is requested with a traceparent header", func() {
+			req := httptest.NewRequest(http.MethodGet, "/v1/areas/E92000001", nil)
+			req.Header.Set("traceparent", "00-"+traceID+"-"+parentSpan+"-01")
+			r.ServeHTTP(httptest.NewRecorder(), req)
+
+			spans := recorder.Ended()
+			So(spans, ShouldHaveLength, 3)
+			server := spans[2]
+
+			Convey("Then the request is a span of the route that continues the trace", func() {
+				So(server.Name(), ShouldEqual, "GET /v1/areas/{id}")
+				So(server.SpanKind(), ShouldEqual, trace.SpanKindServer)
+				So(server.SpanContext().TraceID().String(), ShouldEqual, traceID)
+				So(server.Parent().SpanID().String(), ShouldEqual, parentSpan)
+				So(server.Status().Code, ShouldEqual, codes.Unset)
+			})
+
+			Convey("Then each query is a child span named by the query", func() {
+				So(spans[0].Name(), ShouldEqual, "getArea")
+				So(spans[1].Name(), ShouldEqual, "getAncestors")
+				for _, span := range spans[:2] {
+					So(span.SpanKind(), ShouldEqual, trace.SpanKindClient)
+					So(span.Parent().SpanID(), ShouldEqual, server.SpanContext().SpanID())
+				}
+			})
+		})
+
+		Convey("When a request fails with a query", func() {
+			r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/v1/areas/broken", nil))
+
+			spans := recorder.Ended()
+			So(spans, ShouldHaveLength, 2)
+
+			Convey("Then both the query and the request spans are errors, in a new trace", func() {
+				So(spans[0].Status().Code, ShouldEqual, codes.Error)
+				So(spans[0].Events(), ShouldHaveLength, 1)
+				So(spans[1].Status().Code, ShouldEqual, codes.Error)
+				So(spans[1].Parent().IsValid(), ShouldBeFalse)
+			})
+		})
+	})
+}
+
+func TestInit(t *testing.T) {
+	ctx := context.Background()
+
+	Convey("Given no collector is configured", t, func() {
+		cfg := &config.Config{OTServiceName: "dp-areas-api"}
+
+		Convey("When tracing is initialised", func() {
+			provider, err := Init(ctx, cfg, "v1.2.3")
+
+			Convey("Then trace contexts are propagated, and the provider shuts down", func() {
+				So(err, ShouldBeNil)
+				So(otel.GetTracerProvider(), ShouldEqual, provider)
+				So(otel.GetTextMapPropagator().Fields(), ShouldContain, "traceparent")
+				So(provider.Shutdown(ctx), ShouldBeNil)
+			})
+		})
+	})
+
+	Convey("Given a collector is configured", t, func() {
+		cfg := &config.Config{OTServiceName: "dp-areas-api", OTExporterOTLPEndpoint: "localhost:4318", OTExporterOTLPInsecure: true}
+
+		Convey("When tracing is initialised", func() {
+			provider, err := Init(ctx, cfg, "v1.2.3")
+
+			Convey("Then the provider exports to it without connecting until there are spans to export", func() {
+				So(err, ShouldBeNil)
+				So(provider.Shutdown(ctx), ShouldBeNil)
+			})
+		})
+	})
+}